@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/policy"
+
+	"github.com/shopspring/decimal"
+)
+
+// secretFlags may not be set from a config file, which is meant to be
+// committed; they come from the command line or environment.
+var secretFlags = []string{"key", "auth-token"}
+
+// agentConfig is a -config file: a JSON object whose keys are flag names
+// (without the dash) plus an optional "risk" section overriding the risk
+// limits. Flags given on the command line win over the file.
+type agentConfig struct {
+	flags map[string]string // Flag name -> value, as it would be typed
+	risk  *riskConfig
+}
+
+// riskConfig overrides individual policy.RiskLimits fields. Unset fields keep
+// the mode's default limits (tight in paper mode, default otherwise).
+type riskConfig struct {
+	MaxPositionSize      *decimal.Decimal           `json:"max-position-size"`
+	MaxTotalExposure     *decimal.Decimal           `json:"max-total-exposure"`
+	MaxConcentration     *decimal.Decimal           `json:"max-concentration"`
+	MaxOpenOrders        *int                       `json:"max-open-orders"`
+	MaxCategoryExposure  map[string]decimal.Decimal `json:"max-category-exposure"`
+	SkewFactor           *decimal.Decimal           `json:"skew-factor"`
+	MaxDailyLoss         *decimal.Decimal           `json:"max-daily-loss"`
+	MaxDailyVolume       *decimal.Decimal           `json:"max-daily-volume"`
+	MaxDailyOrders       *int                       `json:"max-daily-orders"`
+	MaxConsecutiveLosses *int                       `json:"max-consecutive-losses"`
+	MaxOrderSize         *decimal.Decimal           `json:"max-order-size"`
+	MinOrderSize         *decimal.Decimal           `json:"min-order-size"`
+	MaxSlippage          *decimal.Decimal           `json:"max-slippage"`
+	CooldownAfterLoss    *configDuration            `json:"cooldown-after-loss"`
+	MaxSessionDuration   *configDuration            `json:"max-session-duration"`
+	MaxPositionAge       *configDuration            `json:"max-position-age"`
+	AllowedMarkets       []string                   `json:"allowed-markets"`
+	BlockedMarkets       []string                   `json:"blocked-markets"`
+}
+
+// configDuration is a time.Duration written as a string such as "15m".
+type configDuration time.Duration
+
+func (d *configDuration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"15m\"")
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = configDuration(parsed)
+	return nil
+}
+
+// loadConfig reads a config file and checks its keys against the flags in fs.
+// Unknown keys, secrets, and invalid risk limits are errors.
+func loadConfig(path string, fs *flag.FlagSet) (*agentConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	config := &agentConfig{flags: make(map[string]string)}
+	for key, value := range raw {
+		if key == "risk" {
+			dec := json.NewDecoder(bytes.NewReader(value))
+			dec.DisallowUnknownFields()
+			config.risk = &riskConfig{}
+			if err := dec.Decode(config.risk); err != nil {
+				return nil, fmt.Errorf("config %s: risk: %w", path, err)
+			}
+			continue
+		}
+
+		switch {
+		case fs.Lookup(key) == nil || key == "config":
+			return nil, fmt.Errorf("config %s: unknown key %q", path, key)
+		case slices.Contains(secretFlags, key):
+			return nil, fmt.Errorf("config %s: %q is a secret; pass -%s or use the environment", path, key, key)
+		}
+
+		s, err := flagValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("config %s: %s: %w", path, key, err)
+		}
+		config.flags[key] = s
+	}
+
+	if config.risk != nil {
+		if err := config.risk.validate(); err != nil {
+			return nil, fmt.Errorf("config %s: risk: %w", path, err)
+		}
+	}
+	return config, nil
+}
+
+// flagValue renders a JSON scalar the way it would be typed as a flag.
+func flagValue(raw json.RawMessage) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return "", err
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return fmt.Sprint(v), nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		return "", fmt.Errorf("must be a string, number, or boolean")
+	}
+}
+
+// applyFlags sets every flag from the file that was not given on the command
+// line. Values are parsed by the flags themselves, so a bad value fails here.
+func (c *agentConfig) applyFlags(fs *flag.FlagSet) error {
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	for name, value := range c.flags {
+		if explicit[name] {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("config: %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validate rejects limits that can never be satisfied or make no sense.
+func (r *riskConfig) validate() error {
+	one := decimal.NewFromInt(1)
+	fraction := func(name string, d *decimal.Decimal) error {
+		if d != nil && (d.IsNegative() || d.GreaterThan(one)) {
+			return fmt.Errorf("%s must be between 0 and 1, got %s", name, d)
+		}
+		return nil
+	}
+	nonNegative := func(name string, d *decimal.Decimal) error {
+		if d != nil && d.IsNegative() {
+			return fmt.Errorf("%s must not be negative, got %s", name, d)
+		}
+		return nil
+	}
+
+	checks := []error{
+		fraction("max-concentration", r.MaxConcentration),
+		fraction("skew-factor", r.SkewFactor),
+		fraction("max-slippage", r.MaxSlippage),
+		nonNegative("max-position-size", r.MaxPositionSize),
+		nonNegative("max-total-exposure", r.MaxTotalExposure),
+		nonNegative("max-daily-loss", r.MaxDailyLoss),
+		nonNegative("max-daily-volume", r.MaxDailyVolume),
+		nonNegative("max-order-size", r.MaxOrderSize),
+		nonNegative("min-order-size", r.MinOrderSize),
+	}
+	for category, cap := range r.MaxCategoryExposure {
+		checks = append(checks, fraction("max-category-exposure."+category, &cap))
+	}
+	for _, err := range checks {
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.MinOrderSize != nil && r.MaxOrderSize != nil && r.MinOrderSize.GreaterThan(*r.MaxOrderSize) {
+		return fmt.Errorf("min-order-size %s exceeds max-order-size %s", r.MinOrderSize, r.MaxOrderSize)
+	}
+	return nil
+}
+
+// fillTrackedKeys returns the set keys whose limits depend on fills reported
+// to the policy engine. Only paper fills are reported, so in live mode these
+// would silently do nothing.
+func (r *riskConfig) fillTrackedKeys() []string {
+	var keys []string
+	if r.MaxCategoryExposure != nil {
+		keys = append(keys, "max-category-exposure")
+	}
+	if r.SkewFactor != nil {
+		keys = append(keys, "skew-factor")
+	}
+	if r.MaxConsecutiveLosses != nil {
+		keys = append(keys, "max-consecutive-losses")
+	}
+	return keys
+}
+
+// apply overrides the set fields of limits.
+func (r *riskConfig) apply(limits *policy.RiskLimits) {
+	setDecimal := func(dst *decimal.Decimal, src *decimal.Decimal) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	setInt := func(dst *int, src *int) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	setDuration := func(dst *time.Duration, src *configDuration) {
+		if src != nil {
+			*dst = time.Duration(*src)
+		}
+	}
+
+	setDecimal(&limits.MaxPositionSize, r.MaxPositionSize)
+	setDecimal(&limits.MaxTotalExposure, r.MaxTotalExposure)
+	setDecimal(&limits.MaxConcentration, r.MaxConcentration)
+	setInt(&limits.MaxOpenOrders, r.MaxOpenOrders)
+	setDecimal(&limits.SkewFactor, r.SkewFactor)
+	setDecimal(&limits.MaxDailyLoss, r.MaxDailyLoss)
+	setDecimal(&limits.MaxDailyVolume, r.MaxDailyVolume)
+	setInt(&limits.MaxDailyOrders, r.MaxDailyOrders)
+	setInt(&limits.MaxConsecutiveLosses, r.MaxConsecutiveLosses)
+	setDecimal(&limits.MaxOrderSize, r.MaxOrderSize)
+	setDecimal(&limits.MinOrderSize, r.MinOrderSize)
+	setDecimal(&limits.MaxSlippage, r.MaxSlippage)
+	setDuration(&limits.CooldownAfterLoss, r.CooldownAfterLoss)
+	setDuration(&limits.MaxSessionDuration, r.MaxSessionDuration)
+	setDuration(&limits.MaxPositionAge, r.MaxPositionAge)
+	if r.MaxCategoryExposure != nil {
+		limits.MaxCategoryExposure = r.MaxCategoryExposure
+	}
+	if r.AllowedMarkets != nil {
+		limits.AllowedMarkets = r.AllowedMarkets
+	}
+	if r.BlockedMarkets != nil {
+		limits.BlockedMarkets = r.BlockedMarkets
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/book"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/orchestrator"
+
+	"github.com/shopspring/decimal"
+)
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "agentd.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// commandLine returns a fresh flag set sharing the command-line flags'
+// values, so each test starts with nothing set explicitly. The values are
+// restored to their defaults after the test.
+func commandLine(t *testing.T) *flag.FlagSet {
+	fs := flag.NewFlagSet("agentd", flag.ContinueOnError)
+	flag.VisitAll(func(f *flag.Flag) { fs.Var(f.Value, f.Name, f.Usage) })
+	t.Cleanup(func() {
+		fs.VisitAll(func(f *flag.Flag) { f.Value.Set(f.DefValue) })
+	})
+	return fs
+}
+
+func TestLoadConfig(t *testing.T) {
+	fs := commandLine(t)
+	path := writeConfig(t, `{
+		"paper": false,
+		"min-edge": 150,
+		"max-markets": 40,
+		"llm-preset": "fast",
+		"market-selection": "edge",
+		"risk": {
+			"max-position-size": "250",
+			"max-daily-loss": 75,
+			"max-open-orders": 12,
+			"cooldown-after-loss": "30m",
+			"blocked-markets": ["0xabc"]
+		}
+	}`)
+
+	config, err := loadConfig(path, fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.applyFlags(fs); err != nil {
+		t.Fatal(err)
+	}
+
+	selection, err := orchestrator.ParseMarketSelection(*selection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wf := workflowConfig(selection, book.PriceModeMid)
+	if wf.MinEdgeBps != 150 || wf.MaxMarkets != 40 || wf.UsePaperTrade {
+		t.Errorf("workflow config = edge %d, markets %d, paper %v; want 150, 40, false", wf.MinEdgeBps, wf.MaxMarkets, wf.UsePaperTrade)
+	}
+	if wf.MarketSelection != orchestrator.SelectionEdge {
+		t.Errorf("MarketSelection = %v, want edge", wf.MarketSelection)
+	}
+	if *llmPreset != "fast" {
+		t.Errorf("llm-preset = %q, want fast", *llmPreset)
+	}
+
+	limits := riskLimits(config)
+	if !limits.MaxPositionSize.Equal(decimal.NewFromInt(250)) || !limits.MaxDailyLoss.Equal(decimal.NewFromInt(75)) {
+		t.Errorf("limits = position %s, daily loss %s; want 250, 75", limits.MaxPositionSize, limits.MaxDailyLoss)
+	}
+	if limits.MaxOpenOrders != 12 || limits.CooldownAfterLoss != 30*time.Minute {
+		t.Errorf("limits = open orders %d, cooldown %s; want 12, 30m", limits.MaxOpenOrders, limits.CooldownAfterLoss)
+	}
+	if len(limits.BlockedMarkets) != 1 || limits.BlockedMarkets[0] != "0xabc" {
+		t.Errorf("BlockedMarkets = %v", limits.BlockedMarkets)
+	}
+}
+
+func TestRiskConfigFillTrackedKeys(t *testing.T) {
+	config, err := loadConfig(writeConfig(t, `{"risk": {"skew-factor": 0.5, "max-consecutive-losses": 3, "max-daily-loss": 75}}`), commandLine(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := config.risk.fillTrackedKeys()
+	if strings.Join(keys, ",") != "skew-factor,max-consecutive-losses" {
+		t.Errorf("fillTrackedKeys = %v, want skew-factor and max-consecutive-losses", keys)
+	}
+}
+
+func TestLoadConfigFlagsOverrideFile(t *testing.T) {
+	fs := commandLine(t)
+	if err := fs.Set("max-markets", "5"); err != nil {
+		t.Fatal(err)
+	}
+	config, err := loadConfig(writeConfig(t, `{"max-markets": 40, "min-edge": 150}`), fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.applyFlags(fs); err != nil {
+		t.Fatal(err)
+	}
+	if *maxMarkets != 5 || *minEdgeBps != 150 {
+		t.Errorf("max-markets = %d, min-edge = %d; want 5 (flag), 150 (file)", *maxMarkets, *minEdgeBps)
+	}
+}
+
+func TestLoadConfigRejects(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"unknown key", `{"max-marktes": 10}`, "unknown key"},
+		{"unknown risk key", `{"risk": {"max-posiiton-size": 10}}`, "unknown field"},
+		{"secret", `{"key": "0xdead"}`, "secret"},
+		{"bad value type", `{"max-markets": [1]}`, "must be"},
+		{"bad fraction", `{"risk": {"max-concentration": 1.5}}`, "between 0 and 1"},
+		{"min above max", `{"risk": {"min-order-size": 50, "max-order-size": 10}}`, "exceeds"},
+		{"bad duration", `{"risk": {"cooldown-after-loss": "soon"}}`, "duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadConfig(writeConfig(t, tt.body), commandLine(t))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+
+	fs := commandLine(t)
+	config, err := loadConfig(writeConfig(t, `{"max-markets": "many"}`), fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.applyFlags(fs); err == nil {
+		t.Error("applyFlags accepted a non-integer max-markets")
+	}
+}
@@ -5,19 +5,25 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"maps"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/book"
 	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/clob"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/data"
 	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/gamma"
+	"github.com/phenomenon0/polymarket-agents/pkg/tracing"
 	"github.com/phenomenon0/polymarket-agents/pkg/trader/agents"
 	"github.com/phenomenon0/polymarket-agents/pkg/trader/metrics"
 	"github.com/phenomenon0/polymarket-agents/pkg/trader/orchestrator"
@@ -28,24 +34,55 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel"
 )
 
 var (
 	// Flags
+	configFile = flag.String("config", "", "JSON config file keyed by flag name, with an optional \"risk\" section; command-line flags override it")
 	paperMode  = flag.Bool("paper", true, "Run in paper trading mode")
+	shadowMode = flag.Bool("shadow", false, "Run on live data with live risk limits but route every order to paper")
 	httpAddr   = flag.String("http", ":8080", "HTTP server address for status API")
+	authToken  = flag.String("auth-token", "", "Bearer token required on mutating endpoints such as /run-once (or AGENTD_AUTH_TOKEN env)")
 	privateKey = flag.String("key", "", "Private key for live trading (or POLYMARKET_PRIVATE_KEY env)")
+	credsFile  = flag.String("creds", "", "L2 API credentials file from polymarket-auth (or POLYMARKET_API_* env)")
 	minEdgeBps = flag.Int("min-edge", 100, "Minimum edge in basis points")
 	maxMarkets = flag.Int("max-markets", 20, "Maximum markets to track")
+	selection  = flag.String("market-selection", "", "Rank discovered markets before capping at -max-markets: volume, edge, diversified (default: discovery order)")
+	exploreEps = flag.Float64("explore-epsilon", 0, "Chance per discovery of trading the last -max-markets slot for a random market that missed the cut (0 disables)")
 	initialBal = flag.Float64("balance", 10000, "Initial paper trading balance")
 	verbose    = flag.Bool("verbose", false, "Verbose logging")
 	llmPreset  = flag.String("llm-preset", "balanced", "LLM preset: elite, balanced, cheap, local, fast")
 	noLLM      = flag.Bool("no-llm", false, "Disable LLM forecasting (signals will not be generated)")
+	newsURL    = flag.String("news-endpoint", "", "News search API endpoint for forecast context (key via NEWS_API_KEY env)")
+	minDepth   = flag.Float64("min-book-depth", 0, "Skip forecasting markets with less top-of-book size (0 disables)")
+	maxBookBps = flag.Float64("max-book-spread", 0, "Skip forecasting markets whose book spread exceeds this many bps (0 disables)")
+	maxSignal  = flag.Float64("max-signal-notional", 0, "Clamp any signal-derived order to at most this many dollars (0 disables)")
+	whaleSize  = flag.Float64("whale-size", 0, "Alert on on-chain trades of at least this many USDC in tracked markets (0 disables)")
+	paperStore = flag.String("paper-store", "", "Directory to persist the paper account in (resumes on restart)")
+	paperAcct  = flag.String("paper-account", "default", "Paper account ID within -paper-store")
+	stateFile  = flag.String("state-file", "", "JSON file to persist runtime toggles such as disabled markets in (resumes on restart)")
+	runOnce    = flag.Bool("once", false, "Run a single workflow cycle, print final stats and exit")
+	minHold    = flag.Duration("min-hold", 0, "Suppress orders that flip a token's direction within this long of the last flip (0 disables)")
+	cancelStop = flag.Bool("cancel-on-stop", false, "Cancel resting orders on shutdown")
+	equityTick = flag.Duration("equity-interval", 10*time.Second, "How often to stream paper equity to WebSocket clients (0 disables)")
+	priceMode  = flag.String("price-mode", "", "Market price for signals and paper fills: mid, last_trade, micro, weighted_mid (default: Gamma price for signals, mid for fills)")
 )
 
 func main() {
 	flag.Parse()
 
+	var config *agentConfig
+	if *configFile != "" {
+		var err error
+		if config, err = loadConfig(*configFile, flag.CommandLine); err != nil {
+			log.Fatal(err)
+		}
+		if err := config.applyFlags(flag.CommandLine); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	log.SetFlags(log.Ldate | log.Ltime | log.Lmicroseconds)
 	log.Println("Starting Polymarket Trading Agent")
 
@@ -57,8 +94,19 @@ func main() {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
+	// Export traces when an OTLP endpoint is configured
+	tp, err := tracing.NewProviderFromEnv(ctx, "agentd")
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	if tp != nil {
+		otel.SetTracerProvider(tp)
+		defer tp.Shutdown(context.Background())
+		log.Println("Tracing enabled: exporting spans over OTLP/HTTP")
+	}
+
 	// Initialize components
-	agent, err := newAgent()
+	agent, err := newAgent(config)
 	if err != nil {
 		log.Fatalf("Failed to initialize agent: %v", err)
 	}
@@ -85,6 +133,10 @@ func main() {
 		agent.streamHub.BroadcastSignal(signal)
 	})
 
+	agent.orch.OnWhaleAlert(func(a data.Activity) {
+		log.Printf("[WHALE] %s %s $%.0f of %s @ %.2f (%s)", a.ProxyWallet, a.Side, a.USDCSize, a.Outcome, a.Price, a.Title)
+	})
+
 	agent.orch.OnError(func(err error) {
 		log.Printf("[ERROR] %v", err)
 
@@ -92,6 +144,34 @@ func main() {
 		agent.streamHub.BroadcastError(err, "orchestrator")
 	})
 
+	if *runOnce {
+		results, err := agent.orch.RunOnce(ctx)
+		for _, result := range results {
+			log.Printf("[%s] %s (%.2fms)", result.Stage, statusStr(result.Success), float64(result.Duration.Microseconds())/1000)
+		}
+		agent.shutdown()
+		if err != nil {
+			log.Fatalf("Cycle failed: %v", err)
+		}
+		return
+	}
+
+	// Expire GTD paper orders even in markets that stop ticking
+	if agent.paperEngine != nil {
+		stopSweeper := agent.paperEngine.StartExpirySweeper(10 * time.Second)
+		defer stopSweeper()
+	}
+
+	// Persist the paper account periodically
+	if agent.accountStore != nil {
+		go agent.paperEngine.RunAutosave(ctx, agent.accountStore, time.Minute)
+	}
+
+	// Push the equity curve so dashboards don't have to poll /stats
+	if agent.paperEngine != nil && *equityTick > 0 {
+		go agent.streamEquity(ctx, *equityTick)
+	}
+
 	// Start HTTP server
 	go agent.startHTTP()
 
@@ -100,7 +180,7 @@ func main() {
 		log.Fatalf("Failed to start orchestrator: %v", err)
 	}
 
-	log.Printf("Agent running (paper=%v, http=%s)", *paperMode, *httpAddr)
+	log.Printf("Agent running (paper=%v, shadow=%v, http=%s)", *paperMode, *shadowMode, *httpAddr)
 	log.Printf("WebSocket streaming available at ws://%s/ws", *httpAddr)
 	log.Println("Press Ctrl+C to stop")
 
@@ -108,20 +188,19 @@ func main() {
 	<-sigCh
 	log.Println("Shutting down...")
 
-	// Graceful shutdown
-	agent.orch.Stop()
-	cancel()
-
-	// Print final stats
-	if agent.paperEngine != nil {
-		stats := agent.paperEngine.GetStats()
-		log.Printf("Final Stats: PnL=$%.2f, Trades=%d, WinRate=%.1f%%",
-			stats.TotalPnL.InexactFloat64(),
-			stats.TotalTrades,
-			stats.WinRate.Mul(decimal.NewFromInt(100)).InexactFloat64())
+	// Graceful shutdown: let an in-flight cycle finish before cancelling
+	report := agent.orch.Stop()
+	if report.TimedOut {
+		log.Println("Timed out waiting for the in-flight cycle to finish")
 	}
-
-	log.Println("Goodbye!")
+	if report.CanceledOrders > 0 {
+		log.Printf("Canceled %d resting orders", report.CanceledOrders)
+	}
+	if len(report.OpenOrders) > 0 {
+		log.Printf("Left %d orders open: %s", len(report.OpenOrders), strings.Join(report.OpenOrders, ", "))
+	}
+	cancel()
+	agent.shutdown()
 }
 
 type tradingAgent struct {
@@ -130,15 +209,23 @@ type tradingAgent struct {
 	forecaster   *agents.Forecaster
 	policyEngine *policy.PolicyEngine
 	paperEngine  *paper.Engine
+	accountStore paper.AccountStore
 	orch         *orchestrator.Orchestrator
 	metrics      *metrics.TradingMetrics
 	streamHub    *streaming.Hub
+	authToken    string // Guards mutating endpoints; empty leaves them open
+	statePath    string // -state-file; empty keeps toggles in memory only
 }
 
-func newAgent() (*tradingAgent, error) {
+func newAgent(config *agentConfig) (*tradingAgent, error) {
 	agent := &tradingAgent{
 		metrics:   metrics.NewTradingMetrics(),
 		streamHub: streaming.NewHub(),
+		authToken: *authToken,
+		statePath: *stateFile,
+	}
+	if agent.authToken == "" {
+		agent.authToken = os.Getenv("AGENTD_AUTH_TOKEN")
 	}
 
 	// Start streaming hub
@@ -154,12 +241,24 @@ func newAgent() (*tradingAgent, error) {
 	}
 
 	if key != "" {
+		opts := []clob.ClientOption{clob.WithClockSkewCorrection()}
+		creds := clob.CredentialsFromEnv()
+		if *credsFile != "" {
+			var err error
+			if creds, err = clob.LoadCredentials(*credsFile); err != nil {
+				return nil, err
+			}
+		}
+		if creds != nil {
+			opts = append(opts, clob.WithCredentials(creds))
+		}
+
 		var err error
-		agent.clobClient, err = clob.NewClient(key)
+		agent.clobClient, err = clob.NewClient(key, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create CLOB client: %w", err)
 		}
-		log.Printf("CLOB client initialized (address: %s)", agent.clobClient.Address())
+		log.Printf("CLOB client initialized (address: %s, L2 credentials: %v)", agent.clobClient.Address(), agent.clobClient.HasCredentials())
 	} else {
 		log.Println("No private key provided - CLOB client in read-only mode")
 		// Create a dummy client for read-only operations
@@ -167,38 +266,53 @@ func newAgent() (*tradingAgent, error) {
 		agent.clobClient, _ = clob.NewClient(dummyKey)
 	}
 
+	var mode book.PriceMode
+	if *priceMode != "" {
+		var err error
+		if mode, err = book.ParsePriceMode(*priceMode); err != nil {
+			return nil, err
+		}
+	}
+
+	marketSelection, err := orchestrator.ParseMarketSelection(*selection)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize policy engine
-	limits := policy.DefaultRiskLimits()
-	if *paperMode {
-		limits = policy.TightRiskLimits() // Tighter limits for paper trading
+	if !*paperMode && !*shadowMode && config != nil && config.risk != nil {
+		if keys := config.risk.fillTrackedKeys(); len(keys) > 0 {
+			return nil, fmt.Errorf("risk keys %s only apply to paper fills; use -paper or -shadow", strings.Join(keys, ", "))
+		}
 	}
-	agent.policyEngine = policy.NewPolicyEngine(limits)
+	agent.policyEngine = policy.NewPolicyEngine(riskLimits(config))
 
-	// Initialize paper trading engine
-	if *paperMode {
+	// Initialize paper trading engine (shadow mode trades into it too)
+	if *paperMode || *shadowMode {
 		paperConfig := paper.DefaultSimulationConfig()
 		paperConfig.InitialBalance = decimal.NewFromFloat(*initialBal)
 
 		// Create a price provider that uses the CLOB client
-		provider := &clobPriceProvider{client: agent.clobClient}
+		provider := &clobPriceProvider{client: agent.clobClient, mode: mode}
 		agent.paperEngine = paper.NewEngine(paperConfig, provider)
 
-		agent.paperEngine.OnTrade(func(trade *paper.Trade) {
-			log.Printf("[TRADE] %s %s @ %s (size: %s)",
-				trade.Side, trade.TokenID, trade.Price, trade.Size)
+		agent.paperEngine.OnTrade(agent.onPaperTrade)
 
-			// Broadcast to WebSocket clients
-			agent.streamHub.BroadcastTrade(trade)
-		})
+		if *paperStore != "" {
+			if err := agent.restorePaperAccount(*paperStore, *paperAcct); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// Initialize forecaster
+	var router *tools.ModelRouter
 	if *noLLM {
 		agent.forecaster = agents.NewForecaster(nil)
 		log.Println("Note: Forecaster initialized without LLM clients - signals will not be generated")
 	} else {
 		// Create model router and forecaster
-		router := tools.NewModelRouter()
+		router = tools.NewModelRouter()
 		preset := parsePreset(*llmPreset)
 
 		forecaster, err := agents.CreateForecasterWithPreset(router, preset)
@@ -209,15 +323,15 @@ func newAgent() (*tradingAgent, error) {
 		} else {
 			agent.forecaster = forecaster
 			log.Printf("Forecaster initialized with preset: %s", strings.ToUpper(*llmPreset))
+			warmupForecaster(forecaster)
 		}
 	}
 
 	// Initialize orchestrator
-	orchConfig := orchestrator.DefaultWorkflowConfig()
-	orchConfig.MinEdgeBps = *minEdgeBps
-	orchConfig.MaxMarkets = *maxMarkets
-	orchConfig.UsePaperTrade = *paperMode
-	orchConfig.MaxOrderSize = decimal.NewFromInt(100)
+	orchConfig := workflowConfig(marketSelection, mode)
+	if router != nil {
+		logEstimatedLLMCost(router, agent.forecaster, orchConfig)
+	}
 
 	agent.orch = orchestrator.NewOrchestrator(
 		orchConfig,
@@ -227,12 +341,110 @@ func newAgent() (*tradingAgent, error) {
 		agent.policyEngine,
 		agent.paperEngine,
 	)
+	if agent.statePath != "" {
+		if err := agent.restoreState(); err != nil {
+			return nil, err
+		}
+		if disabled := agent.orch.DisabledMarkets(); len(disabled) > 0 {
+			log.Printf("Trading disabled for markets: %s", strings.Join(disabled, ", "))
+		}
+	}
+
+	// Market-quality gauges, sized against the largest order we'd send
+	agent.orch.OnBook(func(tokenID string, ob *book.OrderBook) {
+		agent.metrics.RecordBook(tokenID, ob, orchConfig.MaxOrderSize)
+	})
+
+	if *whaleSize > 0 {
+		agent.orch.SetActivityClient(data.NewClient())
+	}
+
+	if *newsURL != "" {
+		agent.orch.SetNewsProvider(agents.NewHTTPNewsProvider(agents.HTTPNewsConfig{
+			Endpoint: *newsURL,
+			APIKey:   os.Getenv("NEWS_API_KEY"),
+		}))
+		log.Printf("News enrichment enabled: %s", *newsURL)
+	}
 
 	return agent, nil
 }
 
+// shutdown saves the paper account and prints final stats.
+func (a *tradingAgent) shutdown() {
+	if a.accountStore != nil {
+		if err := a.paperEngine.Save(a.accountStore); err != nil {
+			log.Printf("Failed to save paper account: %v", err)
+		}
+	}
+
+	// Print final stats
+	if a.paperEngine != nil {
+		stats := a.paperEngine.GetStats()
+		log.Printf("Final Stats: PnL=$%.2f, Trades=%d, WinRate=%.1f%%",
+			stats.TotalPnL.InexactFloat64(),
+			stats.TotalTrades,
+			stats.WinRate.Mul(decimal.NewFromInt(100)).InexactFloat64())
+	}
+
+	log.Println("Goodbye!")
+}
+
+// streamEquity broadcasts the paper account's equity and drawdown from its
+// running peak every interval until ctx is done.
+func (a *tradingAgent) streamEquity(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var peak decimal.Decimal
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			equity := a.paperEngine.GetEquity()
+			if equity.GreaterThan(peak) {
+				peak = equity
+			}
+			drawdown := decimal.Zero
+			if peak.IsPositive() {
+				drawdown = peak.Sub(equity).Div(peak)
+			}
+			a.streamHub.BroadcastEquity(streaming.EquityPoint{
+				Timestamp: now,
+				Equity:    equity,
+				Drawdown:  drawdown,
+			})
+		}
+	}
+}
+
 func (a *tradingAgent) startHTTP() {
+	server := &http.Server{
+		Addr:         *httpAddr,
+		Handler:      a.routes(),
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	log.Printf("HTTP server listening on %s", *httpAddr)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		log.Printf("HTTP server error: %v", err)
+	}
+}
+
+// runOnceResponse is the body returned by POST /run-once.
+type runOnceResponse struct {
+	Stages []*orchestrator.StageResult `json:"stages"`
+	Error  string                      `json:"error,omitempty"`
+}
+
+func (a *tradingAgent) routes() *http.ServeMux {
 	mux := http.NewServeMux()
+	// Every POST endpoint goes through auth, so anything that changes state
+	// (or can drive the agent) needs the token; reads, health, and metrics
+	// stay open
+	protect := bearerAuth(a.authToken)
 
 	// Health check
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -242,14 +454,20 @@ func (a *tradingAgent) startHTTP() {
 
 	// Status endpoint
 	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
-		status := a.orch.GetStatus()
+		status := a.orch.Snapshot().Status
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(status)
 	})
 
+	// Full state captured in one consistent read
+	mux.HandleFunc("/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(a.orch.Snapshot())
+	})
+
 	// Markets endpoint
 	mux.HandleFunc("/markets", func(w http.ResponseWriter, r *http.Request) {
-		markets := a.orch.GetActiveMarkets()
+		markets := a.orch.Snapshot().ActiveMarkets
 		w.Header().Set("Content-Type", "application/json")
 
 		summaries := make([]map[string]interface{}, len(markets))
@@ -264,13 +482,31 @@ func (a *tradingAgent) startHTTP() {
 		json.NewEncoder(w).Encode(summaries)
 	})
 
-	// Signals endpoint
-	mux.HandleFunc("/signals", func(w http.ResponseWriter, r *http.Request) {
-		signals := a.orch.GetSignals()
+	// Stop or resume trading a market without stopping its forecasts
+	mux.Handle("POST /markets/{id}/disable", protect(a.marketToggleHandler(true)))
+	mux.Handle("POST /markets/{id}/enable", protect(a.marketToggleHandler(false)))
+
+	// Market-level price history: ?condition_id=...&interval=1d
+	mux.HandleFunc("/markets/history", func(w http.ResponseWriter, r *http.Request) {
+		conditionID := r.URL.Query().Get("condition_id")
+		if conditionID == "" {
+			http.Error(w, "condition_id required", http.StatusBadRequest)
+			return
+		}
+		history, err := a.gammaClient.GetMarketPriceHistory(r.Context(), conditionID, r.URL.Query().Get("interval"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(signals)
+		json.NewEncoder(w).Encode(history)
 	})
 
+	// Signals endpoint
+	mux.HandleFunc("/signals", signalsHandler(func() []*agents.TradingSignal {
+		return a.orch.Snapshot().Signals
+	}))
+
 	// Account endpoint (paper trading)
 	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -291,29 +527,98 @@ func (a *tradingAgent) startHTTP() {
 		}
 	})
 
+	// Per-market stats endpoint
+	mux.HandleFunc("/stats/markets", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if a.paperEngine != nil {
+			json.NewEncoder(w).Encode(a.paperEngine.GetStatsByMarket())
+		} else {
+			json.NewEncoder(w).Encode(map[string]string{"error": "not in paper mode"})
+		}
+	})
+
 	// Policy endpoint
 	mux.HandleFunc("/policy", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(a.policyEngine.Status())
 	})
 
+	// Policy dry-run: POST a JSON array of proposed orders
+	mux.Handle("POST /policy/simulate", protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var orders []policy.ProposedOrder
+		if err := json.NewDecoder(r.Body).Decode(&orders); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(a.policyEngine.SimulateOrders(orders))
+	})))
+
+	// Trigger a single workflow cycle and return its stage results
+	mux.Handle("POST /run-once", protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results, err := a.orch.RunOnce(r.Context())
+		resp := runOnceResponse{Stages: results}
+		w.Header().Set("Content-Type", "application/json")
+		if err != nil {
+			resp.Error = err.Error()
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		json.NewEncoder(w).Encode(resp)
+	})))
+
 	// Prometheus metrics endpoint
 	mux.Handle("/metrics", promhttp.HandlerFor(a.metrics.Registry(), promhttp.HandlerOpts{}))
 
 	// WebSocket streaming endpoint
 	mux.HandleFunc("/ws", a.streamHub.ServeWS)
 
-	server := &http.Server{
-		Addr:         *httpAddr,
-		Handler:      mux,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
-	}
+	return mux
+}
 
-	log.Printf("HTTP server listening on %s", *httpAddr)
-	if err := server.ListenAndServe(); err != http.ErrServerClosed {
-		log.Printf("HTTP server error: %v", err)
+// workflowConfig builds the orchestrator config from the flags.
+func workflowConfig(selection orchestrator.MarketSelection, mode book.PriceMode) *orchestrator.WorkflowConfig {
+	config := orchestrator.DefaultWorkflowConfig()
+	config.MinEdgeBps = *minEdgeBps
+	config.MaxMarkets = *maxMarkets
+	config.MarketSelection = selection
+	config.ExploreEpsilon = *exploreEps
+	config.UsePaperTrade = *paperMode
+	config.ShadowMode = *shadowMode
+	config.MaxOrderSize = decimal.NewFromInt(100)
+	config.MinBookDepth = decimal.NewFromFloat(*minDepth)
+	config.MaxBookSpreadBps = decimal.NewFromFloat(*maxBookBps)
+	config.MaxSignalNotional = decimal.NewFromFloat(*maxSignal)
+	config.WhaleTradeUSDC = decimal.NewFromFloat(*whaleSize)
+	config.CancelOnStop = *cancelStop
+	config.MinHoldingPeriod = *minHold
+	config.PriceMode = mode
+	return config
+}
+
+// onPaperTrade logs a paper fill, reports it to the policy engine so
+// position, loss and category limits see it, and broadcasts it.
+func (a *tradingAgent) onPaperTrade(trade *paper.Trade) {
+	log.Printf("[TRADE] %s %s @ %s (size: %s)",
+		trade.Side, trade.TokenID, trade.Price, trade.Size)
+
+	a.policyEngine.RecordFill(trade.TokenID, trade.Size, trade.Price, trade.Side == paper.SideBuy, trade.PnL)
+
+	// Broadcast to WebSocket clients
+	a.streamHub.BroadcastTrade(trade)
+}
+
+// riskLimits picks the mode's limits (tighter for paper trading) and applies
+// the config file's risk overrides, if any.
+func riskLimits(config *agentConfig) *policy.RiskLimits {
+	limits := policy.DefaultRiskLimits()
+	if *paperMode && !*shadowMode {
+		limits = policy.TightRiskLimits()
+	}
+	if config != nil && config.risk != nil {
+		config.risk.apply(limits)
 	}
+	return limits
 }
 
 func statusStr(success bool) string {
@@ -323,6 +628,46 @@ func statusStr(success bool) string {
 	return "FAILED"
 }
 
+// warmupForecaster preflights every LLM provider so a bad key or unreachable
+// endpoint shows up at boot rather than on the first market.
+func warmupForecaster(f *agents.Forecaster) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	results := f.Warmup(ctx)
+	for _, provider := range slices.Sorted(maps.Keys(results)) {
+		if err := results[provider]; err != nil {
+			log.Printf("Warning: LLM provider %s failed warm-up: %v", provider, err)
+		} else {
+			log.Printf("LLM provider %s ready", provider)
+		}
+	}
+}
+
+// Typical token counts of one forecast call, for the startup cost estimate.
+const (
+	estimatedPromptTokens     = 1500
+	estimatedCompletionTokens = 400
+)
+
+// logEstimatedLLMCost logs the expected daily LLM spend if every tracked
+// market is re-forecast by every ensemble member each ForecastInterval.
+// Forecast caching and adaptive intervals usually bring the real figure
+// lower.
+func logEstimatedLLMCost(router *tools.ModelRouter, f *agents.Forecaster, config *orchestrator.WorkflowConfig) {
+	if config.ForecastInterval <= 0 {
+		return
+	}
+	calls := config.MaxMarkets * int(24*time.Hour/config.ForecastInterval)
+
+	var total float64
+	for _, preset := range f.ModelPresets() {
+		total += router.EstimateWorkloadCost(preset, calls, estimatedPromptTokens, estimatedCompletionTokens)
+	}
+	log.Printf("Estimated daily LLM cost at current config: $%.2f (%d markets, forecast every %s)",
+		total, config.MaxMarkets, config.ForecastInterval)
+}
+
 func parsePreset(s string) agents.ForecasterPreset {
 	switch strings.ToLower(s) {
 	case "elite":
@@ -340,17 +685,96 @@ func parsePreset(s string) agents.ForecasterPreset {
 	}
 }
 
+// signalsHandler serves the current signals. Member forecasts are left out
+// unless the request has ?verbose=true, since they dominate the payload.
+func signalsHandler(signals func() []*agents.TradingSignal) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		out := signals()
+		if verbose, _ := strconv.ParseBool(r.URL.Query().Get("verbose")); !verbose {
+			out = withoutMemberForecasts(out)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(out)
+	}
+}
+
+// withoutMemberForecasts copies signals with their ensemble's individual
+// forecasts dropped. The originals are shared with the orchestrator and are
+// left untouched.
+func withoutMemberForecasts(signals []*agents.TradingSignal) []*agents.TradingSignal {
+	out := make([]*agents.TradingSignal, len(signals))
+	for i, s := range signals {
+		if s == nil || s.Forecast == nil {
+			out[i] = s
+			continue
+		}
+		signal := *s
+		forecast := *s.Forecast
+		forecast.IndividualForecasts = nil
+		signal.Forecast = &forecast
+		out[i] = &signal
+	}
+	return out
+}
+
+// restorePaperAccount resumes the paper account from dir, or starts a fresh
+// one under id if none has been saved yet.
+func (a *tradingAgent) restorePaperAccount(dir, id string) error {
+	store, err := paper.NewFileAccountStore(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open paper store: %w", err)
+	}
+	a.accountStore = store
+
+	account, err := store.Load(id)
+	switch {
+	case err == nil:
+		log.Printf("Resumed paper account %s (balance: $%s, positions: %d)",
+			id, account.Balance.StringFixed(2), len(account.Positions))
+	case errors.Is(err, os.ErrNotExist):
+		account = a.paperEngine.GetAccount()
+		account.ID = id
+		log.Printf("Starting new paper account %s in %s", id, dir)
+	default:
+		return fmt.Errorf("failed to load paper account: %w", err)
+	}
+
+	a.paperEngine.Restore(account)
+	return nil
+}
+
 // clobPriceProvider implements paper.PriceProvider using the CLOB client.
 type clobPriceProvider struct {
 	client *clob.Client
+	mode   book.PriceMode // Empty means the venue midpoint
 }
 
+// GetMidPrice returns the token's price under the provider's price mode.
 func (p *clobPriceProvider) GetMidPrice(ctx context.Context, tokenID string) (decimal.Decimal, error) {
-	mid, err := p.client.GetMidpoint(ctx, tokenID)
-	if err != nil {
-		return decimal.Zero, err
+	switch p.mode {
+	case "", book.PriceModeMid:
+		mid, err := p.client.GetMidpoint(ctx, tokenID)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		return decimal.NewFromString(mid)
+	case book.PriceModeLastTrade:
+		last, err := p.client.GetLastTradePrice(ctx, tokenID)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		return decimal.NewFromString(last)
+	default:
+		ob, err := p.GetOrderBook(ctx, tokenID)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		price, ok := ob.Price(p.mode)
+		if !ok {
+			return decimal.Zero, fmt.Errorf("no %s price for %s: one-sided book", p.mode, tokenID)
+		}
+		return price, nil
 	}
-	return decimal.NewFromString(mid)
 }
 
 func (p *clobPriceProvider) GetOrderBook(ctx context.Context, tokenID string) (*book.OrderBook, error) {
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/book"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/clob"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/gamma"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/agents"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/metrics"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/orchestrator"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/paper"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/policy"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/streaming"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestRunOnceEndpoint(t *testing.T) {
+	gammaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	}))
+	defer gammaServer.Close()
+
+	a := &tradingAgent{
+		gammaClient:  gamma.NewClient(gamma.WithBaseURL(gammaServer.URL)),
+		forecaster:   agents.NewForecaster(nil),
+		policyEngine: policy.NewPolicyEngine(policy.DefaultRiskLimits()),
+		metrics:      metrics.NewTradingMetrics(),
+		streamHub:    streaming.NewHub(),
+	}
+	a.orch = orchestrator.NewOrchestrator(nil, a.gammaClient, nil, a.forecaster, a.policyEngine, nil)
+
+	server := httptest.NewServer(a.routes())
+	defer server.Close()
+
+	// The orchestrator was never started: RunOnce must work without the loops
+	resp, err := http.Post(server.URL+"/run-once", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST /run-once failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+
+	var body runOnceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := []orchestrator.Stage{
+		orchestrator.StageMarketDiscovery,
+		orchestrator.StageDataCollection,
+		orchestrator.StageForecasting,
+		orchestrator.StageSignalGen,
+		orchestrator.StageRiskCheck,
+		orchestrator.StageOrderExecution,
+	}
+	if len(body.Stages) != len(want) {
+		t.Fatalf("Expected %d stage results, got %d (error: %s)", len(want), len(body.Stages), body.Error)
+	}
+	for i, result := range body.Stages {
+		if result.Stage != want[i] || !result.Success {
+			t.Errorf("Stage %d: expected successful %s, got %+v", i, want[i], result)
+		}
+	}
+
+	if resp, err := http.Get(server.URL + "/run-once"); err != nil {
+		t.Fatalf("GET /run-once failed: %v", err)
+	} else if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET, got %d", resp.StatusCode)
+	}
+}
+
+func TestMarketToggleEndpointsPersistState(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	newTestAgent := func() *tradingAgent {
+		a := &tradingAgent{
+			forecaster:   agents.NewForecaster(nil),
+			policyEngine: policy.NewPolicyEngine(policy.DefaultRiskLimits()),
+			metrics:      metrics.NewTradingMetrics(),
+			streamHub:    streaming.NewHub(),
+			statePath:    statePath,
+		}
+		a.orch = orchestrator.NewOrchestrator(nil, nil, nil, a.forecaster, a.policyEngine, nil)
+		return a
+	}
+
+	a := newTestAgent()
+	server := httptest.NewServer(a.routes())
+	defer server.Close()
+
+	for _, path := range []string{"/markets/cond-a/disable", "/markets/cond-b/disable", "/markets/cond-b/enable"} {
+		resp, err := http.Post(server.URL+path, "application/json", nil)
+		if err != nil {
+			t.Fatalf("POST %s failed: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("POST %s: expected 200, got %d", path, resp.StatusCode)
+		}
+	}
+	if got := a.orch.DisabledMarkets(); len(got) != 1 || got[0] != "cond-a" {
+		t.Fatalf("Expected only cond-a disabled, got %v", got)
+	}
+
+	// A restarted agent picks the toggle back up from the state file
+	restarted := newTestAgent()
+	if err := restarted.restoreState(); err != nil {
+		t.Fatalf("restoreState failed: %v", err)
+	}
+	if got := restarted.orch.DisabledMarkets(); len(got) != 1 || got[0] != "cond-a" {
+		t.Errorf("Expected cond-a disabled after restart, got %v", got)
+	}
+
+	if resp, err := http.Get(server.URL + "/markets/cond-a/disable"); err != nil {
+		t.Fatalf("GET failed: %v", err)
+	} else if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET, got %d", resp.StatusCode)
+	}
+}
+
+func TestRunOnceRequiresAuthToken(t *testing.T) {
+	gammaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	}))
+	defer gammaServer.Close()
+
+	a := &tradingAgent{
+		gammaClient:  gamma.NewClient(gamma.WithBaseURL(gammaServer.URL)),
+		forecaster:   agents.NewForecaster(nil),
+		policyEngine: policy.NewPolicyEngine(policy.DefaultRiskLimits()),
+		metrics:      metrics.NewTradingMetrics(),
+		streamHub:    streaming.NewHub(),
+		authToken:    "s3cret-token",
+	}
+	a.orch = orchestrator.NewOrchestrator(nil, a.gammaClient, nil, a.forecaster, a.policyEngine, nil)
+
+	server := httptest.NewServer(a.routes())
+	defer server.Close()
+
+	do := func(method, path, auth string) int {
+		req, _ := http.NewRequest(method, server.URL+path, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		auth   string
+		want   int
+	}{
+		{"missing token", http.MethodPost, "/run-once", "", http.StatusUnauthorized},
+		{"wrong token", http.MethodPost, "/run-once", "Bearer nope", http.StatusUnauthorized},
+		{"wrong scheme", http.MethodPost, "/run-once", "Basic s3cret-token", http.StatusUnauthorized},
+		{"valid token", http.MethodPost, "/run-once", "Bearer s3cret-token", http.StatusOK},
+		{"disable needs token", http.MethodPost, "/markets/m1/disable", "", http.StatusUnauthorized},
+		{"enable needs token", http.MethodPost, "/markets/m1/enable", "", http.StatusUnauthorized},
+		{"simulate needs token", http.MethodPost, "/policy/simulate", "", http.StatusUnauthorized},
+		{"policy stays open", http.MethodGet, "/policy", "", http.StatusOK},
+		{"health stays open", http.MethodGet, "/health", "", http.StatusOK},
+		{"metrics stay open", http.MethodGet, "/metrics", "", http.StatusOK},
+	}
+	for _, tc := range cases {
+		if got := do(tc.method, tc.path, tc.auth); got != tc.want {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestSignalsVerboseIncludesMemberForecasts(t *testing.T) {
+	signal := &agents.TradingSignal{
+		Signal:  agents.SignalBuy,
+		TokenID: "tok-yes",
+		Forecast: &agents.EnsembleForecast{
+			TokenID:     "tok-yes",
+			Probability: decimal.NewFromFloat(0.62),
+			IndividualForecasts: []agents.Forecast{
+				{Provider: agents.ProviderClaude, Probability: decimal.NewFromFloat(0.7), Confidence: decimal.NewFromFloat(0.8), LatencyMs: 900},
+				{Provider: agents.ProviderGPT4, Probability: decimal.NewFromFloat(0.54), Confidence: decimal.NewFromFloat(0.6), LatencyMs: 1200},
+			},
+		},
+	}
+	handler := signalsHandler(func() []*agents.TradingSignal {
+		return []*agents.TradingSignal{signal}
+	})
+
+	get := func(query string) []map[string]any {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/signals"+query, nil))
+		var body []map[string]any
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("decode %q: %v", query, err)
+		}
+		if len(body) != 1 {
+			t.Fatalf("%q: expected 1 signal, got %d", query, len(body))
+		}
+		return body
+	}
+	members := func(body []map[string]any) ([]any, bool) {
+		forecast := body[0]["forecast"].(map[string]any)
+		m, ok := forecast["individual_forecasts"].([]any)
+		return m, ok
+	}
+
+	for _, query := range []string{"", "?verbose=false"} {
+		if m, ok := members(get(query)); ok {
+			t.Errorf("%q: expected member forecasts omitted, got %v", query, m)
+		}
+	}
+
+	m, ok := members(get("?verbose=true"))
+	if !ok || len(m) != 2 {
+		t.Fatalf("verbose: expected 2 member forecasts, got %v", m)
+	}
+	first := m[0].(map[string]any)
+	for _, key := range []string{"provider", "probability", "confidence", "latency_ms"} {
+		if _, ok := first[key]; !ok {
+			t.Errorf("verbose: member forecast missing %q: %v", key, first)
+		}
+	}
+	if first["provider"] != "claude" || first["latency_ms"] != float64(900) {
+		t.Errorf("verbose: unexpected first member %v", first)
+	}
+
+	// Trimming must not touch the orchestrator's shared signal
+	if len(signal.Forecast.IndividualForecasts) != 2 {
+		t.Error("non-verbose request modified the shared forecast")
+	}
+}
+
+func TestClobPriceProviderModes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/midpoint":
+			w.Write([]byte(`{"mid":"0.5"}`))
+		case "/last-trade-price":
+			w.Write([]byte(`{"price":"0.51","side":"BUY"}`))
+		case "/book":
+			json.NewEncoder(w).Encode(clob.OrderBookSummary{
+				Bids: []clob.PriceLevel{{Price: "0.48", Size: "100"}, {Price: "0.47", Size: "300"}},
+				Asks: []clob.PriceLevel{{Price: "0.52", Size: "300"}, {Price: "0.53", Size: "100"}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := clob.NewPublicClient(clob.WithCLOBBaseURL(server.URL))
+	tests := []struct {
+		mode book.PriceMode
+		want string
+	}{
+		{book.PriceModeMid, "0.5"},
+		{book.PriceModeLastTrade, "0.51"},
+		{book.PriceModeMicro, "0.49"},         // (0.48*300 + 0.52*100) / 400
+		{book.PriceModeWeightedMid, "0.4975"}, // (0.4725 + 0.5225) / 2
+	}
+	for _, tc := range tests {
+		provider := &clobPriceProvider{client: client, mode: tc.mode}
+		got, err := provider.GetMidPrice(context.Background(), "tok")
+		if err != nil {
+			t.Fatalf("%s: GetMidPrice failed: %v", tc.mode, err)
+		}
+		if !got.Equal(decimal.RequireFromString(tc.want)) {
+			t.Errorf("%s: expected %s, got %s", tc.mode, tc.want, got)
+		}
+	}
+}
+
+func TestPaperTradesReachPolicyEngine(t *testing.T) {
+	limits := policy.DefaultRiskLimits()
+	limits.MaxConsecutiveLosses = 2
+	a := &tradingAgent{
+		policyEngine: policy.NewPolicyEngine(limits),
+		streamHub:    streaming.NewHub(),
+	}
+
+	for i := 0; i < 2; i++ {
+		a.onPaperTrade(&paper.Trade{
+			TokenID: "tok",
+			Side:    paper.SideSell,
+			Price:   decimal.NewFromFloat(0.4),
+			Size:    decimal.NewFromInt(10),
+			PnL:     decimal.NewFromInt(-1),
+		})
+	}
+
+	if streak := a.policyEngine.Status().Streak; streak != -2 {
+		t.Errorf("Expected a losing streak of 2 from paper fills, got %d", streak)
+	}
+	if err := a.policyEngine.CheckOrder("tok", decimal.NewFromInt(10), decimal.NewFromFloat(0.4), true); err == nil {
+		t.Error("Expected max-consecutive-losses to halt trading after two losing fills")
+	}
+}
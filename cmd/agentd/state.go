@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// agentState is what -state-file keeps across restarts: the runtime toggles
+// made over the API.
+type agentState struct {
+	DisabledMarkets []string `json:"disabled_markets"`
+}
+
+// loadState reads the state file. A missing file is an empty state.
+func loadState(path string) (*agentState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &agentState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read state: %w", err)
+	}
+
+	var state agentState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("decode state %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// saveState replaces the state file atomically, so a crash mid-write leaves
+// the previous state intact.
+func saveState(path string, state *agentState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replace state file: %w", err)
+	}
+	return nil
+}
+
+// restoreState disables the markets recorded in the state file.
+func (a *tradingAgent) restoreState() error {
+	state, err := loadState(a.statePath)
+	if err != nil {
+		return err
+	}
+	for _, id := range state.DisabledMarkets {
+		a.orch.DisableMarket(id)
+	}
+	return nil
+}
+
+// persistState writes the current toggles to the state file, if one is set.
+func (a *tradingAgent) persistState() error {
+	if a.statePath == "" {
+		return nil
+	}
+	return saveState(a.statePath, &agentState{DisabledMarkets: a.orch.DisabledMarkets()})
+}
+
+// marketToggleResponse is the body returned by /markets/{id}/enable and
+// /markets/{id}/disable.
+type marketToggleResponse struct {
+	ID              string   `json:"id"`
+	Disabled        bool     `json:"disabled"`
+	DisabledMarkets []string `json:"disabled_markets"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// marketToggleHandler enables or disables order execution for the market in
+// the path, which keeps being forecast, and saves the change to the state
+// file.
+func (a *tradingAgent) marketToggleHandler(disable bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if disable {
+			a.orch.DisableMarket(id)
+		} else {
+			a.orch.EnableMarket(id)
+		}
+
+		resp := marketToggleResponse{ID: id, Disabled: disable, DisabledMarkets: a.orch.DisabledMarkets()}
+		w.Header().Set("Content-Type", "application/json")
+		if err := a.persistState(); err != nil {
+			resp.Error = err.Error()
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -2,6 +2,7 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/csv"
 	"encoding/json"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	"github.com/phenomenon0/polymarket-agents/pkg/trader/backtest"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/paper"
 
 	"github.com/shopspring/decimal"
 )
@@ -21,13 +23,21 @@ var (
 	// Input flags
 	dataFile   = flag.String("data", "", "Path to historical data file (JSON or CSV)")
 	strategy   = flag.String("strategy", "momentum", "Strategy: momentum, meanreversion, buyhold")
-	outputFile = flag.String("output", "", "Output file for results (JSON or CSV)")
+	outputFile = flag.String("output", "", "Output file for results (JSON, CSV, or NDJSON)")
+	format     = flag.String("format", "", "Output format: json, csv, ndjson (default: from -output extension)")
 
 	// Config flags
-	balance  = flag.Float64("balance", 10000, "Initial balance")
-	makerFee = flag.Float64("maker-fee", 0.0, "Maker fee in basis points")
-	takerFee = flag.Float64("taker-fee", 0.5, "Taker fee in basis points")
-	verbose  = flag.Bool("verbose", false, "Verbose output")
+	balance     = flag.Float64("balance", 10000, "Initial balance")
+	makerFee    = flag.Float64("maker-fee", 0.0, "Maker fee in basis points")
+	takerFee    = flag.Float64("taker-fee", 0.5, "Taker fee in basis points")
+	makerRebate = flag.Float64("maker-rebate", 0.0, "Maker rebate in basis points, credited on resting fills")
+	warmup      = flag.Int("warmup", 0, "Ticks to run the strategy before counting trades (e.g. -ma-period)")
+	realistic   = flag.Bool("realistic", false, "Fill against the simulated order book instead of at the price")
+	bookSpread  = flag.Float64("book-spread", 0.01, "Synthetic book spread for points without bid/ask")
+	bookDepth   = flag.Float64("book-depth", 1000, "Synthetic book size per level")
+	bookLevels  = flag.Int("book-levels", 5, "Synthetic book levels per side")
+	validate    = flag.String("validate", "warn", "Check data before running: off, warn (print issues), strict (refuse to run)")
+	verbose     = flag.Bool("verbose", false, "Verbose output")
 
 	// Strategy-specific flags
 	maPeriod       = flag.Int("ma-period", 10, "Moving average period")
@@ -52,7 +62,20 @@ func main() {
 		InitialBalance: decimal.NewFromFloat(*balance),
 		MakerFeeBps:    decimal.NewFromFloat(*makerFee),
 		TakerFeeBps:    decimal.NewFromFloat(*takerFee),
+		MakerRebateBps: decimal.NewFromFloat(*makerRebate),
+		WarmupTicks:    *warmup,
+		BookSpread:     decimal.NewFromFloat(*bookSpread),
+		BookDepth:      decimal.NewFromFloat(*bookDepth),
+		BookLevels:     *bookLevels,
 	}
+	if *realistic {
+		config.Mode = paper.ModeRealistic
+	}
+	validation, err := backtest.ParseValidationMode(*validate)
+	if err != nil {
+		log.Fatalf("Invalid -validate: %v", err)
+	}
+	config.Validation = validation
 	bt := backtest.New(config)
 
 	// Load data
@@ -81,6 +104,10 @@ func main() {
 		log.Fatalf("Backtest failed: %v", err)
 	}
 
+	for _, issue := range result.DataIssues {
+		log.Printf("Data issue: %s", issue)
+	}
+
 	// Print results
 	printResults(result)
 
@@ -136,6 +163,8 @@ func printResults(result *backtest.Result) {
 	fmt.Printf("  Final Balance:   $%.2f\n", result.FinalBalance.InexactFloat64())
 	fmt.Printf("  Total PnL:       $%.2f\n", result.TotalPnL.InexactFloat64())
 	fmt.Printf("  Total Return:    %.2f%%\n", result.TotalReturn.InexactFloat64())
+	fmt.Printf("  Buy & Hold:      %.2f%%\n", result.BenchmarkReturn.InexactFloat64())
+	fmt.Printf("  Alpha:           %+.2f pts\n", result.Alpha.InexactFloat64())
 	fmt.Println()
 	fmt.Printf("  Total Trades:    %d\n", result.TotalTrades)
 	fmt.Printf("  Winning Trades:  %d\n", result.WinningTrades)
@@ -146,6 +175,7 @@ func printResults(result *backtest.Result) {
 	fmt.Printf("  Sharpe Ratio:    %.2f\n", result.SharpeRatio.InexactFloat64())
 	fmt.Printf("  Total Volume:    $%.2f\n", result.TotalVolume.InexactFloat64())
 	fmt.Printf("  Total Fees:      $%.2f\n", result.TotalFees.InexactFloat64())
+	fmt.Printf("  Impl. Shortfall: $%.2f\n", result.ImplementationShortfall.InexactFloat64())
 	fmt.Println()
 	fmt.Println("===========================================================")
 
@@ -165,8 +195,40 @@ func printResults(result *backtest.Result) {
 	}
 }
 
+// printComparison prints one row per strategy in the order given.
+func printComparison(results []backtest.Result) {
+	fmt.Printf("%-20s | %8s | %8s | %10s | %8s | %8s | %6s | %6s\n",
+		"Strategy", "Sharpe", "Calmar", "PnL", "Return", "Alpha", "MaxDD", "Trades")
+	fmt.Println(strings.Repeat("-", 95))
+	for _, r := range results {
+		fmt.Printf("%-20s | %8.2f | %8.2f | $%9.2f | %7.2f%% | %+8.2f | %5.2f%% | %6d\n",
+			r.Strategy,
+			r.SharpeRatio.InexactFloat64(),
+			r.CalmarRatio.InexactFloat64(),
+			r.TotalPnL.InexactFloat64(),
+			r.TotalReturn.InexactFloat64(),
+			r.Alpha.InexactFloat64(),
+			r.MaxDrawdown.Mul(decimal.NewFromInt(100)).InexactFloat64(),
+			r.TotalTrades)
+	}
+}
+
 func exportResults(result *backtest.Result, filename string) error {
-	if strings.HasSuffix(filename, ".json") {
+	switch *format {
+	case "json":
+		return exportJSON(result, filename)
+	case "csv":
+		return exportCSV(result, filename)
+	case "ndjson":
+		return exportNDJSON(result, filename)
+	case "":
+	default:
+		return fmt.Errorf("unknown format: %s (expected json, csv, or ndjson)", *format)
+	}
+
+	if strings.HasSuffix(filename, ".ndjson") {
+		return exportNDJSON(result, filename)
+	} else if strings.HasSuffix(filename, ".json") {
 		return exportJSON(result, filename)
 	} else if strings.HasSuffix(filename, ".csv") {
 		return exportCSV(result, filename)
@@ -183,6 +245,57 @@ func exportJSON(result *backtest.Result, filename string) error {
 	return os.WriteFile(filename, data, 0644)
 }
 
+// ndjsonRecord is one line of NDJSON output. Exactly one of Summary, Trade,
+// Equity, or Annotation is set, matching Type.
+type ndjsonRecord struct {
+	Type       string                `json:"type"` // summary, trade, equity, annotation
+	Summary    *backtest.Result      `json:"summary,omitempty"`
+	Trade      *backtest.TradeRecord `json:"trade,omitempty"`
+	Equity     *backtest.EquityPoint `json:"equity,omitempty"`
+	Annotation *backtest.Annotation  `json:"annotation,omitempty"`
+}
+
+// exportNDJSON writes the summary as the first line, followed by one line per
+// trade, equity point, and annotation.
+func exportNDJSON(result *backtest.Result, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	enc := json.NewEncoder(w)
+
+	summary := *result
+	summary.Trades = nil
+	summary.EquityCurve = nil
+	summary.Annotations = nil
+	if err := enc.Encode(ndjsonRecord{Type: "summary", Summary: &summary}); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+
+	for i := range result.Trades {
+		if err := enc.Encode(ndjsonRecord{Type: "trade", Trade: &result.Trades[i]}); err != nil {
+			return fmt.Errorf("failed to write trade: %w", err)
+		}
+	}
+
+	for i := range result.EquityCurve {
+		if err := enc.Encode(ndjsonRecord{Type: "equity", Equity: &result.EquityCurve[i]}); err != nil {
+			return fmt.Errorf("failed to write equity point: %w", err)
+		}
+	}
+
+	for i := range result.Annotations {
+		if err := enc.Encode(ndjsonRecord{Type: "annotation", Annotation: &result.Annotations[i]}); err != nil {
+			return fmt.Errorf("failed to write annotation: %w", err)
+		}
+	}
+
+	return w.Flush()
+}
+
 func exportCSV(result *backtest.Result, filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
@@ -201,6 +314,8 @@ func exportCSV(result *backtest.Result, filename string) error {
 	w.Write([]string{"final_balance", result.FinalBalance.String()})
 	w.Write([]string{"total_pnl", result.TotalPnL.String()})
 	w.Write([]string{"total_return_pct", result.TotalReturn.String()})
+	w.Write([]string{"benchmark_return_pct", result.BenchmarkReturn.String()})
+	w.Write([]string{"alpha_pct", result.Alpha.String()})
 	w.Write([]string{"total_trades", fmt.Sprintf("%d", result.TotalTrades)})
 	w.Write([]string{"winning_trades", fmt.Sprintf("%d", result.WinningTrades)})
 	w.Write([]string{"losing_trades", fmt.Sprintf("%d", result.LosingTrades)})
@@ -227,6 +342,15 @@ func exportCSV(result *backtest.Result, filename string) error {
 		}
 	}
 
+	// Write annotations
+	if len(result.Annotations) > 0 {
+		w.Write([]string{})
+		w.Write([]string{"timestamp", "token_id", "message"})
+		for _, note := range result.Annotations {
+			w.Write([]string{note.Timestamp.Format(time.RFC3339), note.TokenID, note.Message})
+		}
+	}
+
 	return nil
 }
 
@@ -279,44 +403,29 @@ func runDemo() {
 	}
 
 	// Run each strategy
-	strategies := []struct {
-		name  string
-		strat backtest.Strategy
-	}{
-		{"Momentum (MA=10)", backtest.NewMomentumStrategy(10, 100.0, 2.0)},
-		{"Mean Reversion", backtest.NewMeanReversionStrategy(10, 100.0, 5.0, 3.0)},
-		{"Buy & Hold", backtest.NewBuyAndHoldStrategy(500.0)},
-		{"LLM Forecaster", backtest.NewForecasterStrategy(&backtest.ForecasterStrategyConfig{
+	strategies := []backtest.NamedStrategy{
+		{Name: "Momentum (MA=10)", Strategy: backtest.NewMomentumStrategy(10, 100.0, 2.0)},
+		{Name: "Mean Reversion", Strategy: backtest.NewMeanReversionStrategy(10, 100.0, 5.0, 3.0)},
+		{Name: "Buy & Hold", Strategy: backtest.NewBuyAndHoldStrategy(500.0)},
+		{Name: "LLM Forecaster", Strategy: backtest.NewForecasterStrategy(&backtest.ForecasterStrategyConfig{
 			PositionSize:    100.0,
 			MinEdgeBps:      500,
 			MinConfidence:   0.6,
 			ForecastEveryN:  5,
 			MaxPositionSize: 1000,
 		})},
-		{"Edge (EMA)", backtest.NewEdgeStrategy(100.0, 300, 100, 10, true)},
+		{Name: "Edge (EMA)", Strategy: backtest.NewEdgeStrategy(100.0, 300, 100, 10, true)},
 	}
 
 	fmt.Println("Running strategies on synthetic data (30 days, price 0.50 -> 0.75)")
 	fmt.Println()
 
-	for _, s := range strategies {
-		bt := backtest.New(backtest.DefaultConfig())
-		bt.LoadData(data)
-
-		result, err := bt.Run(context.Background(), s.strat)
-		if err != nil {
-			log.Printf("Strategy %s failed: %v", s.name, err)
-			continue
-		}
-
-		fmt.Printf("%-20s | PnL: $%8.2f | Return: %6.2f%% | Trades: %3d | MaxDD: %5.2f%%\n",
-			s.name,
-			result.TotalPnL.InexactFloat64(),
-			result.TotalReturn.InexactFloat64(),
-			result.TotalTrades,
-			result.MaxDrawdown.Mul(decimal.NewFromInt(100)).InexactFloat64())
+	results, err := backtest.CompareStrategies(context.Background(), data, nil, strategies)
+	if err != nil {
+		log.Fatalf("Comparison failed: %v", err)
 	}
-
+	backtest.RankResults(results, backtest.RankBySharpe)
+	printComparison(results)
 	fmt.Println()
 	fmt.Println("To run with real data, use:")
 	fmt.Println("  polymarket-backtest -data prices.json -strategy momentum")
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/backtest"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestExportNDJSONRoundTrip(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := &backtest.Result{
+		StartTime:      start,
+		EndTime:        start.Add(2 * time.Hour),
+		InitialBalance: decimal.NewFromInt(10000),
+		FinalBalance:   decimal.NewFromInt(10050),
+		TotalPnL:       decimal.NewFromInt(50),
+		TotalTrades:    2,
+		WinningTrades:  1,
+		Trades: []backtest.TradeRecord{
+			{Timestamp: start, TokenID: "tok", Side: "BUY", Price: decimal.NewFromFloat(0.4), Size: decimal.NewFromInt(100)},
+			{Timestamp: start.Add(time.Hour), TokenID: "tok", Side: "SELL", Price: decimal.NewFromFloat(0.9), Size: decimal.NewFromInt(100), PnL: decimal.NewFromInt(50)},
+		},
+		EquityCurve: []backtest.EquityPoint{
+			{Timestamp: start, Equity: decimal.NewFromInt(10000)},
+			{Timestamp: start.Add(time.Hour), Equity: decimal.NewFromInt(10050)},
+			{Timestamp: start.Add(2 * time.Hour), Equity: decimal.NewFromInt(10050)},
+		},
+	}
+
+	filename := filepath.Join(t.TempDir(), "result.ndjson")
+	if err := exportNDJSON(result, filename); err != nil {
+		t.Fatalf("exportNDJSON failed: %v", err)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	defer file.Close()
+
+	var got backtest.Result
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var rec ndjsonRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", lines+1, err)
+		}
+		if lines == 0 && rec.Type != "summary" {
+			t.Fatalf("Expected summary first, got %q", rec.Type)
+		}
+		switch rec.Type {
+		case "summary":
+			got = *rec.Summary
+		case "trade":
+			got.Trades = append(got.Trades, *rec.Trade)
+		case "equity":
+			got.EquityCurve = append(got.EquityCurve, *rec.Equity)
+		default:
+			t.Fatalf("Unexpected record type %q", rec.Type)
+		}
+		lines++
+	}
+
+	if lines != 1+len(result.Trades)+len(result.EquityCurve) {
+		t.Errorf("Expected %d lines, got %d", 1+len(result.Trades)+len(result.EquityCurve), lines)
+	}
+	if !got.FinalBalance.Equal(result.FinalBalance) || got.TotalTrades != result.TotalTrades {
+		t.Errorf("Summary mismatch: %+v", got)
+	}
+	if len(got.Trades) != 2 || !got.Trades[1].PnL.Equal(decimal.NewFromInt(50)) || got.Trades[1].Side != "SELL" {
+		t.Errorf("Trades mismatch: %+v", got.Trades)
+	}
+	if len(got.EquityCurve) != 3 || !got.EquityCurve[1].Equity.Equal(decimal.NewFromInt(10050)) {
+		t.Errorf("Equity curve mismatch: %+v", got.EquityCurve)
+	}
+	if !got.EquityCurve[2].Timestamp.Equal(result.EquityCurve[2].Timestamp) {
+		t.Errorf("Timestamp mismatch: %v", got.EquityCurve[2].Timestamp)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/phenomenon0/polymarket-agents/core"
+	"github.com/phenomenon0/polymarket-agents/tools/polymarket"
+)
+
+// approvalHandler is the human approval path for orders the agent prepared
+// under --require-commit: POST /commit with {"prepared_id": "..."} posts the
+// order. It is served on its own listener, never over the MCP channel, so
+// the agent that prepares an order can't also commit it, and every request
+// must carry "Authorization: Bearer <token>" so nothing else on the host can
+// either.
+func approvalHandler(commit *polymarket.CommitOrderTool, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /commit", func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="approvals"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var input polymarket.CommitOrderInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		raw, _ := json.Marshal(input)
+
+		result := commit.Execute(&core.ToolContext{
+			Ctx: r.Context(),
+			Request: &core.Message{
+				ToolReq: &core.ToolRequestPayload{
+					Name:     commit.Name(),
+					InputRaw: raw,
+				},
+			},
+		})
+		if result.Status != core.ToolComplete {
+			http.Error(w, result.Error, http.StatusUnprocessableEntity)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result.Output)
+	})
+	return mux
+}
@@ -0,0 +1,129 @@
+// mcp-server exposes the Polymarket and LLM tools to MCP clients (e.g. Claude
+// Desktop) over stdio using JSON-RPC 2.0.
+//
+// Read-only tools are always served. Authenticated tools need a private key,
+// and trading tools additionally need --allow-trading. With --require-commit
+// the agent can only prepare orders; a human posts them through the approval
+// endpoint on --approve-addr, authenticated with --approve-token.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/phenomenon0/polymarket-agents/core"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/clob"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/gamma"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/agents"
+	"github.com/phenomenon0/polymarket-agents/tools"
+	"github.com/phenomenon0/polymarket-agents/tools/polymarket"
+)
+
+var (
+	privateKey    = flag.String("key", "", "Private key for authenticated tools (or POLYMARKET_PRIVATE_KEY env)")
+	allowTrading  = flag.Bool("allow-trading", false, "Expose tools that place and cancel orders")
+	requireCommit = flag.Bool("require-commit", false, "Only prepare orders; post them via POST /commit on -approve-addr")
+	approveAddr   = flag.String("approve-addr", "127.0.0.1:8790", "Listen address for the order approval endpoint (with -require-commit)")
+	approveToken  = flag.String("approve-token", "", "Bearer token required by the approval endpoint (or POLYMARKET_APPROVE_TOKEN env)")
+	llmTier       = flag.String("llm-tier", string(tools.TierBalanced), "Model router tier for the llm tool (empty to disable)")
+	forecast      = flag.String("forecast-preset", "", "Forecaster preset for polymarket_forecast_markets: elite, balanced, cheap, local, fast (empty to disable)")
+	forecastCap   = flag.Float64("forecast-budget", 1.0, "Max LLM spend in USD per polymarket_forecast_markets call (0 for no limit)")
+)
+
+func main() {
+	flag.Parse()
+
+	// stdout carries the protocol, so logs go to stderr
+	log.SetOutput(os.Stderr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	registry := core.NewToolRegistry()
+	gammaClient := gamma.NewClient()
+	polymarket.RegisterGammaTools(registry, gammaClient)
+
+	key := *privateKey
+	if key == "" {
+		key = os.Getenv("POLYMARKET_PRIVATE_KEY")
+	}
+
+	if key != "" {
+		client, err := clob.NewClient(key, clob.WithClockSkewCorrection())
+		if err != nil {
+			log.Fatalf("Failed to create CLOB client: %v", err)
+		}
+
+		credCtx, credCancel := context.WithTimeout(ctx, 30*time.Second)
+		_, err = client.CreateOrDeriveAPIKey(credCtx)
+		credCancel()
+		if err != nil {
+			log.Fatalf("Failed to derive API credentials: %v", err)
+		}
+
+		polymarket.RegisterAllCLOBTools(registry, client, *requireCommit)
+		polymarket.RegisterResolveTokensTool(registry, client, gammaClient)
+		log.Printf("CLOB tools registered (address: %s, trading: %v)", client.Address(), *allowTrading)
+
+		if *allowTrading && *requireCommit {
+			token := *approveToken
+			if token == "" {
+				token = os.Getenv("POLYMARKET_APPROVE_TOKEN")
+			}
+			if token == "" {
+				log.Fatal("-require-commit needs -approve-token (or POLYMARKET_APPROVE_TOKEN) to guard the approval endpoint")
+			}
+			approvals := &http.Server{Addr: *approveAddr, Handler: approvalHandler(polymarket.NewCommitOrderTool(client), token)}
+			go func() {
+				if err := approvals.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					log.Printf("Approval endpoint error: %v", err)
+				}
+			}()
+			defer approvals.Close()
+			log.Printf("Order approvals on http://%s/commit", *approveAddr)
+		}
+	} else {
+		public := clob.NewPublicClient()
+		polymarket.RegisterCLOBReadOnlyTools(registry, public)
+		polymarket.RegisterResolveTokensTool(registry, public, gammaClient)
+		log.Println("No private key provided - serving read-only CLOB tools")
+	}
+
+	if *llmTier != "" {
+		cfg, err := tools.NewModelRouter().GetConfig(tools.ModelTier(*llmTier), 0)
+		if err != nil {
+			log.Printf("Warning: llm tool disabled: %v", err)
+		} else {
+			registry.Register(tools.NewLLMTool(cfg), core.ToolPolicy{DefaultTimeout: cfg.Timeout}, nil)
+		}
+	}
+
+	if *forecast != "" {
+		forecaster, err := agents.CreateForecasterWithPreset(tools.NewModelRouter(), agents.ForecasterPreset(*forecast))
+		if err != nil {
+			log.Printf("Warning: forecast tool disabled: %v", err)
+		} else {
+			polymarket.RegisterForecastMarketsTool(registry, gammaClient, forecaster, polymarket.WithForecastBudget(*forecastCap))
+		}
+	}
+
+	srv := newServer(registry, *allowTrading)
+	log.Printf("MCP server ready with %d tools", len(srv.order))
+
+	if err := srv.serve(ctx, os.Stdin, os.Stdout); err != nil && err != context.Canceled {
+		log.Fatalf("Server error: %v", err)
+	}
+}
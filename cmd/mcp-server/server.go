@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/phenomenon0/polymarket-agents/core"
+	"github.com/phenomenon0/polymarket-agents/tools/polymarket"
+)
+
+// protocolVersion is the MCP revision this server speaks.
+const protocolVersion = "2024-11-05"
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// mcpTool is the subset of a registered tool the server needs.
+type mcpTool interface {
+	Name() string
+	InputSchema() []byte
+	Execute(*core.ToolContext) *core.ToolExecResult
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type toolDefinition struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+type callParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type callResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError"`
+}
+
+// server exposes registry tools over MCP (JSON-RPC 2.0, one message per line).
+type server struct {
+	tools map[string]mcpTool
+	order []string
+}
+
+// newServer collects the servable tools from a registry. Tools in the trading
+// risk classes are left out unless allowTrading is set.
+func newServer(registry *core.ToolRegistry, allowTrading bool) *server {
+	s := &server{tools: make(map[string]mcpTool)}
+
+	registry.Each(func(tool interface{}, _ core.ToolPolicy, riskClass interface{}) {
+		t, ok := tool.(mcpTool)
+		if !ok {
+			return
+		}
+		if !allowTrading && (riskClass == polymarket.RiskClassTrading || riskClass == polymarket.RiskClassHighRisk) {
+			return
+		}
+		if _, exists := s.tools[t.Name()]; exists {
+			return
+		}
+		s.tools[t.Name()] = t
+		s.order = append(s.order, t.Name())
+	})
+
+	return s
+}
+
+// serve reads requests from r and writes responses to w until r is exhausted
+// or ctx is canceled. Requests are handled concurrently.
+func (s *server) serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	write := func(resp *rpcResponse) {
+		mu.Lock()
+		defer mu.Unlock()
+		enc.Encode(resp)
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			write(&rpcResponse{
+				JSONRPC: "2.0",
+				ID:      json.RawMessage("null"),
+				Error:   &rpcError{Code: codeParseError, Message: err.Error()},
+			})
+			continue
+		}
+
+		wg.Add(1)
+		go func(req rpcRequest) {
+			defer wg.Done()
+			if resp := s.handle(ctx, &req); resp != nil {
+				write(resp)
+			}
+		}(req)
+	}
+
+	return scanner.Err()
+}
+
+// handle dispatches one request. Notifications (no ID) get no response.
+func (s *server) handle(ctx context.Context, req *rpcRequest) *rpcResponse {
+	var result interface{}
+	var rpcErr *rpcError
+
+	switch req.Method {
+	case "initialize":
+		result = map[string]interface{}{
+			"protocolVersion": protocolVersion,
+			"capabilities": map[string]interface{}{
+				"tools": map[string]interface{}{},
+			},
+			"serverInfo": map[string]string{
+				"name":    "polymarket-agents",
+				"version": "0.1.0",
+			},
+		}
+	case "ping":
+		result = map[string]interface{}{}
+	case "tools/list":
+		result = map[string]interface{}{"tools": s.listTools()}
+	case "tools/call":
+		result, rpcErr = s.callTool(ctx, req.Params)
+	default:
+		if len(req.ID) == 0 {
+			return nil // Unknown notification, e.g. notifications/initialized
+		}
+		rpcErr = &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+
+	if len(req.ID) == 0 {
+		return nil
+	}
+	if req.JSONRPC != "2.0" {
+		rpcErr = &rpcError{Code: codeInvalidRequest, Message: "jsonrpc must be 2.0"}
+		result = nil
+	}
+
+	return &rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr}
+}
+
+func (s *server) listTools() []toolDefinition {
+	defs := make([]toolDefinition, 0, len(s.order))
+	for _, name := range s.order {
+		t := s.tools[name]
+
+		description := name
+		if d, ok := t.(interface{ Description() string }); ok {
+			description = d.Description()
+		}
+
+		defs = append(defs, toolDefinition{
+			Name:        name,
+			Description: description,
+			InputSchema: json.RawMessage(t.InputSchema()),
+		})
+	}
+	return defs
+}
+
+func (s *server) callTool(ctx context.Context, raw json.RawMessage) (interface{}, *rpcError) {
+	var params callParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	t, ok := s.tools[params.Name]
+	if !ok {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool: %s", params.Name)}
+	}
+
+	var input map[string]any
+	if len(params.Arguments) > 0 {
+		if err := json.Unmarshal(params.Arguments, &input); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+	}
+
+	result := t.Execute(&core.ToolContext{
+		Ctx: ctx,
+		Request: &core.Message{
+			ToolReq: &core.ToolRequestPayload{
+				Name:     params.Name,
+				Input:    input,
+				InputRaw: params.Arguments,
+			},
+		},
+	})
+
+	if result.Status != core.ToolComplete {
+		return &callResult{
+			Content: []textContent{{Type: "text", Text: result.Error}},
+			IsError: true,
+		}, nil
+	}
+
+	text, err := json.Marshal(result.Output)
+	if err != nil {
+		return &callResult{
+			Content: []textContent{{Type: "text", Text: fmt.Sprintf("encode output: %v", err)}},
+			IsError: true,
+		}, nil
+	}
+
+	return &callResult{Content: []textContent{{Type: "text", Text: string(text)}}}, nil
+}
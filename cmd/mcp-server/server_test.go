@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phenomenon0/polymarket-agents/core"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/clob"
+	"github.com/phenomenon0/polymarket-agents/tools/polymarket"
+)
+
+func newTestCLOB(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/book" {
+			t.Errorf("Expected path /book, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(clob.OrderBookSummary{
+			TokenID: r.URL.Query().Get("token_id"),
+			Bids:    []clob.PriceLevel{{Price: "0.48", Size: "100"}},
+			Asks:    []clob.PriceLevel{{Price: "0.52", Size: "100"}},
+		})
+	}))
+}
+
+func runRequests(t *testing.T, srv *server, lines ...string) map[string]rpcResponse {
+	var out bytes.Buffer
+	in := strings.NewReader(strings.Join(lines, "\n") + "\n")
+	if err := srv.serve(context.Background(), in, &out); err != nil {
+		t.Fatalf("serve failed: %v", err)
+	}
+
+	responses := make(map[string]rpcResponse)
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var resp rpcResponse
+		if err := dec.Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		responses[string(resp.ID)] = resp
+	}
+	return responses
+}
+
+func TestCallGetOrderBook(t *testing.T) {
+	clobServer := newTestCLOB(t)
+	defer clobServer.Close()
+
+	registry := core.NewToolRegistry()
+	polymarket.RegisterCLOBReadOnlyTools(registry, clob.NewPublicClient(clob.WithCLOBBaseURL(clobServer.URL)))
+	srv := newServer(registry, false)
+
+	responses := runRequests(t, srv,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"polymarket_get_orderbook","arguments":{"token_id":"token123"}}}`,
+	)
+
+	if len(responses) != 2 {
+		t.Fatalf("Expected 2 responses (notification gets none), got %d", len(responses))
+	}
+
+	resp := responses["2"]
+	if resp.Error != nil {
+		t.Fatalf("tools/call failed: %s", resp.Error.Message)
+	}
+
+	data, _ := json.Marshal(resp.Result)
+	var result callResult
+	json.Unmarshal(data, &result)
+	if result.IsError || len(result.Content) != 1 {
+		t.Fatalf("Unexpected call result: %+v", result)
+	}
+
+	var output polymarket.GetOrderBookOutput
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &output); err != nil {
+		t.Fatalf("decode tool output: %v", err)
+	}
+	if output.TokenID != "token123" {
+		t.Errorf("Wrong token ID: %s", output.TokenID)
+	}
+	if output.Midpoint != "0.5" {
+		t.Errorf("Expected midpoint 0.5, got %s", output.Midpoint)
+	}
+}
+
+func TestGetOrderBookStaleness(t *testing.T) {
+	booked := time.Now().Add(-2 * time.Minute)
+	clobServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(clob.OrderBookSummary{
+			TokenID:   r.URL.Query().Get("token_id"),
+			Timestamp: strconv.FormatInt(booked.UnixMilli(), 10),
+			Bids:      []clob.PriceLevel{{Price: "0.48", Size: "100"}},
+			Asks:      []clob.PriceLevel{{Price: "0.52", Size: "100"}},
+		})
+	}))
+	defer clobServer.Close()
+
+	registry := core.NewToolRegistry()
+	polymarket.RegisterCLOBReadOnlyTools(registry, clob.NewPublicClient(clob.WithCLOBBaseURL(clobServer.URL)))
+	srv := newServer(registry, false)
+
+	responses := runRequests(t, srv,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"polymarket_get_orderbook","arguments":{"token_id":"token123"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"polymarket_get_orderbook","arguments":{"token_id":"token123","stale_after_seconds":600}}}`,
+	)
+
+	for id, wantStale := range map[string]bool{"1": true, "2": false} {
+		data, _ := json.Marshal(responses[id].Result)
+		var result callResult
+		json.Unmarshal(data, &result)
+		if result.IsError || len(result.Content) != 1 {
+			t.Fatalf("Unexpected call result: %+v", result)
+		}
+
+		var output polymarket.GetOrderBookOutput
+		if err := json.Unmarshal([]byte(result.Content[0].Text), &output); err != nil {
+			t.Fatalf("decode tool output: %v", err)
+		}
+		if output.AgeSeconds == nil || *output.AgeSeconds < 119 || *output.AgeSeconds > 180 {
+			t.Errorf("Expected age of about 120s, got %v", output.AgeSeconds)
+		}
+		if output.Timestamp == "" {
+			t.Error("Expected venue timestamp in output")
+		}
+		if output.Stale != wantStale {
+			t.Errorf("Request %s: expected stale=%v, got %v", id, wantStale, output.Stale)
+		}
+	}
+}
+
+func TestTradingToolsGated(t *testing.T) {
+	client := clob.NewPublicClient()
+
+	registry := core.NewToolRegistry()
+	polymarket.RegisterAllCLOBTools(registry, client, false)
+
+	for _, allow := range []bool{false, true} {
+		srv := newServer(registry, allow)
+		_, exposed := srv.tools["polymarket_place_order"]
+		if exposed != allow {
+			t.Errorf("allowTrading=%v: place_order exposed=%v", allow, exposed)
+		}
+		if _, ok := srv.tools["polymarket_get_orderbook"]; !ok {
+			t.Errorf("allowTrading=%v: read-only tool missing", allow)
+		}
+	}
+
+	responses := runRequests(t, newServer(registry, false),
+		`{"jsonrpc":"2.0","id":"a","method":"tools/call","params":{"name":"polymarket_place_order","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":"b","method":"bogus"}`,
+	)
+	if resp := responses[`"a"`]; resp.Error == nil || resp.Error.Code != codeInvalidParams {
+		t.Errorf("Expected invalid params for gated tool, got %+v", resp)
+	}
+	if resp := responses[`"b"`]; resp.Error == nil || resp.Error.Code != codeMethodNotFound {
+		t.Errorf("Expected method not found, got %+v", resp)
+	}
+}
+
+func TestCommitKeptOffAgentRegistry(t *testing.T) {
+	registry := core.NewToolRegistry()
+	polymarket.RegisterAllCLOBTools(registry, clob.NewPublicClient(), true)
+
+	srv := newServer(registry, true)
+	if _, exposed := srv.tools["polymarket_commit_order"]; exposed {
+		t.Error("Expected polymarket_commit_order to stay off the agent's registry")
+	}
+	if _, exposed := srv.tools["polymarket_place_order"]; !exposed {
+		t.Error("Expected polymarket_place_order to be served")
+	}
+
+	handler := approvalHandler(polymarket.NewCommitOrderTool(clob.NewPublicClient()), "secret")
+	tests := []struct {
+		method, auth, body string
+		want               int
+	}{
+		{http.MethodGet, "Bearer secret", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "", `{"prepared_id":"missing"}`, http.StatusUnauthorized},
+		{http.MethodPost, "Bearer wrong", `{"prepared_id":"missing"}`, http.StatusUnauthorized},
+		{http.MethodPost, "Bearer secret", "{", http.StatusBadRequest},
+		{http.MethodPost, "Bearer secret", `{"prepared_id":"missing"}`, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/commit", strings.NewReader(tt.body))
+		if tt.auth != "" {
+			req.Header.Set("Authorization", tt.auth)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s %q with %q: expected status %d, got %d", tt.method, tt.body, tt.auth, tt.want, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// polymarket-auth derives (or creates) L2 API credentials for a wallet and
+// saves them for the other commands, so the L1 signing round trip happens
+// once rather than at every startup.
+//
+// With -out the credentials are written to a JSON file that agentd loads with
+// -creds; otherwise they are printed as shell export lines.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/clob"
+)
+
+var (
+	privateKey = flag.String("key", "", "Private key (or POLYMARKET_PRIVATE_KEY env)")
+	outFile    = flag.String("out", "", "Write credentials to this JSON file (default: print export lines)")
+	deriveOnly = flag.Bool("derive-only", false, "Only derive existing credentials; never create new ones")
+	timeout    = flag.Duration("timeout", 30*time.Second, "Request timeout")
+)
+
+// authConfig is what run needs from the flags.
+type authConfig struct {
+	Key        string
+	Out        string
+	DeriveOnly bool
+	ClientOpts []clob.ClientOption
+}
+
+func main() {
+	flag.Parse()
+
+	key := *privateKey
+	if key == "" {
+		key = os.Getenv("POLYMARKET_PRIVATE_KEY")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	cfg := authConfig{
+		Key:        key,
+		Out:        *outFile,
+		DeriveOnly: *deriveOnly,
+		ClientOpts: []clob.ClientOption{clob.WithClockSkewCorrection()},
+	}
+	if err := run(ctx, cfg, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run fetches credentials and writes them to cfg.Out, or as export lines to
+// stdout if cfg.Out is empty.
+func run(ctx context.Context, cfg authConfig, stdout io.Writer) error {
+	if cfg.Key == "" {
+		return errors.New("private key required: pass -key or set POLYMARKET_PRIVATE_KEY")
+	}
+
+	client, err := clob.NewClient(cfg.Key, cfg.ClientOpts...)
+	if err != nil {
+		return fmt.Errorf("create CLOB client: %w", err)
+	}
+
+	var creds *clob.APICredentials
+	if cfg.DeriveOnly {
+		creds, err = client.DeriveAPIKey(ctx, 0)
+	} else {
+		creds, err = client.CreateOrDeriveAPIKey(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("get API credentials for %s: %w", client.Address(), err)
+	}
+
+	if cfg.Out == "" {
+		fmt.Fprintf(stdout, "export %s=%s\n", clob.EnvAPIKey, creds.APIKey)
+		fmt.Fprintf(stdout, "export %s=%s\n", clob.EnvAPISecret, creds.Secret)
+		fmt.Fprintf(stdout, "export %s=%s\n", clob.EnvAPIPassphrase, creds.Passphrase)
+		return nil
+	}
+
+	if err := clob.SaveCredentials(cfg.Out, creds); err != nil {
+		return err
+	}
+	log.Printf("Credentials for %s written to %s", client.Address(), cfg.Out)
+	return nil
+}
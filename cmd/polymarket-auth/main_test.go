@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/clob"
+)
+
+const testPrivateKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+// mockAuthServer has no credentials to derive, so they must be created.
+func mockAuthServer(t *testing.T, created *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("POLY_SIGNATURE") == "" {
+			t.Errorf("%s %s missing L1 auth headers", r.Method, r.URL.Path)
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/auth/derive-api-key":
+			http.Error(w, `{"error":"no api key found"}`, http.StatusNotFound)
+		case r.Method == http.MethodPost && r.URL.Path == "/auth/api-key":
+			*created++
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(clob.APICredentials{APIKey: "key-1", Secret: "c2VjcmV0", Passphrase: "pass-1"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestRunWritesCredentials(t *testing.T) {
+	created := 0
+	server := mockAuthServer(t, &created)
+	defer server.Close()
+
+	out := filepath.Join(t.TempDir(), "creds.json")
+	cfg := authConfig{Key: testPrivateKey, Out: out, ClientOpts: []clob.ClientOption{clob.WithCLOBBaseURL(server.URL)}}
+	if err := run(context.Background(), cfg, &bytes.Buffer{}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if created != 1 {
+		t.Errorf("Expected credentials created once after derive failed, got %d", created)
+	}
+
+	creds, err := clob.LoadCredentials(out)
+	if err != nil {
+		t.Fatalf("LoadCredentials failed: %v", err)
+	}
+	if creds.APIKey != "key-1" || creds.Secret != "c2VjcmV0" || creds.Passphrase != "pass-1" {
+		t.Errorf("Unexpected credentials %+v", creds)
+	}
+	if info, _ := os.Stat(out); info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestRunPrintsExports(t *testing.T) {
+	created := 0
+	server := mockAuthServer(t, &created)
+	defer server.Close()
+
+	var stdout bytes.Buffer
+	cfg := authConfig{Key: testPrivateKey, ClientOpts: []clob.ClientOption{clob.WithCLOBBaseURL(server.URL)}}
+	if err := run(context.Background(), cfg, &stdout); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "export POLYMARKET_API_KEY=key-1\n") {
+		t.Errorf("Expected export lines, got:\n%s", stdout.String())
+	}
+}
+
+func TestRunDeriveOnlyNeverCreates(t *testing.T) {
+	created := 0
+	server := mockAuthServer(t, &created)
+	defer server.Close()
+
+	out := filepath.Join(t.TempDir(), "creds.json")
+	cfg := authConfig{Key: testPrivateKey, Out: out, DeriveOnly: true, ClientOpts: []clob.ClientOption{clob.WithCLOBBaseURL(server.URL)}}
+	if err := run(context.Background(), cfg, &bytes.Buffer{}); err == nil {
+		t.Fatal("Expected an error with nothing to derive")
+	}
+	if created != 0 {
+		t.Errorf("derive-only created credentials %d times", created)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Error("No credentials file should be written on failure")
+	}
+}
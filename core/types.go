@@ -88,3 +88,10 @@ func (r *ToolRegistry) Register(tool interface{}, policy ToolPolicy, riskClass i
 		riskClass: riskClass,
 	})
 }
+
+// Each calls fn for every registered tool, in registration order.
+func (r *ToolRegistry) Each(fn func(tool interface{}, policy ToolPolicy, riskClass interface{})) {
+	for _, rt := range r.tools {
+		fn(rt.tool, rt.policy, rt.riskClass)
+	}
+}
@@ -10,3 +10,18 @@ var (
 	// NegRiskCTFExchangeAddress is the Neg Risk CTF Exchange contract.
 	NegRiskCTFExchangeAddress = common.HexToAddress("0xC5d563A36AE78145C45a50134d48A1215220f80a")
 )
+
+// Conditional Tokens (CTF) contracts on Polygon, used to merge and redeem
+// outcome tokens outside the exchange.
+var (
+	// ConditionalTokensAddress is the Gnosis Conditional Tokens contract
+	// holding every outcome token.
+	ConditionalTokensAddress = common.HexToAddress("0x4D97DCd97eC945f40cF65F87097ACe5EA0476045")
+
+	// NegRiskAdapterAddress wraps the CTF for neg-risk markets; their merges
+	// and redemptions go through it.
+	NegRiskAdapterAddress = common.HexToAddress("0xd91E80cF2E7be2e162c6513ceD06f1dD0dA35296")
+
+	// USDCAddress is the bridged USDC (USDC.e) used as collateral.
+	USDCAddress = common.HexToAddress("0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174")
+)
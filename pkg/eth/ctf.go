@@ -0,0 +1,82 @@
+package eth
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ctfABI covers the Conditional Tokens and NegRiskAdapter methods used to
+// merge and redeem binary positions.
+const ctfABI = `[
+	{"name":"mergePositions","type":"function","inputs":[
+		{"name":"collateralToken","type":"address"},
+		{"name":"parentCollectionId","type":"bytes32"},
+		{"name":"conditionId","type":"bytes32"},
+		{"name":"partition","type":"uint256[]"},
+		{"name":"amount","type":"uint256"}]},
+	{"name":"redeemPositions","type":"function","inputs":[
+		{"name":"collateralToken","type":"address"},
+		{"name":"parentCollectionId","type":"bytes32"},
+		{"name":"conditionId","type":"bytes32"},
+		{"name":"indexSets","type":"uint256[]"}]}
+]`
+
+// negRiskABI is the NegRiskAdapter's overloads, which take the condition
+// directly and pay out in collateral.
+const negRiskABI = `[
+	{"name":"mergePositions","type":"function","inputs":[
+		{"name":"conditionId","type":"bytes32"},
+		{"name":"amount","type":"uint256"}]},
+	{"name":"redeemPositions","type":"function","inputs":[
+		{"name":"conditionId","type":"bytes32"},
+		{"name":"amounts","type":"uint256[]"}]}
+]`
+
+var (
+	ctfContract     = mustParseABI(ctfABI)
+	negRiskContract = mustParseABI(negRiskABI)
+
+	// binaryPartition is the YES (index set 1) and NO (index set 2) outcome
+	// slots of a binary condition.
+	binaryPartition = []*big.Int{big.NewInt(1), big.NewInt(2)}
+)
+
+func mustParseABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(fmt.Sprintf("eth: bad ABI: %v", err))
+	}
+	return parsed
+}
+
+// MergePositionsCalldata encodes a merge of amount (in 6-decimal base units)
+// of each of a binary condition's YES and NO tokens back into USDC. The call
+// goes to the contract returned by CTFTarget.
+func MergePositionsCalldata(conditionID common.Hash, amount *big.Int, negRisk bool) ([]byte, error) {
+	if negRisk {
+		return negRiskContract.Pack("mergePositions", conditionID, amount)
+	}
+	return ctfContract.Pack("mergePositions", USDCAddress, common.Hash{}, conditionID, binaryPartition, amount)
+}
+
+// RedeemPositionsCalldata encodes a redemption of a resolved binary
+// condition. The CTF redeems the whole balance of both outcomes; the
+// NegRiskAdapter needs the YES and NO amounts (6-decimal base units).
+func RedeemPositionsCalldata(conditionID common.Hash, yesAmount, noAmount *big.Int, negRisk bool) ([]byte, error) {
+	if negRisk {
+		return negRiskContract.Pack("redeemPositions", conditionID, []*big.Int{yesAmount, noAmount})
+	}
+	return ctfContract.Pack("redeemPositions", USDCAddress, common.Hash{}, conditionID, binaryPartition)
+}
+
+// CTFTarget is the contract merges and redemptions are sent to.
+func CTFTarget(negRisk bool) common.Address {
+	if negRisk {
+		return NegRiskAdapterAddress
+	}
+	return ConditionalTokensAddress
+}
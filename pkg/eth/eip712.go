@@ -11,12 +11,12 @@ import (
 
 // EIP712Signer handles EIP-712 typed data signing for Polymarket.
 type EIP712Signer struct {
-	wallet *Wallet
+	signer Signer
 }
 
 // NewEIP712Signer creates a new EIP-712 signer.
-func NewEIP712Signer(wallet *Wallet) *EIP712Signer {
-	return &EIP712Signer{wallet: wallet}
+func NewEIP712Signer(signer Signer) *EIP712Signer {
+	return &EIP712Signer{signer: signer}
 }
 
 // SignClobAuth signs an authentication message for the CLOB API (L1 auth).
@@ -28,7 +28,7 @@ func (s *EIP712Signer) SignClobAuth(chainID int64, timestamp string, nonce *big.
 	typeHash := crypto.Keccak256Hash([]byte("ClobAuth(address address,string timestamp,uint256 nonce)"))
 
 	// Encode the message
-	addrHash := crypto.Keccak256Hash(s.wallet.Address().Bytes())
+	addrHash := crypto.Keccak256Hash(s.signer.Address().Bytes())
 	tsHash := crypto.Keccak256Hash([]byte(timestamp))
 	nonceHash := common.LeftPadBytes(nonce.Bytes(), 32)
 
@@ -46,7 +46,7 @@ func (s *EIP712Signer) SignClobAuth(chainID int64, timestamp string, nonce *big.
 		msgHash.Bytes(),
 	)
 
-	sig, err := s.wallet.SignHash(finalHash.Bytes())
+	sig, err := s.signer.SignHash(finalHash.Bytes())
 	if err != nil {
 		return "", fmt.Errorf("sign auth: %w", err)
 	}
@@ -105,7 +105,7 @@ func (s *EIP712Signer) SignOrder(chainID int64, exchangeAddress common.Address,
 		msgHash.Bytes(),
 	)
 
-	sig, err := s.wallet.SignHash(finalHash.Bytes())
+	sig, err := s.signer.SignHash(finalHash.Bytes())
 	if err != nil {
 		return "", fmt.Errorf("sign order: %w", err)
 	}
@@ -10,6 +10,15 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// Signer produces Ethereum signatures over 32-byte hashes. Wallet is the
+// in-memory implementation; KMS or hardware signers can implement it to keep
+// the private key out of process memory.
+type Signer interface {
+	// SignHash returns a 65-byte [R || S || V] signature with V in {27, 28}.
+	SignHash(hash []byte) ([]byte, error)
+	Address() common.Address
+}
+
 // Wallet wraps an ECDSA private key for Ethereum signing.
 type Wallet struct {
 	privateKey *ecdsa.PrivateKey
@@ -0,0 +1,82 @@
+// Package httpx builds the HTTP transports shared by the API and LLM clients,
+// so connection pooling is tuned in one place.
+package httpx
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportConfig tunes connection pooling and HTTP/2. Zero durations and
+// limits mean no limit, as in http.Transport.
+type TransportConfig struct {
+	MaxIdleConns        int           // Idle connections kept across all hosts
+	MaxIdleConnsPerHost int           // Idle connections kept per host
+	MaxConnsPerHost     int           // Total connections per host, 0 = unlimited
+	IdleConnTimeout     time.Duration // How long an idle connection stays open
+
+	DialTimeout           time.Duration
+	KeepAlive             time.Duration // TCP keepalive interval
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration // Wait for headers after the request is written
+
+	// DisableHTTP2 restricts the transport to HTTP/1.1. HTTP/2 multiplexes
+	// many requests over one connection per host, so with it enabled the
+	// per-host limits above mostly bound HTTP/1.1 fallbacks.
+	DisableHTTP2 bool
+
+	// HTTP2PingInterval sends a health-check ping on an HTTP/2 connection
+	// idle this long, so dead connections are noticed before a request is
+	// sent on them. 0 disables pings.
+	HTTP2PingInterval time.Duration
+
+	// HTTP2PingTimeout closes the connection if a ping gets no response in
+	// time. 0 means the net/http default (15s).
+	HTTP2PingTimeout time.Duration
+}
+
+// DefaultTransportConfig is the pool every client starts from. Raise
+// MaxIdleConnsPerHost and MaxConnsPerHost when forecasting many markets in
+// parallel.
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConns:        20,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		DialTimeout:         15 * time.Second,
+		KeepAlive:           30 * time.Second,
+		TLSHandshakeTimeout: 15 * time.Second,
+	}
+}
+
+// NewTransport builds an http.Transport from config.
+func NewTransport(config TransportConfig) *http.Transport {
+	t := &http.Transport{
+		MaxIdleConns:          config.MaxIdleConns,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       config.MaxConnsPerHost,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		TLSHandshakeTimeout:   config.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: config.ResponseHeaderTimeout,
+		DialContext: (&net.Dialer{
+			Timeout:   config.DialTimeout,
+			KeepAlive: config.KeepAlive,
+		}).DialContext,
+	}
+
+	// A custom DialContext turns off automatic HTTP/2, so opt back in
+	// explicitly
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	if !config.DisableHTTP2 {
+		protocols.SetHTTP2(true)
+		t.ForceAttemptHTTP2 = true
+		t.HTTP2 = &http.HTTP2Config{
+			SendPingTimeout: config.HTTP2PingInterval,
+			PingTimeout:     config.HTTP2PingTimeout,
+		}
+	}
+	t.Protocols = protocols
+	return t
+}
@@ -0,0 +1,44 @@
+package httpx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewTransportAppliesConfig(t *testing.T) {
+	config := TransportConfig{
+		MaxIdleConns:          200,
+		MaxIdleConnsPerHost:   64,
+		MaxConnsPerHost:       100,
+		IdleConnTimeout:       2 * time.Minute,
+		TLSHandshakeTimeout:   7 * time.Second,
+		ResponseHeaderTimeout: 45 * time.Second,
+		HTTP2PingInterval:     20 * time.Second,
+		HTTP2PingTimeout:      5 * time.Second,
+	}
+	tr := NewTransport(config)
+
+	if tr.MaxIdleConns != 200 || tr.MaxIdleConnsPerHost != 64 || tr.MaxConnsPerHost != 100 {
+		t.Errorf("pool limits not applied: idle %d, idle/host %d, conns/host %d",
+			tr.MaxIdleConns, tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost)
+	}
+	if tr.IdleConnTimeout != 2*time.Minute || tr.TLSHandshakeTimeout != 7*time.Second || tr.ResponseHeaderTimeout != 45*time.Second {
+		t.Errorf("timeouts not applied: idle %s, tls %s, headers %s",
+			tr.IdleConnTimeout, tr.TLSHandshakeTimeout, tr.ResponseHeaderTimeout)
+	}
+	if tr.DialContext == nil {
+		t.Error("dialer not set")
+	}
+	if !tr.ForceAttemptHTTP2 || tr.Protocols == nil || !tr.Protocols.HTTP2() || !tr.Protocols.HTTP1() {
+		t.Errorf("expected HTTP/2 with HTTP/1.1 fallback, got %v", tr.Protocols)
+	}
+	if tr.HTTP2 == nil || tr.HTTP2.SendPingTimeout != 20*time.Second || tr.HTTP2.PingTimeout != 5*time.Second {
+		t.Errorf("HTTP/2 ping settings not applied: %+v", tr.HTTP2)
+	}
+
+	config.DisableHTTP2 = true
+	tr = NewTransport(config)
+	if tr.ForceAttemptHTTP2 || tr.Protocols.HTTP2() || !tr.Protocols.HTTP1() {
+		t.Errorf("expected HTTP/1.1 only, got %v", tr.Protocols)
+	}
+}
@@ -209,41 +209,78 @@ func (ob *OrderBook) VolumeWeightedPrice(side Side, size decimal.Decimal) (decim
 	ob.mu.RLock()
 	defer ob.mu.RUnlock()
 
-	var levels []PriceLevel
-	if side == SideBuy {
-		// Buying = take from asks
-		levels = ob.asks
-	} else {
-		// Selling = take from bids
-		levels = ob.bids
-	}
-
+	levels := ob.takerLevelsLocked(side)
 	if len(levels) == 0 {
 		return decimal.Zero, fmt.Errorf("no liquidity on %s side", side)
 	}
 
-	remaining := size
-	totalCost := decimal.Zero
+	totalCost, remaining := walkLevels(levels, size, nil)
+	if remaining.GreaterThan(decimal.Zero) {
+		return decimal.Zero, fmt.Errorf("insufficient liquidity: needed %s, missing %s", size, remaining)
+	}
 
-	for _, level := range levels {
-		if remaining.IsZero() {
+	return totalCost.Div(size), nil
+}
+
+// CostToFill returns what taking size on side would cost walking the book
+// best level first: the total paid (received, for a sell), the average
+// price, and the size the book can't fill. avgPrice is zero if nothing
+// fills. The book is not modified.
+func (ob *OrderBook) CostToFill(side Side, size decimal.Decimal) (cost, avgPrice, unfilled decimal.Decimal) {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	cost, unfilled = walkLevels(ob.takerLevelsLocked(side), size, nil)
+	if filled := size.Sub(unfilled); filled.IsPositive() {
+		avgPrice = cost.Div(filled)
+	}
+	return cost, avgPrice, unfilled
+}
+
+// SizeAvailable returns how much an order on side can take at limitPrice or
+// better: the asks at or below it for a buy, the bids at or above it for a
+// sell.
+func (ob *OrderBook) SizeAvailable(side Side, limitPrice decimal.Decimal) decimal.Decimal {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	total := decimal.Zero
+	for _, level := range ob.takerLevelsLocked(side) {
+		if side == SideBuy && level.Price.GreaterThan(limitPrice) ||
+			side == SideSell && level.Price.LessThan(limitPrice) {
 			break
 		}
+		total = total.Add(level.Size)
+	}
+	return total
+}
 
-		fillSize := level.Size
-		if fillSize.GreaterThan(remaining) {
-			fillSize = remaining
+// takerLevelsLocked returns the levels an order on side trades against,
+// best first: asks for a buy, bids for a sell.
+func (ob *OrderBook) takerLevelsLocked(side Side) []PriceLevel {
+	if side == SideBuy {
+		return ob.asks
+	}
+	return ob.bids
+}
+
+// walkLevels fills size against levels in order, calling fill (if not nil)
+// for each level touched. It returns the total cost and the size left over.
+func walkLevels(levels []PriceLevel, size decimal.Decimal, fill func(Fill)) (cost, remaining decimal.Decimal) {
+	remaining = size
+	for _, level := range levels {
+		if !remaining.IsPositive() {
+			break
 		}
 
-		totalCost = totalCost.Add(level.Price.Mul(fillSize))
+		fillSize := decimal.Min(level.Size, remaining)
+		if fill != nil {
+			fill(Fill{Price: level.Price, Size: fillSize})
+		}
+		cost = cost.Add(level.Price.Mul(fillSize))
 		remaining = remaining.Sub(fillSize)
 	}
-
-	if remaining.GreaterThan(decimal.Zero) {
-		return decimal.Zero, fmt.Errorf("insufficient liquidity: needed %s, missing %s", size, remaining)
-	}
-
-	return totalCost.Div(size), nil
+	return cost, remaining
 }
 
 // PriceImpact calculates the price impact of a trade of given size.
@@ -310,80 +347,72 @@ func (ob *OrderBook) SetAsks(levels []PriceLevel) {
 // UpdateLevel updates a single price level on the specified side.
 // If size is zero, the level is removed.
 func (ob *OrderBook) UpdateLevel(side Side, price, size decimal.Decimal) {
+	ob.ApplyDelta(side, price, size)
+}
+
+// Delta is a single price-level change from an incremental feed.
+type Delta struct {
+	Side  Side
+	Price decimal.Decimal
+	Size  decimal.Decimal // New total size at Price; zero removes the level
+}
+
+// ApplyDelta inserts, updates, or removes (size <= 0) one price level,
+// keeping the ladder sorted without rebuilding it.
+func (ob *OrderBook) ApplyDelta(side Side, price, size decimal.Decimal) {
 	ob.mu.Lock()
 	defer ob.mu.Unlock()
 
-	if side == SideBuy {
-		ob.updateBidLevel(price, size)
-	} else {
-		ob.updateAskLevel(price, size)
-	}
+	ob.applyDeltaLocked(side, price, size)
 }
 
-func (ob *OrderBook) updateBidLevel(price, size decimal.Decimal) {
-	// Find existing level
-	idx := -1
-	for i, level := range ob.bids {
-		if level.Price.Equal(price) {
-			idx = i
-			break
-		}
-	}
+// ApplyDeltas applies a batch of deltas under a single lock, so readers never
+// observe a partially applied update.
+func (ob *OrderBook) ApplyDeltas(deltas []Delta) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
 
-	if size.IsZero() {
-		// Remove level
-		if idx >= 0 {
-			ob.bids = append(ob.bids[:idx], ob.bids[idx+1:]...)
-		}
-		return
+	for _, d := range deltas {
+		ob.applyDeltaLocked(d.Side, d.Price, d.Size)
 	}
+}
 
-	if idx >= 0 {
-		// Update existing
-		ob.bids[idx].Size = size
+func (ob *OrderBook) applyDeltaLocked(side Side, price, size decimal.Decimal) {
+	if side == SideBuy {
+		ob.bids = applyLevel(ob.bids, price, size, true)
 	} else {
-		// Insert new level in sorted position (descending by price)
-		newLevel := PriceLevel{Price: price, Size: size}
-		insertIdx := sort.Search(len(ob.bids), func(i int) bool {
-			return ob.bids[i].Price.LessThan(price)
-		})
-		ob.bids = append(ob.bids, PriceLevel{})
-		copy(ob.bids[insertIdx+1:], ob.bids[insertIdx:])
-		ob.bids[insertIdx] = newLevel
+		ob.asks = applyLevel(ob.asks, price, size, false)
 	}
 }
 
-func (ob *OrderBook) updateAskLevel(price, size decimal.Decimal) {
-	// Find existing level
-	idx := -1
-	for i, level := range ob.asks {
-		if level.Price.Equal(price) {
-			idx = i
-			break
+// applyLevel sets the size at price in a sorted ladder (descending for bids,
+// ascending for asks), using binary search to find the level.
+func applyLevel(levels []PriceLevel, price, size decimal.Decimal, descending bool) []PriceLevel {
+	idx := sort.Search(len(levels), func(i int) bool {
+		if descending {
+			return levels[i].Price.LessThanOrEqual(price)
 		}
-	}
+		return levels[i].Price.GreaterThanOrEqual(price)
+	})
+	exists := idx < len(levels) && levels[idx].Price.Equal(price)
 
-	if size.IsZero() {
+	if !size.IsPositive() {
 		// Remove level
-		if idx >= 0 {
-			ob.asks = append(ob.asks[:idx], ob.asks[idx+1:]...)
+		if exists {
+			levels = append(levels[:idx], levels[idx+1:]...)
 		}
-		return
+		return levels
 	}
 
-	if idx >= 0 {
-		// Update existing
-		ob.asks[idx].Size = size
-	} else {
-		// Insert new level in sorted position (ascending by price)
-		newLevel := PriceLevel{Price: price, Size: size}
-		insertIdx := sort.Search(len(ob.asks), func(i int) bool {
-			return ob.asks[i].Price.GreaterThan(price)
-		})
-		ob.asks = append(ob.asks, PriceLevel{})
-		copy(ob.asks[insertIdx+1:], ob.asks[insertIdx:])
-		ob.asks[insertIdx] = newLevel
+	if exists {
+		levels[idx].Size = size
+		return levels
 	}
+
+	levels = append(levels, PriceLevel{})
+	copy(levels[idx+1:], levels[idx:])
+	levels[idx] = PriceLevel{Price: price, Size: size}
+	return levels
 }
 
 // SetTimestamp updates the orderbook timestamp.
@@ -427,13 +456,6 @@ func (ob *OrderBook) SimulateMarketOrder(side Side, size decimal.Decimal) MatchR
 	ob.mu.RLock()
 	defer ob.mu.RUnlock()
 
-	var levels []PriceLevel
-	if side == SideBuy {
-		levels = ob.asks
-	} else {
-		levels = ob.bids
-	}
-
 	result := MatchResult{
 		Side:      side,
 		TotalSize: decimal.Zero,
@@ -441,40 +463,16 @@ func (ob *OrderBook) SimulateMarketOrder(side Side, size decimal.Decimal) MatchR
 		Fills:     make([]Fill, 0),
 	}
 
-	remaining := size
-	var firstPrice decimal.Decimal
-
-	for _, level := range levels {
-		if remaining.IsZero() {
-			break
-		}
-
-		if result.TotalSize.IsZero() {
-			firstPrice = level.Price
-		}
-
-		fillSize := level.Size
-		if fillSize.GreaterThan(remaining) {
-			fillSize = remaining
-		}
-
-		result.Fills = append(result.Fills, Fill{
-			Price: level.Price,
-			Size:  fillSize,
-		})
-
-		result.TotalCost = result.TotalCost.Add(level.Price.Mul(fillSize))
-		result.TotalSize = result.TotalSize.Add(fillSize)
-		remaining = remaining.Sub(fillSize)
-	}
-
-	result.Unfilled = remaining
+	result.TotalCost, result.Unfilled = walkLevels(ob.takerLevelsLocked(side), size, func(f Fill) {
+		result.Fills = append(result.Fills, f)
+		result.TotalSize = result.TotalSize.Add(f.Size)
+	})
 
 	if result.TotalSize.GreaterThan(decimal.Zero) {
 		result.AvgPrice = result.TotalCost.Div(result.TotalSize)
 
 		// Calculate price impact
-		if !firstPrice.IsZero() {
+		if firstPrice := result.Fills[0].Price; !firstPrice.IsZero() {
 			diff := result.AvgPrice.Sub(firstPrice).Abs()
 			result.PriceImpact = diff.Div(firstPrice).Mul(decimal.NewFromInt(100))
 		}
@@ -194,6 +194,60 @@ func TestUpdateAskLevel(t *testing.T) {
 	}
 }
 
+func TestApplyDelta(t *testing.T) {
+	ob := NewOrderBook("token123", "market456")
+	d := decimal.RequireFromString
+
+	// Inserts arrive out of price order
+	ob.ApplyDelta(SideBuy, d("0.48"), d("100"))
+	ob.ApplyDelta(SideBuy, d("0.50"), d("50"))
+	ob.ApplyDelta(SideBuy, d("0.49"), d("75"))
+	ob.ApplyDelta(SideSell, d("0.53"), d("80"))
+	ob.ApplyDelta(SideSell, d("0.51"), d("40"))
+	ob.ApplyDelta(SideSell, d("0.52"), d("60"))
+
+	// Update an interior level, delete the best bid, delete a missing level
+	ob.ApplyDelta(SideBuy, d("0.49"), d("90"))
+	ob.ApplyDelta(SideBuy, d("0.50"), decimal.Zero)
+	ob.ApplyDelta(SideSell, d("0.60"), decimal.Zero)
+
+	wantBids := []PriceLevel{{Price: d("0.49"), Size: d("90")}, {Price: d("0.48"), Size: d("100")}}
+	wantAsks := []PriceLevel{{Price: d("0.51"), Size: d("40")}, {Price: d("0.52"), Size: d("60")}, {Price: d("0.53"), Size: d("80")}}
+	assertLadder(t, "bids", ob.Bids(), wantBids)
+	assertLadder(t, "asks", ob.Asks(), wantAsks)
+
+	bid, _ := ob.BestBid()
+	ask, _ := ob.BestAsk()
+	if !bid.Equal(d("0.49")) || !ask.Equal(d("0.51")) {
+		t.Errorf("Expected best 0.49/0.51, got %s/%s", bid, ask)
+	}
+
+	// A batch that removes the best ask and adds a better one
+	ob.ApplyDeltas([]Delta{
+		{Side: SideSell, Price: d("0.51"), Size: decimal.Zero},
+		{Side: SideSell, Price: d("0.505"), Size: d("10")},
+	})
+	ask, size := ob.BestAsk()
+	if !ask.Equal(d("0.505")) || !size.Equal(d("10")) {
+		t.Errorf("Expected best ask 0.505 x 10, got %s x %s", ask, size)
+	}
+	if ob.AskDepth() != 3 {
+		t.Errorf("Expected 3 ask levels, got %d", ob.AskDepth())
+	}
+}
+
+func assertLadder(t *testing.T, name string, got, want []PriceLevel) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected %d levels, got %d", name, len(want), len(got))
+	}
+	for i := range want {
+		if !got[i].Price.Equal(want[i].Price) || !got[i].Size.Equal(want[i].Size) {
+			t.Errorf("%s[%d]: expected %s x %s, got %s x %s", name, i, want[i].Price, want[i].Size, got[i].Price, got[i].Size)
+		}
+	}
+}
+
 func TestTotalSize(t *testing.T) {
 	ob := NewOrderBook("token123", "market456")
 
@@ -325,6 +379,85 @@ func TestSimulateMarketOrderPartialFill(t *testing.T) {
 	}
 }
 
+func multiLevelBook() *OrderBook {
+	ob := NewOrderBook("token123", "market456")
+	ob.SetBids([]PriceLevel{
+		{Price: decimal.RequireFromString("0.49"), Size: decimal.NewFromInt(50)},
+		{Price: decimal.RequireFromString("0.48"), Size: decimal.NewFromInt(100)},
+		{Price: decimal.RequireFromString("0.45"), Size: decimal.NewFromInt(200)},
+	})
+	ob.SetAsks([]PriceLevel{
+		{Price: decimal.RequireFromString("0.51"), Size: decimal.NewFromInt(100)},
+		{Price: decimal.RequireFromString("0.52"), Size: decimal.NewFromInt(100)},
+		{Price: decimal.RequireFromString("0.55"), Size: decimal.NewFromInt(100)},
+	})
+	return ob
+}
+
+func TestCostToFill(t *testing.T) {
+	ob := multiLevelBook()
+
+	tests := []struct {
+		name                     string
+		side                     Side
+		size                     string
+		cost, avgPrice, unfilled string
+	}{
+		{"within top level", SideBuy, "40", "20.4", "0.51", "0"},
+		{"across levels", SideBuy, "250", "130.5", "0.522", "0"},
+		{"beyond the book", SideBuy, "400", "158", "0.5266666666666667", "100"},
+		{"sell across levels", SideSell, "150", "72.5", "0.4833333333333333", "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, avgPrice, unfilled := ob.CostToFill(tt.side, decimal.RequireFromString(tt.size))
+			if !cost.Equal(decimal.RequireFromString(tt.cost)) {
+				t.Errorf("cost: expected %s, got %s", tt.cost, cost)
+			}
+			if !avgPrice.Equal(decimal.RequireFromString(tt.avgPrice)) {
+				t.Errorf("avgPrice: expected %s, got %s", tt.avgPrice, avgPrice)
+			}
+			if !unfilled.Equal(decimal.RequireFromString(tt.unfilled)) {
+				t.Errorf("unfilled: expected %s, got %s", tt.unfilled, unfilled)
+			}
+		})
+	}
+
+	// Agrees with the full simulation
+	sim := ob.SimulateMarketOrder(SideBuy, decimal.NewFromInt(250))
+	if cost, avg, _ := ob.CostToFill(SideBuy, decimal.NewFromInt(250)); !cost.Equal(sim.TotalCost) || !avg.Equal(sim.AvgPrice) {
+		t.Errorf("CostToFill %s @ %s disagrees with SimulateMarketOrder %s @ %s", cost, avg, sim.TotalCost, sim.AvgPrice)
+	}
+
+	if _, avg, unfilled := NewOrderBook("t", "m").CostToFill(SideBuy, decimal.NewFromInt(10)); !avg.IsZero() || !unfilled.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("empty book: expected nothing filled, got avg %s with %s unfilled", avg, unfilled)
+	}
+}
+
+func TestSizeAvailable(t *testing.T) {
+	ob := multiLevelBook()
+
+	tests := []struct {
+		side  Side
+		limit string
+		want  int64
+	}{
+		{SideBuy, "0.50", 0},
+		{SideBuy, "0.51", 100},
+		{SideBuy, "0.54", 200},
+		{SideBuy, "0.99", 300},
+		{SideSell, "0.50", 0},
+		{SideSell, "0.48", 150},
+		{SideSell, "0.01", 350},
+	}
+	for _, tt := range tests {
+		got := ob.SizeAvailable(tt.side, decimal.RequireFromString(tt.limit))
+		if !got.Equal(decimal.NewFromInt(tt.want)) {
+			t.Errorf("%s at %s: expected %d, got %s", tt.side, tt.limit, tt.want, got)
+		}
+	}
+}
+
 func TestSnapshot(t *testing.T) {
 	ob := NewOrderBook("token123", "market456")
 	ob.SetTimestamp(1234567890)
@@ -0,0 +1,90 @@
+package book
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceMode selects what "current price" means for a token.
+type PriceMode string
+
+const (
+	// PriceModeMid is the midpoint of the best bid and ask.
+	PriceModeMid PriceMode = "mid"
+	// PriceModeLastTrade is the venue's last traded price. A book alone can't
+	// answer it; see OrderBook.Price.
+	PriceModeLastTrade PriceMode = "last_trade"
+	// PriceModeMicro weights the best bid and ask by the size on the opposite
+	// side, leaning toward the side more likely to trade through.
+	PriceModeMicro PriceMode = "micro"
+	// PriceModeWeightedMid averages the size-weighted bid and ask prices over
+	// the top WeightedMidLevels levels of each side.
+	PriceModeWeightedMid PriceMode = "weighted_mid"
+)
+
+// WeightedMidLevels is the number of levels per side PriceModeWeightedMid uses.
+const WeightedMidLevels = 5
+
+// ParsePriceMode validates a price mode name. Empty means PriceModeMid.
+func ParsePriceMode(s string) (PriceMode, error) {
+	switch mode := PriceMode(s); mode {
+	case "":
+		return PriceModeMid, nil
+	case PriceModeMid, PriceModeLastTrade, PriceModeMicro, PriceModeWeightedMid:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown price mode %q", s)
+	}
+}
+
+// Price returns the book's price under mode. It returns false if either side
+// is empty, or for PriceModeLastTrade, which needs trade data.
+func (ob *OrderBook) Price(mode PriceMode) (decimal.Decimal, bool) {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	if len(ob.bids) == 0 || len(ob.asks) == 0 {
+		return decimal.Zero, false
+	}
+	bid, ask := ob.bids[0], ob.asks[0]
+
+	switch mode {
+	case "", PriceModeMid:
+		return bid.Price.Add(ask.Price).Div(decimal.NewFromInt(2)), true
+
+	case PriceModeMicro:
+		total := bid.Size.Add(ask.Size)
+		if !total.IsPositive() {
+			return bid.Price.Add(ask.Price).Div(decimal.NewFromInt(2)), true
+		}
+		return bid.Price.Mul(ask.Size).Add(ask.Price.Mul(bid.Size)).Div(total), true
+
+	case PriceModeWeightedMid:
+		vwBid, okBid := weightedPrice(ob.bids, WeightedMidLevels)
+		vwAsk, okAsk := weightedPrice(ob.asks, WeightedMidLevels)
+		if !okBid || !okAsk {
+			return bid.Price.Add(ask.Price).Div(decimal.NewFromInt(2)), true
+		}
+		return vwBid.Add(vwAsk).Div(decimal.NewFromInt(2)), true
+
+	default:
+		return decimal.Zero, false
+	}
+}
+
+// weightedPrice is the size-weighted average price of the first n levels.
+func weightedPrice(levels []PriceLevel, n int) (decimal.Decimal, bool) {
+	var notional, size decimal.Decimal
+	for i, level := range levels {
+		if i >= n {
+			break
+		}
+		notional = notional.Add(level.Price.Mul(level.Size))
+		size = size.Add(level.Size)
+	}
+	if !size.IsPositive() {
+		return decimal.Zero, false
+	}
+	return notional.Div(size), true
+}
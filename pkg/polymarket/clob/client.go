@@ -2,20 +2,29 @@ package clob
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/rand"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"math/big"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/phenomenon0/polymarket-agents/pkg/eth"
+	"github.com/phenomenon0/polymarket-agents/pkg/httpx"
+	"github.com/phenomenon0/polymarket-agents/pkg/redact"
+	"github.com/phenomenon0/polymarket-agents/pkg/tracing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/time/rate"
 )
 
@@ -23,14 +32,58 @@ import (
 type Client struct {
 	baseURL    string
 	chainID    int
-	wallet     *eth.Wallet
+	signer     eth.Signer
 	eip712     *eth.EIP712Signer
 	hmac       *eth.HMACSigner
 	creds      *APICredentials
 	httpClient *http.Client
 	limiter    *rate.Limiter
-	sigType    int    // 0=EOA, 1=PolyProxy, 2=GnosisSafe
-	funder     string // Funder address (for proxy wallets)
+	baseRate   rate.Limit // limiter rate with headroom; see ratelimit.go
+	sigType    int        // 0=EOA, 1=PolyProxy, 2=GnosisSafe
+	funder     string     // Funder address (for proxy wallets)
+
+	metaMu    sync.Mutex
+	metaCache map[string]marketMetaEntry // conditionID -> metadata
+	metaTTL   time.Duration
+
+	onSelfCross SelfCrossHandler
+
+	rateMu      sync.Mutex
+	pausedUntil time.Time // set from Retry-After
+
+	// Two-phase orders awaiting CommitOrder; see prepare.go
+	prepareTTL time.Duration
+	preparedMu sync.Mutex
+	prepared   map[string]*PreparedOrder
+
+	// Signing clock. clockOffset is added to now() once synced; see clock.go.
+	now            func() time.Time
+	skewCorrection bool
+	maxSkew        time.Duration
+	clockMu        sync.Mutex
+	clockOffset    time.Duration
+	clockSynced    bool
+	clockRetryAt   time.Time // no sync attempt before this after a failure
+
+	// Transport overrides, applied after all options so they compose with
+	// WithCLOBHTTPClient regardless of order
+	proxy     func(*http.Request) (*url.URL, error)
+	tlsConfig *tls.Config
+}
+
+// SelfCrossHandler is called before posting an order that would cross one of
+// the account's own open orders on the opposite side.
+type SelfCrossHandler func(args *OrderArgs, resting Order)
+
+// LogSelfCross is a SelfCrossHandler that logs a warning.
+func LogSelfCross(args *OrderArgs, resting Order) {
+	log.Printf("[CLOB] Warning: %s %v@%v on %s crosses own open %s order %s at %s",
+		args.Side, args.Size, args.Price, args.TokenID, resting.Side, resting.ID, resting.Price)
+}
+
+type marketMetaEntry struct {
+	meta      *MarketMeta
+	fetchedAt time.Time
 }
 
 // ClientOption configures the client.
@@ -77,6 +130,32 @@ func WithFunder(funder string) ClientOption {
 	}
 }
 
+// WithExternalSigner delegates signing to an external signer (e.g. KMS or a
+// hardware wallet) instead of an in-memory private key. When set, NewClient
+// may be called with an empty private key.
+func WithExternalSigner(signer eth.Signer) ClientOption {
+	return func(c *Client) {
+		c.signer = signer
+	}
+}
+
+// WithMarketMetaTTL sets how long GetMarketMeta results are cached.
+func WithMarketMetaTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.metaTTL = ttl
+	}
+}
+
+// WithSelfCrossHandler enables the self-cross check: before each order is
+// signed the client fetches its open orders and calls fn for every one the
+// order would trade against. The check costs a GetOpenOrders round trip per
+// order, so it is off unless a handler is set; LogSelfCross just logs.
+func WithSelfCrossHandler(fn SelfCrossHandler) ClientOption {
+	return func(c *Client) {
+		c.onSelfCross = fn
+	}
+}
+
 // WithCLOBHTTPClient sets a custom HTTP client.
 func WithCLOBHTTPClient(client *http.Client) ClientOption {
 	return func(c *Client) {
@@ -84,37 +163,79 @@ func WithCLOBHTTPClient(client *http.Client) ClientOption {
 	}
 }
 
-// NewClient creates a new CLOB API client.
-func NewClient(privateKey string, opts ...ClientOption) (*Client, error) {
-	wallet, err := eth.NewWallet(privateKey)
-	if err != nil {
-		return nil, fmt.Errorf("invalid private key: %w", err)
+// WithTransportConfig replaces the HTTP client's transport with one built
+// from config, e.g. to raise the connection pool for high throughput.
+// WithProxy and WithTLSConfig still apply on top.
+func WithTransportConfig(config httpx.TransportConfig) ClientOption {
+	return func(c *Client) {
+		client := *c.httpClient
+		client.Transport = httpx.NewTransport(config)
+		c.httpClient = &client
 	}
+}
 
+// WithProxy routes all CLOB requests through the given HTTP(S) or SOCKS5
+// proxy URL. An unparseable URL makes every request fail rather than
+// silently connecting directly.
+func WithProxy(proxyURL string) ClientOption {
+	return func(c *Client) {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Host == "" {
+			c.proxy = func(*http.Request) (*url.URL, error) {
+				return nil, fmt.Errorf("invalid proxy URL %q", proxyURL)
+			}
+			return
+		}
+		c.proxy = http.ProxyURL(u)
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used for CLOB connections, e.g.
+// to trust a corporate proxy's CA.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.tlsConfig = config
+	}
+}
+
+// NewClient creates a new CLOB API client. WithProxy or WithTLSConfig
+// together with a custom RoundTripper other than *http.Transport is an
+// error, since they can't be applied to it.
+func NewClient(privateKey string, opts ...ClientOption) (*Client, error) {
 	c := &Client{
 		baseURL: DefaultBaseURL,
 		chainID: ChainIDPolygon,
-		wallet:  wallet,
-		eip712:  eth.NewEIP712Signer(wallet),
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				MaxIdleConns:        20,
-				MaxIdleConnsPerHost: 10,
-				IdleConnTimeout:     90 * time.Second,
-			},
+			Timeout:   30 * time.Second,
+			Transport: httpx.NewTransport(httpx.DefaultTransportConfig()),
 		},
-		limiter: rate.NewLimiter(rate.Limit(10), 5),
-		sigType: 0, // EOA by default
+		limiter:  rate.NewLimiter(rate.Limit(DefaultRateLimit), DefaultRateBurst),
+		baseRate: rate.Limit(DefaultRateLimit),
+		sigType:  0, // EOA by default
+		metaTTL:  DefaultMarketMetaTTL,
+		now:      time.Now,
+		maxSkew:  DefaultMaxClockSkew,
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
+	if err := c.applyTransportOptions(); err != nil {
+		return nil, err
+	}
+
+	if c.signer == nil {
+		wallet, err := eth.NewWallet(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", redact.Error(err, privateKey))
+		}
+		c.signer = wallet
+	}
+	c.eip712 = eth.NewEIP712Signer(c.signer)
 
-	// Default funder to wallet address
+	// Default funder to signer address
 	if c.funder == "" {
-		c.funder = wallet.AddressHex()
+		c.funder = c.signer.Address().Hex()
 	}
 
 	return c, nil
@@ -122,31 +243,35 @@ func NewClient(privateKey string, opts ...ClientOption) (*Client, error) {
 
 // NewPublicClient creates a CLOB client for public (unauthenticated) operations only.
 // Use this for reading orderbooks, prices, and market data without needing a wallet.
+// Proxy or TLS options it can't apply (see NewClient) are logged.
 func NewPublicClient(opts ...ClientOption) *Client {
 	c := &Client{
 		baseURL: DefaultBaseURL,
 		chainID: ChainIDPolygon,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				MaxIdleConns:        20,
-				MaxIdleConnsPerHost: 10,
-				IdleConnTimeout:     90 * time.Second,
-			},
+			Timeout:   30 * time.Second,
+			Transport: httpx.NewTransport(httpx.DefaultTransportConfig()),
 		},
-		limiter: rate.NewLimiter(rate.Limit(10), 5),
+		limiter:  rate.NewLimiter(rate.Limit(DefaultRateLimit), DefaultRateBurst),
+		baseRate: rate.Limit(DefaultRateLimit),
+		metaTTL:  DefaultMarketMetaTTL,
+		now:      time.Now,
+		maxSkew:  DefaultMaxClockSkew,
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
+	if err := c.applyTransportOptions(); err != nil {
+		log.Printf("[CLOB] Warning: %v; proxy and TLS settings not applied", err)
+	}
 
 	return c
 }
 
-// Address returns the wallet address.
+// Address returns the signer address.
 func (c *Client) Address() string {
-	return c.wallet.AddressHex()
+	return c.signer.Address().Hex()
 }
 
 // Funder returns the funder address.
@@ -159,6 +284,15 @@ func (c *Client) HasCredentials() bool {
 	return c.creds != nil
 }
 
+// scrub removes the L2 credentials from a response body before it is
+// quoted in an error.
+func (c *Client) scrub(body []byte) string {
+	if c.creds == nil {
+		return string(body)
+	}
+	return redact.Redact(string(body), c.creds.APIKey, c.creds.Secret, c.creds.Passphrase)
+}
+
 // --- L1 Authentication Methods ---
 
 // CreateOrDeriveAPIKey creates or derives L2 API credentials.
@@ -176,7 +310,7 @@ func (c *Client) CreateOrDeriveAPIKey(ctx context.Context) (*APICredentials, err
 
 // CreateAPIKey creates new L2 API credentials.
 func (c *Client) CreateAPIKey(ctx context.Context, nonce int64) (*APICredentials, error) {
-	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	timestamp := strconv.FormatInt(c.signingTime(ctx).Unix(), 10)
 	nonceBI := big.NewInt(nonce)
 
 	signature, err := c.eip712.SignClobAuth(int64(c.chainID), timestamp, nonceBI)
@@ -184,7 +318,7 @@ func (c *Client) CreateAPIKey(ctx context.Context, nonce int64) (*APICredentials
 		return nil, fmt.Errorf("sign failed: %w", err)
 	}
 
-	headers := eth.L1AuthHeaders(c.wallet.AddressHex(), signature, timestamp, nonce)
+	headers := eth.L1AuthHeaders(c.signer.Address().Hex(), signature, timestamp, nonce)
 
 	var creds APICredentials
 	if err := c.post(ctx, "/auth/api-key", headers, nil, &creds); err != nil {
@@ -204,7 +338,7 @@ func (c *Client) CreateAPIKey(ctx context.Context, nonce int64) (*APICredentials
 
 // DeriveAPIKey derives existing L2 API credentials.
 func (c *Client) DeriveAPIKey(ctx context.Context, nonce int64) (*APICredentials, error) {
-	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	timestamp := strconv.FormatInt(c.signingTime(ctx).Unix(), 10)
 	nonceBI := big.NewInt(nonce)
 
 	signature, err := c.eip712.SignClobAuth(int64(c.chainID), timestamp, nonceBI)
@@ -212,7 +346,7 @@ func (c *Client) DeriveAPIKey(ctx context.Context, nonce int64) (*APICredentials
 		return nil, fmt.Errorf("sign failed: %w", err)
 	}
 
-	headers := eth.L1AuthHeaders(c.wallet.AddressHex(), signature, timestamp, nonce)
+	headers := eth.L1AuthHeaders(c.signer.Address().Hex(), signature, timestamp, nonce)
 
 	var creds APICredentials
 	if err := c.get(ctx, "/auth/derive-api-key", headers, nil, &creds); err != nil {
@@ -258,6 +392,20 @@ func (c *Client) GetPrice(ctx context.Context, tokenID string) (string, error) {
 	return result.Price, nil
 }
 
+// GetLastTradePrice fetches the price of the most recent trade in a token.
+func (c *Client) GetLastTradePrice(ctx context.Context, tokenID string) (string, error) {
+	params := url.Values{}
+	params.Set("token_id", tokenID)
+
+	var result struct {
+		Price string `json:"price"`
+	}
+	if err := c.get(ctx, "/last-trade-price", nil, params, &result); err != nil {
+		return "", err
+	}
+	return result.Price, nil
+}
+
 // GetMidpoint fetches the midpoint price for a token.
 func (c *Client) GetMidpoint(ctx context.Context, tokenID string) (string, error) {
 	params := url.Values{}
@@ -331,15 +479,70 @@ func (c *Client) GetMarket(ctx context.Context, conditionID string) (*MarketInfo
 	return &market, nil
 }
 
+// GetMarketMeta returns tick size, minimum order size, and negRisk for a
+// market, serving from an in-memory cache for metaTTL after the first fetch.
+func (c *Client) GetMarketMeta(ctx context.Context, conditionID string) (*MarketMeta, error) {
+	c.metaMu.Lock()
+	entry, ok := c.metaCache[conditionID]
+	c.metaMu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < c.metaTTL {
+		return entry.meta, nil
+	}
+
+	market, err := c.GetMarket(ctx, conditionID)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &MarketMeta{
+		ConditionID:      market.ConditionID,
+		MinimumTickSize:  market.MinimumTickSize,
+		MinimumOrderSize: market.MinimumOrderSize,
+		NegRisk:          market.NegRisk,
+		Tokens:           market.Tokens,
+	}
+
+	c.metaMu.Lock()
+	if c.metaCache == nil {
+		c.metaCache = make(map[string]marketMetaEntry)
+	}
+	c.metaCache[conditionID] = marketMetaEntry{meta: meta, fetchedAt: time.Now()}
+	c.metaMu.Unlock()
+
+	return meta, nil
+}
+
+// GetTickSize returns the minimum tick size for a market (cached).
+func (c *Client) GetTickSize(ctx context.Context, conditionID string) (string, error) {
+	meta, err := c.GetMarketMeta(ctx, conditionID)
+	if err != nil {
+		return "", err
+	}
+	return meta.MinimumTickSize, nil
+}
+
+// InvalidateMarketMeta drops cached metadata for a market, or for all
+// markets if conditionID is empty.
+func (c *Client) InvalidateMarketMeta(conditionID string) {
+	c.metaMu.Lock()
+	defer c.metaMu.Unlock()
+
+	if conditionID == "" {
+		c.metaCache = nil
+		return
+	}
+	delete(c.metaCache, conditionID)
+}
+
 // --- L2 Authenticated Methods ---
 
 // GetOpenOrders fetches open orders for the authenticated user.
 func (c *Client) GetOpenOrders(ctx context.Context) ([]Order, error) {
 	if !c.HasCredentials() {
-		return nil, fmt.Errorf("L2 credentials required")
+		return nil, errNoCredentials
 	}
 
-	headers, err := c.l2Headers("GET", "/orders", nil)
+	headers, err := c.l2Headers(ctx, "GET", "/orders", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -354,11 +557,11 @@ func (c *Client) GetOpenOrders(ctx context.Context) ([]Order, error) {
 // GetOrder fetches a specific order by ID.
 func (c *Client) GetOrder(ctx context.Context, orderID string) (*Order, error) {
 	if !c.HasCredentials() {
-		return nil, fmt.Errorf("L2 credentials required")
+		return nil, errNoCredentials
 	}
 
 	path := "/orders/" + orderID
-	headers, err := c.l2Headers("GET", path, nil)
+	headers, err := c.l2Headers(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -373,10 +576,10 @@ func (c *Client) GetOrder(ctx context.Context, orderID string) (*Order, error) {
 // GetTrades fetches trades for the authenticated user.
 func (c *Client) GetTrades(ctx context.Context) ([]Trade, error) {
 	if !c.HasCredentials() {
-		return nil, fmt.Errorf("L2 credentials required")
+		return nil, errNoCredentials
 	}
 
-	headers, err := c.l2Headers("GET", "/trades", nil)
+	headers, err := c.l2Headers(ctx, "GET", "/trades", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -391,7 +594,7 @@ func (c *Client) GetTrades(ctx context.Context) ([]Trade, error) {
 // PostOrder submits a signed order.
 func (c *Client) PostOrder(ctx context.Context, order *SignedOrder) (*PostOrderResponse, error) {
 	if !c.HasCredentials() {
-		return nil, fmt.Errorf("L2 credentials required")
+		return nil, errNoCredentials
 	}
 
 	body, err := json.Marshal(order)
@@ -399,7 +602,7 @@ func (c *Client) PostOrder(ctx context.Context, order *SignedOrder) (*PostOrderR
 		return nil, err
 	}
 
-	headers, err := c.l2Headers("POST", "/order", body)
+	headers, err := c.l2Headers(ctx, "POST", "/order", body)
 	if err != nil {
 		return nil, err
 	}
@@ -419,7 +622,7 @@ func (c *Client) CancelOrder(ctx context.Context, orderID string) error {
 // CancelOrders cancels multiple orders.
 func (c *Client) CancelOrders(ctx context.Context, orderIDs []string) error {
 	if !c.HasCredentials() {
-		return fmt.Errorf("L2 credentials required")
+		return errNoCredentials
 	}
 
 	body, err := json.Marshal(orderIDs)
@@ -427,7 +630,7 @@ func (c *Client) CancelOrders(ctx context.Context, orderIDs []string) error {
 		return err
 	}
 
-	headers, err := c.l2Headers("DELETE", "/orders", body)
+	headers, err := c.l2Headers(ctx, "DELETE", "/orders", body)
 	if err != nil {
 		return err
 	}
@@ -447,10 +650,10 @@ func (c *Client) CancelOrders(ctx context.Context, orderIDs []string) error {
 // CancelAllOrders cancels all open orders.
 func (c *Client) CancelAllOrders(ctx context.Context) error {
 	if !c.HasCredentials() {
-		return fmt.Errorf("L2 credentials required")
+		return errNoCredentials
 	}
 
-	headers, err := c.l2Headers("DELETE", "/orders/all", nil)
+	headers, err := c.l2Headers(ctx, "DELETE", "/orders/all", nil)
 	if err != nil {
 		return err
 	}
@@ -458,6 +661,70 @@ func (c *Client) CancelAllOrders(ctx context.Context) error {
 	return c.delete(ctx, "/orders/all", headers, nil, nil)
 }
 
+// CancelAllOrdersVerified cancels all open orders, then re-lists open orders
+// and batch-cancels any the venue left behind, up to retries times. Cancel
+// failures along the way are not fatal; only orders still open at the end
+// are, reported as ErrOrdersRemain with their IDs.
+func (c *Client) CancelAllOrdersVerified(ctx context.Context, retries int) error {
+	cancelErr := c.CancelAllOrders(ctx)
+	for attempt := 0; ; attempt++ {
+		orders, err := c.GetOpenOrders(ctx)
+		if err != nil {
+			return fmt.Errorf("verify cancel: list open orders failed: %w", err)
+		}
+		if len(orders) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(orders))
+		for i, o := range orders {
+			ids[i] = o.ID
+		}
+		if attempt >= retries {
+			if cancelErr != nil {
+				return fmt.Errorf("%w after %d retries: %v (last cancel: %v)", ErrOrdersRemain, retries, ids, cancelErr)
+			}
+			return fmt.Errorf("%w after %d retries: %v", ErrOrdersRemain, retries, ids)
+		}
+		cancelErr = c.CancelOrders(ctx, ids)
+	}
+}
+
+// CancelOrdersByMarket cancels every open order in the market with
+// conditionID and returns how many were canceled.
+func (c *Client) CancelOrdersByMarket(ctx context.Context, conditionID string) (int, error) {
+	return c.cancelMatching(ctx, func(o Order) bool { return o.Market == conditionID })
+}
+
+// CancelOrdersByToken cancels every open order for tokenID and returns how
+// many were canceled.
+func (c *Client) CancelOrdersByToken(ctx context.Context, tokenID string) (int, error) {
+	return c.cancelMatching(ctx, func(o Order) bool { return o.TokenID == tokenID })
+}
+
+// cancelMatching fetches open orders and batch-cancels those matching.
+func (c *Client) cancelMatching(ctx context.Context, match func(Order) bool) (int, error) {
+	orders, err := c.GetOpenOrders(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list open orders failed: %w", err)
+	}
+
+	var ids []string
+	for _, o := range orders {
+		if match(o) {
+			ids = append(ids, o.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if err := c.CancelOrders(ctx, ids); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
 // --- Order Building ---
 
 // BuildOrder creates an order payload from args.
@@ -495,7 +762,7 @@ func (c *Client) BuildOrder(args *OrderArgs, tickSize string, negRisk bool) (*Or
 	order := &OrderPayload{
 		Salt:          salt,
 		Maker:         c.funder,
-		Signer:        c.wallet.AddressHex(),
+		Signer:        c.signer.Address().Hex(),
 		Taker:         taker,
 		TokenID:       args.TokenID,
 		MakerAmount:   makerAmount,
@@ -555,13 +822,29 @@ func (c *Client) SignOrder(order *OrderPayload, negRisk bool) (string, error) {
 
 // CreateAndPostOrder builds, signs, and posts an order.
 func (c *Client) CreateAndPostOrder(ctx context.Context, args *OrderArgs, tickSize string, negRisk bool) (*PostOrderResponse, error) {
-	// Build order
+	signedOrder, err := c.signOrderArgs(ctx, args, tickSize, negRisk)
+	if err != nil {
+		return nil, err
+	}
+	return c.PostOrder(ctx, signedOrder)
+}
+
+// signOrderArgs runs the checks every order goes through before signing
+// (post-only, self-cross), then builds and signs it. The order type defaults
+// to GTC.
+func (c *Client) signOrderArgs(ctx context.Context, args *OrderArgs, tickSize string, negRisk bool) (*SignedOrder, error) {
+	if args.PostOnly {
+		if err := c.checkPostOnly(ctx, args); err != nil {
+			return nil, err
+		}
+	}
+
+	c.warnSelfCross(ctx, args)
+
 	order, err := c.BuildOrder(args, tickSize, negRisk)
 	if err != nil {
 		return nil, fmt.Errorf("build order: %w", err)
 	}
-
-	// Sign order
 	signature, err := c.SignOrder(order, negRisk)
 	if err != nil {
 		return nil, fmt.Errorf("sign order: %w", err)
@@ -572,103 +855,142 @@ func (c *Client) CreateAndPostOrder(ctx context.Context, args *OrderArgs, tickSi
 		orderType = OrderTypeGTC
 	}
 
-	signedOrder := &SignedOrder{
+	return &SignedOrder{
 		Order:     *order,
 		Signature: signature,
 		Owner:     c.funder,
 		OrderType: orderType,
-	}
-
-	// Post order
-	return c.PostOrder(ctx, signedOrder)
+	}, nil
 }
 
 // --- Internal helpers ---
 
-func (c *Client) l2Headers(method, path string, body []byte) (map[string]string, error) {
-	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
-	return c.hmac.SignRequest(timestamp, method, path, body, c.funder)
-}
-
-func (c *Client) get(ctx context.Context, path string, headers map[string]string, params url.Values, result interface{}) error {
-	if err := c.limiter.Wait(ctx); err != nil {
-		return fmt.Errorf("rate limiter: %w", err)
+// applyTransportOptions installs the WithProxy/WithTLSConfig settings on a
+// copy of the HTTP client's transport, leaving a caller-supplied client and
+// transport unmodified. A custom RoundTripper other than *http.Transport
+// can't take them, which is an error rather than a silent drop.
+func (c *Client) applyTransportOptions() error {
+	if c.proxy == nil && c.tlsConfig == nil {
+		return nil
 	}
 
-	u := c.baseURL + path
-	if len(params) > 0 {
-		u += "?" + params.Encode()
+	var transport *http.Transport
+	switch t := c.httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return fmt.Errorf("WithProxy/WithTLSConfig need an *http.Transport, HTTP client has %T", t)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+	if c.proxy != nil {
+		transport.Proxy = c.proxy
 	}
-
-	req.Header.Set("Accept", "application/json")
-	for k, v := range headers {
-		req.Header.Set(k, v)
+	if c.tlsConfig != nil {
+		transport.TLSClientConfig = c.tlsConfig
 	}
 
-	resp, err := c.httpClient.Do(req)
+	client := *c.httpClient
+	client.Transport = transport
+	c.httpClient = &client
+	return nil
+}
+
+// checkPostOnly fetches the book and rejects an order that would take
+// liquidity: a buy at or above the best ask, or a sell at or below the best bid.
+func (c *Client) checkPostOnly(ctx context.Context, args *OrderArgs) error {
+	book, err := c.GetOrderBook(ctx, args.TokenID)
 	if err != nil {
-		return fmt.Errorf("http request: %w", err)
+		return fmt.Errorf("post-only check: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("api error %d: %s", resp.StatusCode, string(body))
+	bestBid, bestAsk := bestPrices(book)
+	if args.Side == OrderSideBuy && bestAsk > 0 && args.Price >= bestAsk {
+		return fmt.Errorf("post-only buy at %v would cross best ask %v", args.Price, bestAsk)
 	}
-
-	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("decode response: %w", err)
-		}
+	if args.Side == OrderSideSell && bestBid > 0 && args.Price <= bestBid {
+		return fmt.Errorf("post-only sell at %v would cross best bid %v", args.Price, bestBid)
 	}
-
 	return nil
 }
 
-func (c *Client) post(ctx context.Context, path string, headers map[string]string, body []byte, result interface{}) error {
-	if err := c.limiter.Wait(ctx); err != nil {
-		return fmt.Errorf("rate limiter: %w", err)
+// warnSelfCross reports open orders on the opposite side of the same token
+// that args would trade against, if a SelfCrossHandler is set. It is
+// advisory: the order is still posted, and failures to fetch open orders are
+// ignored.
+func (c *Client) warnSelfCross(ctx context.Context, args *OrderArgs) {
+	if c.onSelfCross == nil || !c.HasCredentials() {
+		return
 	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewReader(body))
+	open, err := c.GetOpenOrders(ctx)
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return
 	}
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
-	for k, v := range headers {
-		req.Header.Set(k, v)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("http request: %w", err)
+	for _, resting := range open {
+		if resting.TokenID != args.TokenID || resting.Side == args.Side {
+			continue
+		}
+		price, err := strconv.ParseFloat(resting.Price, 64)
+		if err != nil {
+			continue
+		}
+		if (args.Side == OrderSideBuy && args.Price >= price) || (args.Side == OrderSideSell && args.Price <= price) {
+			c.onSelfCross(args, resting)
+		}
 	}
-	defer resp.Body.Close()
+}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("api error %d: %s", resp.StatusCode, string(body))
+// bestPrices returns the highest bid and lowest ask in a book summary, or 0
+// for an empty side. Levels are scanned rather than indexed so the result
+// does not depend on the order the API returns them in.
+func bestPrices(book *OrderBookSummary) (bestBid, bestAsk float64) {
+	for _, level := range book.Bids {
+		if p, err := strconv.ParseFloat(level.Price, 64); err == nil && p > bestBid {
+			bestBid = p
+		}
 	}
-
-	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			return fmt.Errorf("decode response: %w", err)
+	for _, level := range book.Asks {
+		if p, err := strconv.ParseFloat(level.Price, 64); err == nil && (bestAsk == 0 || p < bestAsk) {
+			bestAsk = p
 		}
 	}
+	return bestBid, bestAsk
+}
 
-	return nil
+func (c *Client) l2Headers(ctx context.Context, method, path string, body []byte) (map[string]string, error) {
+	timestamp := strconv.FormatInt(c.signingTime(ctx).Unix(), 10)
+	return c.hmac.SignRequest(timestamp, method, path, body, c.funder)
+}
+
+func (c *Client) get(ctx context.Context, path string, headers map[string]string, params url.Values, result interface{}) error {
+	return c.do(ctx, http.MethodGet, path, headers, params, nil, result)
+}
+
+func (c *Client) post(ctx context.Context, path string, headers map[string]string, body []byte, result interface{}) error {
+	return c.do(ctx, http.MethodPost, path, headers, nil, body, result)
 }
 
 func (c *Client) delete(ctx context.Context, path string, headers map[string]string, body []byte, result interface{}) error {
-	if err := c.limiter.Wait(ctx); err != nil {
-		return fmt.Errorf("rate limiter: %w", err)
+	return c.do(ctx, http.MethodDelete, path, headers, nil, body, result)
+}
+
+// do sends one request and decodes a successful JSON response into result.
+// It waits on the rate limiter, feeds the response's rate-limit headers back
+// into it, decompresses gzip, and turns error statuses into typed API errors
+// (re-checking credentials on a 401 of an authenticated request).
+func (c *Client) do(ctx context.Context, method, path string, headers map[string]string, params url.Values, body []byte, result interface{}) (err error) {
+	ctx, span := tracing.Start(ctx, "clob.request", attribute.String("http.method", method), attribute.String("http.path", path))
+	defer func() { tracing.End(span, err) }()
+
+	if err := c.waitRateLimit(ctx); err != nil {
+		return err
+	}
+
+	u := c.baseURL + path
+	if len(params) > 0 {
+		u += "?" + params.Encode()
 	}
 
 	var bodyReader io.Reader
@@ -676,13 +998,14 @@ func (c *Client) delete(ctx context.Context, path string, headers map[string]str
 		bodyReader = bytes.NewReader(body)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+path, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, u, bodyReader)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
-	if body != nil {
+	req.Header.Set("Accept-Encoding", "gzip")
+	if body != nil || method == http.MethodPost {
 		req.Header.Set("Content-Type", "application/json")
 	}
 	for k, v := range headers {
@@ -694,14 +1017,31 @@ func (c *Client) delete(ctx context.Context, path string, headers map[string]str
 		return fmt.Errorf("http request: %w", err)
 	}
 	defer resp.Body.Close()
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	c.observeRateLimit(resp.Header)
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("api error %d: %s", resp.StatusCode, string(body))
+	respBody, decodeErr := responseBody(resp)
+	if decodeErr == nil {
+		defer respBody.Close()
+	}
+
+	ok := resp.StatusCode == http.StatusOK || (method == http.MethodPost && resp.StatusCode == http.StatusCreated)
+	if !ok {
+		var body []byte
+		if decodeErr == nil {
+			body, _ = io.ReadAll(respBody)
+		}
+		if resp.StatusCode == http.StatusUnauthorized && headers != nil {
+			return c.authError(ctx, body)
+		}
+		return newAPIError(resp.StatusCode, c.scrub(body))
+	}
+	if decodeErr != nil {
+		return fmt.Errorf("decode response: %w", decodeErr)
 	}
 
 	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		if err := json.NewDecoder(respBody).Decode(result); err != nil {
 			return fmt.Errorf("decode response: %w", err)
 		}
 	}
@@ -709,6 +1049,17 @@ func (c *Client) delete(ctx context.Context, path string, headers map[string]str
 	return nil
 }
 
+// responseBody returns the response body, decompressing it if the server
+// sent gzip. Setting Accept-Encoding explicitly disables the transport's
+// transparent decompression, so it has to happen here. The caller closes the
+// returned reader; resp.Body itself is still closed separately.
+func responseBody(resp *http.Response) (io.ReadCloser, error) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return io.NopCloser(resp.Body), nil
+	}
+	return gzip.NewReader(resp.Body)
+}
+
 func generateSalt() (string, error) {
 	max := new(big.Int).Lsh(big.NewInt(1), 128) // 2^128
 	n, err := rand.Int(rand.Reader, max)
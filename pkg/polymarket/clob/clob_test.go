@@ -1,14 +1,29 @@
 package clob
 
 import (
+	"compress/gzip"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/eth"
+	"github.com/phenomenon0/polymarket-agents/pkg/httpx"
+	"github.com/phenomenon0/polymarket-agents/pkg/redact"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // Test private key (DO NOT use in production!)
@@ -249,6 +264,51 @@ func TestGetMarket(t *testing.T) {
 	}
 }
 
+func TestGetMarketMetaCached(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(MarketInfo{
+			ConditionID:      "cond-1",
+			MinimumTickSize:  "0.01",
+			MinimumOrderSize: "5",
+			NegRisk:          true,
+		})
+	}))
+	defer server.Close()
+
+	client := NewPublicClient(WithCLOBBaseURL(server.URL))
+	ctx := context.Background()
+
+	meta, err := client.GetMarketMeta(ctx, "cond-1")
+	if err != nil {
+		t.Fatalf("GetMarketMeta failed: %v", err)
+	}
+	if meta.MinimumTickSize != "0.01" || meta.MinimumOrderSize != "5" || !meta.NegRisk {
+		t.Errorf("Unexpected meta: %+v", meta)
+	}
+
+	tickSize, err := client.GetTickSize(ctx, "cond-1")
+	if err != nil {
+		t.Fatalf("GetTickSize failed: %v", err)
+	}
+	if tickSize != "0.01" {
+		t.Errorf("Expected tick size 0.01, got %s", tickSize)
+	}
+	if calls != 1 {
+		t.Errorf("Expected second lookup within TTL to be cached, got %d calls", calls)
+	}
+
+	client.InvalidateMarketMeta("cond-1")
+	if _, err := client.GetMarketMeta(ctx, "cond-1"); err != nil {
+		t.Fatalf("GetMarketMeta after invalidate failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected refetch after invalidation, got %d calls", calls)
+	}
+}
+
 func TestGetOpenOrdersNoCredentials(t *testing.T) {
 	client, _ := NewClient(testPrivateKey)
 
@@ -579,6 +639,131 @@ func TestPostOrder(t *testing.T) {
 	}
 }
 
+// fakeSigner is an external signer that returns a fixed signature.
+type fakeSigner struct {
+	address common.Address
+	sig     []byte
+	hashes  [][]byte
+}
+
+func (f *fakeSigner) SignHash(hash []byte) ([]byte, error) {
+	f.hashes = append(f.hashes, hash)
+	return f.sig, nil
+}
+
+func (f *fakeSigner) Address() common.Address {
+	return f.address
+}
+
+func TestExternalSigner(t *testing.T) {
+	signer := &fakeSigner{
+		address: common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		sig:     []byte(strings.Repeat("\xcd", 65)),
+	}
+
+	var posted SignedOrder
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodPost {
+			w.Write([]byte("[]"))
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			t.Errorf("Failed to decode order: %v", err)
+		}
+		json.NewEncoder(w).Encode(PostOrderResponse{OrderID: "ext-1", Success: true})
+	}))
+	defer server.Close()
+
+	client, err := NewClient("",
+		WithExternalSigner(signer),
+		WithCLOBBaseURL(server.URL),
+		WithCredentials(&APICredentials{
+			APIKey:     "test-key",
+			Secret:     "dGVzdC1zZWNyZXQ=",
+			Passphrase: "test-pass",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient with external signer failed: %v", err)
+	}
+
+	if client.Address() != signer.address.Hex() {
+		t.Errorf("Address should come from signer, got %s", client.Address())
+	}
+
+	_, err = client.CreateAndPostOrder(context.Background(), &OrderArgs{
+		TokenID: "12345",
+		Side:    OrderSideBuy,
+		Price:   0.5,
+		Size:    10,
+	}, "0.01", false)
+	if err != nil {
+		t.Fatalf("CreateAndPostOrder failed: %v", err)
+	}
+
+	if len(signer.hashes) != 1 || len(signer.hashes[0]) != 32 {
+		t.Fatalf("Expected one 32-byte hash signed, got %d", len(signer.hashes))
+	}
+	if posted.Signature != "0x"+strings.Repeat("cd", 65) {
+		t.Errorf("Posted order should carry the external signature, got %s", posted.Signature)
+	}
+	if posted.Order.Signer != signer.address.Hex() {
+		t.Errorf("Order signer should be external address, got %s", posted.Order.Signer)
+	}
+}
+
+func TestCreateAndPostOrderPostOnly(t *testing.T) {
+	posted := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/book":
+			json.NewEncoder(w).Encode(OrderBookSummary{
+				Bids: []PriceLevel{{Price: "0.48", Size: "100"}, {Price: "0.49", Size: "100"}},
+				Asks: []PriceLevel{{Price: "0.52", Size: "100"}, {Price: "0.51", Size: "100"}},
+			})
+		case "/order":
+			posted++
+			json.NewEncoder(w).Encode(PostOrderResponse{OrderID: "maker-1", Success: true})
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(testPrivateKey,
+		WithCLOBBaseURL(server.URL),
+		WithCredentials(&APICredentials{
+			APIKey:     "test-key",
+			Secret:     "dGVzdC1zZWNyZXQ=",
+			Passphrase: "test-pass",
+		}),
+	)
+	ctx := context.Background()
+
+	crossing := []*OrderArgs{
+		{TokenID: "12345", Side: OrderSideBuy, Price: 0.51, Size: 10, PostOnly: true},
+		{TokenID: "12345", Side: OrderSideSell, Price: 0.49, Size: 10, PostOnly: true},
+	}
+	for _, args := range crossing {
+		if _, err := client.CreateAndPostOrder(ctx, args, "0.01", false); err == nil || !strings.Contains(err.Error(), "post-only") {
+			t.Errorf("Expected post-only rejection for %s at %v, got %v", args.Side, args.Price, err)
+		}
+	}
+	if posted != 0 {
+		t.Errorf("Crossing post-only orders must not be posted, got %d posts", posted)
+	}
+
+	resp, err := client.CreateAndPostOrder(ctx, &OrderArgs{
+		TokenID: "12345", Side: OrderSideBuy, Price: 0.50, Size: 10, PostOnly: true,
+	}, "0.01", false)
+	if err != nil {
+		t.Fatalf("Non-crossing post-only order failed: %v", err)
+	}
+	if !resp.Success || posted != 1 {
+		t.Errorf("Expected non-crossing order to be posted, success=%v posts=%d", resp.Success, posted)
+	}
+}
+
 func TestCancelOrder(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "DELETE" {
@@ -647,6 +832,105 @@ func TestCancelOrderPartialFailure(t *testing.T) {
 	}
 }
 
+func TestCancelOrdersByMarket(t *testing.T) {
+	var canceled []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			json.NewEncoder(w).Encode([]Order{
+				{ID: "order-1", Market: "0xmarket-a", TokenID: "token-a"},
+				{ID: "order-2", Market: "0xmarket-b", TokenID: "token-b"},
+				{ID: "order-3", Market: "0xmarket-a", TokenID: "token-a-no"},
+			})
+		case "DELETE":
+			json.NewDecoder(r.Body).Decode(&canceled)
+			json.NewEncoder(w).Encode(CancelOrderResponse{Canceled: canceled})
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(testPrivateKey,
+		WithCLOBBaseURL(server.URL),
+		WithCredentials(&APICredentials{
+			APIKey:     "test-key",
+			Secret:     "dGVzdC1zZWNyZXQ=",
+			Passphrase: "test-pass",
+		}),
+	)
+
+	count, err := client.CancelOrdersByMarket(context.Background(), "0xmarket-a")
+	if err != nil {
+		t.Fatalf("CancelOrdersByMarket failed: %v", err)
+	}
+	if count != 2 || len(canceled) != 2 || canceled[0] != "order-1" || canceled[1] != "order-3" {
+		t.Errorf("Expected order-1 and order-3 canceled, got %d %v", count, canceled)
+	}
+
+	canceled = nil
+	count, err = client.CancelOrdersByToken(context.Background(), "token-b")
+	if err != nil {
+		t.Fatalf("CancelOrdersByToken failed: %v", err)
+	}
+	if count != 1 || len(canceled) != 1 || canceled[0] != "order-2" {
+		t.Errorf("Expected order-2 canceled, got %d %v", count, canceled)
+	}
+}
+
+func TestCancelAllOrdersVerified(t *testing.T) {
+	var mu sync.Mutex
+	open := []Order{{ID: "order-1"}, {ID: "order-2"}, {ID: "order-3"}}
+	var retried []string
+	refuse := false // The venue accepts cancels but cancels nothing
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET":
+			json.NewEncoder(w).Encode(open)
+		case refuse:
+			w.Write([]byte(`{}`))
+		case r.URL.Path == "/orders/all":
+			// The venue only gets through part of the book
+			open = open[2:]
+			w.Write([]byte(`{}`))
+		default:
+			json.NewDecoder(r.Body).Decode(&retried)
+			open = nil
+			json.NewEncoder(w).Encode(CancelOrderResponse{Canceled: retried})
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(testPrivateKey,
+		WithCLOBBaseURL(server.URL),
+		WithCredentials(&APICredentials{
+			APIKey:     "test-key",
+			Secret:     "dGVzdC1zZWNyZXQ=",
+			Passphrase: "test-pass",
+		}),
+	)
+
+	if err := client.CancelAllOrdersVerified(context.Background(), 2); err != nil {
+		t.Fatalf("CancelAllOrdersVerified failed: %v", err)
+	}
+	mu.Lock()
+	if len(retried) != 1 || retried[0] != "order-3" {
+		t.Errorf("Expected the straggler order-3 to be retried, got %v", retried)
+	}
+
+	// A straggler the venue refuses to cancel is reported
+	open = []Order{{ID: "order-4"}}
+	refuse = true
+	mu.Unlock()
+
+	err := client.CancelAllOrdersVerified(context.Background(), 1)
+	if !errors.Is(err, ErrOrdersRemain) || !strings.Contains(err.Error(), "order-4") {
+		t.Errorf("Expected ErrOrdersRemain naming order-4, got %v", err)
+	}
+}
+
 func TestAPIError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
@@ -666,6 +950,107 @@ func TestAPIError(t *testing.T) {
 	}
 }
 
+func TestAPIErrorTypes(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"balance", http.StatusBadRequest, `{"error": "not enough balance / allowance"}`, ErrInsufficientBalance},
+		{"closed", http.StatusBadRequest, `{"error": "the market is not yet ready to process new orders"}`, ErrMarketClosed},
+		{"closed plain", http.StatusBadRequest, `market is closed`, ErrMarketClosed},
+		{"rate limited", http.StatusTooManyRequests, `Too Many Requests`, ErrRateLimited},
+		{"auth", http.StatusUnauthorized, `{"error": "Unauthorized/Invalid api key"}`, ErrAuthRequired},
+		{"tick", http.StatusBadRequest, `{"error": "order 0x1 is invalid. Price (0.123) breaks minimum tick size rule: 0.01"}`, ErrInvalidTick},
+		{"unknown", http.StatusBadRequest, `{"error": "invalid token_id"}`, nil},
+	}
+	kinds := []error{ErrInsufficientBalance, ErrMarketClosed, ErrRateLimited, ErrAuthRequired, ErrInvalidTick}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client, _ := NewClient(testPrivateKey, WithCLOBBaseURL(server.URL))
+			_, err := client.GetOrderBook(context.Background(), "token")
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Fatalf("Expected *APIError with status %d, got %v", tt.status, err)
+			}
+			for _, kind := range kinds {
+				if got := errors.Is(err, kind); got != (kind == tt.want) {
+					t.Errorf("errors.Is(%v) = %v for %v", kind, got, err)
+				}
+			}
+		})
+	}
+
+	// Client-side credential checks are typed too
+	client, _ := NewClient(testPrivateKey)
+	if _, err := client.GetOpenOrders(context.Background()); !errors.Is(err, ErrAuthRequired) {
+		t.Errorf("Expected ErrAuthRequired without credentials, got %v", err)
+	}
+}
+
+func TestGzipResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("Expected Accept-Encoding gzip, got %q", r.Header.Get("Accept-Encoding"))
+		}
+
+		book := OrderBookSummary{TokenID: "token123"}
+		for i := 0; i < 500; i++ {
+			book.Bids = append(book.Bids, PriceLevel{Price: "0.50", Size: "100"})
+			book.Asks = append(book.Asks, PriceLevel{Price: "0.51", Size: "100"})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		json.NewEncoder(gz).Encode(book)
+		gz.Close()
+	}))
+	defer server.Close()
+
+	client := NewPublicClient(WithCLOBBaseURL(server.URL))
+
+	book, err := client.GetOrderBook(context.Background(), "token123")
+	if err != nil {
+		t.Fatalf("GetOrderBook failed: %v", err)
+	}
+
+	if book.TokenID != "token123" {
+		t.Errorf("Wrong token ID: %s", book.TokenID)
+	}
+
+	if len(book.Bids) != 500 || len(book.Asks) != 500 {
+		t.Errorf("Expected 500 bids and asks, got %d/%d", len(book.Bids), len(book.Asks))
+	}
+}
+
+func TestGzipErrorKeepsStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Claims gzip but isn't: the status must still surface
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("Too Many Requests"))
+	}))
+	defer server.Close()
+
+	client := NewPublicClient(WithCLOBBaseURL(server.URL))
+
+	_, err := client.GetOrderBook(context.Background(), "token123")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected *APIError with status 429, got %v", err)
+	}
+}
+
 // --- Integration Tests ---
 
 func TestIntegrationGetOrderBook(t *testing.T) {
@@ -759,7 +1144,7 @@ func TestIntegrationCreateAPIKey(t *testing.T) {
 		t.Fatalf("CreateOrDeriveAPIKey failed: %v", err)
 	}
 
-	t.Logf("API Key: %s", creds.APIKey[:10]+"...")
+	t.Logf("API Key: %s", redact.Mask(creds.APIKey))
 	t.Logf("Has Secret: %v", creds.Secret != "")
 	t.Logf("Has Passphrase: %v", creds.Passphrase != "")
 
@@ -767,3 +1152,638 @@ func TestIntegrationCreateAPIKey(t *testing.T) {
 		t.Error("Client should have credentials after CreateOrDeriveAPIKey")
 	}
 }
+
+func TestCreateAndPostOrderWarnsOnSelfCross(t *testing.T) {
+	posted := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/orders":
+			json.NewEncoder(w).Encode([]Order{
+				{ID: "own-bid", TokenID: "12345", Side: OrderSideBuy, Price: "0.45"},
+				{ID: "other-token", TokenID: "99999", Side: OrderSideBuy, Price: "0.60"},
+			})
+		case "/order":
+			posted++
+			json.NewEncoder(w).Encode(PostOrderResponse{OrderID: "new-1", Success: true})
+		}
+	}))
+	defer server.Close()
+
+	var warned []string
+	client, _ := NewClient(testPrivateKey,
+		WithCLOBBaseURL(server.URL),
+		WithCredentials(&APICredentials{
+			APIKey:     "test-key",
+			Secret:     "dGVzdC1zZWNyZXQ=",
+			Passphrase: "test-pass",
+		}),
+		WithSelfCrossHandler(func(args *OrderArgs, resting Order) {
+			warned = append(warned, resting.ID)
+		}),
+	)
+	ctx := context.Background()
+
+	// Above our own bid: no warning
+	if _, err := client.CreateAndPostOrder(ctx, &OrderArgs{
+		TokenID: "12345", Side: OrderSideSell, Price: 0.50, Size: 10,
+	}, "0.01", false); err != nil {
+		t.Fatalf("CreateAndPostOrder failed: %v", err)
+	}
+	if len(warned) != 0 {
+		t.Errorf("Non-crossing sell should not warn, got %v", warned)
+	}
+
+	// At our own bid: warns but still posts
+	if _, err := client.CreateAndPostOrder(ctx, &OrderArgs{
+		TokenID: "12345", Side: OrderSideSell, Price: 0.45, Size: 10,
+	}, "0.01", false); err != nil {
+		t.Fatalf("CreateAndPostOrder failed: %v", err)
+	}
+	if len(warned) != 1 || warned[0] != "own-bid" {
+		t.Errorf("Expected warning for own-bid, got %v", warned)
+	}
+	if posted != 2 {
+		t.Errorf("Expected both orders posted, got %d", posted)
+	}
+}
+
+func TestSelfCrossCheckIsOptIn(t *testing.T) {
+	fetched := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/orders":
+			fetched++
+			json.NewEncoder(w).Encode([]Order{})
+		case "/order":
+			json.NewEncoder(w).Encode(PostOrderResponse{OrderID: "new-1", Success: true})
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(testPrivateKey,
+		WithCLOBBaseURL(server.URL),
+		WithCredentials(&APICredentials{
+			APIKey:     "test-key",
+			Secret:     "dGVzdC1zZWNyZXQ=",
+			Passphrase: "test-pass",
+		}),
+	)
+
+	if _, err := client.CreateAndPostOrder(context.Background(), &OrderArgs{
+		TokenID: "12345", Side: OrderSideSell, Price: 0.45, Size: 10,
+	}, "0.01", false); err != nil {
+		t.Fatalf("CreateAndPostOrder failed: %v", err)
+	}
+	if fetched != 0 {
+		t.Errorf("Expected no open-orders fetch without a handler, got %d", fetched)
+	}
+}
+
+func TestWithProxyAndTLSConfig(t *testing.T) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS13}
+	custom := &http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{MaxIdleConns: 3}}
+
+	// Order must not matter: the custom client is set after the proxy option
+	client := NewPublicClient(
+		WithProxy("http://proxy.internal:3128"),
+		WithTLSConfig(tlsConfig),
+		WithCLOBHTTPClient(custom),
+	)
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected *http.Transport, got %T", client.httpClient.Transport)
+	}
+	if transport.Proxy == nil {
+		t.Fatal("Proxy function not set")
+	}
+	req, _ := http.NewRequest("GET", DefaultBaseURL+"/book", nil)
+	proxyURL, err := transport.Proxy(req)
+	if err != nil || proxyURL == nil || proxyURL.Host != "proxy.internal:3128" {
+		t.Errorf("Expected proxy.internal:3128, got %v (err %v)", proxyURL, err)
+	}
+	if transport.TLSClientConfig != tlsConfig {
+		t.Error("TLS config not applied")
+	}
+	if transport.MaxIdleConns != 3 || client.httpClient.Timeout != 5*time.Second {
+		t.Error("Custom client settings should be preserved")
+	}
+	if custom.Transport.(*http.Transport).Proxy != nil {
+		t.Error("Caller's transport must not be mutated")
+	}
+
+	bad := NewPublicClient(WithProxy("::not a url"))
+	if _, err := bad.httpClient.Transport.(*http.Transport).Proxy(req); err == nil {
+		t.Error("Invalid proxy URL should fail requests")
+	}
+
+	// A custom RoundTripper can't take the settings
+	roundTripper := &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) { return nil, nil })}
+	if _, err := NewClient(testPrivateKey, WithCLOBHTTPClient(roundTripper), WithTLSConfig(tlsConfig)); err == nil {
+		t.Error("Expected TLS config with a custom RoundTripper to be rejected")
+	}
+	if _, err := NewClient(testPrivateKey, WithCLOBHTTPClient(roundTripper)); err != nil {
+		t.Errorf("Custom RoundTripper without transport options should be accepted, got %v", err)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestWithTransportConfig(t *testing.T) {
+	config := httpx.DefaultTransportConfig()
+	config.MaxIdleConnsPerHost = 50
+	config.MaxConnsPerHost = 60
+	config.IdleConnTimeout = 3 * time.Minute
+
+	client := NewPublicClient(WithProxy("http://proxy.internal:3128"), WithTransportConfig(config))
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected *http.Transport, got %T", client.httpClient.Transport)
+	}
+	if transport.MaxIdleConnsPerHost != 50 || transport.MaxConnsPerHost != 60 || transport.IdleConnTimeout != 3*time.Minute {
+		t.Errorf("Transport config not applied: idle/host %d, conns/host %d, idle timeout %s",
+			transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost, transport.IdleConnTimeout)
+	}
+	if transport.Proxy == nil {
+		t.Error("WithProxy should still apply on top of the transport config")
+	}
+	if client.httpClient.Timeout != 30*time.Second {
+		t.Errorf("Client timeout should be kept, got %s", client.httpClient.Timeout)
+	}
+}
+
+func TestClockSkewCorrection(t *testing.T) {
+	creds := &APICredentials{
+		APIKey:     "test-key",
+		Secret:     "dGVzdC1zZWNyZXQ=",
+		Passphrase: "test-pass",
+	}
+	skewed := func() time.Time { return time.Now().Add(-5 * time.Minute) }
+
+	// The server rejects timestamps more than 5s off its clock
+	var rejectAll bool
+	var signedAt int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/time" {
+			fmt.Fprint(w, time.Now().Unix())
+			return
+		}
+		signedAt, _ = strconv.ParseInt(r.Header.Get("POLY_TIMESTAMP"), 10, 64)
+		if rejectAll || time.Since(time.Unix(signedAt, 0)).Abs() > 5*time.Second {
+			http.Error(w, `{"error":"Unauthorized/Invalid api key"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	t.Run("corrected", func(t *testing.T) {
+		client, _ := NewClient(testPrivateKey,
+			WithCLOBBaseURL(server.URL),
+			WithCredentials(creds),
+			WithCLOBClock(skewed),
+			WithClockSkewCorrection(),
+		)
+		if _, err := client.GetOpenOrders(context.Background()); err != nil {
+			t.Fatalf("GetOpenOrders failed: %v", err)
+		}
+		if drift := time.Since(time.Unix(signedAt, 0)).Abs(); drift > 2*time.Second {
+			t.Errorf("Signed timestamp is %s off server time, expected the corrected clock", drift)
+		}
+		if offset := client.ClockOffset(); offset < 4*time.Minute || offset > 6*time.Minute {
+			t.Errorf("Expected ~5m offset, got %s", offset)
+		}
+	})
+
+	t.Run("uncorrected", func(t *testing.T) {
+		client, _ := NewClient(testPrivateKey,
+			WithCLOBBaseURL(server.URL),
+			WithCredentials(creds),
+			WithCLOBClock(skewed),
+		)
+		_, err := client.GetOpenOrders(context.Background())
+		if !errors.Is(err, ErrClockSkew) {
+			t.Errorf("Expected ErrClockSkew, got %v", err)
+		}
+	})
+
+	t.Run("bad signature", func(t *testing.T) {
+		rejectAll = true
+		defer func() { rejectAll = false }()
+
+		client, _ := NewClient(testPrivateKey,
+			WithCLOBBaseURL(server.URL),
+			WithCredentials(creds),
+		)
+		_, err := client.GetOpenOrders(context.Background())
+		if !errors.Is(err, ErrAuthSignature) || !errors.Is(err, ErrAuthRequired) || errors.Is(err, ErrClockSkew) {
+			t.Errorf("Expected ErrAuthSignature, got %v", err)
+		}
+	})
+
+	t.Run("failed sync backs off", func(t *testing.T) {
+		var timeCalls int
+		down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/time" {
+				timeCalls++
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`[]`))
+		}))
+		defer down.Close()
+
+		now := time.Now()
+		client, _ := NewClient(testPrivateKey,
+			WithCLOBBaseURL(down.URL),
+			WithCredentials(creds),
+			WithCLOBClock(func() time.Time { return now }),
+			WithClockSkewCorrection(),
+		)
+		ctx := context.Background()
+		for i := 0; i < 3; i++ {
+			if _, err := client.GetOpenOrders(ctx); err != nil {
+				t.Fatalf("GetOpenOrders failed: %v", err)
+			}
+		}
+		if timeCalls != 1 {
+			t.Errorf("Expected one sync attempt within the backoff, got %d", timeCalls)
+		}
+
+		now = now.Add(clockSyncBackoff)
+		if _, err := client.GetOpenOrders(ctx); err != nil {
+			t.Fatalf("GetOpenOrders failed: %v", err)
+		}
+		if timeCalls != 2 {
+			t.Errorf("Expected a retry once the backoff passed, got %d attempts", timeCalls)
+		}
+	})
+}
+
+func TestAdaptiveRateLimit(t *testing.T) {
+	remaining := "1"
+	retryAfter := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", remaining)
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		fmt.Fprint(w, time.Now().Unix())
+	}))
+	defer server.Close()
+
+	client := NewPublicClient(WithCLOBBaseURL(server.URL), WithCLOBRateLimit(100, 1))
+	ctx := context.Background()
+
+	// Near exhaustion: 1 of 10 low-water requests left
+	if _, err := client.GetServerTime(ctx); err != nil {
+		t.Fatalf("GetServerTime failed: %v", err)
+	}
+	if got := client.RateLimit(); got != 10 {
+		t.Errorf("Expected rate tightened to 10/s, got %v", got)
+	}
+
+	// The tightened limiter spaces out a burst: 3 more requests take ~200ms
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetServerTime(ctx); err != nil {
+			t.Fatalf("GetServerTime failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("Expected tightened limiter to slow requests, took %v", elapsed)
+	}
+
+	// Recovery loosens gradually back to the configured rate
+	remaining = "500"
+	client.GetServerTime(ctx)
+	if got := client.RateLimit(); got != 20 {
+		t.Errorf("Expected rate to double to 20/s, got %v", got)
+	}
+	for i := 0; i < 5; i++ {
+		client.GetServerTime(ctx)
+	}
+	if got := client.RateLimit(); got != 100 {
+		t.Errorf("Expected rate restored to 100/s, got %v", got)
+	}
+
+	// Retry-After pauses requests and drops to the floor
+	retryAfter = "30"
+	client.GetServerTime(ctx)
+	if got := client.RateLimit(); got != 100*minRateFraction {
+		t.Errorf("Expected floor rate after Retry-After, got %v", got)
+	}
+	shortCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetServerTime(shortCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected request to wait out Retry-After, got %v", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{"soon", 0, false},
+	}
+	for _, tc := range cases {
+		got, ok := parseRetryAfter(tc.value, now)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tc.value, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestCredentialsRedacted(t *testing.T) {
+	creds := &APICredentials{
+		APIKey:     "3f2a9c1e-0b7d-4e5f-8a6b-1c2d3e4f5a6b",
+		Secret:     "c2VjcmV0LXNlY3JldC1zZWNyZXQ=",
+		Passphrase: "passphrase-123",
+	}
+
+	// A server that echoes the request's credentials back in its error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, fmt.Sprintf("bad request for key %s secret %s passphrase %s",
+			r.Header.Get("POLY_API_KEY"), creds.Secret, r.Header.Get("POLY_PASSPHRASE")), http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(testPrivateKey, WithCLOBBaseURL(server.URL), WithCredentials(creds))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	_, err = client.GetOpenOrders(context.Background())
+	if err == nil {
+		t.Fatal("Expected an API error")
+	}
+
+	for name, dump := range map[string]string{
+		"error": err.Error(),
+		"%v":    fmt.Sprintf("%v", creds),
+		"%+v":   fmt.Sprintf("%+v", *creds),
+		"%#v":   fmt.Sprintf("%#v", creds),
+	} {
+		for _, secret := range []string{creds.APIKey, creds.Secret, creds.Passphrase} {
+			if strings.Contains(dump, secret) {
+				t.Errorf("%s leaks %q: %s", name, secret, dump)
+			}
+		}
+	}
+}
+
+func TestPrepareAndCommitOrder(t *testing.T) {
+	var posts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/book":
+			json.NewEncoder(w).Encode(OrderBookSummary{
+				Asks: []PriceLevel{{Price: "0.52", Size: "40"}, {Price: "0.50", Size: "30"}, {Price: "0.60", Size: "100"}},
+			})
+		case "/order":
+			posts++
+			json.NewEncoder(w).Encode(PostOrderResponse{Success: true, OrderID: fmt.Sprintf("order-%d", posts)})
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	client, err := NewClient(testPrivateKey,
+		WithCLOBBaseURL(server.URL),
+		WithCredentials(&APICredentials{APIKey: "key", Secret: "c2VjcmV0", Passphrase: "pass"}),
+		WithCLOBClock(func() time.Time { return now }),
+		WithPrepareTTL(time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	ctx := context.Background()
+	args := &OrderArgs{TokenID: "123", Side: OrderSideBuy, Price: 0.55, Size: 50}
+
+	t.Run("commit", func(t *testing.T) {
+		prepared, err := client.PrepareOrder(ctx, args, "0.01", false)
+		if err != nil {
+			t.Fatalf("PrepareOrder failed: %v", err)
+		}
+		if posts != 0 {
+			t.Fatal("Prepare must not post the order")
+		}
+		// 30 @ 0.50 then 20 @ 0.52; the 0.60 level is above the limit
+		if prepared.FillSize != 50 || math.Abs(prepared.AvgPrice-0.508) > 1e-9 {
+			t.Errorf("Expected 50 @ 0.508, got %v @ %v", prepared.FillSize, prepared.AvgPrice)
+		}
+
+		resp, err := client.CommitOrder(ctx, prepared.ID)
+		if err != nil {
+			t.Fatalf("CommitOrder failed: %v", err)
+		}
+		if posts != 1 || resp.OrderID != "order-1" {
+			t.Errorf("Expected one post, got %d (%+v)", posts, resp)
+		}
+
+		if _, err := client.CommitOrder(ctx, prepared.ID); !errors.Is(err, ErrPreparedOrderNotFound) {
+			t.Errorf("Expected a second commit to fail with ErrPreparedOrderNotFound, got %v", err)
+		}
+	})
+
+	t.Run("expire", func(t *testing.T) {
+		posts = 0
+		prepared, err := client.PrepareOrder(ctx, args, "0.01", false)
+		if err != nil {
+			t.Fatalf("PrepareOrder failed: %v", err)
+		}
+
+		now = now.Add(time.Minute + time.Second)
+		if _, err := client.CommitOrder(ctx, prepared.ID); !errors.Is(err, ErrPreparedOrderExpired) {
+			t.Errorf("Expected ErrPreparedOrderExpired, got %v", err)
+		}
+		if posts != 0 {
+			t.Errorf("Expired order must not be posted, got %d posts", posts)
+		}
+	})
+}
+
+func TestPlanSettlement(t *testing.T) {
+	market := &MarketInfo{
+		ConditionID: "0x" + strings.Repeat("ab", 32),
+		Tokens: []Token{
+			{TokenID: "yes-token", Outcome: "Yes"},
+			{TokenID: "no-token", Outcome: "No"},
+		},
+	}
+	selector := func(signature string) string {
+		return hexutil.Encode(crypto.Keccak256([]byte(signature))[:4])
+	}
+
+	// Open market with both sides held: merge the matched pairs
+	merge, err := PlanSettlement(market, map[string]float64{"yes-token": 120, "no-token": 75.5})
+	if err != nil || merge == nil {
+		t.Fatalf("Expected a merge, got %+v (err %v)", merge, err)
+	}
+	if merge.Kind != SettlementMerge || merge.Shares != 75.5 || merge.Payout != 75.5 {
+		t.Errorf("Expected merge of 75.5 pairs, got %+v", merge)
+	}
+	if merge.To != eth.ConditionalTokensAddress.Hex() ||
+		!strings.HasPrefix(merge.Data, selector("mergePositions(address,bytes32,bytes32,uint256[],uint256)")) {
+		t.Errorf("Expected CTF mergePositions call, got to=%s data=%.10s", merge.To, merge.Data)
+	}
+
+	// One side only: nothing to merge
+	if s, err := PlanSettlement(market, map[string]float64{"yes-token": 10}); s != nil || err != nil {
+		t.Errorf("Expected no settlement for a one-sided holding, got %+v (err %v)", s, err)
+	}
+
+	// Resolved NO: redeem, paying only the NO shares
+	market.Closed = true
+	market.Tokens[1].Winner = true
+	redeem, err := PlanSettlement(market, map[string]float64{"yes-token": 10, "no-token": 4})
+	if err != nil || redeem == nil || redeem.Kind != SettlementRedeem || redeem.Payout != 4 {
+		t.Fatalf("Expected redemption paying 4, got %+v (err %v)", redeem, err)
+	}
+	if !strings.HasPrefix(redeem.Data, selector("redeemPositions(address,bytes32,bytes32,uint256[])")) {
+		t.Errorf("Expected CTF redeemPositions call, got %.10s", redeem.Data)
+	}
+
+	// Neg-risk markets go through the adapter
+	market.NegRisk = true
+	redeem, _ = PlanSettlement(market, map[string]float64{"no-token": 4})
+	if redeem.To != eth.NegRiskAdapterAddress.Hex() ||
+		!strings.HasPrefix(redeem.Data, selector("redeemPositions(bytes32,uint256[])")) {
+		t.Errorf("Expected NegRiskAdapter redeemPositions call, got to=%s data=%.10s", redeem.To, redeem.Data)
+	}
+}
+
+func TestBinaryOutcomesByName(t *testing.T) {
+	market := &MarketInfo{
+		ConditionID: "cond",
+		NegRisk:     true,
+		Tokens: []Token{
+			{TokenID: "no-token", Outcome: "No"},
+			{TokenID: "yes-token", Outcome: "Yes"},
+		},
+	}
+	yes, no, err := binaryOutcomes(market)
+	if err != nil || yes.TokenID != "yes-token" || no.TokenID != "no-token" {
+		t.Errorf("Expected YES/NO picked by outcome, got %s/%s (err %v)", yes.TokenID, no.TokenID, err)
+	}
+
+	market.Tokens = []Token{{TokenID: "a", Outcome: "Lakers"}, {TokenID: "b", Outcome: "Celtics"}}
+	if _, _, err := binaryOutcomes(market); err == nil {
+		t.Error("Expected neg-risk market without Yes/No outcomes to be rejected")
+	}
+	market.NegRisk = false
+	if first, second, err := binaryOutcomes(market); err != nil || first.TokenID != "a" || second.TokenID != "b" {
+		t.Errorf("Expected named outcomes in listed order, got %s/%s (err %v)", first.TokenID, second.TokenID, err)
+	}
+}
+
+func TestSubmitLegsRollsBackOnFailedLeg(t *testing.T) {
+	var mu sync.Mutex
+	var cancelled, fetched []string
+	cancelFails := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/markets/"):
+			conditionID := strings.TrimPrefix(r.URL.Path, "/markets/")
+			fetched = append(fetched, conditionID)
+			market := MarketInfo{ConditionID: conditionID, MinimumTickSize: "0.01", Tokens: []Token{{TokenID: "111"}}}
+			if conditionID == "neg-risk" {
+				market.MinimumTickSize, market.NegRisk = "0.001", true
+				market.Tokens = []Token{{TokenID: "222"}}
+			}
+			json.NewEncoder(w).Encode(market)
+		case r.URL.Path == "/order":
+			var posted SignedOrder
+			json.NewDecoder(r.Body).Decode(&posted)
+			if posted.Order.TokenID == "222" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": "not enough balance / allowance"}`))
+				return
+			}
+			json.NewEncoder(w).Encode(PostOrderResponse{OrderID: "order-" + posted.Order.TokenID, Success: true})
+		case r.Method == "DELETE" && r.URL.Path == "/orders":
+			if cancelFails {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			var ids []string
+			json.NewDecoder(r.Body).Decode(&ids)
+			cancelled = append(cancelled, ids...)
+			json.NewEncoder(w).Encode(CancelOrderResponse{Canceled: ids})
+		case strings.HasPrefix(r.URL.Path, "/orders/"):
+			json.NewEncoder(w).Encode(Order{ID: strings.TrimPrefix(r.URL.Path, "/orders/"), Status: OrderStatusCancelled, SizeFilled: "0"})
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(testPrivateKey,
+		WithCLOBBaseURL(server.URL),
+		WithCredentials(&APICredentials{
+			APIKey:     "test-key",
+			Secret:     "dGVzdC1zZWNyZXQ=",
+			Passphrase: "test-pass",
+		}),
+	)
+	legs := []*Leg{
+		{OrderArgs: OrderArgs{TokenID: "111", Side: OrderSideBuy, Price: 0.40, Size: 10}, ConditionID: "binary"},
+		{OrderArgs: OrderArgs{TokenID: "222", Side: OrderSideBuy, Price: 0.555, Size: 10}, ConditionID: "neg-risk"},
+	}
+
+	result, err := client.SubmitLegs(context.Background(), legs, LegPolicy{})
+	if !errors.Is(err, ErrLegsFailed) {
+		t.Fatalf("Expected ErrLegsFailed, got %v", err)
+	}
+	mu.Lock()
+	if len(fetched) != 2 || fetched[0] != "binary" || fetched[1] != "neg-risk" {
+		t.Errorf("Expected each leg's market looked up, got %v", fetched)
+	}
+	mu.Unlock()
+	if result.Legs[0].OrderID != "order-111" || result.Legs[1].OrderID != "" || result.Legs[1].Error == "" {
+		t.Fatalf("Expected leg 0 accepted and leg 1 rejected, got %+v", result.Legs)
+	}
+	if !result.RolledBack || len(cancelled) != 1 || cancelled[0] != "order-111" {
+		t.Errorf("Expected the accepted leg to be cancelled, rolled back %v, cancelled %v", result.RolledBack, cancelled)
+	}
+	if order := result.Legs[0].Order; order == nil || order.Status != OrderStatusCancelled {
+		t.Errorf("Expected leg 0 reconciled as cancelled, got %+v", order)
+	}
+
+	// Best effort keeps the accepted leg
+	cancelled = nil
+	result, err = client.SubmitLegs(context.Background(), legs, LegPolicy{BestEffort: true})
+	if !errors.Is(err, ErrLegsFailed) || result.RolledBack || len(cancelled) != 0 {
+		t.Errorf("Expected no rollback in best effort, got err %v, rolled back %v, cancelled %v", err, result.RolledBack, cancelled)
+	}
+
+	// A rollback whose cancel fails is not reported as rolled back
+	mu.Lock()
+	cancelFails = true
+	mu.Unlock()
+	result, err = client.SubmitLegs(context.Background(), legs, LegPolicy{})
+	if !errors.Is(err, ErrLegsFailed) || result.RolledBack || result.RollbackError == "" {
+		t.Errorf("Expected a failed rollback, got err %v, rolled back %v, rollback error %q", err, result.RolledBack, result.RollbackError)
+	}
+
+	// A leg whose token isn't in its market sends nothing
+	mismatched := []*Leg{{OrderArgs: OrderArgs{TokenID: "222", Side: OrderSideBuy, Price: 0.5, Size: 10}, ConditionID: "binary"}}
+	if result, err := client.SubmitLegs(context.Background(), mismatched, LegPolicy{}); result != nil || err == nil || !strings.Contains(err.Error(), "not in market") {
+		t.Errorf("Expected the mismatched leg to be refused, got %+v (err %v)", result, err)
+	}
+}
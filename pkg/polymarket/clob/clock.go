@@ -0,0 +1,136 @@
+package clob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// DefaultMaxClockSkew is how far the local clock may drift from the server's
+// before a rejected signature is blamed on the clock.
+const DefaultMaxClockSkew = 10 * time.Second
+
+// clockSyncBackoff is how long signing waits after a failed sync before
+// trying /time again, so an unreachable endpoint doesn't cost a request (and
+// a log line) per signed call.
+const clockSyncBackoff = time.Minute
+
+var (
+	// ErrClockSkew means a signed request was rejected and the local clock is
+	// more than the acceptable skew away from server time.
+	ErrClockSkew = errors.New("clock skew")
+
+	// ErrAuthSignature means a signed request was rejected with the clock in
+	// sync, so the credentials or signature are at fault.
+	ErrAuthSignature = errors.New("auth signature rejected")
+)
+
+// WithCLOBClock sets the local clock used for signing timestamps.
+func WithCLOBClock(now func() time.Time) ClientOption {
+	return func(c *Client) {
+		c.now = now
+	}
+}
+
+// WithClockSkewCorrection measures the offset to server time before the
+// first signed request, and again after a clock-skew rejection, and applies
+// it to signing timestamps.
+func WithClockSkewCorrection() ClientOption {
+	return func(c *Client) {
+		c.skewCorrection = true
+	}
+}
+
+// WithMaxClockSkew sets the acceptable skew. See DefaultMaxClockSkew.
+func WithMaxClockSkew(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxSkew = d
+	}
+}
+
+// GetServerTime fetches the server's current time (second resolution).
+func (c *Client) GetServerTime(ctx context.Context) (time.Time, error) {
+	var ts int64
+	if err := c.get(ctx, "/time", nil, nil, &ts); err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(ts, 0), nil
+}
+
+// SyncClock measures the offset from the local clock to server time and
+// applies it to subsequent signing timestamps.
+func (c *Client) SyncClock(ctx context.Context) (time.Duration, error) {
+	offset, err := c.measureClockOffset(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("sync clock: %w", err)
+	}
+	c.setClockOffset(offset)
+	return offset, nil
+}
+
+// ClockOffset returns the offset applied to signing timestamps, zero until
+// the clock has been synced.
+func (c *Client) ClockOffset() time.Duration {
+	c.clockMu.Lock()
+	defer c.clockMu.Unlock()
+	return c.clockOffset
+}
+
+func (c *Client) setClockOffset(offset time.Duration) {
+	c.clockMu.Lock()
+	defer c.clockMu.Unlock()
+	c.clockOffset = offset
+	c.clockSynced = true
+}
+
+// measureClockOffset compares server time against the local clock at the
+// midpoint of the request.
+func (c *Client) measureClockOffset(ctx context.Context) (time.Duration, error) {
+	before := c.now()
+	server, err := c.GetServerTime(ctx)
+	if err != nil {
+		return 0, err
+	}
+	after := c.now()
+	local := before.Add(after.Sub(before) / 2)
+	return server.Sub(local).Truncate(time.Second), nil
+}
+
+// signingTime is the timestamp for auth headers: the local clock plus the
+// server offset, syncing first if correction is on and it hasn't happened.
+// A failed sync is retried no sooner than clockSyncBackoff later.
+func (c *Client) signingTime(ctx context.Context) time.Time {
+	if c.skewCorrection {
+		c.clockMu.Lock()
+		due := !c.clockSynced && !c.now().Before(c.clockRetryAt)
+		c.clockMu.Unlock()
+		if due {
+			if _, err := c.SyncClock(ctx); err != nil {
+				c.clockMu.Lock()
+				c.clockRetryAt = c.now().Add(clockSyncBackoff)
+				c.clockMu.Unlock()
+				log.Printf("[CLOB] %v; signing with the local clock for %s", err, clockSyncBackoff)
+			}
+		}
+	}
+	return c.now().Add(c.ClockOffset())
+}
+
+// authError explains a 401 on a signed request. If the signing clock is off
+// server time by more than the acceptable skew, the clock is to blame.
+func (c *Client) authError(ctx context.Context, body []byte) error {
+	applied := c.ClockOffset()
+	if offset, err := c.measureClockOffset(ctx); err == nil {
+		skew := offset - applied
+		if skew.Abs() > c.maxSkew {
+			if c.skewCorrection {
+				c.setClockOffset(offset)
+			}
+			return fmt.Errorf("%w: signing clock is %s off server time: %w", ErrClockSkew, skew, newAPIError(http.StatusUnauthorized, c.scrub(body)))
+		}
+	}
+	return fmt.Errorf("%w: %w", ErrAuthSignature, newAPIError(http.StatusUnauthorized, c.scrub(body)))
+}
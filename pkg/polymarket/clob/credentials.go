@@ -0,0 +1,58 @@
+package clob
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Environment variables read by CredentialsFromEnv.
+const (
+	EnvAPIKey        = "POLYMARKET_API_KEY"
+	EnvAPISecret     = "POLYMARKET_API_SECRET"
+	EnvAPIPassphrase = "POLYMARKET_API_PASSPHRASE"
+)
+
+// CredentialsFromEnv returns L2 API credentials from the environment, or nil
+// if any of the three variables is unset.
+func CredentialsFromEnv() *APICredentials {
+	creds := &APICredentials{
+		APIKey:     os.Getenv(EnvAPIKey),
+		Secret:     os.Getenv(EnvAPISecret),
+		Passphrase: os.Getenv(EnvAPIPassphrase),
+	}
+	if creds.APIKey == "" || creds.Secret == "" || creds.Passphrase == "" {
+		return nil
+	}
+	return creds
+}
+
+// SaveCredentials writes L2 API credentials to path as JSON, readable only
+// by the owner.
+func SaveCredentials(path string, creds *APICredentials) error {
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode credentials: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write credentials: %w", err)
+	}
+	return nil
+}
+
+// LoadCredentials reads L2 API credentials written by SaveCredentials.
+func LoadCredentials(path string) (*APICredentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read credentials: %w", err)
+	}
+	var creds APICredentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("decode credentials: %w", err)
+	}
+	if creds.APIKey == "" || creds.Secret == "" || creds.Passphrase == "" {
+		return nil, errors.New("credentials file is missing apiKey, secret, or passphrase")
+	}
+	return &creds, nil
+}
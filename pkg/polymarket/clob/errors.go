@@ -0,0 +1,105 @@
+package clob
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Typed errors for rejected requests. API responses are returned as
+// *APIError, which matches the relevant one under errors.Is.
+var (
+	// ErrInsufficientBalance means the wallet lacks the balance or allowance
+	// for the order.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+
+	// ErrMarketClosed means the market no longer (or does not yet) accept
+	// orders.
+	ErrMarketClosed = errors.New("market closed")
+
+	// ErrRateLimited means the request was throttled (HTTP 429).
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrAuthRequired means the request needs credentials that are missing or
+	// were rejected. ErrClockSkew and ErrAuthSignature rejections match it too.
+	ErrAuthRequired = errors.New("authentication required")
+
+	// ErrInvalidTick means the order price breaks the market's tick size.
+	ErrInvalidTick = errors.New("invalid tick size")
+)
+
+// errNoCredentials is returned by L2 endpoints called without credentials.
+var errNoCredentials = fmt.Errorf("%w: L2 credentials required", ErrAuthRequired)
+
+// ErrOrdersRemain is returned by CancelAllOrdersVerified when orders are
+// still open after every retry.
+var ErrOrdersRemain = errors.New("orders remain open")
+
+// APIError is a non-success response from the CLOB API.
+type APIError struct {
+	StatusCode int
+	Message    string // The body's "error" field, or the whole body, scrubbed
+	Kind       error  // One of the typed errors above, or nil if unrecognized
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap returns Kind, so errors.Is(err, ErrRateLimited) etc. work.
+func (e *APIError) Unwrap() error {
+	return e.Kind
+}
+
+// apiErrorPatterns maps lowercase substrings of API error messages to typed
+// errors, checked in order.
+var apiErrorPatterns = []struct {
+	match string
+	kind  error
+}{
+	{"not enough balance", ErrInsufficientBalance},
+	{"insufficient balance", ErrInsufficientBalance},
+	{"not enough allowance", ErrInsufficientBalance},
+	{"tick size", ErrInvalidTick},
+	{"market is closed", ErrMarketClosed},
+	{"market closed", ErrMarketClosed},
+	{"not accepting orders", ErrMarketClosed},
+	{"not yet ready to process new orders", ErrMarketClosed},
+	{"rate limit", ErrRateLimited},
+	{"too many requests", ErrRateLimited},
+	{"unauthorized", ErrAuthRequired},
+	{"invalid api key", ErrAuthRequired},
+}
+
+// newAPIError builds the error for a non-success response. body must
+// already be scrubbed of credentials.
+func newAPIError(statusCode int, body string) *APIError {
+	message := body
+	var parsed struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal([]byte(body), &parsed) == nil && parsed.Error != "" {
+		message = parsed.Error
+	}
+
+	e := &APIError{StatusCode: statusCode, Message: message}
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		e.Kind = ErrRateLimited
+		return e
+	case http.StatusUnauthorized:
+		e.Kind = ErrAuthRequired
+		return e
+	}
+
+	lower := strings.ToLower(message)
+	for _, p := range apiErrorPatterns {
+		if strings.Contains(lower, p.match) {
+			e.Kind = p.kind
+			break
+		}
+	}
+	return e
+}
@@ -0,0 +1,165 @@
+package clob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrLegsFailed is returned by SubmitLegs when at least one leg was not
+// accepted. The LegsResult returned with it says which.
+var ErrLegsFailed = errors.New("legs failed")
+
+// Leg is one order of a multi-leg submission. ConditionID is the market the
+// leg's token trades in; the leg is signed with that market's tick size and
+// neg-risk flag.
+type Leg struct {
+	OrderArgs
+	ConditionID string `json:"condition_id"`
+}
+
+// LegPolicy controls how SubmitLegs handles a multi-leg order. The zero value
+// is all-or-nothing.
+type LegPolicy struct {
+	// BestEffort keeps the legs that were accepted when others fail. By
+	// default a failure rolls back: every accepted leg is cancelled.
+	BestEffort bool
+}
+
+// LegResult is the outcome of one leg, in the order the legs were given.
+type LegResult struct {
+	Args    OrderArgs `json:"args"`
+	OrderID string    `json:"order_id,omitempty"` // Set if the exchange accepted the leg
+	Error   string    `json:"error,omitempty"`    // Why the leg was not accepted
+	// Order is the leg as reconciled after submission (and rollback), so
+	// SizeFilled shows what traded before any cancel landed. Nil if the
+	// leg was not accepted or could not be fetched.
+	Order *Order `json:"order,omitempty"`
+}
+
+// LegsResult is the outcome of SubmitLegs.
+type LegsResult struct {
+	Legs       []LegResult `json:"legs"`
+	RolledBack bool        `json:"rolled_back"` // Accepted legs were all cancelled after a failure
+	// RollbackError is set if cancelling the accepted legs failed, leaving
+	// some of them live.
+	RollbackError string `json:"rollback_error,omitempty"`
+}
+
+// SubmitLegs submits several orders as one intent, e.g. the legs of a
+// neg-risk arbitrage or a pairs trade. Every leg's market is looked up
+// (see GetMarketMeta) and every leg built and signed, and post-only legs
+// checked, before any is posted, so a leg that can't be built sends nothing. The legs are then posted concurrently to narrow the window
+// in which only some of them rest on the book.
+//
+// If any leg is rejected, policy decides what happens to the rest: by
+// default the accepted legs are cancelled; with BestEffort they are kept.
+// Cancelling cannot undo what already traded, so each accepted leg is
+// fetched again at the end and reported with its fill. The error wraps
+// ErrLegsFailed when any leg was rejected; the result is returned either way
+// once posting has begun.
+func (c *Client) SubmitLegs(ctx context.Context, legs []*Leg, policy LegPolicy) (*LegsResult, error) {
+	if len(legs) == 0 {
+		return nil, fmt.Errorf("no legs to submit")
+	}
+	if !c.HasCredentials() {
+		return nil, errNoCredentials
+	}
+
+	signed := make([]*SignedOrder, len(legs))
+	for i, leg := range legs {
+		meta, err := c.legMarket(ctx, leg)
+		if err != nil {
+			return nil, fmt.Errorf("leg %d: %w", i, err)
+		}
+		signed[i], err = c.signOrderArgs(ctx, &leg.OrderArgs, meta.MinimumTickSize, meta.NegRisk)
+		if err != nil {
+			return nil, fmt.Errorf("leg %d: %w", i, err)
+		}
+	}
+
+	result := &LegsResult{Legs: make([]LegResult, len(legs))}
+	var wg sync.WaitGroup
+	for i := range legs {
+		result.Legs[i].Args = legs[i].OrderArgs
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			leg := &result.Legs[i]
+			resp, err := c.PostOrder(ctx, signed[i])
+			switch {
+			case err != nil:
+				leg.Error = err.Error()
+			case !resp.Success || resp.OrderID == "":
+				leg.Error = "rejected: " + resp.ErrorMsg
+			default:
+				leg.OrderID = resp.OrderID
+			}
+		}()
+	}
+	wg.Wait()
+
+	var accepted []string
+	failed := 0
+	for _, leg := range result.Legs {
+		if leg.OrderID != "" {
+			accepted = append(accepted, leg.OrderID)
+		} else {
+			failed++
+		}
+	}
+
+	if failed > 0 && !policy.BestEffort && len(accepted) > 0 {
+		if err := c.CancelOrders(ctx, accepted); err != nil {
+			result.RollbackError = err.Error()
+		} else {
+			result.RolledBack = true
+		}
+	}
+
+	c.reconcileLegs(ctx, result)
+
+	if failed > 0 {
+		return result, fmt.Errorf("%w: %d of %d rejected", ErrLegsFailed, failed, len(legs))
+	}
+	return result, nil
+}
+
+// legMarket returns the metadata of leg's market, checking that the market
+// lists the leg's token.
+func (c *Client) legMarket(ctx context.Context, leg *Leg) (*MarketMeta, error) {
+	if leg.ConditionID == "" {
+		return nil, fmt.Errorf("condition ID is required")
+	}
+	meta, err := c.GetMarketMeta(ctx, leg.ConditionID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch market %s: %w", leg.ConditionID, err)
+	}
+	for _, token := range meta.Tokens {
+		if token.TokenID == leg.TokenID {
+			return meta, nil
+		}
+	}
+	return nil, fmt.Errorf("token %s is not in market %s", leg.TokenID, leg.ConditionID)
+}
+
+// reconcileLegs fetches every accepted leg's current state. A leg that
+// can't be fetched keeps a nil Order.
+func (c *Client) reconcileLegs(ctx context.Context, result *LegsResult) {
+	var wg sync.WaitGroup
+	for i := range result.Legs {
+		leg := &result.Legs[i]
+		if leg.OrderID == "" {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if order, err := c.GetOrder(ctx, leg.OrderID); err == nil {
+				leg.Order = order
+			}
+		}()
+	}
+	wg.Wait()
+}
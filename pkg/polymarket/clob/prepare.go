@@ -0,0 +1,166 @@
+package clob
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DefaultPrepareTTL is how long a prepared order can be committed.
+const DefaultPrepareTTL = 2 * time.Minute
+
+var (
+	// ErrPreparedOrderExpired means the order was not committed within the
+	// prepare TTL and has been discarded.
+	ErrPreparedOrderExpired = errors.New("prepared order expired")
+
+	// ErrPreparedOrderNotFound means no order was prepared under the ID, or
+	// it was already committed or discarded.
+	ErrPreparedOrderNotFound = errors.New("prepared order not found")
+)
+
+// PreparedOrder is a signed order held back from the exchange until it is
+// committed. FillSize and AvgPrice describe how it would trade against the
+// book at prepare time, for review before committing.
+type PreparedOrder struct {
+	ID        string       `json:"id"`
+	Args      OrderArgs    `json:"args"`
+	Order     *SignedOrder `json:"order"`
+	FillSize  float64      `json:"fill_size"` // Size that would fill immediately
+	AvgPrice  float64      `json:"avg_price"` // Average price of that fill; 0 if none
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// WithPrepareTTL sets how long a prepared order can be committed. See
+// DefaultPrepareTTL.
+func WithPrepareTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.prepareTTL = ttl
+	}
+}
+
+// PrepareOrder runs the checks CreateAndPostOrder does, builds and signs the
+// order, and simulates it against the current book, but does not post it.
+// The order is posted only by CommitOrder with the returned ID before
+// ExpiresAt.
+func (c *Client) PrepareOrder(ctx context.Context, args *OrderArgs, tickSize string, negRisk bool) (*PreparedOrder, error) {
+	signed, err := c.signOrderArgs(ctx, args, tickSize, negRisk)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := c.GetOrderBook(ctx, args.TokenID)
+	if err != nil {
+		return nil, fmt.Errorf("simulate order: %w", err)
+	}
+	fillSize, avgPrice := simulateLimitFill(book, args)
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("generate prepared order id: %w", err)
+	}
+
+	ttl := c.prepareTTL
+	if ttl <= 0 {
+		ttl = DefaultPrepareTTL
+	}
+	now := c.now()
+
+	prepared := &PreparedOrder{
+		ID:        hex.EncodeToString(id),
+		Args:      *args,
+		Order:     signed,
+		FillSize:  fillSize,
+		AvgPrice:  avgPrice,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	c.preparedMu.Lock()
+	if c.prepared == nil {
+		c.prepared = make(map[string]*PreparedOrder)
+	}
+	for id, p := range c.prepared {
+		if now.After(p.ExpiresAt) {
+			delete(c.prepared, id)
+		}
+	}
+	c.prepared[prepared.ID] = prepared
+	c.preparedMu.Unlock()
+
+	return prepared, nil
+}
+
+// CommitOrder posts a prepared order. Each prepared order can be committed
+// once; after ExpiresAt it fails with ErrPreparedOrderExpired.
+func (c *Client) CommitOrder(ctx context.Context, id string) (*PostOrderResponse, error) {
+	c.preparedMu.Lock()
+	prepared, ok := c.prepared[id]
+	delete(c.prepared, id)
+	c.preparedMu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPreparedOrderNotFound, id)
+	}
+	if c.now().After(prepared.ExpiresAt) {
+		return nil, fmt.Errorf("%w: %s at %s", ErrPreparedOrderExpired, id, prepared.ExpiresAt.Format(time.RFC3339))
+	}
+	return c.PostOrder(ctx, prepared.Order)
+}
+
+// DiscardOrder drops a prepared order without posting it. It reports
+// whether the order was still pending.
+func (c *Client) DiscardOrder(id string) bool {
+	c.preparedMu.Lock()
+	defer c.preparedMu.Unlock()
+	_, ok := c.prepared[id]
+	delete(c.prepared, id)
+	return ok
+}
+
+// simulateLimitFill walks the opposite side of book up to the order's limit
+// price and returns the size that would fill immediately and its average
+// price.
+func simulateLimitFill(book *OrderBookSummary, args *OrderArgs) (size, avgPrice float64) {
+	levels := book.Asks
+	crosses := func(p float64) bool { return p <= args.Price }
+	if args.Side == OrderSideSell {
+		levels = book.Bids
+		crosses = func(p float64) bool { return p >= args.Price }
+	}
+
+	type level struct{ price, size float64 }
+	var usable []level
+	for _, l := range levels {
+		p, err1 := strconv.ParseFloat(l.Price, 64)
+		s, err2 := strconv.ParseFloat(l.Size, 64)
+		if err1 == nil && err2 == nil && crosses(p) {
+			usable = append(usable, level{p, s})
+		}
+	}
+	// Best price first regardless of the order the API returns levels in
+	sort.Slice(usable, func(i, j int) bool {
+		if args.Side == OrderSideSell {
+			return usable[i].price > usable[j].price
+		}
+		return usable[i].price < usable[j].price
+	})
+
+	var cost float64
+	for _, l := range usable {
+		take := min(l.size, args.Size-size)
+		if take <= 0 {
+			break
+		}
+		size += take
+		cost += take * l.price
+	}
+	if size > 0 {
+		avgPrice = cost / size
+	}
+	return size, avgPrice
+}
@@ -0,0 +1,121 @@
+package clob
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultRateLimit is the request rate used while the server reports
+	// plenty of headroom.
+	DefaultRateLimit = 10.0 // requests per second
+
+	// DefaultRateBurst is the limiter burst size.
+	DefaultRateBurst = 5
+
+	// rateLowWater is the X-RateLimit-Remaining value below which the limiter
+	// is tightened in proportion to what is left.
+	rateLowWater = 10
+
+	// minRateFraction is the floor the limiter is tightened to, as a
+	// fraction of the configured rate.
+	minRateFraction = 0.05
+)
+
+// WithCLOBRateLimit sets the request rate used while the server reports
+// plenty of headroom. See DefaultRateLimit.
+func WithCLOBRateLimit(rps float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.baseRate = rate.Limit(rps)
+		c.limiter = rate.NewLimiter(rate.Limit(rps), burst)
+	}
+}
+
+// RateLimit returns the current request rate, which drops below the
+// configured rate while the server signals it is close to throttling.
+func (c *Client) RateLimit() float64 {
+	return float64(c.limiter.Limit())
+}
+
+// waitRateLimit blocks until a Retry-After pause has passed and the limiter
+// allows a request.
+func (c *Client) waitRateLimit(ctx context.Context) error {
+	c.rateMu.Lock()
+	pause := time.Until(c.pausedUntil)
+	c.rateMu.Unlock()
+
+	if pause > 0 {
+		timer := time.NewTimer(pause)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("rate limiter: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limiter: %w", err)
+	}
+	return nil
+}
+
+// observeRateLimit adjusts the limiter from a response's rate-limit headers.
+// Retry-After pauses all requests and drops to the minimum rate. A low
+// X-RateLimit-Remaining tightens the rate in proportion to what is left;
+// as it recovers the rate is loosened, at most doubling per response, back
+// to the configured rate.
+func (c *Client) observeRateLimit(header http.Header) {
+	floor := c.baseRate * minRateFraction
+
+	if wait, ok := parseRetryAfter(header.Get("Retry-After"), time.Now()); ok {
+		c.rateMu.Lock()
+		if until := time.Now().Add(wait); until.After(c.pausedUntil) {
+			c.pausedUntil = until
+		}
+		c.rateMu.Unlock()
+		c.limiter.SetLimit(floor)
+		return
+	}
+
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	target := c.baseRate
+	if remaining < rateLowWater {
+		target = max(c.baseRate*rate.Limit(remaining)/rateLowWater, floor)
+	}
+
+	current := c.limiter.Limit()
+	switch {
+	case target < current:
+		c.limiter.SetLimit(target)
+	case target > current:
+		c.limiter.SetLimit(min(target, current*2))
+	}
+}
+
+// parseRetryAfter parses a Retry-After value given in seconds or as an HTTP
+// date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
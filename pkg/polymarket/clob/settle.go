@@ -0,0 +1,132 @@
+package clob
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/eth"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// SettlementKind is how a position is closed on-chain instead of on the book.
+type SettlementKind string
+
+const (
+	// SettlementMerge burns equal YES and NO shares for 1 USDC per pair.
+	SettlementMerge SettlementKind = "merge"
+	// SettlementRedeem burns the shares of a resolved market, paying 1 USDC
+	// per winning share.
+	SettlementRedeem SettlementKind = "redeem"
+)
+
+// Settlement is an unsigned transaction closing a position through the
+// Conditional Tokens contracts. Send Data to To from the wallet holding the
+// shares.
+type Settlement struct {
+	Kind        SettlementKind `json:"kind"`
+	ConditionID string         `json:"condition_id"`
+	NegRisk     bool           `json:"neg_risk"`
+	Shares      float64        `json:"shares"` // Pairs merged, or shares redeemed
+	Payout      float64        `json:"payout"` // USDC returned
+	To          string         `json:"to"`
+	Data        string         `json:"data"` // Hex-encoded calldata
+}
+
+// PlanSettlement returns the transaction that closes holdings (token ID →
+// shares) in market without trading, or nil if there is none: a redemption
+// once the market has a winner, otherwise a merge of the matched YES/NO
+// shares.
+func PlanSettlement(market *MarketInfo, holdings map[string]float64) (*Settlement, error) {
+	yes, no, err := binaryOutcomes(market)
+	if err != nil {
+		return nil, err
+	}
+	yesShares, noShares := holdings[yes.TokenID], holdings[no.TokenID]
+	conditionID := common.HexToHash(market.ConditionID)
+
+	settlement := &Settlement{
+		ConditionID: market.ConditionID,
+		NegRisk:     market.NegRisk,
+		To:          eth.CTFTarget(market.NegRisk).Hex(),
+	}
+
+	var data []byte
+	switch {
+	case yes.Winner || no.Winner:
+		if yesShares <= 0 && noShares <= 0 {
+			return nil, nil
+		}
+		settlement.Kind = SettlementRedeem
+		settlement.Shares = yesShares + noShares
+		if yes.Winner {
+			settlement.Payout = yesShares
+		} else {
+			settlement.Payout = noShares
+		}
+		data, err = eth.RedeemPositionsCalldata(conditionID, baseUnits(yesShares), baseUnits(noShares), market.NegRisk)
+
+	case yesShares > 0 && noShares > 0:
+		pairs := math.Min(yesShares, noShares)
+		settlement.Kind = SettlementMerge
+		settlement.Shares = pairs
+		settlement.Payout = pairs
+		data, err = eth.MergePositionsCalldata(conditionID, baseUnits(pairs), market.NegRisk)
+
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", settlement.Kind, err)
+	}
+	settlement.Data = hexutil.Encode(data)
+	return settlement, nil
+}
+
+// binaryOutcomes returns market's YES and NO tokens by their Outcome, not
+// their position in Tokens. A binary market with other outcome names (e.g.
+// two teams) keeps the listed order, which is the condition's index-set
+// order; neg-risk markets must be Yes/No since the adapter takes the YES and
+// NO amounts.
+func binaryOutcomes(market *MarketInfo) (yes, no Token, err error) {
+	if len(market.Tokens) != 2 {
+		return yes, no, fmt.Errorf("settlement needs a binary market, %s has %d tokens", market.ConditionID, len(market.Tokens))
+	}
+	var foundYes, foundNo bool
+	for _, token := range market.Tokens {
+		switch {
+		case strings.EqualFold(token.Outcome, "yes"):
+			yes, foundYes = token, true
+		case strings.EqualFold(token.Outcome, "no"):
+			no, foundNo = token, true
+		}
+	}
+	switch {
+	case foundYes && foundNo:
+		return yes, no, nil
+	case foundYes || foundNo || market.NegRisk:
+		return yes, no, fmt.Errorf("market %s has no Yes/No outcome pair: %q, %q",
+			market.ConditionID, market.Tokens[0].Outcome, market.Tokens[1].Outcome)
+	}
+	return market.Tokens[0], market.Tokens[1], nil
+}
+
+// PlanSettlement fetches the market and plans the settlement of holdings in
+// it; see the package-level PlanSettlement.
+func (c *Client) PlanSettlement(ctx context.Context, conditionID string, holdings map[string]float64) (*Settlement, error) {
+	market, err := c.GetMarket(ctx, conditionID)
+	if err != nil {
+		return nil, fmt.Errorf("get market: %w", err)
+	}
+	return PlanSettlement(market, holdings)
+}
+
+// baseUnits converts shares to 6-decimal token units, rounding down (past
+// float error) so a merge never asks for more than is held.
+func baseUnits(shares float64) *big.Int {
+	return big.NewInt(int64(math.Floor(shares*1e6 + 1e-6)))
+}
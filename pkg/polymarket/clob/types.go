@@ -3,7 +3,10 @@
 package clob
 
 import (
+	"fmt"
 	"time"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/redact"
 )
 
 const (
@@ -18,6 +21,9 @@ const (
 
 	// ChainID for Polygon mainnet
 	ChainIDPolygon = 137
+
+	// DefaultMarketMetaTTL is how long GetMarketMeta results are cached
+	DefaultMarketMetaTTL = 10 * time.Minute
 )
 
 // Order represents a trading order.
@@ -28,6 +34,7 @@ type Order struct {
 	Maker         string      `json:"maker"`
 	Taker         string      `json:"taker"`
 	TokenID       string      `json:"asset_id"`
+	Market        string      `json:"market"` // Condition ID
 	MakerAmount   string      `json:"maker_amount"`
 	TakerAmount   string      `json:"taker_amount"`
 	Side          OrderSide   `json:"side"`
@@ -129,6 +136,15 @@ type MarketInfo struct {
 	NegRisk          bool    `json:"neg_risk"`
 }
 
+// MarketMeta is the subset of MarketInfo needed to build orders.
+type MarketMeta struct {
+	ConditionID      string  `json:"condition_id"`
+	MinimumTickSize  string  `json:"minimum_tick_size"`
+	MinimumOrderSize string  `json:"minimum_order_size"`
+	NegRisk          bool    `json:"neg_risk"`
+	Tokens           []Token `json:"tokens"`
+}
+
 // Token represents a token in a market.
 type Token struct {
 	TokenID string `json:"token_id"`
@@ -151,6 +167,7 @@ type OrderArgs struct {
 	Size       float64   `json:"size"`
 	OrderType  OrderType `json:"order_type,omitempty"`
 	Expiration int64     `json:"expiration,omitempty"` // Unix timestamp
+	PostOnly   bool      `json:"post_only,omitempty"`  // Reject before signing if the order would cross the book
 }
 
 // MarketOrderArgs represents arguments for creating a market order.
@@ -191,6 +208,18 @@ type APICredentials struct {
 	Passphrase string `json:"passphrase"`
 }
 
+// String masks the key and hides the secret and passphrase, so credentials
+// printed with %v or %+v never leak.
+func (c APICredentials) String() string {
+	return fmt.Sprintf("{APIKey:%s Secret:%s Passphrase:%s}",
+		redact.Mask(c.APIKey), redact.Hide(c.Secret), redact.Hide(c.Passphrase))
+}
+
+// GoString is String for %#v.
+func (c APICredentials) GoString() string {
+	return "clob.APICredentials" + c.String()
+}
+
 // PostOrderResponse is the response from posting an order.
 type PostOrderResponse struct {
 	OrderID  string `json:"orderID"`
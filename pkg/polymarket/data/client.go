@@ -0,0 +1,143 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultBaseURL is the Data API base URL
+	DefaultBaseURL = "https://data-api.polymarket.com"
+
+	defaultRateLimit = 10.0 // requests per second
+	defaultBurst     = 5
+)
+
+// Client is a Data API client.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	limiter    *rate.Limiter
+}
+
+// ClientOption configures the client.
+type ClientOption func(*Client)
+
+// WithBaseURL sets a custom base URL.
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithRateLimit sets custom rate limiting.
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.limiter = rate.NewLimiter(rate.Limit(rps), burst)
+	}
+}
+
+// NewClient creates a new Data API client.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL: DefaultBaseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		limiter: rate.NewLimiter(rate.Limit(defaultRateLimit), defaultBurst),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// GetActivity fetches on-chain activity, newest first.
+func (c *Client) GetActivity(ctx context.Context, filter *ActivityFilter) ([]Activity, error) {
+	params := url.Values{}
+	if filter != nil {
+		if filter.User != "" {
+			params.Set("user", filter.User)
+		}
+		if filter.Market != "" {
+			params.Set("market", filter.Market)
+		}
+		if len(filter.Types) > 0 {
+			types := make([]string, len(filter.Types))
+			for i, t := range filter.Types {
+				types[i] = string(t)
+			}
+			params.Set("type", strings.Join(types, ","))
+		}
+		if filter.Side != "" {
+			params.Set("side", string(filter.Side))
+		}
+		if !filter.Start.IsZero() {
+			params.Set("start", strconv.FormatInt(filter.Start.Unix(), 10))
+		}
+		if filter.Limit > 0 {
+			params.Set("limit", strconv.Itoa(filter.Limit))
+		}
+		if filter.Offset > 0 {
+			params.Set("offset", strconv.Itoa(filter.Offset))
+		}
+	}
+
+	var activity []Activity
+	if err := c.get(ctx, "/activity", params, &activity); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, params url.Values, result interface{}) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limiter: %w", err)
+	}
+
+	u := c.baseURL + path
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("api error %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package data
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetActivityParsesLargeTrade(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/activity" {
+			t.Errorf("Expected path /activity, got %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("market"); got != "0xcond" {
+			t.Errorf("Expected market=0xcond, got %q", got)
+		}
+		if got := r.URL.Query().Get("type"); got != "TRADE,SPLIT" {
+			t.Errorf("Expected type=TRADE,SPLIT, got %q", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"proxyWallet":"0xwhale","timestamp":1700000000,"conditionId":"0xcond","type":"TRADE",
+			 "size":50000,"usdcSize":32500,"price":0.65,"asset":"tok-yes","side":"BUY","outcome":"Yes"},
+			{"proxyWallet":"0xminnow","timestamp":1699999990,"conditionId":"0xcond","type":"TRADE",
+			 "size":20,"usdcSize":13,"price":0.65,"asset":"tok-yes","side":"SELL","outcome":"Yes"},
+			{"proxyWallet":"0xlp","timestamp":1699999980,"conditionId":"0xcond","type":"SPLIT",
+			 "size":100000,"usdcSize":100000}
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	activity, err := client.GetActivity(context.Background(), &ActivityFilter{
+		Market: "0xcond",
+		Types:  []ActivityType{ActivityTrade, ActivitySplit},
+	})
+	if err != nil {
+		t.Fatalf("GetActivity failed: %v", err)
+	}
+	if len(activity) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(activity))
+	}
+
+	big := activity[0]
+	if big.Type != ActivityTrade || big.Side != SideBuy || big.Size != 50000 || big.USDCSize != 32500 {
+		t.Errorf("Large trade parsed wrong: %+v", big)
+	}
+	if big.Asset != "tok-yes" || big.Time().Unix() != 1700000000 {
+		t.Errorf("Large trade asset/time wrong: %+v", big)
+	}
+
+	// Splits never count as whale trades, however large
+	whales := Whales(activity, 10000)
+	if len(whales) != 1 || whales[0].ProxyWallet != "0xwhale" {
+		t.Errorf("Expected only 0xwhale flagged, got %+v", whales)
+	}
+}
@@ -0,0 +1,73 @@
+// Package data provides a client for the Polymarket Data API, which serves
+// on-chain user activity, positions, and trades.
+package data
+
+import "time"
+
+// ActivityType is the kind of on-chain activity.
+type ActivityType string
+
+const (
+	ActivityTrade  ActivityType = "TRADE"
+	ActivitySplit  ActivityType = "SPLIT"
+	ActivityMerge  ActivityType = "MERGE"
+	ActivityRedeem ActivityType = "REDEEM"
+)
+
+// Side is the side of a trade activity.
+type Side string
+
+const (
+	SideBuy  Side = "BUY"
+	SideSell Side = "SELL"
+)
+
+// Activity is a single on-chain event. Side and Price are only set for
+// trades.
+type Activity struct {
+	ProxyWallet     string       `json:"proxyWallet"`
+	Timestamp       int64        `json:"timestamp"` // Unix seconds
+	ConditionID     string       `json:"conditionId"`
+	Type            ActivityType `json:"type"`
+	Size            float64      `json:"size"`     // Outcome tokens
+	USDCSize        float64      `json:"usdcSize"` // Notional in USDC
+	TransactionHash string       `json:"transactionHash"`
+	Price           float64      `json:"price"`
+	Asset           string       `json:"asset"` // CLOB token ID
+	Side            Side         `json:"side"`
+	OutcomeIndex    int          `json:"outcomeIndex"`
+	Title           string       `json:"title"`
+	Slug            string       `json:"slug"`
+	Outcome         string       `json:"outcome"`
+	Name            string       `json:"name"`
+	Pseudonym       string       `json:"pseudonym"`
+}
+
+// Time returns the activity timestamp.
+func (a Activity) Time() time.Time {
+	return time.Unix(a.Timestamp, 0).UTC()
+}
+
+// ActivityFilter narrows an activity query. At least one of User or Market
+// is normally set.
+type ActivityFilter struct {
+	User   string         // Proxy wallet address
+	Market string         // Condition ID
+	Types  []ActivityType // Empty means all types
+	Side   Side
+	Start  time.Time // Zero means no lower bound
+	Limit  int
+	Offset int
+}
+
+// Whales returns the trades in activity whose USDC notional is at least
+// minUSDC, in their original order.
+func Whales(activity []Activity, minUSDC float64) []Activity {
+	var whales []Activity
+	for _, a := range activity {
+		if a.Type == ActivityTrade && a.USDCSize >= minUSDC {
+			whales = append(whales, a)
+		}
+	}
+	return whales
+}
@@ -7,9 +7,12 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"time"
 
+	"github.com/phenomenon0/polymarket-agents/pkg/httpx"
+
 	"golang.org/x/time/rate"
 )
 
@@ -17,6 +20,9 @@ const (
 	// DefaultBaseURL is the Gamma API base URL
 	DefaultBaseURL = "https://gamma-api.polymarket.com"
 
+	// DefaultCLOBURL is the CLOB API base URL, used for price history
+	DefaultCLOBURL = "https://clob.polymarket.com"
+
 	// Rate limits (from Polymarket docs)
 	defaultRateLimit = 10.0 // requests per second
 	defaultBurst     = 5
@@ -25,6 +31,7 @@ const (
 // Client is a Gamma API client.
 type Client struct {
 	baseURL    string
+	clobURL    string
 	httpClient *http.Client
 	limiter    *rate.Limiter
 }
@@ -39,6 +46,13 @@ func WithBaseURL(url string) ClientOption {
 	}
 }
 
+// WithCLOBURL sets a custom CLOB base URL for price history.
+func WithCLOBURL(url string) ClientOption {
+	return func(c *Client) {
+		c.clobURL = url
+	}
+}
+
 // WithHTTPClient sets a custom HTTP client.
 func WithHTTPClient(client *http.Client) ClientOption {
 	return func(c *Client) {
@@ -57,13 +71,10 @@ func WithRateLimit(rps float64, burst int) ClientOption {
 func NewClient(opts ...ClientOption) *Client {
 	c := &Client{
 		baseURL: DefaultBaseURL,
+		clobURL: DefaultCLOBURL,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				MaxIdleConns:        20,
-				MaxIdleConnsPerHost: 10,
-				IdleConnTimeout:     90 * time.Second,
-			},
+			Timeout:   30 * time.Second,
+			Transport: httpx.NewTransport(httpx.DefaultTransportConfig()),
 		},
 		limiter: rate.NewLimiter(rate.Limit(defaultRateLimit), defaultBurst),
 	}
@@ -191,6 +202,86 @@ func (c *Client) GetMarket(ctx context.Context, conditionID string) (*Market, er
 	return &market, nil
 }
 
+// GetMarketPriceHistory fetches the YES-price history of a market by
+// condition ID, oldest first. Gamma has no history route of its own, so the
+// market is resolved to its YES token and the CLOB's prices-history is
+// queried for that token. interval is one of "1h", "6h", "1d", "1w", "1m",
+// or "max"; empty uses the API default.
+func (c *Client) GetMarketPriceHistory(ctx context.Context, conditionID, interval string) ([]PricePoint, error) {
+	markets, err := c.ListMarkets(ctx, &MarketsFilter{ConditionID: conditionID, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(markets) == 0 {
+		return nil, fmt.Errorf("market not found: %s", conditionID)
+	}
+	tokenID := markets[0].YesTokenID()
+	if tokenID == "" {
+		return nil, fmt.Errorf("market %s has no YES token", conditionID)
+	}
+
+	params := url.Values{}
+	params.Set("market", tokenID)
+	if interval != "" {
+		params.Set("interval", interval)
+	}
+
+	var result struct {
+		History []priceHistoryPoint `json:"history"`
+	}
+	if err := c.getFrom(ctx, c.clobURL, "/prices-history", params, &result); err != nil {
+		return nil, err
+	}
+
+	points := make([]PricePoint, len(result.History))
+	for i, s := range result.History {
+		points[i] = PricePoint{Timestamp: time.Unix(s.T, 0).UTC(), Price: s.P.Float64()}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Timestamp.Before(points[j].Timestamp)
+	})
+	return points, nil
+}
+
+// SearchMarkets finds markets by keyword through Gamma's public search, which
+// matches event titles and market questions, and returns the markets of the
+// matching events in relevance order.
+func (c *Client) SearchMarkets(ctx context.Context, query string, filter *SearchFilter) ([]Market, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	if filter != nil {
+		if filter.Active != nil {
+			if *filter.Active {
+				params.Set("events_status", "active")
+			} else {
+				params.Set("events_status", "closed")
+			}
+		}
+		if filter.Limit > 0 {
+			params.Set("limit_per_type", strconv.Itoa(filter.Limit))
+		}
+	}
+
+	var result struct {
+		Events []Event `json:"events"`
+	}
+	if err := c.get(ctx, "/public-search", params, &result); err != nil {
+		return nil, err
+	}
+
+	var markets []Market
+	for _, event := range result.Events {
+		for _, m := range event.Markets {
+			// A resolved market can sit in a still-active event
+			if filter != nil && filter.Active != nil && *filter.Active == m.Closed {
+				continue
+			}
+			markets = append(markets, m)
+		}
+	}
+	return markets, nil
+}
+
 // GetMarketByTokenID fetches a market by one of its CLOB token IDs.
 func (c *Client) GetMarketByTokenID(ctx context.Context, tokenID string) (*Market, error) {
 	markets, err := c.ListMarkets(ctx, &MarketsFilter{ClobTokenIDs: tokenID, Limit: 1})
@@ -275,15 +366,20 @@ func (c *Client) ListAllTradeableMarkets(ctx context.Context) ([]Market, error)
 	return allMarkets, nil
 }
 
-// get performs a GET request with rate limiting.
+// get performs a GET request against the Gamma API with rate limiting.
 func (c *Client) get(ctx context.Context, path string, params url.Values, result interface{}) error {
+	return c.getFrom(ctx, c.baseURL, path, params, result)
+}
+
+// getFrom performs a GET request against baseURL with rate limiting.
+func (c *Client) getFrom(ctx context.Context, baseURL, path string, params url.Values, result interface{}) error {
 	// Wait for rate limiter
 	if err := c.limiter.Wait(ctx); err != nil {
 		return fmt.Errorf("rate limiter: %w", err)
 	}
 
 	// Build URL
-	u := c.baseURL + path
+	u := baseURL + path
 	if len(params) > 0 {
 		u += "?" + params.Encode()
 	}
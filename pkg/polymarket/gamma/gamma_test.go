@@ -183,6 +183,55 @@ func TestGetMarketByTokenID(t *testing.T) {
 	}
 }
 
+func TestGetMarketPriceHistory(t *testing.T) {
+	gammaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/markets" {
+			t.Errorf("Expected path /markets, got %s", r.URL.Path)
+		}
+		var markets []Market
+		if r.URL.Query().Get("condition_id") == "0xcond&x" {
+			markets = []Market{{ID: "1", ClobTokenIDsRaw: `["yes token", "no-token"]`}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(markets)
+	}))
+	defer gammaServer.Close()
+
+	clobServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/prices-history" {
+			t.Errorf("Expected path /prices-history, got %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("market"); got != "yes token" {
+			t.Errorf("Expected market=yes token, got %q", got)
+		}
+		if got := r.URL.Query().Get("interval"); got != "1d" {
+			t.Errorf("Expected interval=1d, got %q", got)
+		}
+		// Out of order, with prices both as numbers and strings
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"history": [{"t": 1700000600, "p": "0.55"}, {"t": 1700000000, "p": 0.50}]}`))
+	}))
+	defer clobServer.Close()
+
+	client := NewClient(WithBaseURL(gammaServer.URL), WithCLOBURL(clobServer.URL))
+	ctx := context.Background()
+
+	history, err := client.GetMarketPriceHistory(ctx, "0xcond&x", "1d")
+	if err != nil {
+		t.Fatalf("GetMarketPriceHistory failed: %v", err)
+	}
+	if len(history) != 2 || history[0].Price != 0.50 || history[1].Price != 0.55 {
+		t.Errorf("Expected sorted [0.50 0.55], got %+v", history)
+	}
+	if !history[0].Timestamp.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Wrong timestamp: %v", history[0].Timestamp)
+	}
+
+	if _, err := client.GetMarketPriceHistory(ctx, "missing", "1d"); err == nil {
+		t.Error("Expected error for unknown market")
+	}
+}
+
 func TestMarketMethods(t *testing.T) {
 	market := Market{
 		ClobTokenIDsRaw:  `["yes-token", "no-token"]`,
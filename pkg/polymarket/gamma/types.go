@@ -96,7 +96,8 @@ type Market struct {
 	EventID string `json:"eventID"`
 
 	// Tags and categories
-	Tags []Tag `json:"tags,omitempty"`
+	Category string `json:"category,omitempty"`
+	Tags     []Tag  `json:"tags,omitempty"`
 }
 
 // Tag represents a category tag.
@@ -141,6 +142,18 @@ func (j JSONFloat) Float64() float64 {
 	return float64(j)
 }
 
+// PricePoint is one sample of a market's YES price.
+type PricePoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Price     float64   `json:"price"`
+}
+
+// priceHistoryPoint is the wire format of a price sample: unix seconds and price.
+type priceHistoryPoint struct {
+	T int64     `json:"t"`
+	P JSONFloat `json:"p"`
+}
+
 // EventsResponse is the response from the events endpoint.
 type EventsResponse []Event
 
@@ -175,6 +188,12 @@ type MarketsFilter struct {
 	Offset       int    `url:"offset,omitempty"`
 }
 
+// SearchFilter narrows a keyword search.
+type SearchFilter struct {
+	Active *bool // True keeps only open markets, false only closed ones
+	Limit  int   // Maximum events searched; each may hold several markets
+}
+
 // BoolPtr returns a pointer to a bool.
 func BoolPtr(b bool) *bool {
 	return &b
@@ -238,6 +257,18 @@ func (m *Market) NoTokenID() string {
 	return ""
 }
 
+// CategoryName returns the market's category, falling back to the label of
+// its first tag, or "" if it has neither.
+func (m *Market) CategoryName() string {
+	if m.Category != "" {
+		return m.Category
+	}
+	if len(m.Tags) > 0 {
+		return m.Tags[0].Label
+	}
+	return ""
+}
+
 // YesPrice returns the current YES price.
 func (m *Market) YesPrice() float64 {
 	prices := m.OutcomePrices()
@@ -4,9 +4,10 @@ import (
 	"context"
 	"fmt"
 	"math"
-	"strconv"
 
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/book"
 	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/clob"
+	"github.com/shopspring/decimal"
 )
 
 // FeeModel calculates trading fees.
@@ -85,83 +86,54 @@ func NewEdgeCalculator(cfg *EdgeConfig, clobClient *clob.Client) *EdgeCalculator
 	}
 }
 
-// BookLevel represents a price level in the order book.
-type BookLevel struct {
-	Price float64
-	Size  float64
-}
-
-// CalculateVWAP computes the volume-weighted average price for a given size.
-// side: BUY = walk up asks, SELL = walk down bids.
-func CalculateVWAP(levels []BookLevel, targetSize float64) (vwap float64, filledSize float64, err error) {
-	if len(levels) == 0 {
-		return 0, 0, fmt.Errorf("empty order book")
-	}
-
-	if targetSize <= 0 {
-		return levels[0].Price, 0, nil
-	}
-
-	var totalCost float64
-	remaining := targetSize
-
-	for _, level := range levels {
-		if remaining <= 0 {
-			break
-		}
-
-		fillSize := math.Min(level.Size, remaining)
-		totalCost += level.Price * fillSize
-		filledSize += fillSize
-		remaining -= fillSize
-	}
-
-	if filledSize == 0 {
-		return 0, 0, fmt.Errorf("no liquidity")
-	}
-
-	vwap = totalCost / filledSize
-	return vwap, filledSize, nil
-}
-
 // FetchBookAndVWAP fetches the order book and calculates VWAP for buying YES.
 func (e *EdgeCalculator) FetchBookAndVWAP(ctx context.Context, tokenID string, targetSizeUSD float64) (vwap, bestAsk, depth float64, err error) {
 	if e.clobClient == nil {
 		return 0, 0, 0, fmt.Errorf("no CLOB client")
 	}
 
-	book, err := e.clobClient.GetOrderBook(ctx, tokenID)
+	summary, err := e.clobClient.GetOrderBook(ctx, tokenID)
 	if err != nil {
 		return 0, 0, 0, fmt.Errorf("fetch order book: %w", err)
 	}
 
-	if len(book.Asks) == 0 {
+	// Convert asks to book levels
+	var asks []book.PriceLevel
+	var totalDepth float64
+	for _, ask := range summary.Asks {
+		price, err1 := decimal.NewFromString(ask.Price)
+		size, err2 := decimal.NewFromString(ask.Size)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		asks = append(asks, book.PriceLevel{Price: price, Size: size})
+		totalDepth += price.Mul(size).InexactFloat64() // Depth in USD
+	}
+	if len(asks) == 0 {
 		return 0, 0, 0, fmt.Errorf("no asks in order book")
 	}
 
-	// Convert asks to BookLevel
-	var asks []BookLevel
-	var totalDepth float64
-	for _, ask := range book.Asks {
-		price, _ := strconv.ParseFloat(ask.Price, 64)
-		size, _ := strconv.ParseFloat(ask.Size, 64)
-		asks = append(asks, BookLevel{Price: price, Size: size})
-		totalDepth += price * size // Depth in USD
+	ob := book.NewOrderBook(tokenID, summary.Market)
+	ob.SetAsks(asks)
+	best, _ := ob.BestAsk()
+	if !best.IsPositive() {
+		return 0, 0, totalDepth, fmt.Errorf("invalid best ask %s", best)
+	}
+	bestAsk = best.InexactFloat64()
+	if targetSizeUSD <= 0 {
+		return bestAsk, bestAsk, totalDepth, nil
 	}
-
-	// Best ask
-	bestAsk = asks[0].Price
 
 	// Target size in shares: USD / price (approximate using best ask)
-	targetShares := targetSizeUSD / bestAsk
+	targetShares := decimal.NewFromFloat(targetSizeUSD).Div(best)
 
-	// Calculate VWAP
-	vwap, _, err = CalculateVWAP(asks, targetShares)
-	if err != nil {
-		return 0, bestAsk, totalDepth, err
+	// VWAP of what the book can fill
+	_, avgPrice, unfilled := ob.CostToFill(book.SideBuy, targetShares)
+	if unfilled.Equal(targetShares) {
+		return 0, bestAsk, totalDepth, fmt.Errorf("no liquidity")
 	}
 
-	return vwap, bestAsk, totalDepth, nil
+	return avgPrice.InexactFloat64(), bestAsk, totalDepth, nil
 }
 
 // Calculate computes edge and sizing for a contract.
@@ -0,0 +1,76 @@
+// Package redact keeps private keys and API credentials out of logs, error
+// strings, and struct dumps.
+package redact
+
+import "strings"
+
+// Placeholder replaces a redacted value.
+const Placeholder = "[REDACTED]"
+
+// minSecretLen is the shortest value Redact scrubs; shorter ones are too
+// likely to match unrelated text.
+const minSecretLen = 6
+
+// maskPrefix is how many leading characters Mask keeps.
+const maskPrefix = 4
+
+// Redact returns s with every occurrence of each secret replaced by
+// Placeholder. Empty and very short secrets are ignored.
+func Redact(s string, secrets ...string) string {
+	for _, secret := range secrets {
+		if len(secret) < minSecretLen {
+			continue
+		}
+		s = strings.ReplaceAll(s, secret, Placeholder)
+		// Private keys also appear with or without their 0x prefix
+		if bare, ok := strings.CutPrefix(secret, "0x"); ok && len(bare) >= minSecretLen {
+			s = strings.ReplaceAll(s, bare, Placeholder)
+		}
+	}
+	return s
+}
+
+// Error returns err with secrets scrubbed from its message. The result
+// still unwraps to err, so errors.Is and errors.As keep working; nil stays
+// nil.
+func Error(err error, secrets ...string) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	if scrubbed := Redact(msg, secrets...); scrubbed != msg {
+		return &redactedError{msg: scrubbed, err: err}
+	}
+	return err
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// Hide returns Placeholder for a set value and "" for an unset one, for
+// dumps that should show whether a secret is configured but not what it is.
+func Hide(s string) string {
+	if s == "" {
+		return ""
+	}
+	return Placeholder
+}
+
+// Mask shows enough of an identifier, such as a CLOB API key, to tell keys
+// apart, and nothing of short values. Use Hide for anything that grants
+// access on its own: private keys, secrets, passphrases, LLM API keys.
+func Mask(s string) string {
+	switch {
+	case s == "":
+		return ""
+	case len(s) <= 2*maskPrefix:
+		return Placeholder
+	default:
+		return s[:maskPrefix] + "..." + Placeholder
+	}
+}
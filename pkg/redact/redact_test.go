@@ -0,0 +1,54 @@
+package redact
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestErrorRedactsSecret(t *testing.T) {
+	const secret = "c2VjcmV0LXNlY3JldC1zZWNyZXQ="
+	const key = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+	base := errors.New("upstream rejected request")
+	err := fmt.Errorf("sign with %s (key %s, bare %s): %w", secret, key, strings.TrimPrefix(key, "0x"), base)
+
+	got := Error(err, secret, key)
+	msg := got.Error()
+	if strings.Contains(msg, secret) || strings.Contains(msg, strings.TrimPrefix(key, "0x")) {
+		t.Errorf("Expected secrets redacted, got %q", msg)
+	}
+	if strings.Count(msg, Placeholder) != 3 {
+		t.Errorf("Expected 3 placeholders, got %q", msg)
+	}
+	if !errors.Is(got, base) {
+		t.Error("Redacted error should still unwrap to the original")
+	}
+
+	if Error(base, secret) != base {
+		t.Error("Error without secrets should be returned unchanged")
+	}
+	if Error(nil, secret) != nil {
+		t.Error("Nil error should stay nil")
+	}
+}
+
+func TestRedactIgnoresShortSecrets(t *testing.T) {
+	if got := Redact("status 1 of 10", "", "1"); got != "status 1 of 10" {
+		t.Errorf("Short secrets should be ignored, got %q", got)
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := map[string]string{
+		"":                                     "",
+		"short":                                Placeholder,
+		"3f2a9c1e-0b7d-4e5f-8a6b-1c2d3e4f5a6b": "3f2a..." + Placeholder,
+	}
+	for in, want := range tests {
+		if got := Mask(in); got != want {
+			t.Errorf("Mask(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Package tracing records OpenTelemetry spans across the trading pipeline:
+// one per workflow cycle, with children per stage, LLM call, and CLOB
+// request.
+//
+// Instrumented code calls Start, which traces through the global OTel
+// tracer provider and so is a no-op until one is installed with
+// otel.SetTracerProvider. NewProviderFromEnv builds an SDK provider that
+// exports over OTLP/HTTP as configured by the standard OTEL_* environment.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies this module's spans to OTel.
+const instrumentationName = "github.com/phenomenon0/polymarket-agents"
+
+// Start starts a span with the global tracer provider. The returned context
+// carries the span, so spans started from it become its children.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Fail records err on span and marks the span failed. A nil err does nothing.
+func Fail(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// End ends span, first failing it with err if err is not nil.
+func End(span trace.Span, err error) {
+	Fail(span, err)
+	span.End()
+}
@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStartNestsSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	ctx, root := Start(context.Background(), "root", attribute.String("k", "v"))
+	_, child := Start(ctx, "child")
+	End(child, errors.New("boom"))
+	End(root, nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	c, r := spans[0], spans[1]
+	if c.Name != "child" || r.Name != "root" {
+		t.Fatalf("Expected child then root, got %s then %s", c.Name, r.Name)
+	}
+	if c.SpanContext.TraceID() != r.SpanContext.TraceID() || c.Parent.SpanID() != r.SpanContext.SpanID() || r.Parent.IsValid() {
+		t.Errorf("Child not linked to root: %+v / %+v", c, r)
+	}
+	if c.Status.Code != codes.Error || c.Status.Description != "boom" || len(c.Events) != 1 {
+		t.Errorf("Expected recorded error, got status %+v and %d events", c.Status, len(c.Events))
+	}
+	if r.Status.Code != codes.Unset {
+		t.Errorf("Expected root without error status, got %+v", r.Status)
+	}
+	if len(r.Attributes) != 1 || r.Attributes[0] != attribute.String("k", "v") {
+		t.Errorf("Expected attribute k=v, got %v", r.Attributes)
+	}
+}
+
+func TestNewProviderFromEnv(t *testing.T) {
+	var payload coltracepb.ExportTraceServiceRequest
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path != "/v1/traces" || proto.Unmarshal(body, &payload) != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", server.URL)
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "Authorization=Bearer abc")
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	tp, err := NewProviderFromEnv(context.Background(), "agentd")
+	if err != nil || tp == nil {
+		t.Fatalf("Expected a provider from env, got %v, %v", tp, err)
+	}
+
+	_, span := tp.Tracer("test").Start(context.Background(), "stage.forecasting")
+	span.End()
+	if err := tp.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	if auth != "Bearer abc" {
+		t.Errorf("Expected headers from env, got %q", auth)
+	}
+	if len(payload.ResourceSpans) != 1 || len(payload.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("Unexpected payload: %v", &payload)
+	}
+	service := ""
+	for _, kv := range payload.ResourceSpans[0].Resource.Attributes {
+		if kv.Key == "service.name" {
+			service = kv.Value.GetStringValue()
+		}
+	}
+	if service != "agentd" {
+		t.Errorf("Expected service.name agentd, got %q", service)
+	}
+	if spans := payload.ResourceSpans[0].ScopeSpans[0].Spans; len(spans) != 1 || spans[0].Name != "stage.forecasting" {
+		t.Errorf("Unexpected spans: %v", spans)
+	}
+}
+
+func TestNewProviderFromEnvWithoutEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	if tp, err := NewProviderFromEnv(context.Background(), "agentd"); tp != nil || err != nil {
+		t.Errorf("Expected no provider without an endpoint, got %v, %v", tp, err)
+	}
+}
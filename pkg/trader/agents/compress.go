@@ -0,0 +1,161 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// charsPerToken is the rough English characters-per-token ratio used by
+// EstimateTokens.
+const charsPerToken = 4
+
+// EstimateTokens approximates the token count of s without a tokenizer.
+func EstimateTokens(s string) int {
+	return (len(s) + charsPerToken - 1) / charsPerToken
+}
+
+// ContextTokens estimates the tokens the variable parts of a market context
+// add to a prompt: question, description, tags, news, and related markets.
+func ContextTokens(mktCtx *MarketContext) int {
+	n := EstimateTokens(mktCtx.Question) + EstimateTokens(mktCtx.Description)
+	for _, s := range mktCtx.Tags {
+		n += EstimateTokens(s)
+	}
+	for _, s := range mktCtx.NewsSnippets {
+		n += EstimateTokens(s)
+	}
+	for _, s := range mktCtx.RelatedMarkets {
+		n += EstimateTokens(s)
+	}
+	return n
+}
+
+// ContextCompressor shrinks a market context to fit a token budget before it
+// is sent to the forecasting models. It must not modify mktCtx.
+type ContextCompressor interface {
+	Compress(ctx context.Context, mktCtx *MarketContext, maxTokens int) (*MarketContext, error)
+}
+
+// HeuristicCompressor fits a context to the budget without a model call. It
+// drops related markets from the end of the list, shortens news snippets and
+// then drops them from the end, and finally truncates the description. The
+// question and tags are never cut, so a budget smaller than them cannot be met.
+type HeuristicCompressor struct{}
+
+// minSnippetChars is the shortest a news snippet is truncated to before it
+// is dropped instead.
+const minSnippetChars = 80
+
+// Compress implements ContextCompressor.
+func (HeuristicCompressor) Compress(ctx context.Context, mktCtx *MarketContext, maxTokens int) (*MarketContext, error) {
+	out := *mktCtx
+	out.NewsSnippets = append([]string(nil), mktCtx.NewsSnippets...)
+	out.RelatedMarkets = append([]string(nil), mktCtx.RelatedMarkets...)
+
+	for ContextTokens(&out) > maxTokens && len(out.RelatedMarkets) > 0 {
+		out.RelatedMarkets = out.RelatedMarkets[:len(out.RelatedMarkets)-1]
+	}
+
+	// Keep the first snippets and shorten them before giving any up
+	for i := range out.NewsSnippets {
+		if ContextTokens(&out) <= maxTokens {
+			break
+		}
+		out.NewsSnippets[i] = truncateWords(out.NewsSnippets[i], minSnippetChars)
+	}
+	for ContextTokens(&out) > maxTokens && len(out.NewsSnippets) > 0 {
+		out.NewsSnippets = out.NewsSnippets[:len(out.NewsSnippets)-1]
+	}
+
+	if over := ContextTokens(&out) - maxTokens; over > 0 {
+		out.Description = truncateWords(out.Description, len(out.Description)-over*charsPerToken)
+	}
+
+	return &out, nil
+}
+
+// truncateWords cuts s to at most n bytes at a word boundary, marking the cut.
+func truncateWords(s string, n int) string {
+	const ellipsis = "..."
+	if len(s) <= n {
+		return s
+	}
+	if n <= len(ellipsis) {
+		return ""
+	}
+	cut := s[:n-len(ellipsis)]
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + ellipsis
+}
+
+// LLMCompressor summarizes news and related markets with a cheap model, e.g.
+// a local one, and falls back to HeuristicCompressor for whatever the summary
+// leaves over budget.
+type LLMCompressor struct {
+	Client LLMClient
+}
+
+// NewLLMCompressor creates a compressor that summarizes with client.
+func NewLLMCompressor(client LLMClient) *LLMCompressor {
+	return &LLMCompressor{Client: client}
+}
+
+const compressSystemPrompt = `You condense background material for a forecaster. Keep concrete facts, numbers, dates, and anything that bears on the question. Drop repetition and filler. Output plain bullet points, one per line, starting with "- ".`
+
+// Compress implements ContextCompressor.
+func (c *LLMCompressor) Compress(ctx context.Context, mktCtx *MarketContext, maxTokens int) (*MarketContext, error) {
+	out := *mktCtx
+	out.NewsSnippets = nil
+	out.RelatedMarkets = nil
+
+	budget := maxTokens - ContextTokens(&out)
+	if budget <= 0 {
+		return HeuristicCompressor{}.Compress(ctx, mktCtx, maxTokens)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Question: %s\n\nSummarize the following in under %d words.\n", mktCtx.Question, budget*3/4)
+	if len(mktCtx.NewsSnippets) > 0 {
+		b.WriteString("\nNews:\n")
+		for _, s := range mktCtx.NewsSnippets {
+			fmt.Fprintf(&b, "- %s\n", s)
+		}
+	}
+	if len(mktCtx.RelatedMarkets) > 0 {
+		b.WriteString("\nRelated markets:\n")
+		for _, s := range mktCtx.RelatedMarkets {
+			fmt.Fprintf(&b, "- %s\n", s)
+		}
+	}
+
+	summary, err := c.Client.Complete(ctx, b.String(), compressSystemPrompt)
+	if err != nil {
+		return nil, fmt.Errorf("summarize context: %w", err)
+	}
+	for _, line := range strings.Split(summary, "\n") {
+		if line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- ")); line != "" {
+			out.NewsSnippets = append(out.NewsSnippets, line)
+		}
+	}
+
+	return HeuristicCompressor{}.Compress(ctx, &out, maxTokens)
+}
+
+// compressContext returns mktCtx fitted to the MaxContextTokens budget, or
+// mktCtx itself if there is no budget or it already fits. A failing
+// compressor falls back to HeuristicCompressor.
+func (f *Forecaster) compressContext(ctx context.Context, mktCtx *MarketContext) *MarketContext {
+	if f.maxContextTokens <= 0 || ContextTokens(mktCtx) <= f.maxContextTokens {
+		return mktCtx
+	}
+	if f.compressor != nil {
+		if compressed, err := f.compressor.Compress(ctx, mktCtx, f.maxContextTokens); err == nil {
+			return compressed
+		}
+	}
+	compressed, _ := HeuristicCompressor{}.Compress(ctx, mktCtx, f.maxContextTokens)
+	return compressed
+}
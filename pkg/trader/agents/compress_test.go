@@ -0,0 +1,111 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// promptRecorder is an LLMClient that records the prompts it receives.
+type promptRecorder struct {
+	mockLLMClient
+	mu      sync.Mutex
+	prompts []string
+}
+
+func (r *promptRecorder) Complete(ctx context.Context, prompt string, systemPrompt string) (string, error) {
+	r.mu.Lock()
+	r.prompts = append(r.prompts, prompt)
+	r.mu.Unlock()
+	return r.mockLLMClient.Complete(ctx, prompt, systemPrompt)
+}
+
+func longContext() *MarketContext {
+	mktCtx := &MarketContext{
+		TokenID:      "token1",
+		Question:     "Will BTC reach $100k by end of 2025?",
+		Description:  strings.Repeat("Resolves YES if Bitcoin trades at or above $100,000 on a major exchange. ", 20),
+		CurrentPrice: decimal.NewFromFloat(0.45),
+		Tags:         []string{"crypto"},
+	}
+	for i := 0; i < 40; i++ {
+		mktCtx.NewsSnippets = append(mktCtx.NewsSnippets, fmt.Sprintf("Headline %d: %s", i, strings.Repeat("analysts weigh ETF flows and halving supply effects ", 6)))
+		mktCtx.RelatedMarkets = append(mktCtx.RelatedMarkets, fmt.Sprintf("Will BTC reach $%dk by end of 2025?", 60+i))
+	}
+	return mktCtx
+}
+
+func TestHeuristicCompressor(t *testing.T) {
+	mktCtx := longContext()
+	before := ContextTokens(mktCtx)
+
+	for _, budget := range []int{2000, 500, 60} {
+		compressed, err := HeuristicCompressor{}.Compress(context.Background(), mktCtx, budget)
+		if err != nil {
+			t.Fatalf("Compress failed: %v", err)
+		}
+		if got := ContextTokens(compressed); got > budget {
+			t.Errorf("Budget %d: compressed context has %d tokens", budget, got)
+		}
+		if compressed.Question != mktCtx.Question {
+			t.Errorf("Budget %d: question was changed", budget)
+		}
+	}
+
+	if ContextTokens(mktCtx) != before || len(mktCtx.NewsSnippets) != 40 {
+		t.Error("Compress modified its input")
+	}
+}
+
+func TestForecastSingle_CompressesOverBudgetContext(t *testing.T) {
+	const budget = 400
+
+	llm := &promptRecorder{mockLLMClient: *newMockLLMClient(ProviderClaude, 0.6, 0.8)}
+	summarizer := &promptRecorder{mockLLMClient: mockLLMClient{
+		provider: ProviderLocal,
+		response: "- ETF inflows hit a record\n- Halving cut new supply\n",
+	}}
+	f := NewForecaster(&ForecasterConfig{
+		Clients:           map[LLMProvider]LLMClient{ProviderClaude: llm},
+		MaxContextTokens:  budget,
+		ContextCompressor: NewLLMCompressor(summarizer),
+	})
+
+	mktCtx := longContext()
+	if ContextTokens(mktCtx) <= budget {
+		t.Fatalf("Test context should be over budget, has %d tokens", ContextTokens(mktCtx))
+	}
+	uncompressed := len(f.buildPrompt(mktCtx))
+
+	if _, err := f.ForecastSingle(context.Background(), mktCtx, ProviderClaude); err != nil {
+		t.Fatalf("ForecastSingle failed: %v", err)
+	}
+
+	if len(summarizer.prompts) != 1 {
+		t.Fatalf("Expected one summarization call, got %d", len(summarizer.prompts))
+	}
+	prompt := llm.prompts[0]
+	if !strings.Contains(prompt, "ETF inflows hit a record") {
+		t.Error("Forecast prompt should carry the summary")
+	}
+	if strings.Contains(prompt, "Headline 0") {
+		t.Error("Forecast prompt should not carry the raw news")
+	}
+	if len(prompt) >= uncompressed {
+		t.Errorf("Expected a shorter prompt, got %d >= %d bytes", len(prompt), uncompressed)
+	}
+
+	// A failing summarizer falls back to the heuristic
+	summarizer.err = errors.New("local model down")
+	if _, err := f.ForecastSingle(context.Background(), mktCtx, ProviderClaude); err != nil {
+		t.Fatalf("ForecastSingle failed with broken compressor: %v", err)
+	}
+	if !strings.Contains(llm.prompts[1], "Headline 0") {
+		t.Error("Heuristic fallback should keep the leading news")
+	}
+}
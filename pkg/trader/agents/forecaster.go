@@ -4,14 +4,19 @@ package agents
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/phenomenon0/polymarket-agents/pkg/tracing"
+
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // LLMProvider represents an LLM provider.
@@ -21,14 +26,63 @@ const (
 	ProviderClaude   LLMProvider = "claude"
 	ProviderGPT4     LLMProvider = "gpt4"
 	ProviderDeepSeek LLMProvider = "deepseek"
+	ProviderLocal    LLMProvider = "local" // Ollama or other self-hosted model
+)
+
+// DefaultFallbackOrder is the provider order ForecastWithFallback uses when
+// ForecasterConfig.FallbackOrder is empty.
+var DefaultFallbackOrder = []LLMProvider{ProviderClaude, ProviderGPT4, ProviderDeepSeek, ProviderLocal}
+
+var (
+	// ErrForecastTimeout is returned when a call exceeds
+	// ForecastOptions.MaxLatency.
+	ErrForecastTimeout = errors.New("forecast exceeded max latency")
+
+	// ErrParseResponse is returned when a provider answered but its output
+	// could not be parsed. ForecastWithFallback does not fall back on it.
+	ErrParseResponse = errors.New("failed to parse response")
 )
 
+// ErrorClass buckets a forecast error for metrics labels: "timeout",
+// "canceled", "parse", or "error".
+func ErrorClass(err error) string {
+	switch {
+	case errors.Is(err, ErrForecastTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, ErrParseResponse):
+		return "parse"
+	default:
+		return "error"
+	}
+}
+
 // LLMClient is an interface for LLM providers.
 type LLMClient interface {
 	Complete(ctx context.Context, prompt string, systemPrompt string) (string, error)
 	Provider() LLMProvider
 }
 
+// ForecastOptions overrides sampling settings for a single forecast call,
+// e.g. for calibration experiments across temperatures or models. Only
+// clients implementing OptionsClient honor Temperature and ModelOverride;
+// MaxLatency applies to every client.
+type ForecastOptions struct {
+	Temperature   *float64 // Nil uses the client's configured temperature; 0 is sent as 0
+	ModelOverride string   // Empty uses the client's configured model
+
+	// MaxLatency abandons the LLM call with ErrForecastTimeout once exceeded.
+	// Zero means no limit beyond ctx.
+	MaxLatency time.Duration
+}
+
+// OptionsClient is an LLMClient that accepts per-request overrides.
+type OptionsClient interface {
+	LLMClient
+	CompleteWithOptions(ctx context.Context, prompt string, systemPrompt string, opts ForecastOptions) (string, error)
+}
+
 // Forecast represents a probability forecast for a market.
 type Forecast struct {
 	TokenID     string          `json:"token_id"`
@@ -40,6 +94,24 @@ type Forecast struct {
 	Provider    LLMProvider     `json:"provider"`
 	Timestamp   time.Time       `json:"timestamp"`
 	LatencyMs   int64           `json:"latency_ms"`
+
+	// Explanation is set when the model returned structured factors.
+	Explanation *ForecastExplanation `json:"explanation,omitempty"`
+}
+
+// ForecastExplanation is the structured reasoning behind a forecast.
+type ForecastExplanation struct {
+	BaseRate         decimal.Decimal `json:"base_rate"`
+	Factors          []Factor        `json:"factors"`
+	FinalProbability decimal.Decimal `json:"final_probability"`
+	Reasoning        string          `json:"reasoning"` // Freeform reasoning, or everything if the model gave no factors
+}
+
+// Factor is a single piece of evidence and how far it moved the estimate.
+type Factor struct {
+	Name       string          `json:"name"`
+	Evidence   string          `json:"evidence"`
+	Adjustment decimal.Decimal `json:"adjustment"` // Signed change in probability (-1 to 1)
 }
 
 // EnsembleForecast combines forecasts from multiple models.
@@ -49,9 +121,29 @@ type EnsembleForecast struct {
 	Question            string          `json:"question"`
 	Probability         decimal.Decimal `json:"probability"` // Weighted average
 	Confidence          decimal.Decimal `json:"confidence"`
-	Disagreement        decimal.Decimal `json:"disagreement"` // Std dev of forecasts
-	IndividualForecasts []Forecast      `json:"individual_forecasts"`
+	Disagreement        decimal.Decimal `json:"disagreement"`                   // Std dev of forecasts
+	LowerBound          decimal.Decimal `json:"lower_bound"`                    // 10th percentile of members
+	UpperBound          decimal.Decimal `json:"upper_bound"`                    // 90th percentile of members
+	IndividualForecasts []Forecast      `json:"individual_forecasts,omitempty"` // Members, with provider and latency
 	Timestamp           time.Time       `json:"timestamp"`
+	EndDate             time.Time       `json:"end_date,omitempty"` // Market resolution, from MarketContext
+}
+
+// intervalQuantile is the lower quantile of the ensemble's credible interval;
+// the upper is its complement, so the interval spans the middle 80% of members.
+const intervalQuantile = 0.1
+
+// HasInterval reports whether the credible interval was computed, i.e. the
+// ensemble had members.
+func (e *EnsembleForecast) HasInterval() bool {
+	return !e.LowerBound.IsZero() || !e.UpperBound.IsZero()
+}
+
+// IntervalContains reports whether price lies within the credible interval,
+// where the members do not agree on which side of it the outcome falls. It is
+// false without an interval.
+func (e *EnsembleForecast) IntervalContains(price decimal.Decimal) bool {
+	return e.HasInterval() && !price.LessThan(e.LowerBound) && !price.GreaterThan(e.UpperBound)
 }
 
 // MarketContext provides context for forecasting.
@@ -69,11 +161,40 @@ type MarketContext struct {
 	RelatedMarkets []string `json:"related_markets,omitempty"`
 }
 
+// AggregationMethod selects how ensemble probabilities are pooled.
+type AggregationMethod string
+
+const (
+	// AggregationWeightedMean weights each forecast by provider weight × confidence (default).
+	AggregationWeightedMean AggregationMethod = "weighted_mean"
+	// AggregationMedian takes the median probability, ignoring weights.
+	AggregationMedian AggregationMethod = "median"
+	// AggregationTrimmedMean drops the highest and lowest forecast (with 3+
+	// forecasts) and averages the rest.
+	AggregationTrimmedMean AggregationMethod = "trimmed_mean"
+	// AggregationLogOdds averages in log-odds space using the same weights as
+	// AggregationWeightedMean, then maps back to a probability.
+	AggregationLogOdds AggregationMethod = "logodds"
+)
+
 // Forecaster uses multiple LLMs to forecast market probabilities.
 type Forecaster struct {
 	clients      map[LLMProvider]LLMClient
 	weights      map[LLMProvider]decimal.Decimal
 	systemPrompt string
+	aggregation  AggregationMethod
+	fallback     []LLMProvider
+	calibration  map[LLMProvider]Calibration
+
+	ensembleTimeout time.Duration
+	minMembers      int
+	priorWeight     decimal.Decimal
+
+	maxContextTokens int
+	compressor       ContextCompressor
+
+	edgeDecay      EdgeDecay
+	requireOutside bool
 
 	mu       sync.RWMutex
 	cache    map[string]*Forecast // tokenID -> latest forecast
@@ -86,6 +207,105 @@ type ForecasterConfig struct {
 	Weights      map[LLMProvider]float64
 	CacheTTL     time.Duration
 	SystemPrompt string
+
+	// AggregationMethod pools ensemble probabilities. Empty means weighted mean.
+	AggregationMethod AggregationMethod
+
+	// FallbackOrder is the provider order for ForecastWithFallback. Empty
+	// means DefaultFallbackOrder.
+	FallbackOrder []LLMProvider
+
+	// Calibration maps a provider to the transform applied to its raw
+	// forecasts, fitted from that provider's historical calibration data.
+	// Providers without an entry are used uncalibrated.
+	Calibration map[LLMProvider]Calibration
+
+	// ForecastEnsemble returns as soon as MinEnsembleMembers providers have
+	// answered, canceling the rest, and fails if fewer than that have
+	// answered once EnsembleTimeout passes. Zero MinEnsembleMembers waits for
+	// every provider and accepts any that succeed; zero EnsembleTimeout waits
+	// as long as the context allows.
+	EnsembleTimeout    time.Duration
+	MinEnsembleMembers int
+
+	// MarketPriorWeight shrinks the ensemble probability toward the market
+	// price in proportion to its lack of confidence: the market gets
+	// MarketPriorWeight × (1 − confidence) of the blend. In [0, 1]; zero
+	// disables blending.
+	MarketPriorWeight float64
+
+	// MaxContextTokens caps the estimated tokens of the market context (see
+	// ContextTokens) sent to the forecasting models. Larger contexts are
+	// shrunk by ContextCompressor, or HeuristicCompressor if that is nil or
+	// fails. Zero sends the context as is.
+	MaxContextTokens  int
+	ContextCompressor ContextCompressor
+
+	// EdgeDecay scales the minimum edge GenerateSignal requires by the
+	// forecast's time to resolution. The zero value leaves it unscaled.
+	EdgeDecay EdgeDecay
+
+	// RequirePriceOutsideInterval makes GenerateSignal hold when the market
+	// price lies within the ensemble's credible interval, however large the
+	// edge on the point probability.
+	RequirePriceOutsideInterval bool
+}
+
+// EdgeDecay scales a minimum edge by time to resolution: edge on a market
+// resolving soon realizes before it can evaporate, so it needs less margin
+// than the same edge months out. The multiplier is NearMultiplier at or
+// inside Near, FarMultiplier at or beyond Far, and linear in between.
+type EdgeDecay struct {
+	Near           time.Duration
+	Far            time.Duration
+	NearMultiplier float64 // e.g. 0.5 halves the minimum edge near resolution
+	FarMultiplier  float64 // e.g. 2 doubles it for far-dated markets
+}
+
+// Multiplier returns the minimum-edge multiplier for a market resolving in
+// ttr. It is 1 for the zero EdgeDecay.
+func (d EdgeDecay) Multiplier(ttr time.Duration) float64 {
+	if d.Far <= d.Near {
+		return 1
+	}
+	switch {
+	case ttr <= d.Near:
+		return d.NearMultiplier
+	case ttr >= d.Far:
+		return d.FarMultiplier
+	}
+	frac := float64(ttr-d.Near) / float64(d.Far-d.Near)
+	return d.NearMultiplier + frac*(d.FarMultiplier-d.NearMultiplier)
+}
+
+// Calibration is a Platt-scaling transform on a provider's raw output:
+// p' = sigmoid(Slope*logit(p) + Intercept). A Slope below 1 shrinks
+// overconfident forecasts toward 0.5 (Slope = 1/T is a log-odds temperature);
+// Intercept shifts them toward a base rate. The zero value is the identity.
+type Calibration struct {
+	Slope     float64 // Zero means 1
+	Intercept float64
+
+	// MaxConfidence caps the reported confidence. Zero leaves it unchanged.
+	MaxConfidence float64
+}
+
+// Apply returns the calibrated probability and confidence.
+func (c Calibration) Apply(prob, conf float64) (float64, float64) {
+	const eps = 1e-4
+
+	slope := c.Slope
+	if slope == 0 {
+		slope = 1
+	}
+	if slope != 1 || c.Intercept != 0 {
+		p := math.Min(math.Max(prob, eps), 1-eps)
+		prob = 1 / (1 + math.Exp(-(slope*math.Log(p/(1-p)) + c.Intercept)))
+	}
+	if c.MaxConfidence > 0 && conf > c.MaxConfidence {
+		conf = c.MaxConfidence
+	}
+	return prob, conf
 }
 
 // DefaultSystemPrompt is the default superforecaster prompt.
@@ -110,6 +330,32 @@ Output format (JSON):
 
 Important: Only output valid JSON, nothing else.`
 
+// ExplainSystemPrompt is a variant of DefaultSystemPrompt that asks for structured factors.
+const ExplainSystemPrompt = `You are an expert superforecaster trained in probabilistic reasoning and calibration.
+Your task is to estimate the probability of an event occurring based on the provided information,
+and to show how you got there as discrete, auditable factors.
+
+Guidelines:
+1. Start from a base rate drawn from a reference class
+2. Adjust for each piece of specific evidence, one factor at a time
+3. Include a time-decay adjustment for the time remaining until resolution
+4. Be well-calibrated: when you say 70%, you should be right 70% of the time
+5. The base rate plus all adjustments should equal your final probability
+
+Output format (JSON):
+{
+  "base_rate": 0.XX,    // Reference-class base rate (0-1)
+  "factors": [
+    {"name": "key evidence", "evidence": "what you observed", "adjustment": 0.XX},
+    {"name": "time decay", "evidence": "time remaining", "adjustment": -0.XX}
+  ],
+  "probability": 0.XX,  // Your final probability estimate (0-1)
+  "confidence": 0.XX,   // Your confidence in this estimate (0-1)
+  "reasoning": "A short summary of your reasoning"
+}
+
+Important: Only output valid JSON, nothing else.`
+
 // NewForecaster creates a new forecaster.
 func NewForecaster(config *ForecasterConfig) *Forecaster {
 	f := &Forecaster{
@@ -130,6 +376,20 @@ func NewForecaster(config *ForecasterConfig) *Forecaster {
 		if config.SystemPrompt != "" {
 			f.systemPrompt = config.SystemPrompt
 		}
+		f.aggregation = config.AggregationMethod
+		f.fallback = config.FallbackOrder
+		f.calibration = config.Calibration
+		f.ensembleTimeout = config.EnsembleTimeout
+		f.minMembers = config.MinEnsembleMembers
+		f.priorWeight = decimal.NewFromFloat(math.Min(math.Max(config.MarketPriorWeight, 0), 1))
+		f.maxContextTokens = config.MaxContextTokens
+		f.compressor = config.ContextCompressor
+		f.edgeDecay = config.EdgeDecay
+		f.requireOutside = config.RequirePriceOutsideInterval
+	}
+
+	if len(f.fallback) == 0 {
+		f.fallback = DefaultFallbackOrder
 	}
 
 	if f.systemPrompt == "" {
@@ -158,8 +418,79 @@ func (f *Forecaster) AddClient(client LLMClient, weight float64) {
 	f.weights[provider] = decimal.NewFromFloat(weight)
 }
 
+// warmupPrompt is the trivial prompt Warmup sends to each provider.
+const warmupPrompt = "Reply with the single word OK."
+
+// Warmup sends a trivial prompt to every configured client in parallel and
+// returns each provider's error, nil for those that answered. Use it at
+// startup to catch bad keys and unreachable providers.
+func (f *Forecaster) Warmup(ctx context.Context) map[LLMProvider]error {
+	f.mu.RLock()
+	clients := make(map[LLMProvider]LLMClient, len(f.clients))
+	for p, c := range f.clients {
+		clients[p] = c
+	}
+	f.mu.RUnlock()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[LLMProvider]error, len(clients))
+	)
+	for provider, client := range clients {
+		wg.Add(1)
+		go func(p LLMProvider, c LLMClient) {
+			defer wg.Done()
+
+			_, err := c.Complete(ctx, warmupPrompt, "")
+			mu.Lock()
+			results[p] = err
+			mu.Unlock()
+		}(provider, client)
+	}
+	wg.Wait()
+
+	return results
+}
+
 // ForecastSingle gets a forecast from a single provider.
-func (f *Forecaster) ForecastSingle(ctx context.Context, mktCtx *MarketContext, provider LLMProvider) (*Forecast, error) {
+// At most one ForecastOptions may be passed to override sampling settings.
+func (f *Forecaster) ForecastSingle(ctx context.Context, mktCtx *MarketContext, provider LLMProvider, opts ...ForecastOptions) (*Forecast, error) {
+	return f.forecastWithPrompt(ctx, mktCtx, provider, f.systemPrompt, opts...)
+}
+
+// Explain gets a forecast from a single provider along with the structured
+// factors behind it. If the model ignores the factor schema, the explanation
+// carries only the final probability and the freeform reasoning.
+func (f *Forecaster) Explain(ctx context.Context, mktCtx *MarketContext, provider LLMProvider) (*ForecastExplanation, error) {
+	forecast, err := f.forecastWithPrompt(ctx, mktCtx, provider, ExplainSystemPrompt)
+	if err != nil {
+		return nil, err
+	}
+
+	if forecast.Explanation != nil {
+		return forecast.Explanation, nil
+	}
+	return &ForecastExplanation{
+		FinalProbability: forecast.Probability,
+		Reasoning:        forecast.Reasoning,
+	}, nil
+}
+
+func (f *Forecaster) forecastWithPrompt(ctx context.Context, mktCtx *MarketContext, provider LLMProvider, systemPrompt string, opts ...ForecastOptions) (forecast *Forecast, err error) {
+	ctx, span := tracing.Start(ctx, "llm.forecast",
+		attribute.String("llm.provider", string(provider)),
+		attribute.String("market.token_id", mktCtx.TokenID))
+	defer func() {
+		if forecast != nil {
+			span.SetAttributes(
+				attribute.Float64("forecast.probability", forecast.Probability.InexactFloat64()),
+				attribute.Float64("forecast.confidence", forecast.Confidence.InexactFloat64()),
+			)
+		}
+		tracing.End(span, err)
+	}()
+
 	f.mu.RLock()
 	client, ok := f.clients[provider]
 	f.mu.RUnlock()
@@ -168,19 +499,39 @@ func (f *Forecaster) ForecastSingle(ctx context.Context, mktCtx *MarketContext,
 		return nil, fmt.Errorf("provider %s not configured", provider)
 	}
 
-	prompt := f.buildPrompt(mktCtx)
+	prompt := f.buildPrompt(f.compressContext(ctx, mktCtx))
+
+	var opt ForecastOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
 
 	start := time.Now()
-	response, err := client.Complete(ctx, prompt, f.systemPrompt)
+	var response string
+	if opt.MaxLatency > 0 {
+		response, err = completeWithin(ctx, opt.MaxLatency, func(callCtx context.Context) (string, error) {
+			return complete(callCtx, client, prompt, systemPrompt, opts)
+		})
+	} else {
+		response, err = complete(ctx, client, prompt, systemPrompt, opts)
+	}
 	latency := time.Since(start).Milliseconds()
 
 	if err != nil {
+		if errors.Is(err, ErrForecastTimeout) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("LLM call failed: %w", err)
 	}
 
-	forecast, err := f.parseResponse(response)
+	forecast, err = f.parseResponse(response)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrParseResponse, err)
+	}
+	if cal, ok := f.calibration[provider]; ok {
+		prob, conf := cal.Apply(forecast.Probability.InexactFloat64(), forecast.Confidence.InexactFloat64())
+		forecast.Probability = decimal.NewFromFloat(prob)
+		forecast.Confidence = decimal.NewFromFloat(conf)
 	}
 
 	forecast.TokenID = mktCtx.TokenID
@@ -193,8 +544,60 @@ func (f *Forecaster) ForecastSingle(ctx context.Context, mktCtx *MarketContext,
 	return forecast, nil
 }
 
+// complete calls the client, passing options through if it supports them.
+func complete(ctx context.Context, client LLMClient, prompt, systemPrompt string, opts []ForecastOptions) (string, error) {
+	if oc, ok := client.(OptionsClient); ok && len(opts) > 0 {
+		return oc.CompleteWithOptions(ctx, prompt, systemPrompt, opts[0])
+	}
+	return client.Complete(ctx, prompt, systemPrompt)
+}
+
+// completeWithin runs call with a deadline of maxLatency, returning
+// ErrForecastTimeout as soon as it passes even if the client ignores
+// cancellation. The abandoned call's result is discarded.
+func completeWithin(ctx context.Context, maxLatency time.Duration, call func(context.Context) (string, error)) (string, error) {
+	callCtx, cancel := context.WithTimeout(ctx, maxLatency)
+	defer cancel()
+
+	type result struct {
+		response string
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		response, err := call(callCtx)
+		done <- result{response, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("%w (%v)", ErrForecastTimeout, maxLatency)
+		}
+		return r.response, r.err
+	case <-callCtx.Done():
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("%w (%v)", ErrForecastTimeout, maxLatency)
+	}
+}
+
 // ForecastEnsemble gets forecasts from all providers and combines them.
-func (f *Forecaster) ForecastEnsemble(ctx context.Context, mktCtx *MarketContext) (*EnsembleForecast, error) {
+// Any ForecastOptions apply to every provider.
+func (f *Forecaster) ForecastEnsemble(ctx context.Context, mktCtx *MarketContext, opts ...ForecastOptions) (ensemble *EnsembleForecast, err error) {
+	ctx, span := tracing.Start(ctx, "forecast.ensemble", attribute.String("market.token_id", mktCtx.TokenID))
+	defer func() {
+		if ensemble != nil {
+			span.SetAttributes(
+				attribute.Int("ensemble.members", len(ensemble.IndividualForecasts)),
+				attribute.Float64("forecast.probability", ensemble.Probability.InexactFloat64()),
+				attribute.Float64("forecast.disagreement", ensemble.Disagreement.InexactFloat64()),
+			)
+		}
+		tracing.End(span, err)
+	}()
+
 	f.mu.RLock()
 	clients := make(map[LLMProvider]LLMClient, len(f.clients))
 	weights := make(map[LLMProvider]decimal.Decimal, len(f.weights))
@@ -210,45 +613,85 @@ func (f *Forecaster) ForecastEnsemble(ctx context.Context, mktCtx *MarketContext
 		return nil, fmt.Errorf("no LLM clients configured")
 	}
 
-	// Run forecasts in parallel
-	var wg sync.WaitGroup
+	// Compress once rather than per member
+	mktCtx = f.compressContext(ctx, mktCtx)
+
+	// Stop early once enough members answer; need is that count, floor the
+	// fewest we accept when everyone has finished or time is up.
+	need, floor := len(clients), 1
+	if f.minMembers > 0 {
+		need = min(f.minMembers, len(clients))
+		floor = need
+	}
+
+	// Stragglers are canceled when we return
+	memberCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Run forecasts in parallel. Channels are buffered so workers never block
+	// on send and exit as soon as their call returns, even if we stop waiting.
 	results := make(chan *Forecast, len(clients))
-	errors := make(chan error, len(clients))
+	errs := make(chan error, len(clients))
 
 	for provider := range clients {
-		wg.Add(1)
 		go func(p LLMProvider) {
-			defer wg.Done()
-
-			forecast, err := f.ForecastSingle(ctx, mktCtx, p)
+			forecast, err := f.ForecastSingle(memberCtx, mktCtx, p, opts...)
 			if err != nil {
-				errors <- fmt.Errorf("%s: %w", p, err)
+				errs <- fmt.Errorf("%s: %w", p, err)
 				return
 			}
 			results <- forecast
 		}(provider)
 	}
 
-	wg.Wait()
-	close(results)
-	close(errors)
+	var timeout <-chan time.Time
+	if f.ensembleTimeout > 0 {
+		timer := time.NewTimer(f.ensembleTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
 
-	// Collect results
+	// Collect results. Don't hold up shutdown for providers that ignore
+	// cancellation.
 	forecasts := make([]Forecast, 0, len(clients))
-	for forecast := range results {
-		forecasts = append(forecasts, *forecast)
+	var firstErr error
+	timedOut := false
+collect:
+	for pending := len(clients); pending > 0 && len(forecasts) < need; pending-- {
+		select {
+		case forecast := <-results:
+			forecasts = append(forecasts, *forecast)
+		case err := <-errs:
+			if firstErr == nil {
+				firstErr = err
+			}
+		case <-timeout:
+			timedOut = true
+			break collect
+		case <-ctx.Done():
+			return nil, fmt.Errorf("ensemble forecast: %w", ctx.Err())
+		}
 	}
 
-	if len(forecasts) == 0 {
-		// Return first error if all failed
-		for err := range errors {
-			return nil, err
+	if len(forecasts) < floor {
+		switch {
+		case timedOut:
+			return nil, fmt.Errorf("ensemble forecast: %d of %d members answered within %s, need %d",
+				len(forecasts), len(clients), f.ensembleTimeout, floor)
+		case len(forecasts) == 0 && firstErr != nil:
+			// Return first error if all failed
+			return nil, firstErr
+		case len(forecasts) == 0:
+			return nil, fmt.Errorf("no forecasts generated")
+		default:
+			return nil, fmt.Errorf("ensemble forecast: only %d of %d members succeeded, need %d: %w",
+				len(forecasts), len(clients), floor, firstErr)
 		}
-		return nil, fmt.Errorf("no forecasts generated")
 	}
 
 	// Calculate weighted ensemble
-	ensemble := f.combineForecasts(mktCtx, forecasts, weights)
+	ensemble = f.combineForecasts(mktCtx, forecasts, weights)
+	ensemble.Probability = f.blendMarketPrior(ensemble.Probability, ensemble.Confidence, mktCtx.CurrentPrice)
 
 	// Cache the result
 	f.mu.Lock()
@@ -260,13 +703,13 @@ func (f *Forecaster) ForecastEnsemble(ctx context.Context, mktCtx *MarketContext
 	return ensemble, nil
 }
 
-// ForecastWithFallback tries providers in order until one succeeds.
+// ForecastWithFallback tries providers in the configured fallback order,
+// skipping unconfigured ones, until one succeeds. It moves on after transport
+// failures (rate limits, timeouts, unreachable providers) but stops on a
+// parse failure or when ctx is done, since retrying elsewhere won't help.
 func (f *Forecaster) ForecastWithFallback(ctx context.Context, mktCtx *MarketContext) (*Forecast, error) {
-	// Order: Claude -> GPT-4 -> DeepSeek
-	providers := []LLMProvider{ProviderClaude, ProviderGPT4, ProviderDeepSeek}
-
 	var lastErr error
-	for _, provider := range providers {
+	for _, provider := range f.fallback {
 		f.mu.RLock()
 		_, ok := f.clients[provider]
 		f.mu.RUnlock()
@@ -277,6 +720,9 @@ func (f *Forecaster) ForecastWithFallback(ctx context.Context, mktCtx *MarketCon
 
 		forecast, err := f.ForecastSingle(ctx, mktCtx, provider)
 		if err != nil {
+			if errors.Is(err, ErrParseResponse) || ctx.Err() != nil {
+				return nil, fmt.Errorf("%s: %w", provider, err)
+			}
 			lastErr = err
 			continue
 		}
@@ -359,75 +805,49 @@ Provide your forecast in JSON format.`
 	return prompt
 }
 
+// parseResponse extracts a forecast from a model's reply. Replies are often
+// messy: fenced in markdown, surrounded by prose, preceded by an echoed
+// schema, or with trailing commas, capitalized keys, percentages, or fields
+// nested under "forecast"/"prediction". Each JSON object in the reply is
+// tried in order and the first that yields a probability wins.
 func (f *Forecaster) parseResponse(response string) (*Forecast, error) {
-	// Strip markdown code blocks if present
 	response = stripMarkdownCodeBlocks(response)
 
-	// Extract JSON from response
-	jsonStr := extractJSON(response)
-	if jsonStr == "" {
+	candidates := jsonObjects(response)
+	if len(candidates) == 0 {
 		return nil, fmt.Errorf("no JSON found in response")
 	}
 
-	// Parse into generic map first to handle various structures
-	var raw map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonStr), &raw); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
-	}
-
-	// Extract probability - check multiple possible locations
-	prob := extractFloat(raw, "probability")
-	if prob == 0 {
-		// Try nested under "forecast"
-		if forecast, ok := raw["forecast"].(map[string]interface{}); ok {
-			prob = extractFloat(forecast, "probability")
+	var lastErr error
+	for _, candidate := range candidates {
+		var raw map[string]interface{}
+		if err := json.Unmarshal([]byte(stripTrailingCommas(candidate)), &raw); err != nil {
+			lastErr = fmt.Errorf("failed to parse JSON: %w", err)
+			continue
 		}
-	}
-
-	// Extract confidence - check multiple possible locations
-	conf := extractFloat(raw, "confidence")
-	if conf == 0 {
-		if forecast, ok := raw["forecast"].(map[string]interface{}); ok {
-			conf = extractFloat(forecast, "confidence")
+		forecast, err := parseForecastObject(raw)
+		if err != nil {
+			lastErr = err
+			continue
 		}
+		return forecast, nil
 	}
+	return nil, lastErr
+}
 
-	// Extract reasoning - check multiple possible locations
-	reasoning := extractString(raw, "reasoning")
-	if reasoning == "" {
-		reasoning = extractString(raw, "rationale")
-		if reasoning == "" {
-			if forecast, ok := raw["forecast"].(map[string]interface{}); ok {
-				reasoning = extractString(forecast, "reasoning")
-				if reasoning == "" {
-					reasoning = extractString(forecast, "rationale")
-				}
-			}
+// parseForecastObject reads a forecast from one decoded JSON object.
+func parseForecastObject(raw map[string]interface{}) (*Forecast, error) {
+	// Fields may sit at the top level or under a wrapper object
+	scopes := []map[string]interface{}{raw}
+	for _, key := range []string{"forecast", "prediction"} {
+		if nested, ok := lookupKey(raw, key).(map[string]interface{}); ok {
+			scopes = append(scopes, nested)
 		}
 	}
 
-	// Handle rationale as array
-	if reasoning == "" {
-		if rationale, ok := raw["rationale"].([]interface{}); ok {
-			var parts []string
-			for _, r := range rationale {
-				if s, ok := r.(string); ok {
-					parts = append(parts, s)
-				}
-			}
-			reasoning = strings.Join(parts, " ")
-		}
-		if forecast, ok := raw["forecast"].(map[string]interface{}); ok {
-			if rationale, ok := forecast["rationale"].([]interface{}); ok {
-				var parts []string
-				for _, r := range rationale {
-					if s, ok := r.(string); ok {
-						parts = append(parts, s)
-					}
-				}
-				reasoning = strings.Join(parts, " ")
-			}
-		}
+	prob, ok := scopedFloat(scopes, "probability")
+	if !ok {
+		return nil, fmt.Errorf("no probability in response")
 	}
 
 	// Normalize probability if given as percentage (e.g., 30 instead of 0.30)
@@ -440,18 +860,78 @@ func (f *Forecaster) parseResponse(response string) (*Forecast, error) {
 		return nil, fmt.Errorf("probability out of range: %f", prob)
 	}
 
+	conf, _ := scopedFloat(scopes, "confidence")
+	if conf > 1 && conf <= 100 {
+		conf = conf / 100.0
+	}
+
 	// Default confidence if not found or invalid
 	if conf <= 0 || conf > 1 {
 		conf = 0.7 // Default confidence
 	}
 
+	var reasoning string
+	for _, scope := range scopes {
+		for _, key := range []string{"reasoning", "rationale"} {
+			if reasoning == "" {
+				reasoning = extractString(scope, key)
+			}
+		}
+	}
+
 	return &Forecast{
 		Probability: decimal.NewFromFloat(prob),
 		Confidence:  decimal.NewFromFloat(conf),
 		Reasoning:   reasoning,
+		Explanation: parseExplanation(raw, prob, reasoning),
 	}, nil
 }
 
+// scopedFloat returns key from the first scope that has it as a number.
+func scopedFloat(scopes []map[string]interface{}, key string) (float64, bool) {
+	for _, scope := range scopes {
+		if v, ok := lookupFloat(scope, key); ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// parseExplanation extracts structured factors from a response, returning nil
+// if the model didn't provide a base rate or any factors.
+func parseExplanation(raw map[string]interface{}, prob float64, reasoning string) *ForecastExplanation {
+	rawFactors, _ := raw["factors"].([]interface{})
+	if _, ok := raw["base_rate"]; !ok && len(rawFactors) == 0 {
+		return nil
+	}
+
+	baseRate := extractFloat(raw, "base_rate")
+	if baseRate > 1 && baseRate <= 100 {
+		baseRate = baseRate / 100.0
+	}
+
+	explanation := &ForecastExplanation{
+		BaseRate:         decimal.NewFromFloat(baseRate),
+		Factors:          make([]Factor, 0, len(rawFactors)),
+		FinalProbability: decimal.NewFromFloat(prob),
+		Reasoning:        reasoning,
+	}
+
+	for _, rf := range rawFactors {
+		m, ok := rf.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		explanation.Factors = append(explanation.Factors, Factor{
+			Name:       extractString(m, "name"),
+			Evidence:   extractString(m, "evidence"),
+			Adjustment: decimal.NewFromFloat(extractFloat(m, "adjustment")),
+		})
+	}
+
+	return explanation
+}
+
 // stripMarkdownCodeBlocks removes ```json ... ``` wrappers
 func stripMarkdownCodeBlocks(s string) string {
 	s = strings.TrimSpace(s)
@@ -469,54 +949,151 @@ func stripMarkdownCodeBlocks(s string) string {
 	return strings.TrimSpace(s)
 }
 
-// extractJSON finds the first complete JSON object in a string
-func extractJSON(s string) string {
+// jsonObjects returns the top-level {...} spans in s, in order. Braces
+// inside JSON strings don't count.
+func jsonObjects(s string) []string {
+	var objects []string
 	start := -1
-	braceCount := 0
+	depth := 0
+	inString, escaped := false, false
 
 	for i, c := range s {
-		if c == '{' {
-			if start == -1 {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			// Quotes only delimit strings inside an object; prose may have
+			// stray ones
+			inString = depth > 0
+		case '{':
+			if depth == 0 {
 				start = i
 			}
-			braceCount++
-		} else if c == '}' {
-			braceCount--
-			if braceCount == 0 && start != -1 {
-				return s[start : i+1]
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+				if depth == 0 {
+					objects = append(objects, s[start:i+1])
+				}
 			}
 		}
 	}
-	return ""
+	return objects
 }
 
-// extractFloat extracts a float from a map
-func extractFloat(m map[string]interface{}, key string) float64 {
-	if v, ok := m[key]; ok {
-		switch val := v.(type) {
-		case float64:
-			return val
-		case int:
-			return float64(val)
-		case string:
-			if f, err := strconv.ParseFloat(val, 64); err == nil {
-				return f
+// stripTrailingCommas drops commas directly before a closing brace or
+// bracket (outside strings), which models often leave in.
+func stripTrailingCommas(s string) string {
+	var b strings.Builder
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
 			}
+			b.WriteByte(c)
+			continue
 		}
+		if c == '"' {
+			inString = true
+		}
+		if c == ',' {
+			rest := strings.TrimLeft(s[i+1:], " \t\r\n")
+			if strings.HasPrefix(rest, "}") || strings.HasPrefix(rest, "]") {
+				continue
+			}
+		}
+		b.WriteByte(c)
 	}
-	return 0
+	return b.String()
 }
 
-// extractString extracts a string from a map
-func extractString(m map[string]interface{}, key string) string {
+// lookupKey returns m[key], falling back to a case-insensitive match.
+func lookupKey(m map[string]interface{}, key string) interface{} {
 	if v, ok := m[key]; ok {
-		if s, ok := v.(string); ok {
-			return s
+		return v
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v
 		}
 	}
+	return nil
+}
+
+// lookupFloat reads a number from m. Strings are accepted, and a "%" suffix
+// divides by 100.
+func lookupFloat(m map[string]interface{}, key string) (float64, bool) {
+	switch val := lookupKey(m, key).(type) {
+	case float64:
+		return val, true
+	case string:
+		val = strings.TrimSpace(val)
+		percent := strings.HasSuffix(val, "%")
+		f, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(val, "%")), 64)
+		if err != nil {
+			return 0, false
+		}
+		if percent {
+			f /= 100
+		}
+		return f, true
+	}
+	return 0, false
+}
+
+// extractFloat extracts a float from a map, 0 if absent
+func extractFloat(m map[string]interface{}, key string) float64 {
+	f, _ := lookupFloat(m, key)
+	return f
+}
+
+// extractString extracts a string from a map. An array of strings is joined
+// with spaces.
+func extractString(m map[string]interface{}, key string) string {
+	switch v := lookupKey(m, key).(type) {
+	case string:
+		return v
+	case []interface{}:
+		var parts []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, " ")
+	}
 	return ""
 }
 
+// blendMarketPrior shrinks prob toward the market price, more so the lower
+// the confidence. It is a no-op without a prior weight or a market price.
+func (f *Forecaster) blendMarketPrior(prob, confidence, marketPrice decimal.Decimal) decimal.Decimal {
+	if !f.priorWeight.IsPositive() || !marketPrice.IsPositive() {
+		return prob
+	}
+	one := decimal.NewFromInt(1)
+	share := f.priorWeight.Mul(one.Sub(decimal.Min(decimal.Max(confidence, decimal.Zero), one)))
+	return prob.Mul(one.Sub(share)).Add(marketPrice.Mul(share))
+}
+
 func (f *Forecaster) combineForecasts(mktCtx *MarketContext, forecasts []Forecast, weights map[LLMProvider]decimal.Decimal) *EnsembleForecast {
 	ensemble := &EnsembleForecast{
 		TokenID:             mktCtx.TokenID,
@@ -524,32 +1101,34 @@ func (f *Forecaster) combineForecasts(mktCtx *MarketContext, forecasts []Forecas
 		Question:            mktCtx.Question,
 		IndividualForecasts: forecasts,
 		Timestamp:           time.Now(),
+		EndDate:             mktCtx.EndDate,
 	}
 
 	if len(forecasts) == 0 {
 		return ensemble
 	}
 
-	// Calculate weighted average
-	totalWeight := decimal.Zero
-	weightedSum := decimal.Zero
+	// Weight by both provider weight and confidence
+	effectiveWeights := make([]decimal.Decimal, len(forecasts))
 	confidenceSum := decimal.Zero
-
-	for _, forecast := range forecasts {
+	for i, forecast := range forecasts {
 		weight := weights[forecast.Provider]
 		if weight.IsZero() {
 			weight = decimal.NewFromFloat(1.0 / float64(len(forecasts)))
 		}
-
-		// Weight by both provider weight and confidence
-		effectiveWeight := weight.Mul(forecast.Confidence)
-		totalWeight = totalWeight.Add(effectiveWeight)
-		weightedSum = weightedSum.Add(forecast.Probability.Mul(effectiveWeight))
+		effectiveWeights[i] = weight.Mul(forecast.Confidence)
 		confidenceSum = confidenceSum.Add(forecast.Confidence)
 	}
 
-	if !totalWeight.IsZero() {
-		ensemble.Probability = weightedSum.Div(totalWeight)
+	switch f.aggregation {
+	case AggregationMedian:
+		ensemble.Probability = medianProbability(forecasts)
+	case AggregationTrimmedMean:
+		ensemble.Probability = trimmedMeanProbability(forecasts)
+	case AggregationLogOdds:
+		ensemble.Probability = logOddsProbability(forecasts, effectiveWeights)
+	default:
+		ensemble.Probability = weightedMeanProbability(forecasts, effectiveWeights)
 	}
 
 	ensemble.Confidence = confidenceSum.Div(decimal.NewFromInt(int64(len(forecasts))))
@@ -566,9 +1145,93 @@ func (f *Forecaster) combineForecasts(mktCtx *MarketContext, forecasts []Forecas
 		ensemble.Disagreement = variance.Pow(decimal.NewFromFloat(0.5))
 	}
 
+	probs := sortedProbabilities(forecasts)
+	ensemble.LowerBound = quantile(probs, intervalQuantile)
+	ensemble.UpperBound = quantile(probs, 1-intervalQuantile)
+
 	return ensemble
 }
 
+// quantile interpolates linearly between the sorted values around q.
+func quantile(sorted []decimal.Decimal, q float64) decimal.Decimal {
+	pos := decimal.NewFromFloat(q).Mul(decimal.NewFromInt(int64(len(sorted) - 1)))
+	i := int(pos.IntPart())
+	if i >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos.Sub(decimal.NewFromInt(int64(i)))
+	return sorted[i].Add(sorted[i+1].Sub(sorted[i]).Mul(frac))
+}
+
+// weightedMeanProbability averages probabilities by weight.
+func weightedMeanProbability(forecasts []Forecast, weights []decimal.Decimal) decimal.Decimal {
+	totalWeight := decimal.Zero
+	weightedSum := decimal.Zero
+	for i, forecast := range forecasts {
+		totalWeight = totalWeight.Add(weights[i])
+		weightedSum = weightedSum.Add(forecast.Probability.Mul(weights[i]))
+	}
+	if totalWeight.IsZero() {
+		return decimal.Zero
+	}
+	return weightedSum.Div(totalWeight)
+}
+
+// sortedProbabilities returns forecast probabilities in ascending order.
+func sortedProbabilities(forecasts []Forecast) []decimal.Decimal {
+	probs := make([]decimal.Decimal, len(forecasts))
+	for i, forecast := range forecasts {
+		probs[i] = forecast.Probability
+	}
+	sort.Slice(probs, func(i, j int) bool { return probs[i].LessThan(probs[j]) })
+	return probs
+}
+
+// medianProbability returns the median probability.
+func medianProbability(forecasts []Forecast) decimal.Decimal {
+	probs := sortedProbabilities(forecasts)
+	mid := len(probs) / 2
+	if len(probs)%2 == 1 {
+		return probs[mid]
+	}
+	return probs[mid-1].Add(probs[mid]).Div(decimal.NewFromInt(2))
+}
+
+// trimmedMeanProbability drops the extremes when there are at least three
+// forecasts and returns the plain mean of the rest.
+func trimmedMeanProbability(forecasts []Forecast) decimal.Decimal {
+	probs := sortedProbabilities(forecasts)
+	if len(probs) >= 3 {
+		probs = probs[1 : len(probs)-1]
+	}
+	sum := decimal.Zero
+	for _, p := range probs {
+		sum = sum.Add(p)
+	}
+	return sum.Div(decimal.NewFromInt(int64(len(probs))))
+}
+
+// logOddsProbability pools probabilities as a weighted mean of their logits.
+// Probabilities are clamped away from 0 and 1 so the logit stays finite.
+func logOddsProbability(forecasts []Forecast, weights []decimal.Decimal) decimal.Decimal {
+	const eps = 1e-4
+
+	totalWeight := 0.0
+	logitSum := 0.0
+	for i, forecast := range forecasts {
+		p := math.Min(math.Max(forecast.Probability.InexactFloat64(), eps), 1-eps)
+		w := weights[i].InexactFloat64()
+		logitSum += w * math.Log(p/(1-p))
+		totalWeight += w
+	}
+	if totalWeight == 0 {
+		return decimal.Zero
+	}
+
+	pooled := 1 / (1 + math.Exp(-logitSum/totalWeight))
+	return decimal.NewFromFloat(pooled)
+}
+
 // --- Trading Signal Generation ---
 
 // Signal represents a trading signal.
@@ -639,8 +1302,20 @@ func (f *Forecaster) GenerateSignal(forecast *EnsembleForecast, currentYesPrice
 
 	// Determine signal strength based on edge and confidence
 	minEdge := decimal.NewFromInt(int64(minEdgeBps))
+	if !forecast.EndDate.IsZero() {
+		mult := f.edgeDecay.Multiplier(forecast.EndDate.Sub(signal.Timestamp))
+		minEdge = minEdge.Mul(decimal.NewFromFloat(mult))
+	}
 
-	if edge.GreaterThan(minEdge) {
+	if f.requireOutside && forecast.IntervalContains(marketProb) {
+		signal.Reasoning = fmt.Sprintf(
+			"Market %.1f%% inside forecast interval %.1f%%-%.1f%%. Forecast: %.1f%%",
+			marketProb.Mul(decimal.NewFromInt(100)).InexactFloat64(),
+			forecast.LowerBound.Mul(decimal.NewFromInt(100)).InexactFloat64(),
+			forecast.UpperBound.Mul(decimal.NewFromInt(100)).InexactFloat64(),
+			forecastProb.Mul(decimal.NewFromInt(100)).InexactFloat64(),
+		)
+	} else if edge.GreaterThan(minEdge) {
 		// Strong enough edge
 		signal.Signal = SignalBuy
 
@@ -661,11 +1336,17 @@ func (f *Forecaster) GenerateSignal(forecast *EnsembleForecast, currentYesPrice
 			forecast.Confidence.Mul(decimal.NewFromInt(100)).InexactFloat64(),
 			forecast.Disagreement.Mul(decimal.NewFromInt(100)).InexactFloat64(),
 		)
+		if forecast.HasInterval() {
+			signal.Reasoning += fmt.Sprintf(". Interval: %.1f%%-%.1f%%",
+				forecast.LowerBound.Mul(decimal.NewFromInt(100)).InexactFloat64(),
+				forecast.UpperBound.Mul(decimal.NewFromInt(100)).InexactFloat64(),
+			)
+		}
 	} else {
 		signal.Reasoning = fmt.Sprintf(
-			"Edge %.0f bps below threshold %d bps. Forecast: %.1f%% vs Market: %.1f%%",
+			"Edge %.0f bps below threshold %.0f bps. Forecast: %.1f%% vs Market: %.1f%%",
 			edge.InexactFloat64(),
-			minEdgeBps,
+			minEdge.InexactFloat64(),
 			forecastProb.Mul(decimal.NewFromInt(100)).InexactFloat64(),
 			marketProb.Mul(decimal.NewFromInt(100)).InexactFloat64(),
 		)
@@ -674,13 +1355,63 @@ func (f *Forecaster) GenerateSignal(forecast *EnsembleForecast, currentYesPrice
 	return signal
 }
 
-// RankSignals ranks trading signals by expected value.
-func RankSignals(signals []*TradingSignal) []*TradingSignal {
-	// Sort by edge * strength (expected value proxy)
-	sort.Slice(signals, func(i, j int) bool {
-		evI := signals[i].EdgeBps.Mul(signals[i].Strength)
-		evJ := signals[j].EdgeBps.Mul(signals[j].Strength)
-		return evI.GreaterThan(evJ)
+// Conviction scores how worth trading a signal is: edge * strength (the
+// expected value proxy), scaled by liquidity and by the forecast's freshness.
+// liquidity is the fraction of the intended size the book can fill, clamped
+// to [0, 1]. Freshness halves every halfLife of forecast age at the signal's
+// timestamp; zero halfLife ignores age.
+func (s *TradingSignal) Conviction(liquidity decimal.Decimal, halfLife time.Duration) decimal.Decimal {
+	liquidity = decimal.Max(decimal.Zero, decimal.Min(liquidity, decimal.NewFromInt(1)))
+	conviction := s.EdgeBps.Mul(s.Strength).Mul(liquidity)
+
+	if halfLife > 0 && s.Forecast != nil && !s.Forecast.Timestamp.IsZero() {
+		if age := s.Timestamp.Sub(s.Forecast.Timestamp); age > 0 {
+			freshness := math.Pow(0.5, float64(age)/float64(halfLife))
+			conviction = conviction.Mul(decimal.NewFromFloat(freshness))
+		}
+	}
+	return conviction
+}
+
+// RankOption configures RankSignals.
+type RankOption func(*rankConfig)
+
+type rankConfig struct {
+	liquidity func(*TradingSignal) decimal.Decimal
+	halfLife  time.Duration
+}
+
+// WithSignalLiquidity supplies each signal's fillable fraction (see
+// Conviction). Without it every signal counts as fully liquid.
+func WithSignalLiquidity(liquidity func(*TradingSignal) decimal.Decimal) RankOption {
+	return func(c *rankConfig) {
+		c.liquidity = liquidity
+	}
+}
+
+// WithForecastHalfLife discounts stale forecasts (see Conviction).
+func WithForecastHalfLife(halfLife time.Duration) RankOption {
+	return func(c *rankConfig) {
+		c.halfLife = halfLife
+	}
+}
+
+// RankSignals ranks trading signals by Conviction, highest first. With no
+// options that is edge * strength, the expected value proxy.
+func RankSignals(signals []*TradingSignal, opts ...RankOption) []*TradingSignal {
+	cfg := rankConfig{
+		liquidity: func(*TradingSignal) decimal.Decimal { return decimal.NewFromInt(1) },
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	conviction := make(map[*TradingSignal]decimal.Decimal, len(signals))
+	for _, s := range signals {
+		conviction[s] = s.Conviction(cfg.liquidity(s), cfg.halfLife)
+	}
+	sort.SliceStable(signals, func(i, j int) bool {
+		return conviction[signals[i]].GreaterThan(conviction[signals[j]])
 	})
 	return signals
 }
@@ -3,9 +3,16 @@ package agents
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/phenomenon0/polymarket-agents/tools"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -89,6 +96,29 @@ func TestAddClient(t *testing.T) {
 	}
 }
 
+func TestWarmup(t *testing.T) {
+	failing := newMockLLMClient(ProviderGPT4, 0, 0)
+	failing.err = errors.New("401 invalid api key")
+
+	f := NewForecaster(&ForecasterConfig{
+		Clients: map[LLMProvider]LLMClient{
+			ProviderClaude: newMockLLMClient(ProviderClaude, 0.6, 0.8),
+			ProviderGPT4:   failing,
+		},
+	})
+
+	results := f.Warmup(context.Background())
+	if len(results) != 2 {
+		t.Fatalf("Expected a result per provider, got %v", results)
+	}
+	if err, ok := results[ProviderClaude]; !ok || err != nil {
+		t.Errorf("Expected Claude to warm up, got %v", err)
+	}
+	if err := results[ProviderGPT4]; err == nil {
+		t.Error("Expected GPT-4 warm-up to fail")
+	}
+}
+
 func TestForecastSingle(t *testing.T) {
 	client := newMockLLMClient(ProviderClaude, 0.75, 0.85)
 	config := &ForecasterConfig{
@@ -128,6 +158,40 @@ func TestForecastSingle(t *testing.T) {
 	}
 }
 
+func TestForecastSingle_Calibration(t *testing.T) {
+	f := NewForecaster(&ForecasterConfig{
+		Clients: map[LLMProvider]LLMClient{
+			ProviderClaude: newMockLLMClient(ProviderClaude, 0.90, 0.95),
+			ProviderGPT4:   newMockLLMClient(ProviderGPT4, 0.90, 0.95),
+		},
+		Calibration: map[LLMProvider]Calibration{
+			// Halving the log-odds maps odds of 9:1 to 3:1
+			ProviderClaude: {Slope: 0.5, MaxConfidence: 0.6},
+		},
+	})
+	mktCtx := &MarketContext{TokenID: "token1", Question: "Will event X happen?"}
+
+	forecast, err := f.ForecastSingle(context.Background(), mktCtx, ProviderClaude)
+	if err != nil {
+		t.Fatalf("ForecastSingle failed: %v", err)
+	}
+	if got := forecast.Probability.InexactFloat64(); math.Abs(got-0.75) > 1e-9 {
+		t.Errorf("Expected calibrated probability 0.75, got %v", got)
+	}
+	if !forecast.Confidence.Equal(decimal.NewFromFloat(0.6)) {
+		t.Errorf("Expected confidence capped at 0.6, got %s", forecast.Confidence)
+	}
+
+	// Providers without calibration data pass through unchanged
+	forecast, err = f.ForecastSingle(context.Background(), mktCtx, ProviderGPT4)
+	if err != nil {
+		t.Fatalf("ForecastSingle failed: %v", err)
+	}
+	if !forecast.Probability.Equal(decimal.NewFromFloat(0.90)) {
+		t.Errorf("Expected uncalibrated probability 0.90, got %s", forecast.Probability)
+	}
+}
+
 func TestForecastSingle_ProviderNotFound(t *testing.T) {
 	f := NewForecaster(nil)
 
@@ -190,6 +254,44 @@ func TestForecastEnsemble(t *testing.T) {
 	}
 }
 
+func TestForecastEnsemble_MarketPrior(t *testing.T) {
+	mktCtx := &MarketContext{TokenID: "token1", CurrentPrice: decimal.NewFromFloat(0.30)}
+
+	tests := []struct {
+		name       string
+		confidence float64
+		want       float64
+	}{
+		{"low confidence leans to market", 0.1, 0.35}, // 0.9 × 0.30 + 0.1 × 0.80
+		{"high confidence leans to model", 0.9, 0.75}, // 0.1 × 0.30 + 0.9 × 0.80
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewForecaster(&ForecasterConfig{
+				Clients:           map[LLMProvider]LLMClient{ProviderClaude: newMockLLMClient(ProviderClaude, 0.80, tc.confidence)},
+				MarketPriorWeight: 1,
+			})
+
+			ensemble, err := f.ForecastEnsemble(context.Background(), mktCtx)
+			if err != nil {
+				t.Fatalf("ForecastEnsemble failed: %v", err)
+			}
+			if !ensemble.Probability.Equal(decimal.NewFromFloat(tc.want)) {
+				t.Errorf("Expected probability %.2f, got %s", tc.want, ensemble.Probability)
+			}
+		})
+	}
+
+	// No weight, no blending
+	f := NewForecaster(&ForecasterConfig{
+		Clients: map[LLMProvider]LLMClient{ProviderClaude: newMockLLMClient(ProviderClaude, 0.80, 0.1)},
+	})
+	ensemble, _ := f.ForecastEnsemble(context.Background(), mktCtx)
+	if !ensemble.Probability.Equal(decimal.NewFromFloat(0.80)) {
+		t.Errorf("Expected the model's 0.80 without a prior weight, got %s", ensemble.Probability)
+	}
+}
+
 func TestForecastEnsemble_NoClients(t *testing.T) {
 	f := NewForecaster(nil)
 
@@ -261,6 +363,190 @@ func TestForecastWithFallback_AllFail(t *testing.T) {
 	}
 }
 
+// blockingLLMClient blocks until its context is canceled.
+type blockingLLMClient struct {
+	provider LLMProvider
+	exited   chan struct{}
+}
+
+func (b *blockingLLMClient) Complete(ctx context.Context, prompt string, systemPrompt string) (string, error) {
+	defer close(b.exited)
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func (b *blockingLLMClient) Provider() LLMProvider {
+	return b.provider
+}
+
+func TestForecastEnsemble_Cancel(t *testing.T) {
+	blocking := &blockingLLMClient{provider: ProviderGPT4, exited: make(chan struct{})}
+	stubborn := newMockLLMClient(ProviderClaude, 0.6, 0.8)
+	stubborn.latencyMs = 5000 // Ignores cancellation
+
+	f := NewForecaster(&ForecasterConfig{
+		Clients: map[LLMProvider]LLMClient{
+			ProviderGPT4:   blocking,
+			ProviderClaude: stubborn,
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := f.ForecastEnsemble(ctx, &MarketContext{TokenID: "token1"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ForecastEnsemble took %v after cancel", elapsed)
+	}
+
+	select {
+	case <-blocking.exited:
+	case <-time.After(time.Second):
+		t.Error("Context-aware provider call did not exit after cancel")
+	}
+}
+
+func TestForecastEnsemble_DropsStragglers(t *testing.T) {
+	slow := &blockingLLMClient{provider: ProviderDeepSeek, exited: make(chan struct{})}
+
+	f := NewForecaster(&ForecasterConfig{
+		Clients: map[LLMProvider]LLMClient{
+			ProviderClaude:   newMockLLMClient(ProviderClaude, 0.6, 0.8),
+			ProviderGPT4:     newMockLLMClient(ProviderGPT4, 0.7, 0.8),
+			ProviderDeepSeek: slow,
+		},
+		EnsembleTimeout:    5 * time.Second,
+		MinEnsembleMembers: 2,
+	})
+
+	start := time.Now()
+	ensemble, err := f.ForecastEnsemble(context.Background(), &MarketContext{TokenID: "token1"})
+	if err != nil {
+		t.Fatalf("ForecastEnsemble failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ForecastEnsemble waited %v for the slow member", elapsed)
+	}
+	if len(ensemble.IndividualForecasts) != 2 {
+		t.Fatalf("Expected 2 members, got %d", len(ensemble.IndividualForecasts))
+	}
+	for _, forecast := range ensemble.IndividualForecasts {
+		if forecast.Provider == ProviderDeepSeek {
+			t.Error("Slow member should have been dropped")
+		}
+	}
+
+	select {
+	case <-slow.exited:
+	case <-time.After(time.Second):
+		t.Error("Straggler was not canceled")
+	}
+}
+
+func TestForecastEnsemble_TimeoutBelowMinimum(t *testing.T) {
+	f := NewForecaster(&ForecasterConfig{
+		Clients: map[LLMProvider]LLMClient{
+			ProviderClaude: newMockLLMClient(ProviderClaude, 0.6, 0.8),
+			ProviderGPT4:   &blockingLLMClient{provider: ProviderGPT4, exited: make(chan struct{})},
+		},
+		EnsembleTimeout:    50 * time.Millisecond,
+		MinEnsembleMembers: 2,
+	})
+
+	if _, err := f.ForecastEnsemble(context.Background(), &MarketContext{TokenID: "token1"}); err == nil {
+		t.Fatal("Expected an error with one of two required members")
+	}
+}
+
+func TestForecastSingle_MaxLatency(t *testing.T) {
+	slow := &blockingLLMClient{provider: ProviderClaude, exited: make(chan struct{})}
+	f := NewForecaster(&ForecasterConfig{
+		Clients: map[LLMProvider]LLMClient{ProviderClaude: slow},
+	})
+
+	start := time.Now()
+	_, err := f.ForecastSingle(context.Background(), &MarketContext{TokenID: "token1"}, ProviderClaude,
+		ForecastOptions{MaxLatency: 50 * time.Millisecond})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrForecastTimeout) {
+		t.Fatalf("Expected ErrForecastTimeout, got %v", err)
+	}
+	if ErrorClass(err) != "timeout" {
+		t.Errorf("Expected timeout class, got %s", ErrorClass(err))
+	}
+	if elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Expected return at the deadline, took %v", elapsed)
+	}
+
+	select {
+	case <-slow.exited:
+	case <-time.After(time.Second):
+		t.Error("LLM call was not canceled at the deadline")
+	}
+
+	// A fast client is unaffected
+	fast := newMockLLMClient(ProviderGPT4, 0.6, 0.8)
+	f.AddClient(fast, 1.0)
+	if _, err := f.ForecastSingle(context.Background(), &MarketContext{TokenID: "token1"}, ProviderGPT4,
+		ForecastOptions{MaxLatency: time.Second}); err != nil {
+		t.Errorf("Fast forecast failed: %v", err)
+	}
+}
+
+func TestForecastWithFallback_CustomOrder(t *testing.T) {
+	local := newMockLLMClient(ProviderLocal, 0.7, 0.9)
+	local.err = errors.New("429 rate limit exceeded")
+	deepseek := newMockLLMClient(ProviderDeepSeek, 0.55, 0.8)
+	claude := newMockLLMClient(ProviderClaude, 0.6, 0.8)
+
+	f := NewForecaster(&ForecasterConfig{
+		Clients: map[LLMProvider]LLMClient{
+			ProviderLocal:    local,
+			ProviderDeepSeek: deepseek,
+			ProviderClaude:   claude,
+		},
+		// GPT4 is listed but not configured, so it is skipped
+		FallbackOrder: []LLMProvider{ProviderLocal, ProviderGPT4, ProviderDeepSeek, ProviderClaude},
+	})
+
+	forecast, err := f.ForecastWithFallback(context.Background(), &MarketContext{TokenID: "token1"})
+	if err != nil {
+		t.Fatalf("ForecastWithFallback failed: %v", err)
+	}
+	if forecast.Provider != ProviderDeepSeek {
+		t.Errorf("Expected DeepSeek after rate-limited local, got %s", forecast.Provider)
+	}
+	if local.callCount != 1 || claude.callCount != 0 {
+		t.Errorf("Unexpected calls: local=%d claude=%d", local.callCount, claude.callCount)
+	}
+}
+
+func TestForecastWithFallback_StopsOnParseFailure(t *testing.T) {
+	garbled := newMockLLMClient(ProviderClaude, 0, 0)
+	garbled.response = "I think it's fairly likely."
+	gpt4 := newMockLLMClient(ProviderGPT4, 0.6, 0.8)
+
+	f := NewForecaster(&ForecasterConfig{
+		Clients: map[LLMProvider]LLMClient{
+			ProviderClaude: garbled,
+			ProviderGPT4:   gpt4,
+		},
+	})
+
+	_, err := f.ForecastWithFallback(context.Background(), &MarketContext{TokenID: "token1"})
+	if !errors.Is(err, ErrParseResponse) {
+		t.Fatalf("Expected parse failure, got %v", err)
+	}
+	if gpt4.callCount != 0 {
+		t.Error("Should not fall back after a parse failure")
+	}
+}
+
 func TestGetCachedForecast(t *testing.T) {
 	client := newMockLLMClient(ProviderClaude, 0.75, 0.85)
 	config := &ForecasterConfig{
@@ -365,6 +651,247 @@ func TestParseResponse(t *testing.T) {
 	}
 }
 
+// parseCorpus is a replayable set of messy LLM outputs seen in practice.
+// A zero wantProb with wantErr false means the probability must parse as 0.
+var parseCorpus = []struct {
+	name          string
+	response      string
+	wantProb      float64
+	wantConf      float64
+	wantReasoning string
+	wantErr       bool
+}{
+	{
+		name:          "markdown json fence",
+		response:      "```json\n{\"probability\": 0.62, \"confidence\": 0.8, \"reasoning\": \"Polls lean yes\"}\n```",
+		wantProb:      0.62,
+		wantConf:      0.8,
+		wantReasoning: "Polls lean yes",
+	},
+	{
+		name:          "bare fence with language on its own line",
+		response:      "```\n{\"probability\": 0.4, \"confidence\": 0.6, \"reasoning\": \"x\"}\n```",
+		wantProb:      0.4,
+		wantConf:      0.6,
+		wantReasoning: "x",
+	},
+	{
+		name: "prose around a fenced block",
+		response: `Let me think about this carefully.
+
+` + "```json" + `
+{"probability": 0.33, "confidence": 0.7, "reasoning": "Base rate is low"}
+` + "```" + `
+
+I hope this helps!`,
+		wantProb:      0.33,
+		wantConf:      0.7,
+		wantReasoning: "Base rate is low",
+	},
+	{
+		name:          "percentage probability number",
+		response:      `{"probability": 65, "confidence": 0.9, "reasoning": "r"}`,
+		wantProb:      0.65,
+		wantConf:      0.9,
+		wantReasoning: "r",
+	},
+	{
+		name:          "percentage probability string",
+		response:      `{"probability": "72%", "confidence": "0.8", "reasoning": "r"}`,
+		wantProb:      0.72,
+		wantConf:      0.8,
+		wantReasoning: "r",
+	},
+	{
+		name:          "percentage confidence",
+		response:      `{"probability": 0.5, "confidence": 85, "reasoning": "r"}`,
+		wantProb:      0.5,
+		wantConf:      0.85,
+		wantReasoning: "r",
+	},
+	{
+		name:          "rationale as array",
+		response:      `{"probability": 0.2, "confidence": 0.6, "rationale": ["Incumbent favored.", "Low turnout expected."]}`,
+		wantProb:      0.2,
+		wantConf:      0.6,
+		wantReasoning: "Incumbent favored. Low turnout expected.",
+	},
+	{
+		name:          "nested forecast object",
+		response:      `{"forecast": {"probability": 0.81, "confidence": 0.75, "rationale": "Strong fundamentals"}}`,
+		wantProb:      0.81,
+		wantConf:      0.75,
+		wantReasoning: "Strong fundamentals",
+	},
+	{
+		name:          "nested forecast with rationale array",
+		response:      `{"question": "Will X?", "forecast": {"probability": 0.1, "confidence": 0.9, "rationale": ["a", "b"]}}`,
+		wantProb:      0.1,
+		wantConf:      0.9,
+		wantReasoning: "a b",
+	},
+	{
+		name:          "nested prediction object",
+		response:      `{"prediction": {"probability": 0.58, "confidence": 0.66}, "reasoning": "Close race"}`,
+		wantProb:      0.58,
+		wantConf:      0.66,
+		wantReasoning: "Close race",
+	},
+	{
+		name:          "capitalized keys",
+		response:      `{"Probability": 0.44, "Confidence": 0.7, "Reasoning": "Mixed signals"}`,
+		wantProb:      0.44,
+		wantConf:      0.7,
+		wantReasoning: "Mixed signals",
+	},
+	{
+		name:          "braces inside reasoning string",
+		response:      `{"probability": 0.3, "confidence": 0.6, "reasoning": "Markets price {YES} too high }"}`,
+		wantProb:      0.3,
+		wantConf:      0.6,
+		wantReasoning: "Markets price {YES} too high }",
+	},
+	{
+		name:          "trailing comma",
+		response:      "{\n  \"probability\": 0.55,\n  \"confidence\": 0.7,\n  \"reasoning\": \"ok\",\n}",
+		wantProb:      0.55,
+		wantConf:      0.7,
+		wantReasoning: "ok",
+	},
+	{
+		name:          "schema echoed before the answer",
+		response:      `Format: {"probability": <0-1>, "confidence": <0-1>}. Answer: {"probability": 0.27, "confidence": 0.65, "reasoning": "r"}`,
+		wantProb:      0.27,
+		wantConf:      0.65,
+		wantReasoning: "r",
+	},
+	{
+		name:          "explicit zero probability",
+		response:      `{"probability": 0, "confidence": 0.9, "reasoning": "Already ruled out"}`,
+		wantProb:      0,
+		wantConf:      0.9,
+		wantReasoning: "Already ruled out",
+	},
+	{
+		name:     "missing probability",
+		response: `{"confidence": 0.8, "reasoning": "Forgot the number"}`,
+		wantErr:  true,
+	},
+	{
+		name:     "probability out of range",
+		response: `{"probability": 250, "confidence": 0.8}`,
+		wantErr:  true,
+	},
+	{
+		name:     "no JSON at all",
+		response: `The probability is roughly 40 percent.`,
+		wantErr:  true,
+	},
+	{
+		name:     "truncated JSON",
+		response: `{"probability": 0.6, "confidence": 0.7, "reasoning": "cut o`,
+		wantErr:  true,
+	},
+}
+
+func TestParseResponseCorpus(t *testing.T) {
+	f := NewForecaster(nil)
+
+	for _, tc := range parseCorpus {
+		t.Run(tc.name, func(t *testing.T) {
+			forecast, err := f.parseResponse(tc.response)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error, got %+v", forecast)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !forecast.Probability.Equal(decimal.NewFromFloat(tc.wantProb)) {
+				t.Errorf("Probability: want %v, got %s", tc.wantProb, forecast.Probability)
+			}
+			if !forecast.Confidence.Equal(decimal.NewFromFloat(tc.wantConf)) {
+				t.Errorf("Confidence: want %v, got %s", tc.wantConf, forecast.Confidence)
+			}
+			if forecast.Reasoning != tc.wantReasoning {
+				t.Errorf("Reasoning: want %q, got %q", tc.wantReasoning, forecast.Reasoning)
+			}
+		})
+	}
+}
+
+func TestParseResponse_Explanation(t *testing.T) {
+	f := NewForecaster(nil)
+
+	response := `{
+		"base_rate": 0.30,
+		"factors": [
+			{"name": "polling lead", "evidence": "Candidate up 5 points", "adjustment": 0.15},
+			{"name": "time decay", "evidence": "Two weeks remain", "adjustment": -0.05}
+		],
+		"probability": 0.40,
+		"confidence": 0.8,
+		"reasoning": "Base rate adjusted for polling"
+	}`
+
+	forecast, err := f.parseResponse(response)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	exp := forecast.Explanation
+	if exp == nil {
+		t.Fatal("Expected explanation to be parsed")
+	}
+	if !exp.BaseRate.Equal(decimal.NewFromFloat(0.30)) {
+		t.Errorf("Expected base rate 0.30, got %s", exp.BaseRate)
+	}
+	if !exp.FinalProbability.Equal(decimal.NewFromFloat(0.40)) {
+		t.Errorf("Expected final probability 0.40, got %s", exp.FinalProbability)
+	}
+	if len(exp.Factors) != 2 {
+		t.Fatalf("Expected 2 factors, got %d", len(exp.Factors))
+	}
+	if exp.Factors[0].Name != "polling lead" || !exp.Factors[0].Adjustment.Equal(decimal.NewFromFloat(0.15)) {
+		t.Errorf("Unexpected first factor: %+v", exp.Factors[0])
+	}
+	if !exp.Factors[1].Adjustment.Equal(decimal.NewFromFloat(-0.05)) {
+		t.Errorf("Expected time decay adjustment -0.05, got %s", exp.Factors[1].Adjustment)
+	}
+
+	// Plain responses carry no explanation
+	forecast, err = f.parseResponse(`{"probability": 0.6, "confidence": 0.7, "reasoning": "blob"}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if forecast.Explanation != nil {
+		t.Error("Expected no explanation for unstructured response")
+	}
+}
+
+func TestExplain_FallbackToReasoning(t *testing.T) {
+	client := newMockLLMClient(ProviderClaude, 0.65, 0.8)
+	f := NewForecaster(&ForecasterConfig{
+		Clients: map[LLMProvider]LLMClient{ProviderClaude: client},
+	})
+
+	exp, err := f.Explain(context.Background(), &MarketContext{TokenID: "token1"}, ProviderClaude)
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if !exp.FinalProbability.Equal(decimal.NewFromFloat(0.65)) {
+		t.Errorf("Expected final probability 0.65, got %s", exp.FinalProbability)
+	}
+	if len(exp.Factors) != 0 {
+		t.Errorf("Expected no factors, got %d", len(exp.Factors))
+	}
+	if exp.Reasoning != "Test reasoning from claude" {
+		t.Errorf("Expected freeform reasoning, got %q", exp.Reasoning)
+	}
+}
+
 func TestGenerateSignal_BuyYES(t *testing.T) {
 	f := NewForecaster(nil)
 
@@ -429,6 +956,43 @@ func TestGenerateSignal_Hold(t *testing.T) {
 	}
 }
 
+func TestGenerateSignal_EdgeDecay(t *testing.T) {
+	f := NewForecaster(&ForecasterConfig{EdgeDecay: EdgeDecay{
+		Near:           7 * 24 * time.Hour,
+		Far:            90 * 24 * time.Hour,
+		NearMultiplier: 0.5,
+		FarMultiplier:  3,
+	}})
+
+	// 0.53 vs 0.50 is 600 bps: over 500 * 0.5 near, under 500 * 3 far
+	newEnsemble := func(endDate time.Time) *EnsembleForecast {
+		return &EnsembleForecast{
+			TokenID:     "token1",
+			Probability: decimal.NewFromFloat(0.53),
+			Confidence:  decimal.NewFromFloat(0.8),
+			EndDate:     endDate,
+		}
+	}
+	price := decimal.NewFromFloat(0.5)
+
+	far := f.GenerateSignal(newEnsemble(time.Now().Add(180*24*time.Hour)), price, 500)
+	if far.Signal != SignalHold {
+		t.Errorf("Expected HOLD for far-dated market, got %s (%s)", far.Signal, far.Reasoning)
+	}
+
+	near := f.GenerateSignal(newEnsemble(time.Now().Add(2*24*time.Hour)), price, 500)
+	if near.Signal != SignalBuy {
+		t.Errorf("Expected BUY near resolution, got %s (%s)", near.Signal, near.Reasoning)
+	}
+
+	if got := (EdgeDecay{Near: 0, Far: 10 * time.Hour, NearMultiplier: 1, FarMultiplier: 3}).Multiplier(5 * time.Hour); got != 2 {
+		t.Errorf("Expected midpoint multiplier 2, got %v", got)
+	}
+	if got := (EdgeDecay{}).Multiplier(time.Hour); got != 1 {
+		t.Errorf("Zero EdgeDecay should not scale, got %v", got)
+	}
+}
+
 func TestRankSignals(t *testing.T) {
 	signals := []*TradingSignal{
 		{Signal: SignalBuy, EdgeBps: decimal.NewFromInt(50), Strength: decimal.NewFromFloat(0.5)},
@@ -450,6 +1014,42 @@ func TestRankSignals(t *testing.T) {
 	}
 }
 
+func TestRankSignalsByConviction(t *testing.T) {
+	now := time.Now()
+	fresh := &EnsembleForecast{Timestamp: now}
+	illiquid := &TradingSignal{TokenID: "illiquid", EdgeBps: decimal.NewFromInt(800), Strength: decimal.NewFromFloat(0.9), Forecast: fresh, Timestamp: now}
+	liquid := &TradingSignal{TokenID: "liquid", EdgeBps: decimal.NewFromInt(300), Strength: decimal.NewFromFloat(0.7), Forecast: fresh, Timestamp: now}
+	stale := &TradingSignal{TokenID: "stale", EdgeBps: decimal.NewFromInt(300), Strength: decimal.NewFromFloat(0.7),
+		Forecast: &EnsembleForecast{Timestamp: now.Add(-2 * time.Hour)}, Timestamp: now}
+
+	depth := map[string]decimal.Decimal{
+		"illiquid": decimal.NewFromFloat(0.05), // Book fills 5% of the size
+		"liquid":   decimal.NewFromInt(1),
+		"stale":    decimal.NewFromInt(1),
+	}
+	ranked := RankSignals([]*TradingSignal{illiquid, stale, liquid},
+		WithSignalLiquidity(func(s *TradingSignal) decimal.Decimal { return depth[s.TokenID] }),
+		WithForecastHalfLife(30*time.Minute),
+	)
+
+	// liquid: 300*0.7 = 210; illiquid: 800*0.9*0.05 = 36; stale: 210/16 ≈ 13
+	var order []string
+	for _, s := range ranked {
+		order = append(order, s.TokenID)
+	}
+	if strings.Join(order, ",") != "liquid,illiquid,stale" {
+		t.Errorf("Expected liquid,illiquid,stale, got %v", order)
+	}
+
+	// Without liquidity the high-edge signal wins
+	if got := RankSignals([]*TradingSignal{liquid, illiquid})[0]; got != illiquid {
+		t.Errorf("Expected the high-edge signal first without liquidity, got %s", got.TokenID)
+	}
+	if c := liquid.Conviction(decimal.NewFromInt(2), 0); !c.Equal(decimal.NewFromInt(210)) {
+		t.Errorf("Expected liquidity clamped to 1 (210), got %s", c)
+	}
+}
+
 func TestSignalString(t *testing.T) {
 	if SignalBuy.String() != "BUY" {
 		t.Error("SignalBuy should be BUY")
@@ -542,6 +1142,58 @@ func TestCombineForecasts(t *testing.T) {
 	}
 }
 
+func TestCombineForecasts_Interval(t *testing.T) {
+	f := NewForecaster(nil)
+	forecasts := []Forecast{
+		{Probability: decimal.NewFromFloat(0.30), Confidence: decimal.NewFromFloat(0.8), Provider: ProviderClaude},
+		{Probability: decimal.NewFromFloat(0.55), Confidence: decimal.NewFromFloat(0.8), Provider: ProviderGPT4},
+		{Probability: decimal.NewFromFloat(0.60), Confidence: decimal.NewFromFloat(0.8), Provider: ProviderDeepSeek},
+		{Probability: decimal.NewFromFloat(0.65), Confidence: decimal.NewFromFloat(0.8), Provider: ProviderLocal},
+		{Probability: decimal.NewFromFloat(0.90), Confidence: decimal.NewFromFloat(0.8), Provider: LLMProvider("other")},
+	}
+
+	ensemble := f.combineForecasts(&MarketContext{TokenID: "token1"}, forecasts, nil)
+
+	// 10th and 90th percentiles of 0.30..0.90 interpolate to 0.40 and 0.80
+	if !ensemble.LowerBound.Equal(decimal.NewFromFloat(0.4)) || !ensemble.UpperBound.Equal(decimal.NewFromFloat(0.8)) {
+		t.Errorf("Expected interval [0.4, 0.8], got [%s, %s]", ensemble.LowerBound, ensemble.UpperBound)
+	}
+	if ensemble.LowerBound.GreaterThan(ensemble.Probability) || ensemble.UpperBound.LessThan(ensemble.Probability) {
+		t.Errorf("Interval [%s, %s] does not bracket probability %s", ensemble.LowerBound, ensemble.UpperBound, ensemble.Probability)
+	}
+	if ensemble.LowerBound.LessThan(forecasts[0].Probability) || ensemble.UpperBound.GreaterThan(forecasts[4].Probability) {
+		t.Errorf("Interval [%s, %s] extends past the members", ensemble.LowerBound, ensemble.UpperBound)
+	}
+
+	single := f.combineForecasts(&MarketContext{TokenID: "token1"}, forecasts[:1], nil)
+	if !single.LowerBound.Equal(decimal.NewFromFloat(0.3)) || !single.UpperBound.Equal(decimal.NewFromFloat(0.3)) {
+		t.Errorf("Single member interval should collapse to 0.3, got [%s, %s]", single.LowerBound, single.UpperBound)
+	}
+}
+
+func TestGenerateSignal_RequirePriceOutsideInterval(t *testing.T) {
+	ensemble := &EnsembleForecast{
+		TokenID:     "token1",
+		Probability: decimal.NewFromFloat(0.6),
+		Confidence:  decimal.NewFromFloat(0.8),
+		LowerBound:  decimal.NewFromFloat(0.45),
+		UpperBound:  decimal.NewFromFloat(0.75),
+	}
+	price := decimal.NewFromFloat(0.5) // 2000 bps of edge, but inside the interval
+
+	if signal := NewForecaster(nil).GenerateSignal(ensemble, price, 100); signal.Signal != SignalBuy {
+		t.Errorf("Expected BUY without the interval check, got %s (%s)", signal.Signal, signal.Reasoning)
+	}
+
+	f := NewForecaster(&ForecasterConfig{RequirePriceOutsideInterval: true})
+	if signal := f.GenerateSignal(ensemble, price, 100); signal.Signal != SignalHold {
+		t.Errorf("Expected HOLD with price inside the interval, got %s (%s)", signal.Signal, signal.Reasoning)
+	}
+	if signal := f.GenerateSignal(ensemble, decimal.NewFromFloat(0.4), 100); signal.Signal != SignalBuy {
+		t.Errorf("Expected BUY with price below the interval, got %s (%s)", signal.Signal, signal.Reasoning)
+	}
+}
+
 func TestCombineForecasts_Empty(t *testing.T) {
 	f := NewForecaster(nil)
 
@@ -555,3 +1207,117 @@ func TestCombineForecasts_Empty(t *testing.T) {
 		t.Error("Empty forecasts should result in zero probability")
 	}
 }
+
+func TestForecastSingle_OptionsReachRequest(t *testing.T) {
+	var got struct {
+		Model       string  `json:"model"`
+		Temperature float64 `json:"temperature"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model":"gpt-4o","choices":[{"message":{"content":"{\"probability\":0.6,\"confidence\":0.7,\"reasoning\":\"r\"}"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewLLMToolClient(tools.LLMConfig{
+		Provider:    "openai",
+		Model:       "gpt-4o-mini",
+		BaseURL:     server.URL,
+		Temperature: 0.3,
+		Timeout:     5 * time.Second,
+	}, ProviderGPT4)
+	f := NewForecaster(&ForecasterConfig{
+		Clients: map[LLMProvider]LLMClient{ProviderGPT4: client},
+	})
+
+	mktCtx := &MarketContext{TokenID: "tok", Question: "Will it happen?", CurrentPrice: decimal.NewFromFloat(0.5)}
+
+	// Defaults flow through when no options are given
+	if _, err := f.ForecastSingle(context.Background(), mktCtx, ProviderGPT4); err != nil {
+		t.Fatalf("ForecastSingle failed: %v", err)
+	}
+	if got.Temperature != 0.3 || got.Model != "gpt-4o-mini" {
+		t.Errorf("Expected configured defaults, got temperature=%v model=%s", got.Temperature, got.Model)
+	}
+
+	temp := 0.9
+	_, err := f.ForecastSingle(context.Background(), mktCtx, ProviderGPT4, ForecastOptions{
+		Temperature:   &temp,
+		ModelOverride: "gpt-4o",
+	})
+	if err != nil {
+		t.Fatalf("ForecastSingle with options failed: %v", err)
+	}
+	if got.Temperature != 0.9 {
+		t.Errorf("Expected override temperature 0.9, got %v", got.Temperature)
+	}
+	if got.Model != "gpt-4o" {
+		t.Errorf("Expected override model gpt-4o, got %s", got.Model)
+	}
+
+	// A requested 0 is honored, not replaced by the configured temperature
+	zero := 0.0
+	got.Temperature = -1
+	if _, err := f.ForecastSingle(context.Background(), mktCtx, ProviderGPT4, ForecastOptions{Temperature: &zero}); err != nil {
+		t.Fatalf("ForecastSingle with zero temperature failed: %v", err)
+	}
+	if got.Temperature != 0 {
+		t.Errorf("Expected requested temperature 0, got %v", got.Temperature)
+	}
+}
+
+func TestCombineForecasts_AggregationMethods(t *testing.T) {
+	// Skewed sample: two confident forecasts near 1, one outlier at 0.5
+	forecasts := []Forecast{
+		{Provider: ProviderClaude, Probability: decimal.NewFromFloat(0.95), Confidence: decimal.NewFromInt(1)},
+		{Provider: ProviderGPT4, Probability: decimal.NewFromFloat(0.99), Confidence: decimal.NewFromInt(1)},
+		{Provider: ProviderDeepSeek, Probability: decimal.NewFromFloat(0.50), Confidence: decimal.NewFromInt(1)},
+	}
+	equal := map[LLMProvider]decimal.Decimal{
+		ProviderClaude:   decimal.NewFromInt(1),
+		ProviderGPT4:     decimal.NewFromInt(1),
+		ProviderDeepSeek: decimal.NewFromInt(1),
+	}
+	mktCtx := &MarketContext{TokenID: "tok"}
+
+	combine := func(method AggregationMethod) float64 {
+		f := NewForecaster(&ForecasterConfig{AggregationMethod: method})
+		return f.combineForecasts(mktCtx, forecasts, equal).Probability.InexactFloat64()
+	}
+
+	mean := combine("")
+	if diff := mean - (0.95+0.99+0.50)/3; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Weighted mean: expected %.4f, got %.4f", (0.95+0.99+0.50)/3, mean)
+	}
+
+	if median := combine(AggregationMedian); median != 0.95 {
+		t.Errorf("Median: expected 0.95, got %.4f", median)
+	}
+
+	// Trimming the max and min of three leaves the middle value
+	if trimmed := combine(AggregationTrimmedMean); trimmed != 0.95 {
+		t.Errorf("Trimmed mean: expected 0.95, got %.4f", trimmed)
+	}
+
+	// Mean logit of {0.95, 0.99, 0.50} ≈ 2.51, so pooled ≈ 0.925
+	logOdds := combine(AggregationLogOdds)
+	if logOdds < 0.92 || logOdds > 0.93 {
+		t.Errorf("Log-odds: expected ~0.925, got %.4f", logOdds)
+	}
+	if logOdds-mean < 0.05 {
+		t.Errorf("Log-odds (%.4f) should sit well above the arithmetic mean (%.4f) for skewed inputs", logOdds, mean)
+	}
+
+	// Symmetric inputs pool to the same value either way
+	symmetric := []Forecast{
+		{Provider: ProviderClaude, Probability: decimal.NewFromFloat(0.3), Confidence: decimal.NewFromInt(1)},
+		{Provider: ProviderGPT4, Probability: decimal.NewFromFloat(0.7), Confidence: decimal.NewFromInt(1)},
+	}
+	f := NewForecaster(&ForecasterConfig{AggregationMethod: AggregationLogOdds})
+	if p := f.combineForecasts(mktCtx, symmetric, equal).Probability.InexactFloat64(); p < 0.4999 || p > 0.5001 {
+		t.Errorf("Log-odds of symmetric inputs should be 0.5, got %.4f", p)
+	}
+}
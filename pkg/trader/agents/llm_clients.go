@@ -3,6 +3,7 @@ package agents
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/phenomenon0/polymarket-agents/core"
@@ -27,15 +28,12 @@ func NewLLMToolClient(config tools.LLMConfig, provider LLMProvider) *LLMToolClie
 
 // Complete implements LLMClient.Complete.
 func (c *LLMToolClient) Complete(ctx context.Context, prompt string, systemPrompt string) (string, error) {
-	// Build the request
-	req := &tools.LLMRequest{
-		Messages: []tools.LLMMessage{
-			{Role: "user", Content: prompt},
-		},
-		System:      systemPrompt,
-		MaxTokens:   c.config.MaxTokens,
-		Temperature: c.config.Temperature,
-	}
+	return c.CompleteWithOptions(ctx, prompt, systemPrompt, ForecastOptions{})
+}
+
+// CompleteWithOptions implements OptionsClient.CompleteWithOptions.
+func (c *LLMToolClient) CompleteWithOptions(ctx context.Context, prompt string, systemPrompt string, opts ForecastOptions) (string, error) {
+	req := c.buildRequest(prompt, systemPrompt, opts)
 
 	// Create a mock ToolContext
 	toolCtx := &core.ToolContext{
@@ -61,6 +59,23 @@ func (c *LLMToolClient) Complete(ctx context.Context, prompt string, systemPromp
 	return resp.Content, nil
 }
 
+// buildRequest assembles the LLMRequest for a prompt, applying any overrides.
+func (c *LLMToolClient) buildRequest(prompt string, systemPrompt string, opts ForecastOptions) *tools.LLMRequest {
+	req := &tools.LLMRequest{
+		Messages: []tools.LLMMessage{
+			{Role: "user", Content: prompt},
+		},
+		System:      systemPrompt,
+		MaxTokens:   c.config.MaxTokens,
+		Temperature: c.config.Temperature,
+		Model:       opts.ModelOverride,
+	}
+	if opts.Temperature != nil {
+		req.Temperature, req.TemperatureSet = *opts.Temperature, true
+	}
+	return req
+}
+
 // Provider implements LLMClient.Provider.
 func (c *LLMToolClient) Provider() LLMProvider {
 	return c.provider
@@ -71,6 +86,44 @@ func (c *LLMToolClient) Cost() *tools.CostTracker {
 	return c.tool.Cost()
 }
 
+// CostClient is an LLMClient that tracks its own spend, like LLMToolClient.
+type CostClient interface {
+	LLMClient
+	Cost() *tools.CostTracker
+}
+
+// LLMCost returns the estimated USD spent so far across all clients that
+// implement CostClient. Other clients count as free.
+func (f *Forecaster) LLMCost() float64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var total float64
+	for _, client := range f.clients {
+		if cc, ok := client.(CostClient); ok {
+			total += cc.Cost().TotalCost()
+		}
+	}
+	return total
+}
+
+// ModelPresets returns the router preset names of the ensemble's
+// LLMToolClient members, sorted. Clients not built from a router preset are
+// left out.
+func (f *Forecaster) ModelPresets() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var presets []string
+	for _, client := range f.clients {
+		if tc, ok := client.(*LLMToolClient); ok && tc.config.Preset != "" {
+			presets = append(presets, tc.config.Preset)
+		}
+	}
+	sort.Strings(presets)
+	return presets
+}
+
 // --- Factory functions using the ModelRouter ---
 
 // CreateClientsFromRouter creates LLM clients using the ModelRouter.
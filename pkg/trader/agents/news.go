@@ -0,0 +1,134 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// NewsProvider fetches recent headlines relevant to a market question.
+type NewsProvider interface {
+	FetchNews(ctx context.Context, query string) ([]string, error)
+}
+
+// HTTPNewsConfig configures an HTTPNewsProvider.
+type HTTPNewsConfig struct {
+	// Endpoint is the search URL. The query is sent as the "q" parameter and
+	// MaxResults as "limit".
+	Endpoint string
+	// APIKey, if set, is sent in the X-Api-Key header.
+	APIKey     string
+	MaxResults int
+	CacheTTL   time.Duration
+	HTTPClient *http.Client
+}
+
+// HTTPNewsProvider is a NewsProvider backed by a pluggable HTTP search API.
+// The endpoint must return JSON of the form
+// {"articles": [{"title": "...", "description": "..."}]}.
+type HTTPNewsProvider struct {
+	config     HTTPNewsConfig
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string]newsCacheEntry // query -> headlines
+}
+
+type newsCacheEntry struct {
+	headlines []string
+	fetchedAt time.Time
+}
+
+type newsResponse struct {
+	Articles []struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	} `json:"articles"`
+}
+
+// NewHTTPNewsProvider creates a news provider for the given endpoint.
+func NewHTTPNewsProvider(config HTTPNewsConfig) *HTTPNewsProvider {
+	if config.MaxResults <= 0 {
+		config.MaxResults = 5
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = 15 * time.Minute
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &HTTPNewsProvider{
+		config:     config,
+		httpClient: httpClient,
+		cache:      make(map[string]newsCacheEntry),
+	}
+}
+
+// FetchNews implements NewsProvider. Results are cached per query for CacheTTL.
+func (p *HTTPNewsProvider) FetchNews(ctx context.Context, query string) ([]string, error) {
+	p.mu.Lock()
+	if entry, ok := p.cache[query]; ok && time.Since(entry.fetchedAt) < p.config.CacheTTL {
+		p.mu.Unlock()
+		return entry.headlines, nil
+	}
+	p.mu.Unlock()
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(p.config.MaxResults))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", p.config.Endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.config.APIKey != "" {
+		req.Header.Set("X-Api-Key", p.config.APIKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch news: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("news API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var parsed newsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("decode news response: %w", err)
+	}
+
+	headlines := make([]string, 0, len(parsed.Articles))
+	for _, a := range parsed.Articles {
+		if a.Title == "" {
+			continue
+		}
+		headline := a.Title
+		if a.Description != "" {
+			headline += " - " + a.Description
+		}
+		headlines = append(headlines, headline)
+		if len(headlines) >= p.config.MaxResults {
+			break
+		}
+	}
+
+	p.mu.Lock()
+	p.cache[query] = newsCacheEntry{headlines: headlines, fetchedAt: time.Now()}
+	p.mu.Unlock()
+
+	return headlines, nil
+}
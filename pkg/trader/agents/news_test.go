@@ -0,0 +1,52 @@
+package agents
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPNewsProvider_CachesPerQuery(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("q") == "" {
+			t.Error("Expected q parameter")
+		}
+		if r.Header.Get("X-Api-Key") != "secret" {
+			t.Errorf("Expected API key header, got %q", r.Header.Get("X-Api-Key"))
+		}
+		w.Write([]byte(`{"articles":[{"title":"Headline one","description":"detail"},{"title":""},{"title":"Headline two"}]}`))
+	}))
+	defer server.Close()
+
+	p := NewHTTPNewsProvider(HTTPNewsConfig{
+		Endpoint: server.URL,
+		APIKey:   "secret",
+		CacheTTL: time.Minute,
+	})
+
+	ctx := context.Background()
+	headlines, err := p.FetchNews(ctx, "Will it rain?")
+	if err != nil {
+		t.Fatalf("FetchNews failed: %v", err)
+	}
+	if len(headlines) != 2 {
+		t.Fatalf("Expected 2 headlines, got %d: %v", len(headlines), headlines)
+	}
+	if headlines[0] != "Headline one - detail" {
+		t.Errorf("Unexpected headline: %q", headlines[0])
+	}
+
+	p.FetchNews(ctx, "Will it rain?")
+	if calls != 1 {
+		t.Errorf("Expected cached second lookup, got %d calls", calls)
+	}
+
+	p.FetchNews(ctx, "Will it snow?")
+	if calls != 2 {
+		t.Errorf("Expected a new query to hit the API, got %d calls", calls)
+	}
+}
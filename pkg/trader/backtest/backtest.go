@@ -24,7 +24,7 @@ type PricePoint struct {
 	TokenID   string          `json:"token_id"`
 	Market    string          `json:"market"`
 	Price     decimal.Decimal `json:"price"`
-	Volume    decimal.Decimal `json:"volume"`
+	Volume    decimal.Decimal `json:"volume"` // Notional traded in the interval ending at Timestamp
 	BidPrice  decimal.Decimal `json:"bid_price,omitempty"`
 	AskPrice  decimal.Decimal `json:"ask_price,omitempty"`
 	BidSize   decimal.Decimal `json:"bid_size,omitempty"`
@@ -50,11 +50,67 @@ type Config struct {
 	TimeScale      float64       // Speed multiplier (1.0 = real-time, 0 = instant)
 	TickInterval   time.Duration // How often to process ticks
 	SlippageModel  paper.SlippageModel
+	SimpleSlippage bool // Apply SlippageModel to market orders in simple Mode
 	MakerFeeBps    decimal.Decimal
 	TakerFeeBps    decimal.Decimal
+	MakerRebateBps decimal.Decimal // Paid on resting fills; see paper.SimulationConfig
 	AllowShorts    bool
+
+	// ShortFundingBps is the daily borrow cost of short positions; see
+	// paper.SimulationConfig. It is reported in Result.TotalFunding and
+	// already deducted from TotalPnL.
+	ShortFundingBps decimal.Decimal
+
+	// Warm-up runs the strategy over the first ticks without counting them:
+	// trades and equity points before the boundary are left out of the
+	// Result. The boundary is the later of WarmupPeriod after the first tick
+	// and WarmupTicks ticks in. Zero for both disables warm-up.
+	WarmupPeriod time.Duration
+	WarmupTicks  int
+
+	// QueueDepth is the size, in shares, assumed to rest ahead of each new
+	// limit order at its price; see paper.SimulationConfig. Each point's
+	// Volume, converted to shares at its Price, is reported to the engine as
+	// traded there, so queued orders fill as volume works through the queue.
+	// Zero disables the queue model.
+	QueueDepth decimal.Decimal
+
+	// QueueFromBook queues each limit order behind the size the point's book
+	// shows at its price instead of QueueDepth, fed by the same volume.
+	// paper.ModeRealistic only.
+	QueueFromBook bool
+
+	// Mode is the fill simulation. paper.ModeRealistic walks the book from
+	// GetOrderBook instead of filling at the price.
+	Mode paper.Mode
+
+	// The book is built from a point's BidPrice/AskPrice and BidSize/AskSize
+	// where present, otherwise BookSpread wide around Price. Each side has
+	// BookLevels levels one tick apart; BookDepth is the size of every level
+	// without a quoted size. Zero values use the DefaultConfig values.
+	BookSpread decimal.Decimal
+	BookDepth  decimal.Decimal
+	BookLevels int
+
+	// Validation checks each dataset with HistoricalData.Validate before
+	// running: ValidateWarn reports issues in Result.DataIssues,
+	// ValidateStrict refuses to run. Off by default.
+	Validation ValidationMode
+
+	// PerformanceFeePct simulates a managed account's performance fee: this
+	// percentage (20 for 20%) of equity gains above the high-water mark is
+	// taken from cash every FeeInterval of simulated time and at the end of
+	// the run. The mark starts at the equity at the warm-up boundary and only
+	// rises, so drawdowns must be recovered before more fees are due.
+	// FeeInterval 0 charges only at the end; zero PerformanceFeePct disables
+	// the fee.
+	PerformanceFeePct decimal.Decimal
+	FeeInterval       time.Duration
 }
 
+// bookTick is the price step between synthetic book levels.
+var bookTick = decimal.NewFromFloat(0.01)
+
 // DefaultConfig returns default backtest configuration.
 func DefaultConfig() *Config {
 	return &Config{
@@ -64,28 +120,55 @@ func DefaultConfig() *Config {
 		SlippageModel:  paper.SlippageLinear,
 		MakerFeeBps:    decimal.Zero,
 		TakerFeeBps:    decimal.NewFromFloat(0.5),
+		BookSpread:     decimal.NewFromFloat(0.01),
+		BookDepth:      decimal.NewFromInt(1000),
+		BookLevels:     5,
 	}
 }
 
 // Result holds backtest results.
 type Result struct {
+	Strategy       string          `json:"strategy,omitempty"` // Set by CompareStrategies
 	StartTime      time.Time       `json:"start_time"`
 	EndTime        time.Time       `json:"end_time"`
 	Duration       time.Duration   `json:"duration"`
 	InitialBalance decimal.Decimal `json:"initial_balance"`
 	FinalBalance   decimal.Decimal `json:"final_balance"`
-	TotalPnL       decimal.Decimal `json:"total_pnl"`
-	TotalReturn    decimal.Decimal `json:"total_return"` // Percentage
+	TotalPnL       decimal.Decimal `json:"total_pnl"`    // Net of PerformanceFeesPaid
+	TotalReturn    decimal.Decimal `json:"total_return"` // Percentage, net of PerformanceFeesPaid
 	TotalTrades    int             `json:"total_trades"`
 	WinningTrades  int             `json:"winning_trades"`
 	LosingTrades   int             `json:"losing_trades"`
 	WinRate        decimal.Decimal `json:"win_rate"`
 	MaxDrawdown    decimal.Decimal `json:"max_drawdown"`
 	SharpeRatio    decimal.Decimal `json:"sharpe_ratio"`
+	CalmarRatio    decimal.Decimal `json:"calmar_ratio"` // Annualized return / max drawdown
 	TotalVolume    decimal.Decimal `json:"total_volume"`
 	TotalFees      decimal.Decimal `json:"total_fees"`
-	Trades         []TradeRecord   `json:"trades,omitempty"`
-	EquityCurve    []EquityPoint   `json:"equity_curve,omitempty"`
+	TotalFunding   decimal.Decimal `json:"total_funding"` // Short borrow cost
+
+	// BenchmarkReturn is the percentage return of buying and holding the
+	// same markets over the same period; see Backtest.benchmarkReturn.
+	// Alpha is TotalReturn minus BenchmarkReturn, in percentage points.
+	BenchmarkReturn decimal.Decimal `json:"benchmark_return"`
+	Alpha           decimal.Decimal `json:"alpha"`
+
+	// PerformanceFeesPaid is the total Config.PerformanceFeePct charged.
+	// It has already come out of FinalBalance.
+	PerformanceFeesPaid decimal.Decimal `json:"performance_fees_paid"`
+
+	// ImplementationShortfall is the total execution cost versus filling every
+	// trade at its decision price with no fees: sum(Slippage*Size) + fees.
+	ImplementationShortfall decimal.Decimal `json:"implementation_shortfall"`
+
+	// LLMCost is the estimated USD strategies spent on LLM calls, reported
+	// with AddLLMCost. Warm-up spend is included.
+	LLMCost decimal.Decimal `json:"llm_cost"`
+
+	Trades      []TradeRecord `json:"trades,omitempty"`
+	EquityCurve []EquityPoint `json:"equity_curve,omitempty"`
+	Annotations []Annotation  `json:"annotations,omitempty"`
+	DataIssues  []DataIssue   `json:"data_issues,omitempty"` // ValidateWarn only
 }
 
 // TradeRecord records a single trade during backtest.
@@ -97,6 +180,11 @@ type TradeRecord struct {
 	Size      decimal.Decimal `json:"size"`
 	Fee       decimal.Decimal `json:"fee"`
 	PnL       decimal.Decimal `json:"pnl"`
+
+	// DecisionPrice is the mid when the strategy placed the order. Slippage is
+	// the per-share cost versus that price (positive means a worse fill).
+	DecisionPrice decimal.Decimal `json:"decision_price"`
+	Slippage      decimal.Decimal `json:"slippage"`
 }
 
 // EquityPoint records equity at a point in time.
@@ -106,6 +194,14 @@ type EquityPoint struct {
 	Drawdown  decimal.Decimal `json:"drawdown"`
 }
 
+// Annotation is a note a strategy attached to a tick with Annotate, e.g. why
+// it did or didn't trade.
+type Annotation struct {
+	Timestamp time.Time `json:"timestamp"`
+	TokenID   string    `json:"token_id"`
+	Message   string    `json:"message"`
+}
+
 // Strategy is the interface for trading strategies.
 type Strategy interface {
 	// OnTick is called for each price update.
@@ -127,10 +223,25 @@ type Backtest struct {
 	currentTime time.Time
 
 	// Results tracking
-	trades      []TradeRecord
-	equityCurve []EquityPoint
-	peakEquity  decimal.Decimal
-	maxDrawdown decimal.Decimal
+	decisionPrices map[string]decimal.Decimal // orderID -> mid at placement
+	trades         []TradeRecord
+	equityCurve    []EquityPoint
+	annotations    []Annotation
+	dataIssues     []DataIssue
+	llmCost        decimal.Decimal
+	peakEquity     decimal.Decimal
+	maxDrawdown    decimal.Decimal
+
+	// Performance fee state; see Config.PerformanceFeePct
+	highWaterMark   decimal.Decimal
+	nextFeeTime     time.Time
+	performanceFees decimal.Decimal
+
+	// Warm-up boundary, set by endWarmup
+	warmedUp     bool
+	warmupEnd    time.Time
+	warmupStats  *paper.AccountStats // Engine stats at the boundary
+	warmupTrades int                 // len(trades) at the boundary
 }
 
 // backtestPriceProvider provides prices from historical data.
@@ -161,35 +272,66 @@ func New(config *Config) *Backtest {
 	}
 
 	bt := &Backtest{
-		config:      config,
-		data:        make(map[string]*HistoricalData),
-		trades:      make([]TradeRecord, 0),
-		equityCurve: make([]EquityPoint, 0),
-		peakEquity:  config.InitialBalance,
+		config:         config,
+		data:           make(map[string]*HistoricalData),
+		decisionPrices: make(map[string]decimal.Decimal),
+		trades:         make([]TradeRecord, 0),
+		equityCurve:    make([]EquityPoint, 0),
+		peakEquity:     config.InitialBalance,
 	}
 
 	paperConfig := &paper.SimulationConfig{
-		Mode:           paper.ModeSimple,
+		Mode:           config.Mode,
 		InitialBalance: config.InitialBalance,
 		MakerFeeBps:    config.MakerFeeBps,
 		TakerFeeBps:    config.TakerFeeBps,
+		MakerRebateBps: config.MakerRebateBps,
 		SlippageModel:  config.SlippageModel,
+
+		SimpleModeSlippage: config.SimpleSlippage,
+		ShortFundingBps:    config.ShortFundingBps,
+		QueueDepth:         config.QueueDepth,
+		QueueFromBook:      config.QueueFromBook,
 	}
 
 	// Create price provider that uses backtest data
 	provider := &backtestPriceProvider{bt: bt}
 	bt.engine = paper.NewEngine(paperConfig, provider)
 
+	// Stamp orders and trades with simulated time
+	bt.engine.SetClock(paper.ClockFunc(func() time.Time { return bt.currentTime }))
+
+	// Capture the decision price when an order is first seen
+	bt.engine.OnOrder(func(order *paper.Order) {
+		if _, ok := bt.decisionPrices[order.ID]; ok {
+			return
+		}
+		if mid, ok := bt.GetPrice(order.TokenID); ok {
+			bt.decisionPrices[order.ID] = mid
+		}
+	})
+
 	// Set up trade tracking
 	bt.engine.OnTrade(func(trade *paper.Trade) {
+		decision, ok := bt.decisionPrices[trade.OrderID]
+		if !ok {
+			decision = trade.Price
+		}
+		slippage := trade.Price.Sub(decision)
+		if trade.Side == paper.SideSell {
+			slippage = slippage.Neg()
+		}
+
 		bt.trades = append(bt.trades, TradeRecord{
-			Timestamp: bt.currentTime,
-			TokenID:   trade.TokenID,
-			Side:      trade.Side.String(),
-			Price:     trade.Price,
-			Size:      trade.Size,
-			Fee:       trade.Fee,
-			PnL:       trade.PnL,
+			Timestamp:     bt.currentTime,
+			TokenID:       trade.TokenID,
+			Side:          trade.Side.String(),
+			Price:         trade.Price,
+			Size:          trade.Size,
+			Fee:           trade.Fee,
+			PnL:           trade.PnL,
+			DecisionPrice: decision,
+			Slippage:      slippage,
 		})
 	})
 
@@ -288,6 +430,12 @@ func (bt *Backtest) LoadDataFromCSV(filename string) error {
 		if idx, ok := colIndex["ask_price"]; ok {
 			point.AskPrice, _ = decimal.NewFromString(record[idx])
 		}
+		if idx, ok := colIndex["bid_size"]; ok {
+			point.BidSize, _ = decimal.NewFromString(record[idx])
+		}
+		if idx, ok := colIndex["ask_size"]; ok {
+			point.AskSize, _ = decimal.NewFromString(record[idx])
+		}
 
 		dataByToken[point.TokenID] = append(dataByToken[point.TokenID], point)
 	}
@@ -320,6 +468,12 @@ func (bt *Backtest) LoadDataFromCSV(filename string) error {
 func (bt *Backtest) Run(ctx context.Context, strategy Strategy) (*Result, error) {
 	bt.strategy = strategy
 
+	if bt.config.Validation != ValidateOff {
+		if err := bt.validateData(); err != nil {
+			return nil, err
+		}
+	}
+
 	// Collect all price points and sort by time
 	allPoints := make([]PricePoint, 0)
 	for _, data := range bt.data {
@@ -337,8 +491,10 @@ func (bt *Backtest) Run(ctx context.Context, strategy Strategy) (*Result, error)
 	bt.currentTime = allPoints[0].Timestamp
 	strategy.OnStart(ctx, bt)
 
+	warmupUntil := allPoints[0].Timestamp.Add(bt.config.WarmupPeriod)
+
 	// Process each tick
-	for _, point := range allPoints {
+	for i, point := range allPoints {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
@@ -347,14 +503,23 @@ func (bt *Backtest) Run(ctx context.Context, strategy Strategy) (*Result, error)
 
 		bt.currentTime = point.Timestamp
 
-		// Update price in engine
+		if !bt.warmedUp && i >= bt.config.WarmupTicks && !point.Timestamp.Before(warmupUntil) {
+			bt.endWarmup()
+		}
+
+		// Update price in engine, then trade the point's volume through
+		// queued limit orders
 		bt.engine.ProcessTick(ctx, point.TokenID, point.Price)
+		bt.processVolume(ctx, point)
 
 		// Call strategy
 		strategy.OnTick(ctx, bt, point)
 
-		// Record equity
-		bt.recordEquity()
+		// Charge fees and record equity
+		if bt.warmedUp {
+			bt.chargeFeeIfDue()
+			bt.recordEquity()
+		}
 
 		// Apply time scaling
 		if bt.config.TimeScale > 0 {
@@ -371,17 +536,85 @@ func (bt *Backtest) Run(ctx context.Context, strategy Strategy) (*Result, error)
 
 	strategy.OnEnd(ctx, bt)
 
+	// Warm-up covered the whole run: nothing counts
+	if !bt.warmedUp {
+		bt.endWarmup()
+	}
+	bt.chargePerformanceFee()
+
 	return bt.calculateResult(), nil
 }
 
-func (bt *Backtest) recordEquity() {
-	equity := bt.engine.GetBalance()
+// processVolume reports a point's traded volume to the engine when limit
+// orders queue, converting its notional to shares at the point's price.
+func (bt *Backtest) processVolume(ctx context.Context, point PricePoint) {
+	queueFromBook := bt.config.QueueFromBook && bt.config.Mode == paper.ModeRealistic
+	if !bt.config.QueueDepth.IsPositive() && !queueFromBook {
+		return
+	}
+	if !point.Volume.IsPositive() || !point.Price.IsPositive() {
+		return
+	}
+	bt.engine.ProcessTrade(ctx, point.TokenID, point.Price, point.Volume.Div(point.Price))
+}
+
+// endWarmup marks the current time as the warm-up boundary. Stats and
+// drawdown are measured from here on.
+func (bt *Backtest) endWarmup() {
+	bt.warmedUp = true
+	bt.warmupEnd = bt.currentTime
+	bt.warmupStats = bt.engine.GetStats()
+	bt.warmupTrades = len(bt.trades)
+	bt.peakEquity = bt.equity()
+	bt.maxDrawdown = decimal.Zero
+	bt.highWaterMark = bt.equity()
+	bt.nextFeeTime = bt.currentTime.Add(bt.config.FeeInterval)
+}
+
+// chargeFeeIfDue charges the performance fee once per FeeInterval.
+func (bt *Backtest) chargeFeeIfDue() {
+	interval := bt.config.FeeInterval
+	if interval <= 0 || bt.currentTime.Before(bt.nextFeeTime) {
+		return
+	}
+	bt.chargePerformanceFee()
+	for !bt.nextFeeTime.After(bt.currentTime) {
+		bt.nextFeeTime = bt.nextFeeTime.Add(interval)
+	}
+}
 
-	// Add unrealized PnL from positions
-	for _, pos := range bt.engine.GetPositions() {
-		equity = equity.Add(pos.UnrealizedPnL)
+// chargePerformanceFee takes Config.PerformanceFeePct of the equity above
+// the high-water mark out of cash. A fee larger than the cash on hand is
+// paid in part, and the mark rises only by the profit that part covers, so
+// the rest stays chargeable.
+func (bt *Backtest) chargePerformanceFee() {
+	rate := bt.config.PerformanceFeePct.Div(decimal.NewFromInt(100))
+	if !rate.IsPositive() {
+		return
+	}
+	profit := bt.equity().Sub(bt.highWaterMark)
+	if !profit.IsPositive() {
+		return
 	}
 
+	fee := decimal.Min(profit.Mul(rate), bt.engine.GetBalance())
+	if !fee.IsPositive() || bt.engine.Withdraw(fee) != nil {
+		return
+	}
+	bt.performanceFees = bt.performanceFees.Add(fee)
+	// Paid in full this is the equity after the fee
+	bt.highWaterMark = bt.highWaterMark.Add(fee.Div(rate)).Sub(fee)
+}
+
+// equity is cash plus positions marked at their last tick, shorts as
+// liabilities. Drawdown and performance fees are both measured on it.
+func (bt *Backtest) equity() decimal.Decimal {
+	return bt.engine.GetEquity()
+}
+
+func (bt *Backtest) recordEquity() {
+	equity := bt.equity()
+
 	// Track peak and drawdown
 	if equity.GreaterThan(bt.peakEquity) {
 		bt.peakEquity = equity
@@ -420,42 +653,131 @@ func (bt *Backtest) resolveMarket(data *HistoricalData) {
 	})
 }
 
+// validateData validates every dataset in token order. In ValidateStrict
+// mode any issue is an error; otherwise issues are kept for the Result.
+func (bt *Backtest) validateData() error {
+	tokenIDs := make([]string, 0, len(bt.data))
+	for tokenID := range bt.data {
+		tokenIDs = append(tokenIDs, tokenID)
+	}
+	sort.Strings(tokenIDs)
+
+	var issues []DataIssue
+	for _, tokenID := range tokenIDs {
+		issues = append(issues, bt.data[tokenID].Validate()...)
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	if bt.config.Validation == ValidateStrict {
+		return fmt.Errorf("%w: %d issues, first: %s", ErrInvalidData, len(issues), issues[0])
+	}
+	bt.dataIssues = issues
+	return nil
+}
+
 func (bt *Backtest) calculateResult() *Result {
 	stats := bt.engine.GetStats()
+	base := bt.warmupStats
+	trades := bt.trades[bt.warmupTrades:]
+
+	startTime := bt.config.StartTime
+	if (bt.config.WarmupPeriod > 0 || bt.config.WarmupTicks > 0) && bt.warmupEnd.After(startTime) {
+		startTime = bt.warmupEnd
+	}
 
 	result := &Result{
-		StartTime:      bt.config.StartTime,
+		StartTime:      startTime,
 		EndTime:        bt.config.EndTime,
-		Duration:       bt.config.EndTime.Sub(bt.config.StartTime),
+		Duration:       bt.config.EndTime.Sub(startTime),
 		InitialBalance: bt.config.InitialBalance,
 		FinalBalance:   bt.engine.GetBalance(),
-		TotalPnL:       stats.TotalPnL,
-		TotalTrades:    stats.TotalTrades,
-		WinningTrades:  stats.WinningTrades,
-		LosingTrades:   stats.LosingTrades,
-		WinRate:        stats.WinRate,
+		TotalPnL:       stats.TotalPnL.Sub(base.TotalPnL).Sub(bt.performanceFees),
+		TotalTrades:    stats.TotalTrades - base.TotalTrades,
+		WinningTrades:  stats.WinningTrades - base.WinningTrades,
+		LosingTrades:   stats.LosingTrades - base.LosingTrades,
 		MaxDrawdown:    bt.maxDrawdown,
-		TotalVolume:    stats.TotalVolume,
-		TotalFees:      stats.TotalFees,
-		Trades:         bt.trades,
+		TotalVolume:    stats.TotalVolume.Sub(base.TotalVolume),
+		TotalFees:      stats.TotalFees.Sub(base.TotalFees),
+		TotalFunding:   stats.TotalFunding.Sub(base.TotalFunding),
+		Trades:         trades,
 		EquityCurve:    bt.equityCurve,
+		Annotations:    bt.annotations,
+		DataIssues:     bt.dataIssues,
+		LLMCost:        bt.llmCost,
+
+		PerformanceFeesPaid: bt.performanceFees,
 	}
+	// OnEnd notes may carry an earlier resolution time
+	sort.SliceStable(result.Annotations, func(i, j int) bool {
+		return result.Annotations[i].Timestamp.Before(result.Annotations[j].Timestamp)
+	})
+	if result.TotalTrades > 0 {
+		result.WinRate = decimal.NewFromInt(int64(result.WinningTrades)).Div(decimal.NewFromInt(int64(result.TotalTrades)))
+	}
+
+	shortfall := decimal.Zero
+	for _, t := range trades {
+		shortfall = shortfall.Add(t.Slippage.Mul(t.Size)).Add(t.Fee)
+	}
+	result.ImplementationShortfall = shortfall
 
 	// Calculate return
 	if !bt.config.InitialBalance.IsZero() {
 		result.TotalReturn = result.TotalPnL.Div(bt.config.InitialBalance).Mul(decimal.NewFromInt(100))
 	}
+	result.BenchmarkReturn = bt.benchmarkReturn(startTime)
+	result.Alpha = result.TotalReturn.Sub(result.BenchmarkReturn)
 
 	// Simple Sharpe ratio approximation
 	// (This is a simplified version - proper Sharpe needs returns distribution)
 	if len(bt.equityCurve) > 1 && !bt.maxDrawdown.IsZero() {
 		// Risk-adjusted return: return / max drawdown
 		result.SharpeRatio = result.TotalReturn.Div(bt.maxDrawdown.Mul(decimal.NewFromInt(100)))
+
+		if result.Duration > 0 {
+			years := decimal.NewFromFloat(result.Duration.Hours() / (365 * 24))
+			annualReturn := result.TotalReturn.Div(years)
+			result.CalmarRatio = annualReturn.Div(bt.maxDrawdown.Mul(decimal.NewFromInt(100)))
+		}
 	}
 
 	return result
 }
 
+// benchmarkReturn is the percentage return of buy-and-hold from start: the
+// balance split equally across tokens, each bought at its first price at or
+// after start and valued at its last. Fees and slippage are ignored. Tokens
+// with no positive price in the period are left out.
+func (bt *Backtest) benchmarkReturn(start time.Time) decimal.Decimal {
+	total := decimal.Zero
+	held := 0
+	for _, data := range bt.data {
+		var first, last *PricePoint
+		for i := range data.Points {
+			p := &data.Points[i]
+			if p.Timestamp.Before(start) || !p.Price.IsPositive() {
+				continue
+			}
+			if first == nil || p.Timestamp.Before(first.Timestamp) {
+				first = p
+			}
+			if last == nil || !p.Timestamp.Before(last.Timestamp) {
+				last = p
+			}
+		}
+		if first == nil {
+			continue
+		}
+		total = total.Add(last.Price.Div(first.Price).Sub(decimal.NewFromInt(1)))
+		held++
+	}
+	if held == 0 {
+		return decimal.Zero
+	}
+	return total.Div(decimal.NewFromInt(int64(held))).Mul(decimal.NewFromInt(100))
+}
+
 // --- Trading methods for strategies ---
 
 // CurrentTime returns the current simulated time.
@@ -463,6 +785,21 @@ func (bt *Backtest) CurrentTime() time.Time {
 	return bt.currentTime
 }
 
+// Annotate attaches a note for tokenID to the current tick. Notes are
+// returned in Result.Annotations in time order, warm-up included.
+func (bt *Backtest) Annotate(tokenID, msg string) {
+	bt.annotations = append(bt.annotations, Annotation{
+		Timestamp: bt.currentTime,
+		TokenID:   tokenID,
+		Message:   msg,
+	})
+}
+
+// AddLLMCost records usd of LLM spend against the run's Result.LLMCost.
+func (bt *Backtest) AddLLMCost(usd decimal.Decimal) {
+	bt.llmCost = bt.llmCost.Add(usd)
+}
+
 // Balance returns the current balance.
 func (bt *Backtest) Balance() decimal.Decimal {
 	return bt.engine.GetBalance()
@@ -530,41 +867,86 @@ func (bt *Backtest) SellLimit(tokenID, market string, size, price decimal.Decima
 
 // GetPrice returns the last price for a token.
 func (bt *Backtest) GetPrice(tokenID string) (decimal.Decimal, bool) {
-	data, ok := bt.data[tokenID]
+	point, ok := bt.currentPoint(tokenID)
 	if !ok {
 		return decimal.Zero, false
 	}
+	return point.Price, true
+}
+
+// currentPoint returns the latest point at or before the current time.
+func (bt *Backtest) currentPoint(tokenID string) (PricePoint, bool) {
+	data, ok := bt.data[tokenID]
+	if !ok {
+		return PricePoint{}, false
+	}
 
-	// Find the latest price at or before current time
 	for i := len(data.Points) - 1; i >= 0; i-- {
 		if !data.Points[i].Timestamp.After(bt.currentTime) {
-			return data.Points[i].Price, true
+			return data.Points[i], true
 		}
 	}
-	return decimal.Zero, false
+	return PricePoint{}, false
 }
 
-// GetOrderBook returns a simulated order book.
+// GetOrderBook returns a simulated order book around the current point's
+// quote, or around its price if it has none.
 func (bt *Backtest) GetOrderBook(tokenID string) *book.OrderBook {
-	price, ok := bt.GetPrice(tokenID)
+	point, ok := bt.currentPoint(tokenID)
 	if !ok {
 		return nil
 	}
 
-	data := bt.data[tokenID]
-	ob := book.NewOrderBook(tokenID, data.Market)
-
-	// Create synthetic orderbook around the price
-	spread := decimal.NewFromFloat(0.01) // 1% spread
-	bidPrice := price.Sub(spread.Div(decimal.NewFromInt(2)))
-	askPrice := price.Add(spread.Div(decimal.NewFromInt(2)))
+	defaults := DefaultConfig()
+	spread := bt.config.BookSpread
+	if !spread.IsPositive() {
+		spread = defaults.BookSpread
+	}
+	depth := bt.config.BookDepth
+	if !depth.IsPositive() {
+		depth = defaults.BookDepth
+	}
+	levels := bt.config.BookLevels
+	if levels <= 0 {
+		levels = defaults.BookLevels
+	}
 
-	ob.SetBids([]book.PriceLevel{
-		{Price: bidPrice, Size: decimal.NewFromInt(1000)},
-	})
-	ob.SetAsks([]book.PriceLevel{
-		{Price: askPrice, Size: decimal.NewFromInt(1000)},
-	})
+	half := spread.Div(decimal.NewFromInt(2))
+	bidPrice, askPrice := point.BidPrice, point.AskPrice
+	if !bidPrice.IsPositive() {
+		bidPrice = point.Price.Sub(half)
+	}
+	if !askPrice.IsPositive() {
+		askPrice = point.Price.Add(half)
+	}
+	bidSize, askSize := point.BidSize, point.AskSize
+	if !bidSize.IsPositive() {
+		bidSize = depth
+	}
+	if !askSize.IsPositive() {
+		askSize = depth
+	}
 
+	ob := book.NewOrderBook(tokenID, bt.data[tokenID].Market)
+	ob.SetBids(bookSide(bidPrice, bidSize, depth, bookTick.Neg(), levels))
+	ob.SetAsks(bookSide(askPrice, askSize, depth, bookTick, levels))
 	return ob
 }
+
+// bookSide builds levels stepping away from the top of book, dropping any
+// that fall outside (0, 1).
+func bookSide(top, topSize, depth, step decimal.Decimal, levels int) []book.PriceLevel {
+	side := make([]book.PriceLevel, 0, levels)
+	for i := 0; i < levels; i++ {
+		price := top.Add(step.Mul(decimal.NewFromInt(int64(i))))
+		if !price.IsPositive() || price.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+			break
+		}
+		size := depth
+		if i == 0 {
+			size = topSize
+		}
+		side = append(side, book.PriceLevel{Price: price, Size: size})
+	}
+	return side
+}
@@ -5,6 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/agents"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/paper"
+	"github.com/phenomenon0/polymarket-agents/tools"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -69,6 +73,86 @@ func TestBacktestWithSyntheticData(t *testing.T) {
 		result.TotalReturn.InexactFloat64())
 }
 
+func TestImplementationShortfall(t *testing.T) {
+	// Fixed model: fills 0.1% worse than mid, no fees
+	bt := New(&Config{
+		InitialBalance: decimal.NewFromInt(1000),
+		SlippageModel:  paper.SlippageFixed,
+		SimpleSlippage: true,
+	})
+
+	now := time.Now()
+	bt.LoadData(&HistoricalData{
+		TokenID: "token1",
+		Market:  "market1",
+		Points: []PricePoint{
+			{Timestamp: now, TokenID: "token1", Market: "market1", Price: decimal.RequireFromString("0.50")},
+			{Timestamp: now.Add(time.Minute), TokenID: "token1", Market: "market1", Price: decimal.RequireFromString("0.60")},
+		},
+	})
+
+	// Buys 100 on the first tick, sells on end at 0.60
+	result, err := bt.Run(context.Background(), NewBuyAndHoldStrategy(100))
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(result.Trades) != 2 {
+		t.Fatalf("Expected 2 trades, got %d", len(result.Trades))
+	}
+
+	buy, sell := result.Trades[0], result.Trades[1]
+	if !buy.DecisionPrice.Equal(decimal.RequireFromString("0.50")) || !buy.Slippage.Equal(decimal.RequireFromString("0.0005")) {
+		t.Errorf("Buy: expected decision 0.50 slippage 0.0005, got %s / %s", buy.DecisionPrice, buy.Slippage)
+	}
+	if !sell.DecisionPrice.Equal(decimal.RequireFromString("0.60")) || !sell.Slippage.Equal(decimal.RequireFromString("0.0006")) {
+		t.Errorf("Sell: expected decision 0.60 slippage 0.0006, got %s / %s", sell.DecisionPrice, sell.Slippage)
+	}
+
+	// 100*0.0005 + 100*0.0006
+	if want := decimal.RequireFromString("0.11"); !result.ImplementationShortfall.Equal(want) {
+		t.Errorf("Expected shortfall %s, got %s", want, result.ImplementationShortfall)
+	}
+}
+
+func TestTradesUseSimulatedTime(t *testing.T) {
+	bt := New(nil)
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ticks := []time.Time{start, start.Add(time.Hour)}
+	bt.LoadData(&HistoricalData{
+		TokenID: "token1",
+		Market:  "market1",
+		Points: []PricePoint{
+			{Timestamp: ticks[0], TokenID: "token1", Market: "market1", Price: decimal.RequireFromString("0.50")},
+			{Timestamp: ticks[1], TokenID: "token1", Market: "market1", Price: decimal.RequireFromString("0.55")},
+		},
+	})
+
+	// Buys on the first tick, sells on end (simulated time is the last tick)
+	result, err := bt.Run(context.Background(), NewBuyAndHoldStrategy(10))
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	history := bt.engine.GetAccount().TradeHistory
+	if len(history) != 2 || len(result.Trades) != 2 {
+		t.Fatalf("Expected 2 trades, got %d engine / %d recorded", len(history), len(result.Trades))
+	}
+	for i, want := range ticks {
+		if !history[i].Timestamp.Equal(want) {
+			t.Errorf("Engine trade %d: expected %v, got %v", i, want, history[i].Timestamp)
+		}
+		if !result.Trades[i].Timestamp.Equal(history[i].Timestamp) {
+			t.Errorf("Trade record %d timestamp %v disagrees with engine %v", i, result.Trades[i].Timestamp, history[i].Timestamp)
+		}
+	}
+
+	pos, _ := bt.Position("token1")
+	if pos != nil && !pos.OpenedAt.Equal(ticks[0]) {
+		t.Errorf("Position opened at %v, expected %v", pos.OpenedAt, ticks[0])
+	}
+}
+
 func TestMomentumStrategy(t *testing.T) {
 	config := &Config{
 		InitialBalance: decimal.NewFromInt(1000),
@@ -196,6 +280,214 @@ func TestBacktestEquityCurve(t *testing.T) {
 	}
 }
 
+// buyEveryTick buys one share on every tick.
+type buyEveryTick struct{}
+
+func (buyEveryTick) OnStart(ctx context.Context, bt *Backtest) {}
+func (buyEveryTick) OnEnd(ctx context.Context, bt *Backtest)   {}
+func (buyEveryTick) OnTick(ctx context.Context, bt *Backtest, point PricePoint) {
+	bt.Buy(point.TokenID, point.Market, decimal.NewFromInt(1))
+}
+
+func TestWarmupExcludesEarlyTrades(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	points := make([]PricePoint, 10)
+	for i := range points {
+		points[i] = PricePoint{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			TokenID:   "token1",
+			Market:    "market1",
+			Price:     decimal.RequireFromString("0.50"),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		config Config
+		want   int
+	}{
+		{"none", Config{}, 10},
+		{"ticks", Config{WarmupTicks: 4}, 6},
+		{"period", Config{WarmupPeriod: 3 * time.Minute}, 7},
+		{"later of both", Config{WarmupTicks: 2, WarmupPeriod: 5 * time.Minute}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			config.InitialBalance = decimal.NewFromInt(1000)
+			bt := New(&config)
+			bt.LoadData(&HistoricalData{TokenID: "token1", Market: "market1", Points: points})
+
+			result, err := bt.Run(context.Background(), buyEveryTick{})
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			// The strategy still trades during warm-up
+			if n := len(bt.engine.GetAccount().TradeHistory); n != 10 {
+				t.Errorf("Expected 10 engine trades, got %d", n)
+			}
+			if result.TotalTrades != tt.want || len(result.Trades) != tt.want {
+				t.Errorf("Expected %d counted trades, got %d (%d records)", tt.want, result.TotalTrades, len(result.Trades))
+			}
+			if len(result.EquityCurve) != tt.want {
+				t.Errorf("Expected %d equity points, got %d", tt.want, len(result.EquityCurve))
+			}
+			if first := result.Trades[0].Timestamp; !first.Equal(points[10-tt.want].Timestamp) {
+				t.Errorf("First counted trade at %v, expected %v", first, points[10-tt.want].Timestamp)
+			}
+		})
+	}
+}
+
+// holdCash never trades.
+type holdCash struct{}
+
+func (holdCash) OnStart(ctx context.Context, bt *Backtest)                  {}
+func (holdCash) OnEnd(ctx context.Context, bt *Backtest)                    {}
+func (holdCash) OnTick(ctx context.Context, bt *Backtest, point PricePoint) {}
+
+func TestFlatStrategyHasNegativeAlphaInRisingMarket(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	points := make([]PricePoint, 5)
+	for i := range points {
+		points[i] = PricePoint{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			TokenID:   "token1",
+			Market:    "market1",
+			Price:     decimal.RequireFromString("0.40").Add(decimal.RequireFromString("0.05").Mul(decimal.NewFromInt(int64(i)))),
+		}
+	}
+
+	bt := New(&Config{InitialBalance: decimal.NewFromInt(1000)})
+	bt.LoadData(&HistoricalData{TokenID: "token1", Market: "market1", Points: points})
+
+	result, err := bt.Run(context.Background(), holdCash{})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// 0.40 -> 0.60 is a 50% buy-and-hold return against the strategy's 0%
+	if !result.TotalReturn.IsZero() {
+		t.Errorf("Expected 0%% strategy return, got %s", result.TotalReturn)
+	}
+	if !result.BenchmarkReturn.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("Expected 50%% benchmark return, got %s", result.BenchmarkReturn)
+	}
+	if !result.Alpha.Equal(decimal.NewFromInt(-50)) {
+		t.Errorf("Expected -50 alpha, got %s", result.Alpha)
+	}
+}
+
+// buyOnce buys size shares on the first tick and holds them.
+type buyOnce struct {
+	size   decimal.Decimal
+	bought bool
+}
+
+func (s *buyOnce) OnStart(ctx context.Context, bt *Backtest) {}
+func (s *buyOnce) OnEnd(ctx context.Context, bt *Backtest)   {}
+func (s *buyOnce) OnTick(ctx context.Context, bt *Backtest, point PricePoint) {
+	if !s.bought {
+		s.bought = bt.Buy(point.TokenID, point.Market, s.size) == nil
+	}
+}
+
+func TestPerformanceFeeChargesGainsAboveHighWaterMark(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	prices := []string{"0.50", "0.60", "0.55", "0.50", "0.65"}
+	points := make([]PricePoint, len(prices))
+	for i, p := range prices {
+		points[i] = PricePoint{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			TokenID:   "token1",
+			Market:    "market1",
+			Price:     decimal.RequireFromString(p),
+		}
+	}
+
+	run := func(feePct int64) *Result {
+		bt := New(&Config{
+			InitialBalance:    decimal.NewFromInt(1000),
+			PerformanceFeePct: decimal.NewFromInt(feePct),
+			FeeInterval:       time.Hour,
+		})
+		bt.LoadData(&HistoricalData{TokenID: "token1", Market: "market1", Points: points})
+
+		result, err := bt.Run(context.Background(), &buyOnce{size: decimal.NewFromInt(1000)})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		return result
+	}
+	gross, result := run(0), run(20)
+
+	// 1000 shares bought for $500. At 0.60 equity is $1100: $20 on the $100
+	// gain, mark $1080. The drawdown to 0.55 and 0.50 is charged nothing. At
+	// 0.65 equity is $1130: $10 on the $50 above the mark.
+	if !result.PerformanceFeesPaid.Equal(decimal.NewFromInt(30)) {
+		t.Errorf("Expected $30 of performance fees, got %s", result.PerformanceFeesPaid)
+	}
+	if !result.FinalBalance.Equal(decimal.NewFromInt(470)) {
+		t.Errorf("Expected fees out of the $500 cash balance, got %s", result.FinalBalance)
+	}
+	if !gross.PerformanceFeesPaid.IsZero() {
+		t.Errorf("Expected no fees without PerformanceFeePct, got %s", gross.PerformanceFeesPaid)
+	}
+	if net := gross.TotalPnL.Sub(result.TotalPnL); !net.Equal(decimal.NewFromInt(30)) {
+		t.Errorf("Expected PnL net of the $30 in fees, got %s less", net)
+	}
+}
+
+type sellOnce struct {
+	size decimal.Decimal
+	sold bool
+}
+
+func (s *sellOnce) OnStart(ctx context.Context, bt *Backtest) {}
+func (s *sellOnce) OnEnd(ctx context.Context, bt *Backtest)   {}
+func (s *sellOnce) OnTick(ctx context.Context, bt *Backtest, point PricePoint) {
+	if !s.sold {
+		s.sold = bt.Sell(point.TokenID, point.Market, s.size) == nil
+	}
+}
+
+func TestPerformanceFeeOnShortBook(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	prices := []string{"0.50", "0.40", "0.45"}
+	points := make([]PricePoint, len(prices))
+	for i, p := range prices {
+		points[i] = PricePoint{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			TokenID:   "token1",
+			Market:    "market1",
+			Price:     decimal.RequireFromString(p),
+		}
+	}
+
+	bt := New(&Config{
+		InitialBalance:    decimal.NewFromInt(1000),
+		PerformanceFeePct: decimal.NewFromInt(20),
+		FeeInterval:       time.Hour,
+	})
+	bt.LoadData(&HistoricalData{TokenID: "token1", Market: "market1", Points: points})
+
+	result, err := bt.Run(context.Background(), &sellOnce{size: decimal.NewFromInt(1000)})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// Short 1000 at 0.50. At 0.40 equity is $1100: $20 on the $100 gain,
+	// mark $1080. Back at 0.45 equity is $1030, below the mark.
+	if !result.PerformanceFeesPaid.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("Expected $20 of performance fees, got %s", result.PerformanceFeesPaid)
+	}
+	if want := decimal.NewFromInt(50).Div(decimal.NewFromInt(1080)); !result.MaxDrawdown.Equal(want) {
+		t.Errorf("Expected max drawdown %s from the $1080 mark, got %s", want, result.MaxDrawdown)
+	}
+}
+
 func TestBacktestNoData(t *testing.T) {
 	bt := New(nil)
 	strategy := NewBuyAndHoldStrategy(100)
@@ -246,3 +538,320 @@ func TestBacktestCancel(t *testing.T) {
 		t.Error("Expected context canceled error")
 	}
 }
+
+func TestGetOrderBookFromQuote(t *testing.T) {
+	d := decimal.RequireFromString
+	now := time.Now()
+
+	bt := New(&Config{InitialBalance: decimal.NewFromInt(1000), BookLevels: 3, BookDepth: d("500")})
+	bt.LoadData(&HistoricalData{
+		TokenID: "token1",
+		Market:  "market1",
+		Points: []PricePoint{
+			{Timestamp: now, TokenID: "token1", Price: d("0.50"),
+				BidPrice: d("0.48"), AskPrice: d("0.53"), BidSize: d("120"), AskSize: d("80")},
+			{Timestamp: now.Add(time.Minute), TokenID: "token1", Price: d("0.60")},
+		},
+	})
+
+	bt.currentTime = now
+	ob := bt.GetOrderBook("token1")
+	if bid, size := ob.BestBid(); !bid.Equal(d("0.48")) || !size.Equal(d("120")) {
+		t.Errorf("Expected best bid 120 @ 0.48, got %s @ %s", size, bid)
+	}
+	if ask, size := ob.BestAsk(); !ask.Equal(d("0.53")) || !size.Equal(d("80")) {
+		t.Errorf("Expected best ask 80 @ 0.53, got %s @ %s", size, ask)
+	}
+	if ob.BidDepth() != 3 || ob.AskDepth() != 3 {
+		t.Errorf("Expected 3 levels a side, got %d bids and %d asks", ob.BidDepth(), ob.AskDepth())
+	}
+	if asks := ob.Asks(); !asks[2].Price.Equal(d("0.55")) || !asks[2].Size.Equal(d("500")) {
+		t.Errorf("Expected third ask 500 @ 0.55, got %s @ %s", asks[2].Size, asks[2].Price)
+	}
+
+	// No quote: default 1% spread around the price
+	bt.currentTime = now.Add(time.Minute)
+	ob = bt.GetOrderBook("token1")
+	bid, _ := ob.BestBid()
+	ask, _ := ob.BestAsk()
+	if !bid.Equal(d("0.595")) || !ask.Equal(d("0.605")) {
+		t.Errorf("Expected synthetic quote 0.595/0.605, got %s/%s", bid, ask)
+	}
+}
+
+// annotatingStrategy notes every tick and once at the end.
+type annotatingStrategy struct{}
+
+func (annotatingStrategy) OnStart(ctx context.Context, bt *Backtest) {}
+
+func (annotatingStrategy) OnEnd(ctx context.Context, bt *Backtest) {
+	bt.Annotate("", "done")
+}
+
+func (annotatingStrategy) OnTick(ctx context.Context, bt *Backtest, point PricePoint) {
+	bt.Annotate(point.TokenID, "price "+point.Price.String())
+}
+
+func TestAnnotationsInTimeOrder(t *testing.T) {
+	bt := New(&Config{InitialBalance: decimal.NewFromInt(1000)})
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, token := range []string{"a", "b"} {
+		points := make([]PricePoint, 5)
+		for j := range points {
+			points[j] = PricePoint{
+				// Interleave the two tokens' ticks
+				Timestamp: start.Add(time.Duration(2*j+i) * time.Minute),
+				TokenID:   token,
+				Price:     decimal.NewFromFloat(0.5),
+			}
+		}
+		bt.LoadData(&HistoricalData{TokenID: token, Points: points})
+	}
+
+	result, err := bt.Run(context.Background(), annotatingStrategy{})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(result.Annotations) != 11 {
+		t.Fatalf("Expected 11 annotations, got %d", len(result.Annotations))
+	}
+	for i := 1; i < len(result.Annotations); i++ {
+		if result.Annotations[i].Timestamp.Before(result.Annotations[i-1].Timestamp) {
+			t.Errorf("Annotation %d at %s is before %s", i, result.Annotations[i].Timestamp, result.Annotations[i-1].Timestamp)
+		}
+	}
+	if first := result.Annotations[0]; first.TokenID != "a" || !first.Timestamp.Equal(start) || first.Message != "price 0.5" {
+		t.Errorf("Unexpected first annotation: %+v", first)
+	}
+	if last := result.Annotations[10]; last.Message != "done" {
+		t.Errorf("Expected OnEnd note last, got %+v", last)
+	}
+}
+
+// costingLLMClient answers every forecast with a fixed probability and
+// charges each call to its cost tracker.
+type costingLLMClient struct {
+	cost  tools.CostTracker
+	calls int
+}
+
+func (c *costingLLMClient) Complete(ctx context.Context, prompt string, systemPrompt string) (string, error) {
+	c.calls++
+	c.cost.AddUsage(1000, 200, "gpt-4o-mini")
+	return `{"probability": 0.7, "confidence": 0.8, "reasoning": "test"}`, nil
+}
+
+func (c *costingLLMClient) Provider() agents.LLMProvider { return agents.ProviderClaude }
+
+func (c *costingLLMClient) Cost() *tools.CostTracker { return &c.cost }
+
+func TestForecasterStrategyLLMCost(t *testing.T) {
+	client := &costingLLMClient{}
+	strategy := NewForecasterStrategy(&ForecasterStrategyConfig{
+		Forecaster: agents.NewForecaster(&agents.ForecasterConfig{
+			Clients: map[agents.LLMProvider]agents.LLMClient{agents.ProviderClaude: client},
+		}),
+		PositionSize:    10,
+		MinEdgeBps:      500,
+		MinConfidence:   0.5,
+		ForecastEveryN:  2,
+		MaxPositionSize: 100,
+	})
+
+	bt := New(&Config{InitialBalance: decimal.NewFromInt(1000)})
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]PricePoint, 10)
+	for i := range points {
+		points[i] = PricePoint{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			TokenID:   "token",
+			Market:    "market",
+			Price:     decimal.NewFromFloat(0.5),
+		}
+	}
+	bt.LoadData(&HistoricalData{TokenID: "token", Market: "market", Points: points})
+
+	result, err := bt.Run(context.Background(), strategy)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if client.calls == 0 {
+		t.Fatal("Expected the forecaster to call the LLM")
+	}
+	want := decimal.NewFromFloat(client.cost.TotalCost())
+	if !want.IsPositive() {
+		t.Fatalf("Expected positive spend, got %s", want)
+	}
+	if !result.LLMCost.Sub(want).Abs().LessThan(decimal.NewFromFloat(1e-12)) {
+		t.Errorf("Expected LLM cost %s, got %s", want, result.LLMCost)
+	}
+}
+
+// restingStrategy buys with a resting limit below the first mid, then sells
+// with a resting limit above the mid once filled, so both fills are maker.
+type restingStrategy struct{ tick int }
+
+func (s *restingStrategy) OnStart(ctx context.Context, bt *Backtest) {}
+func (s *restingStrategy) OnEnd(ctx context.Context, bt *Backtest)   {}
+
+func (s *restingStrategy) OnTick(ctx context.Context, bt *Backtest, point PricePoint) {
+	s.tick++
+	switch s.tick {
+	case 1:
+		bt.BuyLimit(point.TokenID, point.Market, decimal.NewFromInt(100), decimal.RequireFromString("0.45"))
+	case 3:
+		bt.SellLimit(point.TokenID, point.Market, decimal.NewFromInt(100), decimal.RequireFromString("0.55"))
+	}
+}
+
+func TestMakerRebate(t *testing.T) {
+	run := func(rebateBps int64) *Result {
+		bt := New(&Config{
+			InitialBalance: decimal.NewFromInt(1000),
+			MakerRebateBps: decimal.NewFromInt(rebateBps),
+		})
+		start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		prices := []string{"0.50", "0.44", "0.48", "0.56"}
+		points := make([]PricePoint, len(prices))
+		for i, p := range prices {
+			points[i] = PricePoint{
+				Timestamp: start.Add(time.Duration(i) * time.Minute),
+				TokenID:   "token1",
+				Market:    "market1",
+				Price:     decimal.RequireFromString(p),
+			}
+		}
+		bt.LoadData(&HistoricalData{TokenID: "token1", Market: "market1", Points: points})
+
+		result, err := bt.Run(context.Background(), &restingStrategy{})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if result.TotalTrades != 2 {
+			t.Fatalf("Expected 2 maker fills, got %d", result.TotalTrades)
+		}
+		return result
+	}
+
+	base, rebated := run(0), run(20)
+
+	// 20bps on 100*0.45 + 100*0.55 of maker volume
+	rebate := decimal.RequireFromString("0.2")
+	if !rebated.TotalFees.Equal(rebate.Neg()) {
+		t.Errorf("Expected fees -%s, got %s", rebate, rebated.TotalFees)
+	}
+	if !rebated.TotalPnL.Equal(base.TotalPnL.Add(rebate)) {
+		t.Errorf("Expected PnL %s + %s, got %s", base.TotalPnL, rebate, rebated.TotalPnL)
+	}
+	if !rebated.FinalBalance.Equal(base.FinalBalance.Add(rebate)) {
+		t.Errorf("Expected balance %s + %s, got %s", base.FinalBalance, rebate, rebated.FinalBalance)
+	}
+}
+
+// limitBuyOnce rests one buy limit a cent under the first tick's price.
+type limitBuyOnce struct{ placed bool }
+
+func (s *limitBuyOnce) OnStart(ctx context.Context, bt *Backtest) {}
+func (s *limitBuyOnce) OnEnd(ctx context.Context, bt *Backtest)   {}
+
+func (s *limitBuyOnce) OnTick(ctx context.Context, bt *Backtest, point PricePoint) {
+	if !s.placed {
+		s.placed = true
+		bt.BuyLimit(point.TokenID, point.Market, decimal.NewFromInt(100), point.Price.Sub(bookTick))
+	}
+}
+
+func TestQueueDepthFillsFromPointVolume(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	run := func(queueDepth int64) *Result {
+		bt := New(&Config{
+			InitialBalance: decimal.NewFromInt(1000),
+			QueueDepth:     decimal.NewFromInt(queueDepth),
+		})
+		// The order rests at 0.49; $98 there is 200 shares per point
+		points := make([]PricePoint, 4)
+		for i := range points {
+			points[i] = PricePoint{
+				Timestamp: start.Add(time.Duration(i) * time.Minute),
+				TokenID:   "token1",
+				Market:    "market1",
+				Price:     decimal.RequireFromString("0.49"),
+				Volume:    decimal.NewFromInt(98),
+			}
+		}
+		points[0].Price = decimal.RequireFromString("0.50")
+		bt.LoadData(&HistoricalData{TokenID: "token1", Market: "market1", Points: points})
+
+		result, err := bt.Run(context.Background(), &limitBuyOnce{})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if len(result.Trades) != 1 {
+			t.Fatalf("Expected 1 fill, got %d", len(result.Trades))
+		}
+		return result
+	}
+
+	// Without a queue the next touch fills
+	if got := run(0).Trades[0].Timestamp; !got.Equal(start.Add(time.Minute)) {
+		t.Errorf("Expected fill at the next tick, got %v", got)
+	}
+
+	// 300 shares ahead: 200 trade at the next point, the rest and the order
+	// at the one after
+	queued := run(300).Trades[0]
+	if !queued.Timestamp.Equal(start.Add(2 * time.Minute)) {
+		t.Errorf("Expected fill once volume cleared the queue, got %v", queued.Timestamp)
+	}
+	if !queued.Size.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected the full 100 shares filled, got %s", queued.Size)
+	}
+}
+
+func TestQueueFromBookFillsFromPointVolume(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	run := func(queueFromBook bool) *Result {
+		bt := New(&Config{
+			InitialBalance: decimal.NewFromInt(1000),
+			Mode:           paper.ModeRealistic,
+			QueueFromBook:  queueFromBook,
+		})
+		// The order joins 300 shares bid at 0.49; $98 there is 200 shares
+		// per point
+		points := make([]PricePoint, 4)
+		for i := range points {
+			points[i] = PricePoint{
+				Timestamp: start.Add(time.Duration(i) * time.Minute),
+				TokenID:   "token1",
+				Market:    "market1",
+				Price:     decimal.RequireFromString("0.49"),
+				Volume:    decimal.NewFromInt(98),
+			}
+		}
+		points[0].Price = decimal.RequireFromString("0.50")
+		points[0].BidPrice = decimal.RequireFromString("0.49")
+		points[0].BidSize = decimal.NewFromInt(300)
+		points[0].AskPrice = decimal.RequireFromString("0.51")
+		points[0].AskSize = decimal.NewFromInt(300)
+		bt.LoadData(&HistoricalData{TokenID: "token1", Market: "market1", Points: points})
+
+		result, err := bt.Run(context.Background(), &limitBuyOnce{})
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if len(result.Trades) != 1 {
+			t.Fatalf("Expected 1 fill, got %d", len(result.Trades))
+		}
+		return result
+	}
+
+	if got := run(false).Trades[0].Timestamp; !got.Equal(start.Add(time.Minute)) {
+		t.Errorf("Expected fill at the next tick, got %v", got)
+	}
+	if got := run(true).Trades[0].Timestamp; !got.Equal(start.Add(2 * time.Minute)) {
+		t.Errorf("Expected fill once volume cleared the book's queue, got %v", got)
+	}
+}
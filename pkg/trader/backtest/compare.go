@@ -0,0 +1,70 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// NamedStrategy pairs a strategy with a display name for comparisons.
+type NamedStrategy struct {
+	Name     string
+	Strategy Strategy
+}
+
+// RankMetric selects the field used to order comparison results.
+type RankMetric string
+
+const (
+	RankBySharpe   RankMetric = "sharpe"
+	RankByCalmar   RankMetric = "calmar"
+	RankByReturn   RankMetric = "return"
+	RankByDrawdown RankMetric = "drawdown" // Smallest drawdown first
+)
+
+// CompareStrategies runs each strategy on its own backtest over identical
+// data, each built from a copy of config (nil means DefaultConfig). Results
+// are returned in input order with Result.Strategy set; use RankResults to
+// sort them.
+func CompareStrategies(ctx context.Context, data *HistoricalData, config *Config, strategies []NamedStrategy) ([]Result, error) {
+	results := make([]Result, 0, len(strategies))
+	for _, s := range strategies {
+		bt := New(runConfig(config))
+		bt.LoadData(data)
+
+		result, err := bt.Run(ctx, s.Strategy)
+		if err != nil {
+			return nil, fmt.Errorf("strategy %s: %w", s.Name, err)
+		}
+		result.Strategy = s.Name
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// runConfig returns a copy of config for one of several runs, so no run
+// shares state with another, or DefaultConfig if config is nil.
+func runConfig(config *Config) *Config {
+	if config == nil {
+		return DefaultConfig()
+	}
+	c := *config
+	return &c
+}
+
+// RankResults sorts results best-first by the given metric.
+func RankResults(results []Result, metric RankMetric) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		switch metric {
+		case RankByCalmar:
+			return a.CalmarRatio.GreaterThan(b.CalmarRatio)
+		case RankByReturn:
+			return a.TotalReturn.GreaterThan(b.TotalReturn)
+		case RankByDrawdown:
+			return a.MaxDrawdown.LessThan(b.MaxDrawdown)
+		default:
+			return a.SharpeRatio.GreaterThan(b.SharpeRatio)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package backtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCompareStrategies(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]PricePoint, 200)
+	for i := range points {
+		// Upward trend with a sawtooth so both strategies trade
+		price := 0.4 + 0.2*float64(i)/200 + (float64(i%10)-5)/200
+		points[i] = PricePoint{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			TokenID:   "token1",
+			Market:    "market1",
+			Price:     decimal.NewFromFloat(price),
+			Volume:    decimal.NewFromInt(1000),
+		}
+	}
+	data := &HistoricalData{
+		TokenID:   "token1",
+		Market:    "market1",
+		StartTime: points[0].Timestamp,
+		EndTime:   points[len(points)-1].Timestamp,
+		Points:    points,
+	}
+
+	results, err := CompareStrategies(context.Background(), data, nil, []NamedStrategy{
+		{Name: "buyhold", Strategy: NewBuyAndHoldStrategy(100)},
+		{Name: "momentum", Strategy: NewMomentumStrategy(5, 100, 1)},
+	})
+	if err != nil {
+		t.Fatalf("CompareStrategies failed: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Strategy != "buyhold" || results[1].Strategy != "momentum" {
+		t.Errorf("Results should be named in input order, got %q, %q", results[0].Strategy, results[1].Strategy)
+	}
+	for _, r := range results {
+		if !r.StartTime.Equal(data.StartTime) || !r.EndTime.Equal(data.EndTime) {
+			t.Errorf("%s: expected identical data window, got %v - %v", r.Strategy, r.StartTime, r.EndTime)
+		}
+		if r.TotalTrades == 0 {
+			t.Errorf("%s: expected trades", r.Strategy)
+		}
+	}
+}
+
+func TestCompareStrategiesUsesConfig(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]PricePoint, 50)
+	for i := range points {
+		points[i] = PricePoint{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			TokenID:   "token1",
+			Market:    "market1",
+			Price:     decimal.NewFromFloat(0.5),
+			Volume:    decimal.NewFromInt(1000),
+		}
+	}
+	data := &HistoricalData{TokenID: "token1", Market: "market1", StartTime: points[0].Timestamp, EndTime: points[49].Timestamp, Points: points}
+
+	config := DefaultConfig()
+	config.InitialBalance = decimal.NewFromInt(5000)
+	config.TakerFeeBps = decimal.NewFromInt(100)
+	results, err := CompareStrategies(context.Background(), data, config, []NamedStrategy{
+		{Name: "a", Strategy: NewBuyAndHoldStrategy(100)},
+		{Name: "b", Strategy: NewBuyAndHoldStrategy(100)},
+	})
+	if err != nil {
+		t.Fatalf("CompareStrategies failed: %v", err)
+	}
+
+	// $100 bought with a 1% taker fee, from a 5000 balance, in every run
+	for _, r := range results {
+		if !r.InitialBalance.Equal(decimal.NewFromInt(5000)) || !r.TotalFees.Equal(decimal.NewFromInt(1)) {
+			t.Errorf("%s: expected the caller's config, got balance %s fees %s", r.Strategy, r.InitialBalance, r.TotalFees)
+		}
+	}
+}
+
+func TestRankResults(t *testing.T) {
+	results := []Result{
+		{Strategy: "a", SharpeRatio: decimal.NewFromInt(1), TotalReturn: decimal.NewFromInt(30), MaxDrawdown: decimal.NewFromFloat(0.2)},
+		{Strategy: "b", SharpeRatio: decimal.NewFromInt(3), TotalReturn: decimal.NewFromInt(10), MaxDrawdown: decimal.NewFromFloat(0.05)},
+		{Strategy: "c", SharpeRatio: decimal.NewFromInt(2), TotalReturn: decimal.NewFromInt(20), MaxDrawdown: decimal.NewFromFloat(0.1)},
+	}
+
+	order := func() string {
+		s := ""
+		for _, r := range results {
+			s += r.Strategy
+		}
+		return s
+	}
+
+	RankResults(results, RankBySharpe)
+	if got := order(); got != "bca" {
+		t.Errorf("By Sharpe: expected bca, got %s", got)
+	}
+	RankResults(results, RankByReturn)
+	if got := order(); got != "acb" {
+		t.Errorf("By return: expected acb, got %s", got)
+	}
+	RankResults(results, RankByDrawdown)
+	if got := order(); got != "bca" {
+		t.Errorf("By drawdown: expected bca, got %s", got)
+	}
+}
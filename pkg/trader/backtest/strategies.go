@@ -2,10 +2,12 @@ package backtest
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/phenomenon0/polymarket-agents/pkg/trader/agents"
+	"github.com/phenomenon0/polymarket-agents/tools"
 
 	"github.com/shopspring/decimal"
 )
@@ -210,16 +212,21 @@ type ForecasterStrategyConfig struct {
 	Verbose         bool
 }
 
+// defaultForecasterStrategyConfig is used when no config is given.
+func defaultForecasterStrategyConfig() *ForecasterStrategyConfig {
+	return &ForecasterStrategyConfig{
+		PositionSize:    100,
+		MinEdgeBps:      500, // 5% edge
+		MinConfidence:   0.6,
+		ForecastEveryN:  10, // Every 10 ticks
+		MaxPositionSize: 1000,
+	}
+}
+
 // NewForecasterStrategy creates a new LLM forecaster strategy.
 func NewForecasterStrategy(config *ForecasterStrategyConfig) *ForecasterStrategy {
 	if config == nil {
-		config = &ForecasterStrategyConfig{
-			PositionSize:    100,
-			MinEdgeBps:      500, // 5% edge
-			MinConfidence:   0.6,
-			ForecastEveryN:  10, // Every 10 ticks
-			MaxPositionSize: 1000,
-		}
+		config = defaultForecasterStrategyConfig()
 	}
 
 	return &ForecasterStrategy{
@@ -236,6 +243,24 @@ func NewForecasterStrategy(config *ForecasterStrategyConfig) *ForecasterStrategy
 	}
 }
 
+// NewForecasterStrategyWithPreset creates a forecaster strategy backed by
+// real LLMs, built from router with agents.CreateForecasterWithPreset. Any
+// Forecaster in config is replaced. LLM spend is reported in Result.LLMCost.
+func NewForecasterStrategyWithPreset(router *tools.ModelRouter, preset agents.ForecasterPreset, config *ForecasterStrategyConfig) (*ForecasterStrategy, error) {
+	forecaster, err := agents.CreateForecasterWithPreset(router, preset)
+	if err != nil {
+		return nil, fmt.Errorf("create %s forecaster: %w", preset, err)
+	}
+
+	cfg := defaultForecasterStrategyConfig()
+	if config != nil {
+		c := *config
+		cfg = &c
+	}
+	cfg.Forecaster = forecaster
+	return NewForecasterStrategy(cfg), nil
+}
+
 func (s *ForecasterStrategy) OnStart(ctx context.Context, bt *Backtest) {
 	if s.verbose {
 		log.Println("ForecasterStrategy: Starting backtest")
@@ -279,7 +304,9 @@ func (s *ForecasterStrategy) OnTick(ctx context.Context, bt *Backtest, point Pri
 	if s.Forecaster != nil {
 		// Use real LLM forecaster
 		marketCtx := s.buildMarketContext(point)
+		spent := s.Forecaster.LLMCost()
 		forecast, err = s.Forecaster.ForecastWithFallback(ctx, marketCtx)
+		bt.AddLLMCost(decimal.NewFromFloat(s.Forecaster.LLMCost() - spent))
 		if err != nil {
 			if s.verbose {
 				log.Printf("ForecasterStrategy: Forecast error: %v", err)
@@ -368,14 +395,16 @@ func (s *ForecasterStrategy) evaluateSignal(ctx context.Context, bt *Backtest, p
 	forecastProb := forecast.Probability
 	confidence := forecast.Confidence
 
+	// Calculate edge: (forecast - price) / price * 10000 (in basis points)
+	edge := forecastProb.Sub(currentPrice).Div(currentPrice).Mul(decimal.NewFromInt(10000))
+	bt.Annotate(point.TokenID, fmt.Sprintf("forecast %s vs price %s: edge %s bps, confidence %s",
+		forecastProb.StringFixed(3), currentPrice, edge.Round(0), confidence.StringFixed(2)))
+
 	// Check minimum confidence
 	if confidence.LessThan(s.MinConfidence) {
 		return
 	}
 
-	// Calculate edge: (forecast - price) / price * 10000 (in basis points)
-	edge := forecastProb.Sub(currentPrice).Div(currentPrice).Mul(decimal.NewFromInt(10000))
-
 	pos, hasPos := bt.Position(point.TokenID)
 
 	// BUY signal: forecast > price by MinEdgeBps
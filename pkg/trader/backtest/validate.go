@@ -0,0 +1,161 @@
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// IssueKind classifies a problem found by HistoricalData.Validate.
+type IssueKind string
+
+const (
+	IssueDuplicateTimestamp IssueKind = "duplicate_timestamp"
+	IssueNonMonotonic       IssueKind = "non_monotonic" // Timestamp earlier than the previous point
+	IssuePriceOutOfRange    IssueKind = "price_out_of_range"
+	IssueGap                IssueKind = "gap"         // Interval far longer than the typical one
+	IssueZeroVolume         IssueKind = "zero_volume" // Long run of points without volume
+	IssuePriceJump          IssueKind = "price_jump"  // Implausibly large move between points
+)
+
+// Validation thresholds.
+const (
+	// gapFactor flags an interval this many times the median interval.
+	gapFactor = 10
+	// zeroVolumeRun is the shortest run of zero-volume points flagged.
+	zeroVolumeRun = 20
+)
+
+// maxPriceJump is the largest plausible price move between consecutive
+// points; prices are probabilities, so 0.3 is already a big surprise.
+var maxPriceJump = decimal.NewFromFloat(0.3)
+
+// DataIssue is one problem in a dataset. Index is the offending point's
+// position in HistoricalData.Points (the first point of a run or gap end).
+type DataIssue struct {
+	Kind      IssueKind `json:"kind"`
+	TokenID   string    `json:"token_id"`
+	Index     int       `json:"index"`
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message"`
+}
+
+func (i DataIssue) String() string {
+	return fmt.Sprintf("%s[%d] %s: %s", i.TokenID, i.Index, i.Kind, i.Message)
+}
+
+// ErrInvalidData is returned by Run in ValidateStrict mode when a dataset
+// has issues.
+var ErrInvalidData = errors.New("invalid historical data")
+
+// ValidationMode decides what Run does with data issues.
+type ValidationMode string
+
+const (
+	// ValidateOff skips validation.
+	ValidateOff ValidationMode = ""
+	// ValidateWarn runs anyway and reports issues in Result.DataIssues.
+	ValidateWarn ValidationMode = "warn"
+	// ValidateStrict refuses to run, returning ErrInvalidData.
+	ValidateStrict ValidationMode = "strict"
+)
+
+// ParseValidationMode validates a mode name. "off" and empty select
+// ValidateOff.
+func ParseValidationMode(s string) (ValidationMode, error) {
+	switch mode := ValidationMode(s); mode {
+	case "off":
+		return ValidateOff, nil
+	case ValidateOff, ValidateWarn, ValidateStrict:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown validation mode %q", s)
+	}
+}
+
+// Validate checks the points, in stored order, for duplicate or
+// out-of-order timestamps, prices outside [0, 1], gaps much longer than the
+// median interval, long zero-volume runs, and large price jumps. Zero-volume
+// runs are only reported when some point has volume, since price-only data
+// has none anywhere.
+func (d *HistoricalData) Validate() []DataIssue {
+	var issues []DataIssue
+	add := func(kind IssueKind, i int, format string, args ...any) {
+		issues = append(issues, DataIssue{
+			Kind:      kind,
+			TokenID:   d.TokenID,
+			Index:     i,
+			Timestamp: d.Points[i].Timestamp,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	one := decimal.NewFromInt(1)
+	hasVolume := false
+	for _, p := range d.Points {
+		if p.Volume.IsPositive() {
+			hasVolume = true
+			break
+		}
+	}
+
+	var intervals []time.Duration
+	zeroRunStart := -1
+	for i, p := range d.Points {
+		if p.Price.IsNegative() || p.Price.GreaterThan(one) {
+			add(IssuePriceOutOfRange, i, "price %s outside [0, 1]", p.Price)
+		}
+
+		if hasVolume {
+			if p.Volume.IsZero() {
+				if zeroRunStart < 0 {
+					zeroRunStart = i
+				}
+			} else {
+				if zeroRunStart >= 0 && i-zeroRunStart >= zeroVolumeRun {
+					add(IssueZeroVolume, zeroRunStart, "%d points without volume", i-zeroRunStart)
+				}
+				zeroRunStart = -1
+			}
+		}
+
+		if i == 0 {
+			continue
+		}
+		prev := d.Points[i-1]
+		switch {
+		case p.Timestamp.Equal(prev.Timestamp):
+			add(IssueDuplicateTimestamp, i, "same timestamp as point %d", i-1)
+		case p.Timestamp.Before(prev.Timestamp):
+			add(IssueNonMonotonic, i, "timestamp %s before previous %s", p.Timestamp.Format(time.RFC3339), prev.Timestamp.Format(time.RFC3339))
+		default:
+			intervals = append(intervals, p.Timestamp.Sub(prev.Timestamp))
+		}
+		if jump := p.Price.Sub(prev.Price).Abs(); jump.GreaterThan(maxPriceJump) {
+			add(IssuePriceJump, i, "price moved %s from %s to %s", jump, prev.Price, p.Price)
+		}
+	}
+	if zeroRunStart >= 0 && len(d.Points)-zeroRunStart >= zeroVolumeRun {
+		add(IssueZeroVolume, zeroRunStart, "%d points without volume", len(d.Points)-zeroRunStart)
+	}
+
+	// Gaps are judged against the median interval, so one missing stretch
+	// stands out whatever the sampling rate
+	if len(intervals) >= 2 {
+		sorted := append([]time.Duration(nil), intervals...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		median := sorted[len(sorted)/2]
+		for i := 1; i < len(d.Points); i++ {
+			gap := d.Points[i].Timestamp.Sub(d.Points[i-1].Timestamp)
+			if median > 0 && gap > gapFactor*median {
+				add(IssueGap, i, "%s since previous point (median %s)", gap, median)
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Index < issues[j].Index })
+	return issues
+}
@@ -0,0 +1,76 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestValidateFlagsBadPriceAndDuplicateTimestamp(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	prices := []float64{0.40, 0.42, 1.5, 0.43, 0.44, 0.45}
+	data := &HistoricalData{TokenID: "tok", StartTime: start, EndTime: start.Add(5 * time.Minute)}
+	for i, price := range prices {
+		data.Points = append(data.Points, PricePoint{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			TokenID:   "tok",
+			Price:     decimal.NewFromFloat(price),
+			Volume:    decimal.NewFromInt(100),
+		})
+	}
+	// Point 4 repeats point 3's timestamp
+	data.Points[4].Timestamp = data.Points[3].Timestamp
+
+	found := make(map[IssueKind]int)
+	for _, issue := range data.Validate() {
+		found[issue.Kind] = issue.Index
+	}
+	if i, ok := found[IssuePriceOutOfRange]; !ok || i != 2 {
+		t.Errorf("Expected price 1.5 at index 2 flagged, got %v", found)
+	}
+	if i, ok := found[IssueDuplicateTimestamp]; !ok || i != 4 {
+		t.Errorf("Expected duplicate timestamp at index 4 flagged, got %v", found)
+	}
+
+	bt := New(&Config{InitialBalance: decimal.NewFromInt(1000), Validation: ValidateStrict})
+	bt.LoadData(data)
+	if _, err := bt.Run(context.Background(), NewBuyAndHoldStrategy(100)); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("Strict validation should refuse to run, got %v", err)
+	}
+
+	bt = New(&Config{InitialBalance: decimal.NewFromInt(1000), Validation: ValidateWarn})
+	bt.LoadData(data)
+	result, err := bt.Run(context.Background(), NewBuyAndHoldStrategy(100))
+	if err != nil {
+		t.Fatalf("Warn validation should still run: %v", err)
+	}
+	if len(result.DataIssues) < 2 {
+		t.Errorf("Expected issues in the result, got %v", result.DataIssues)
+	}
+}
+
+func TestValidateCleanData(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := &HistoricalData{TokenID: "tok"}
+	for i := 0; i < 50; i++ {
+		data.Points = append(data.Points, PricePoint{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Price:     decimal.NewFromFloat(0.5 + float64(i%5)/100),
+		})
+	}
+	if issues := data.Validate(); len(issues) != 0 {
+		t.Errorf("Expected no issues for clean price-only data, got %v", issues)
+	}
+
+	// A three-hour hole in minute data is a gap
+	for i := 30; i < len(data.Points); i++ {
+		data.Points[i].Timestamp = data.Points[i].Timestamp.Add(3 * time.Hour)
+	}
+	issues := data.Validate()
+	if len(issues) != 1 || issues[0].Kind != IssueGap || issues[0].Index != 30 {
+		t.Errorf("Expected one gap at index 30, got %v", issues)
+	}
+}
@@ -0,0 +1,148 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Params is one set of strategy parameters, keyed by name.
+type Params map[string]float64
+
+// WalkForwardWindow is one in-sample/out-of-sample split.
+type WalkForwardWindow struct {
+	InSampleStart    time.Time `json:"in_sample_start"`
+	InSampleEnd      time.Time `json:"in_sample_end"`
+	OutOfSampleStart time.Time `json:"out_of_sample_start"`
+	OutOfSampleEnd   time.Time `json:"out_of_sample_end"`
+
+	// Params won in-sample and were then run out-of-sample.
+	Params      Params `json:"params"`
+	InSample    Result `json:"in_sample"`
+	OutOfSample Result `json:"out_of_sample"`
+}
+
+// WalkForwardResult holds per-window results and the out-of-sample
+// aggregate, which is the honest performance estimate.
+type WalkForwardResult struct {
+	Windows []WalkForwardWindow `json:"windows"`
+
+	TotalPnL    decimal.Decimal `json:"total_pnl"`
+	TotalReturn decimal.Decimal `json:"total_return"` // Percentage, window returns compounded
+	TotalTrades int             `json:"total_trades"`
+	WinRate     decimal.Decimal `json:"win_rate"`
+	MeanSharpe  decimal.Decimal `json:"mean_sharpe"`
+	MaxDrawdown decimal.Decimal `json:"max_drawdown"` // Worst window
+}
+
+// WalkForward estimates out-of-sample performance of a parameterized
+// strategy. The data is split by time into windows+1 equal segments; window
+// i optimizes over grid on segment i (best Sharpe, ties to the earlier grid
+// entry) and runs the winning params on segment i+1. Each run gets a fresh
+// strategy from factory and a fresh backtest built from a copy of config
+// (nil means DefaultConfig). A
+// resolution outcome only applies to the final segment, so in-sample runs
+// never see it.
+func WalkForward(ctx context.Context, data *HistoricalData, config *Config, factory func(Params) Strategy, grid []Params, windows int) (*WalkForwardResult, error) {
+	if windows < 1 {
+		return nil, fmt.Errorf("walk-forward needs at least 1 window, got %d", windows)
+	}
+	if len(grid) == 0 {
+		return nil, fmt.Errorf("walk-forward needs a non-empty parameter grid")
+	}
+
+	segments, err := splitSegments(data, windows+1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WalkForwardResult{}
+	growth := decimal.NewFromInt(1)
+	hundred := decimal.NewFromInt(100)
+	winning := 0
+
+	for i := 0; i < windows; i++ {
+		inSample, outOfSample := segments[i], segments[i+1]
+
+		var best int
+		var bestResult *Result
+		for j, params := range grid {
+			r, err := runSegment(ctx, inSample, config, factory(params))
+			if err != nil {
+				return nil, fmt.Errorf("window %d in-sample params %v: %w", i, params, err)
+			}
+			if bestResult == nil || r.SharpeRatio.GreaterThan(bestResult.SharpeRatio) {
+				best, bestResult = j, r
+			}
+		}
+
+		oos, err := runSegment(ctx, outOfSample, config, factory(grid[best]))
+		if err != nil {
+			return nil, fmt.Errorf("window %d out-of-sample: %w", i, err)
+		}
+
+		result.Windows = append(result.Windows, WalkForwardWindow{
+			InSampleStart:    inSample.StartTime,
+			InSampleEnd:      inSample.EndTime,
+			OutOfSampleStart: outOfSample.StartTime,
+			OutOfSampleEnd:   outOfSample.EndTime,
+			Params:           grid[best],
+			InSample:         *bestResult,
+			OutOfSample:      *oos,
+		})
+
+		result.TotalPnL = result.TotalPnL.Add(oos.TotalPnL)
+		result.TotalTrades += oos.TotalTrades
+		winning += oos.WinningTrades
+		result.MeanSharpe = result.MeanSharpe.Add(oos.SharpeRatio)
+		if oos.MaxDrawdown.GreaterThan(result.MaxDrawdown) {
+			result.MaxDrawdown = oos.MaxDrawdown
+		}
+		growth = growth.Mul(decimal.NewFromInt(1).Add(oos.TotalReturn.Div(hundred)))
+	}
+
+	result.TotalReturn = growth.Sub(decimal.NewFromInt(1)).Mul(hundred)
+	result.MeanSharpe = result.MeanSharpe.Div(decimal.NewFromInt(int64(windows)))
+	if result.TotalTrades > 0 {
+		result.WinRate = decimal.NewFromInt(int64(winning)).Div(decimal.NewFromInt(int64(result.TotalTrades)))
+	}
+	return result, nil
+}
+
+// runSegment backtests one strategy on one segment.
+func runSegment(ctx context.Context, data *HistoricalData, config *Config, strategy Strategy) (*Result, error) {
+	bt := New(runConfig(config))
+	bt.LoadData(data)
+	return bt.Run(ctx, strategy)
+}
+
+// splitSegments cuts data into n consecutive segments with equal numbers of
+// points. Only the last segment keeps the resolution outcome.
+func splitSegments(data *HistoricalData, n int) ([]*HistoricalData, error) {
+	points := append([]PricePoint(nil), data.Points...)
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Timestamp.Before(points[j].Timestamp)
+	})
+	if len(points) < n {
+		return nil, fmt.Errorf("walk-forward needs at least %d points, got %d", n, len(points))
+	}
+
+	segments := make([]*HistoricalData, n)
+	for i := range segments {
+		part := points[i*len(points)/n : (i+1)*len(points)/n]
+		segments[i] = &HistoricalData{
+			TokenID:   data.TokenID,
+			Market:    data.Market,
+			StartTime: part[0].Timestamp,
+			EndTime:   part[len(part)-1].Timestamp,
+			Points:    part,
+		}
+	}
+	last := segments[n-1]
+	last.Resolution = data.Resolution
+	last.Outcome = data.Outcome
+	return segments, nil
+}
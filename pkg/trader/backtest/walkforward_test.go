@@ -0,0 +1,81 @@
+package backtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestWalkForward(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]PricePoint, 300)
+	for i := range points {
+		price := 0.4 + 0.2*float64(i)/300 + (float64(i%10)-5)/200
+		points[i] = PricePoint{
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+			TokenID:   "token1",
+			Market:    "market1",
+			Price:     decimal.NewFromFloat(price),
+			Volume:    decimal.NewFromInt(1000),
+		}
+	}
+	data := &HistoricalData{
+		TokenID:   "token1",
+		Market:    "market1",
+		StartTime: points[0].Timestamp,
+		EndTime:   points[len(points)-1].Timestamp,
+		Points:    points,
+	}
+
+	grid := []Params{{"lookback": 3}, {"lookback": 10}}
+	factory := func(p Params) Strategy {
+		return NewMomentumStrategy(int(p["lookback"]), 100, 0.01)
+	}
+
+	result, err := WalkForward(context.Background(), data, nil, factory, grid, 3)
+	if err != nil {
+		t.Fatalf("WalkForward failed: %v", err)
+	}
+
+	if len(result.Windows) != 3 {
+		t.Fatalf("Expected 3 windows, got %d", len(result.Windows))
+	}
+	trades := 0
+	for i, w := range result.Windows {
+		if !w.InSampleEnd.Before(w.OutOfSampleStart) {
+			t.Errorf("Window %d: out-of-sample %v overlaps in-sample ending %v", i, w.OutOfSampleStart, w.InSampleEnd)
+		}
+		if !w.OutOfSample.StartTime.Equal(w.OutOfSampleStart) || !w.OutOfSample.EndTime.Equal(w.OutOfSampleEnd) {
+			t.Errorf("Window %d: out-of-sample result covers %v - %v", i, w.OutOfSample.StartTime, w.OutOfSample.EndTime)
+		}
+		if w.Params == nil {
+			t.Errorf("Window %d: no params chosen", i)
+		}
+		if i > 0 && !w.InSampleStart.Equal(result.Windows[i-1].OutOfSampleStart) {
+			t.Errorf("Window %d: expected windows to roll forward by one segment", i)
+		}
+		trades += w.OutOfSample.TotalTrades
+	}
+	if result.TotalTrades != trades {
+		t.Errorf("Aggregate trades %d != sum of windows %d", result.TotalTrades, trades)
+	}
+
+	config := DefaultConfig()
+	config.InitialBalance = decimal.NewFromInt(5000)
+	custom, err := WalkForward(context.Background(), data, config, factory, grid, 1)
+	if err != nil {
+		t.Fatalf("WalkForward with config failed: %v", err)
+	}
+	if got := custom.Windows[0].OutOfSample.InitialBalance; !got.Equal(config.InitialBalance) {
+		t.Errorf("Expected runs from the given config's balance %s, got %s", config.InitialBalance, got)
+	}
+
+	if _, err := WalkForward(context.Background(), data, nil, factory, grid, 0); err == nil {
+		t.Error("Expected error for zero windows")
+	}
+	if _, err := WalkForward(context.Background(), data, nil, factory, nil, 3); err == nil {
+		t.Error("Expected error for empty grid")
+	}
+}
@@ -2,12 +2,18 @@
 package metrics
 
 import (
+	"math"
 	"sync"
 
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/book"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/shopspring/decimal"
 )
 
+// BookDepthLevels is how many levels per side BookDepth sums.
+const BookDepthLevels = 5
+
 // TradingMetrics collects and exposes trading-related Prometheus metrics.
 type TradingMetrics struct {
 	mu       sync.RWMutex
@@ -49,6 +55,11 @@ type TradingMetrics struct {
 	SignalEdge     *prometheus.HistogramVec
 	SignalStrength *prometheus.HistogramVec
 
+	// Order book metrics
+	BookSpreadBps   *prometheus.GaugeVec
+	BookDepth       *prometheus.GaugeVec
+	BookSlippageBps *prometheus.GaugeVec
+
 	// Policy metrics
 	PolicyViolations *prometheus.CounterVec
 	CooldownActive   *prometheus.GaugeVec
@@ -257,6 +268,29 @@ func NewTradingMetrics() *TradingMetrics {
 			[]string{},
 		),
 
+		// Order book metrics
+		BookSpreadBps: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "polymarket_book_spread_bps",
+				Help: "Best ask minus best bid in basis points of probability",
+			},
+			[]string{"token_id"},
+		),
+		BookDepth: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "polymarket_book_depth_shares",
+				Help: "Resting size in the top 5 levels of one side of the book",
+			},
+			[]string{"token_id", "side"},
+		),
+		BookSlippageBps: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "polymarket_book_slippage_bps",
+				Help: "Simulated slippage versus mid to fill the reference size (+Inf if the book can't)",
+			},
+			[]string{"token_id", "side"},
+		),
+
 		// Policy metrics
 		PolicyViolations: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
@@ -352,6 +386,9 @@ func (tm *TradingMetrics) registerAll() {
 		tm.SignalsTotal,
 		tm.SignalEdge,
 		tm.SignalStrength,
+		tm.BookSpreadBps,
+		tm.BookDepth,
+		tm.BookSlippageBps,
 		tm.PolicyViolations,
 		tm.CooldownActive,
 		tm.DailyOrdersUsed,
@@ -441,6 +478,48 @@ func (tm *TradingMetrics) RecordSignal(signal, side string, edgeBps, strength fl
 	tm.SignalStrength.WithLabelValues().Observe(strength)
 }
 
+// RecordBook updates the order book metrics for tokenID: spread, top-level
+// depth on each side, and the slippage of a market order of refSize shares.
+// One-sided books leave the spread unset.
+func (tm *TradingMetrics) RecordBook(tokenID string, ob *book.OrderBook, refSize decimal.Decimal) {
+	bid, _ := ob.BestBid()
+	ask, _ := ob.BestAsk()
+	if bid.IsPositive() && ask.IsPositive() {
+		tm.BookSpreadBps.WithLabelValues(tokenID).Set(DecimalToFloat64(ask.Sub(bid).Mul(decimal.NewFromInt(10000))))
+	}
+
+	tm.BookDepth.WithLabelValues(tokenID, "bid").Set(DecimalToFloat64(topDepth(ob.Bids())))
+	tm.BookDepth.WithLabelValues(tokenID, "ask").Set(DecimalToFloat64(topDepth(ob.Asks())))
+
+	mid := ob.Midpoint()
+	if !refSize.IsPositive() || !mid.IsPositive() {
+		return
+	}
+	tm.BookSlippageBps.WithLabelValues(tokenID, "buy").Set(slippageBps(ob, book.SideBuy, refSize, mid))
+	tm.BookSlippageBps.WithLabelValues(tokenID, "sell").Set(slippageBps(ob, book.SideSell, refSize, mid))
+}
+
+func topDepth(levels []book.PriceLevel) decimal.Decimal {
+	var depth decimal.Decimal
+	for i, level := range levels {
+		if i >= BookDepthLevels {
+			break
+		}
+		depth = depth.Add(level.Size)
+	}
+	return depth
+}
+
+// slippageBps is how far the average fill for size lands from mid, in bps of
+// mid, or +Inf if the book is too thin to fill it.
+func slippageBps(ob *book.OrderBook, side book.Side, size, mid decimal.Decimal) float64 {
+	fill := ob.SimulateMarketOrder(side, size)
+	if fill.Unfilled.IsPositive() {
+		return math.Inf(1)
+	}
+	return DecimalToFloat64(fill.AvgPrice.Sub(mid).Abs().Div(mid).Mul(decimal.NewFromInt(10000)))
+}
+
 // RecordPolicyViolation records a policy violation.
 func (tm *TradingMetrics) RecordPolicyViolation(violationType string) {
 	tm.PolicyViolations.WithLabelValues(violationType).Inc()
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/book"
+
+	"github.com/shopspring/decimal"
+)
+
+// gauge scrapes the registry for the gauge name with the given labels.
+func gauge(t *testing.T, tm *TradingMetrics, name string, labels map[string]string) float64 {
+	t.Helper()
+
+	families, err := tm.Registry().Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range family.GetMetric() {
+			for _, pair := range m.GetLabel() {
+				if labels[pair.GetName()] != pair.GetValue() {
+					continue metrics
+				}
+			}
+			return m.GetGauge().GetValue()
+		}
+	}
+	t.Fatalf("No %s%v in registry", name, labels)
+	return 0
+}
+
+func TestRecordBook(t *testing.T) {
+	d := decimal.RequireFromString
+	ob := book.NewOrderBook("tok", "market")
+	ob.SetBids([]book.PriceLevel{{Price: d("0.48"), Size: d("100")}, {Price: d("0.47"), Size: d("200")}})
+	ob.SetAsks([]book.PriceLevel{{Price: d("0.52"), Size: d("100")}, {Price: d("0.54"), Size: d("50")}})
+
+	tm := NewTradingMetrics()
+	tm.RecordBook("tok", ob, d("150"))
+
+	if got := gauge(t, tm, "polymarket_book_spread_bps", map[string]string{"token_id": "tok"}); got != 400 {
+		t.Errorf("Expected spread 400 bps, got %v", got)
+	}
+	if got := gauge(t, tm, "polymarket_book_depth_shares", map[string]string{"token_id": "tok", "side": "bid"}); got != 300 {
+		t.Errorf("Expected bid depth 300, got %v", got)
+	}
+
+	// Buying 150 averages (100*0.52 + 50*0.54)/150 = 0.52667, 0.02667/0.50 = 533 bps
+	buy := gauge(t, tm, "polymarket_book_slippage_bps", map[string]string{"token_id": "tok", "side": "buy"})
+	if math.Abs(buy-533.33) > 0.01 {
+		t.Errorf("Expected buy slippage ~533.33 bps, got %v", buy)
+	}
+
+	// Too thin to fill on the ask side
+	tm.RecordBook("tok", ob, d("1000"))
+	if buy := gauge(t, tm, "polymarket_book_slippage_bps", map[string]string{"token_id": "tok", "side": "buy"}); !math.IsInf(buy, 1) {
+		t.Errorf("Expected +Inf slippage for an unfillable size, got %v", buy)
+	}
+}
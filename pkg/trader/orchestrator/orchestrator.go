@@ -3,17 +3,26 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/book"
 	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/clob"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/data"
 	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/gamma"
+	"github.com/phenomenon0/polymarket-agents/pkg/tracing"
 	"github.com/phenomenon0/polymarket-agents/pkg/trader/agents"
 	"github.com/phenomenon0/polymarket-agents/pkg/trader/paper"
 	"github.com/phenomenon0/polymarket-agents/pkg/trader/policy"
 
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Stage represents a stage in the trading workflow.
@@ -48,18 +57,105 @@ type WorkflowConfig struct {
 	Categories   []string
 	MaxMarkets   int
 
+	// MarketSelection ranks the markets that pass the filters before they
+	// are cut to MaxMarkets. Empty keeps discovery order.
+	MarketSelection MarketSelection
+
+	// ExploreEpsilon is the chance, per discovery, that the last MaxMarkets
+	// slot goes to a random eligible market that missed the cut instead, so
+	// markets the selection never ranks still get forecast (and calibration
+	// data). Zero disables it.
+	ExploreEpsilon float64
+
+	// Liquidity gate applied to the live order book during data collection,
+	// before any forecast spend. MinBookDepth is the combined size at the best
+	// bid and best ask; MaxBookSpreadBps is the book spread, in bps of
+	// probability. Zero disables each. Either one, when enabled, also skips
+	// one-sided books.
+	MinBookDepth     decimal.Decimal
+	MaxBookSpreadBps decimal.Decimal
+
+	// WhaleTradeUSDC raises a whale alert for any on-chain trade in an active
+	// market at least this large (USDC notional). Requires an activity client;
+	// zero disables it.
+	WhaleTradeUSDC decimal.Decimal
+
+	// PriceMode is the market price signals are computed against, taken from
+	// the CLOB during data collection. Empty uses the Gamma outcome price.
+	PriceMode book.PriceMode
+
 	// Forecasting
 	MinEdgeBps    int
 	MinConfidence decimal.Decimal
 
+	// MaxForecastLatency abandons a market's forecast for this cycle once
+	// exceeded, so one slow model can't stall the loop. Zero (the default)
+	// disables it.
+	MaxForecastLatency time.Duration
+
+	// ConvictionHalfLife discounts signals from older forecasts when ranking
+	// them (see agents.TradingSignal.Conviction). Signals are also scaled by
+	// how much of MaxOrderSize the top of the book can fill. Zero ignores
+	// forecast age.
+	ConvictionHalfLife time.Duration
+
+	// Signal debounce: a signal for a token is only re-emitted when its side
+	// flips or its edge moves by more than this many bps since the last emission.
+	SignalChangeThresholdBps int
+
 	// Execution
 	MaxOrderSize  decimal.Decimal
 	UsePaperTrade bool
 
+	// ShadowMode runs the full pipeline on live data but sends every order
+	// to the paper engine, never the CLOB, so live decisions can be judged
+	// before risking money. It overrides UsePaperTrade.
+	ShadowMode bool
+
+	// MarketableLimits sends signal orders as limits at the expected price
+	// moved against us by the policy engine's MaxSlippage, instead of at
+	// market (or at the signal price, live), so fills beyond tolerance rest
+	// rather than execute. Orders whose fill, simulated against the live CLOB
+	// book, fails CheckSlippage are not sent. Requires a policy engine.
+	MarketableLimits bool
+
+	// MinHoldingPeriod suppresses signal orders that would flip a token's
+	// direction (YES to NO or back) until this long after the last order that
+	// set it, to cut whipsaw fee drag. A holding whose price has fallen at
+	// least HoldingStopLoss (fraction of entry, 0-1) may flip early; zero
+	// HoldingStopLoss never lifts the hold. Zero MinHoldingPeriod disables it.
+	MinHoldingPeriod time.Duration
+	HoldingStopLoss  decimal.Decimal
+
+	// SizingCurve scales MaxOrderSize by the signal's edge. Nil trades
+	// MaxOrderSize for every signal.
+	SizingCurve *SizingCurve
+
+	// MaxSignalNotional clamps every signal-derived order to at most this
+	// many dollars (size * price), ahead of and independent of the policy
+	// engine, as a backstop against a misparsed forecast. Zero disables it.
+	MaxSignalNotional decimal.Decimal
+
+	// Shutdown: Stop waits up to DrainTimeout for an in-flight cycle to
+	// finish, then, if CancelOnStop is set, cancels resting orders. Zero
+	// DrainTimeout doesn't wait.
+	DrainTimeout time.Duration
+	CancelOnStop bool
+
 	// Timing
 	DiscoveryInterval time.Duration
 	ForecastInterval  time.Duration
 	MonitorInterval   time.Duration
+
+	// Adaptive forecasting: each market is re-forecast somewhere between
+	// MinForecastInterval (volatility at or above HighVolatilityBps) and
+	// MaxForecastInterval (flat price). Markets with fewer than three price
+	// ticks use ForecastInterval. Zero Min/Max (the default) disables
+	// adaptation.
+	MinForecastInterval time.Duration
+	MaxForecastInterval time.Duration
+	HighVolatilityBps   int
+	VolatilityWindow    int // Price ticks kept per market
 }
 
 // DefaultWorkflowConfig returns default configuration.
@@ -72,9 +168,15 @@ func DefaultWorkflowConfig() *WorkflowConfig {
 		MinConfidence:     decimal.NewFromFloat(0.6),
 		MaxOrderSize:      decimal.NewFromInt(100),
 		UsePaperTrade:     true,
+		DrainTimeout:      30 * time.Second,
 		DiscoveryInterval: 5 * time.Minute,
 		ForecastInterval:  1 * time.Minute,
 		MonitorInterval:   10 * time.Second,
+
+		SignalChangeThresholdBps: 50,
+
+		HighVolatilityBps: 200,
+		VolatilityWindow:  20,
 	}
 }
 
@@ -86,20 +188,40 @@ type Orchestrator struct {
 	forecaster   *agents.Forecaster
 	policyEngine *policy.PolicyEngine
 	paperEngine  *paper.Engine
+	newsProvider agents.NewsProvider
+	activity     *data.Client
 
 	mu      sync.RWMutex
 	running bool
 	stopCh  chan struct{}
 
+	// cycleMu serializes full trading cycles so a RunOnce trigger cannot
+	// interleave its stages with the forecast loop's.
+	cycleMu sync.Mutex
+
 	// State
 	activeMarkets []gamma.Market
 	forecasts     map[string]*agents.EnsembleForecast // tokenID -> forecast
+	news          map[string][]string                 // tokenID -> recent headlines
+	priceHistory  map[string][]decimal.Decimal        // tokenID -> recent mid prices
+	nextForecast  map[string]time.Time                // tokenID -> next forecast due
+	illiquid      map[string]string                   // tokenID -> liquidity skip reason
+	refPrices     map[string]decimal.Decimal          // tokenID -> price under PriceMode
+	bookDepth     map[string]decimal.Decimal          // tokenID -> top-of-book size
 	signals       []*agents.TradingSignal
-	pendingOrders []string
+	pendingOrders []string                         // Live order IDs placed by execution, reconciled on Stop
+	shadowOrders  int                              // Orders placed in paper instead of live
+	lastEmitted   map[string]*agents.TradingSignal // tokenID -> last signal passed to onSignal
+	lastActivity  map[string]int64                 // conditionID -> newest activity timestamp seen
+	holdings      map[string]holding               // tokenID -> direction of the last executed order
+	disabled      map[string]bool                  // Market IDs, condition IDs, or token IDs not to trade
+	rng           *rand.Rand                       // Exploration draws, guarded by mu
 
 	// Callbacks
 	onStageComplete func(*StageResult)
 	onSignal        func(*agents.TradingSignal)
+	onWhaleAlert    func(data.Activity)
+	onBook          func(string, *book.OrderBook)
 	onError         func(error)
 }
 
@@ -125,9 +247,44 @@ func NewOrchestrator(
 		paperEngine:  paperEngine,
 		stopCh:       make(chan struct{}),
 		forecasts:    make(map[string]*agents.EnsembleForecast),
+		news:         make(map[string][]string),
+		priceHistory: make(map[string][]decimal.Decimal),
+		nextForecast: make(map[string]time.Time),
+		illiquid:     make(map[string]string),
+		refPrices:    make(map[string]decimal.Decimal),
+		bookDepth:    make(map[string]decimal.Decimal),
+		holdings:     make(map[string]holding),
+		lastEmitted:  make(map[string]*agents.TradingSignal),
+		lastActivity: make(map[string]int64),
+		disabled:     make(map[string]bool),
+		rng:          rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0)),
 	}
 }
 
+// SetNewsProvider sets the source of headlines used to enrich forecasts.
+func (o *Orchestrator) SetNewsProvider(p agents.NewsProvider) {
+	o.newsProvider = p
+}
+
+// SetActivityClient sets the Data API client used to watch on-chain activity
+// for whale trades.
+func (o *Orchestrator) SetActivityClient(c *data.Client) {
+	o.activity = c
+}
+
+// OnWhaleAlert sets a callback for trades above WhaleTradeUSDC. Each trade is
+// reported once.
+func (o *Orchestrator) OnWhaleAlert(fn func(data.Activity)) {
+	o.onWhaleAlert = fn
+}
+
+// OnBook registers a callback given each tracked market's YES book during
+// monitoring, e.g. for market-quality metrics. Books are only fetched while
+// a callback is set.
+func (o *Orchestrator) OnBook(fn func(tokenID string, ob *book.OrderBook)) {
+	o.onBook = fn
+}
+
 // OnStageComplete sets a callback for stage completions.
 func (o *Orchestrator) OnStageComplete(fn func(*StageResult)) {
 	o.onStageComplete = fn
@@ -143,6 +300,52 @@ func (o *Orchestrator) OnError(fn func(error)) {
 	o.onError = fn
 }
 
+// DisableMarket stops order execution for a market, which keeps being
+// forecast. id may be the Gamma market ID, the condition ID, or the YES token
+// ID.
+func (o *Orchestrator) DisableMarket(id string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.disabled[id] = true
+}
+
+// EnableMarket resumes order execution for a market disabled under id.
+func (o *Orchestrator) EnableMarket(id string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.disabled, id)
+}
+
+// DisabledMarkets returns the IDs passed to DisableMarket, sorted.
+func (o *Orchestrator) DisabledMarkets() []string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	ids := make([]string, 0, len(o.disabled))
+	for id := range o.disabled {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// disabledTokensLocked returns the YES token IDs of disabled markets.
+// Callers must hold o.mu.
+func (o *Orchestrator) disabledTokensLocked() map[string]bool {
+	tokens := make(map[string]bool)
+	if len(o.disabled) == 0 {
+		return tokens
+	}
+	for id := range o.disabled {
+		tokens[id] = true
+	}
+	for _, m := range o.activeMarkets {
+		if o.disabled[m.ID] || o.disabled[m.ConditionID] {
+			tokens[m.YesTokenID()] = true
+		}
+	}
+	return tokens
+}
+
 // Start starts the trading workflow.
 func (o *Orchestrator) Start(ctx context.Context) error {
 	o.mu.Lock()
@@ -152,30 +355,135 @@ func (o *Orchestrator) Start(ctx context.Context) error {
 	}
 	o.running = true
 	o.stopCh = make(chan struct{})
+	stopCh := o.stopCh
 	o.mu.Unlock()
 
 	// Run initial market discovery
-	if err := o.runStage(ctx, StageMarketDiscovery); err != nil {
+	if _, err := o.runStage(ctx, StageMarketDiscovery); err != nil {
 		o.handleError(fmt.Errorf("initial discovery failed: %w", err))
 	}
 
 	// Start background loops
-	go o.discoveryLoop(ctx)
-	go o.forecastLoop(ctx)
-	go o.monitorLoop(ctx)
+	go o.discoveryLoop(ctx, stopCh)
+	go o.forecastLoop(ctx, stopCh)
+	go o.monitorLoop(ctx, stopCh)
 
 	return nil
 }
 
-// Stop stops the trading workflow.
-func (o *Orchestrator) Stop() {
-	o.mu.Lock()
-	defer o.mu.Unlock()
+// DrainReport describes what Stop left behind.
+type DrainReport struct {
+	TimedOut       bool     `json:"timed_out"` // A cycle was still in flight at DrainTimeout
+	CanceledOrders int      `json:"canceled_orders"`
+	OpenOrders     []string `json:"open_orders,omitempty"` // IDs of orders still resting
+}
 
+// cancelTimeout bounds the order cancellation and lookup done by Stop.
+const cancelTimeout = 10 * time.Second
+
+// Stop stops the trading workflow. It waits up to DrainTimeout for an
+// in-flight cycle so orders aren't left half-submitted, cancels resting
+// orders if CancelOnStop is set, and reports what remains open.
+func (o *Orchestrator) Stop() *DrainReport {
+	o.mu.Lock()
+	wasRunning := o.running
 	if o.running {
 		close(o.stopCh)
 		o.running = false
 	}
+	o.mu.Unlock()
+
+	report := &DrainReport{}
+	if !wasRunning {
+		return report
+	}
+
+	report.TimedOut = !o.drain(o.config.DrainTimeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cancelTimeout)
+	defer cancel()
+	o.reconcileOrders(ctx, report)
+
+	return report
+}
+
+// drain waits up to timeout for an in-flight cycle to finish, returning false
+// if one is still running.
+func (o *Orchestrator) drain(timeout time.Duration) bool {
+	if timeout <= 0 {
+		if !o.cycleMu.TryLock() {
+			return false
+		}
+		o.cycleMu.Unlock()
+		return true
+	}
+
+	done := make(chan struct{})
+	go func() {
+		o.cycleMu.Lock()
+		o.cycleMu.Unlock()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// reconcileOrders optionally cancels resting orders and records those still
+// open in report.
+func (o *Orchestrator) reconcileOrders(ctx context.Context, report *DrainReport) {
+	if o.tradesPaper() {
+		if o.config.CancelOnStop {
+			report.CanceledOrders = o.paperEngine.CancelAllOrders()
+		}
+		for _, order := range o.paperEngine.GetOpenOrders() {
+			report.OpenOrders = append(report.OpenOrders, order.ID)
+		}
+		return
+	}
+
+	o.mu.Lock()
+	pending := o.pendingOrders
+	o.mu.Unlock()
+	if len(pending) == 0 || o.clobClient == nil || !o.clobClient.HasCredentials() {
+		return
+	}
+
+	if o.config.CancelOnStop {
+		if err := o.clobClient.CancelOrders(ctx, pending); err != nil {
+			o.handleError(fmt.Errorf("cancel orders on stop: %w", err))
+		} else {
+			report.CanceledOrders = len(pending)
+			pending = nil
+		}
+	}
+
+	// Only orders the venue still shows as open remain pending
+	if len(pending) > 0 {
+		open, err := o.clobClient.GetOpenOrders(ctx)
+		if err != nil {
+			o.handleError(fmt.Errorf("list open orders on stop: %w", err))
+			report.OpenOrders = pending
+		} else {
+			isOpen := make(map[string]bool, len(open))
+			for _, order := range open {
+				isOpen[order.ID] = true
+			}
+			for _, id := range pending {
+				if isOpen[id] {
+					report.OpenOrders = append(report.OpenOrders, id)
+				}
+			}
+		}
+	}
+
+	o.mu.Lock()
+	o.pendingOrders = report.OpenOrders
+	o.mu.Unlock()
 }
 
 // IsRunning returns true if the orchestrator is running.
@@ -185,8 +493,11 @@ func (o *Orchestrator) IsRunning() bool {
 	return o.running
 }
 
-// RunOnce executes a single workflow cycle.
-func (o *Orchestrator) RunOnce(ctx context.Context) error {
+// RunOnce executes a single workflow cycle and returns the result of each
+// stage that ran, including the one that failed. It does not depend on the
+// background loops and may be called whether or not the orchestrator is
+// running; concurrent cycles are serialized.
+func (o *Orchestrator) RunOnce(ctx context.Context) ([]*StageResult, error) {
 	stages := []Stage{
 		StageMarketDiscovery,
 		StageDataCollection,
@@ -196,13 +507,23 @@ func (o *Orchestrator) RunOnce(ctx context.Context) error {
 		StageOrderExecution,
 	}
 
+	o.cycleMu.Lock()
+	defer o.cycleMu.Unlock()
+
+	ctx, span := tracing.Start(ctx, "workflow.cycle")
+	defer span.End()
+
+	results := make([]*StageResult, 0, len(stages))
 	for _, stage := range stages {
-		if err := o.runStage(ctx, stage); err != nil {
-			return fmt.Errorf("stage %s failed: %w", stage, err)
+		result, err := o.runStage(ctx, stage)
+		results = append(results, result)
+		if err != nil {
+			tracing.Fail(span, err)
+			return results, fmt.Errorf("stage %s failed: %w", stage, err)
 		}
 	}
 
-	return nil
+	return results, nil
 }
 
 // GetActiveMarkets returns currently active markets.
@@ -236,7 +557,7 @@ func (o *Orchestrator) GetForecast(tokenID string) (*agents.EnsembleForecast, bo
 
 // --- Background Loops ---
 
-func (o *Orchestrator) discoveryLoop(ctx context.Context) {
+func (o *Orchestrator) discoveryLoop(ctx context.Context, stopCh <-chan struct{}) {
 	ticker := time.NewTicker(o.config.DiscoveryInterval)
 	defer ticker.Stop()
 
@@ -244,25 +565,31 @@ func (o *Orchestrator) discoveryLoop(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
-		case <-o.stopCh:
+		case <-stopCh:
 			return
 		case <-ticker.C:
-			if err := o.runStage(ctx, StageMarketDiscovery); err != nil {
+			if _, err := o.runStage(ctx, StageMarketDiscovery); err != nil {
 				o.handleError(fmt.Errorf("discovery failed: %w", err))
 			}
 		}
 	}
 }
 
-func (o *Orchestrator) forecastLoop(ctx context.Context) {
-	ticker := time.NewTicker(o.config.ForecastInterval)
+func (o *Orchestrator) forecastLoop(ctx context.Context, stopCh <-chan struct{}) {
+	// Tick at the finest interval any market can be due; executeForecasting
+	// skips markets that are not yet due.
+	interval := o.config.ForecastInterval
+	if o.config.MinForecastInterval > 0 && o.config.MinForecastInterval < interval {
+		interval = o.config.MinForecastInterval
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-o.stopCh:
+		case <-stopCh:
 			return
 		case <-ticker.C:
 			stages := []Stage{
@@ -273,17 +600,28 @@ func (o *Orchestrator) forecastLoop(ctx context.Context) {
 				StageOrderExecution,
 			}
 
+			o.cycleMu.Lock()
+			select {
+			case <-stopCh:
+				o.cycleMu.Unlock()
+				return
+			default:
+			}
+			cycleCtx, span := tracing.Start(ctx, "workflow.cycle")
 			for _, stage := range stages {
-				if err := o.runStage(ctx, stage); err != nil {
+				if _, err := o.runStage(cycleCtx, stage); err != nil {
+					tracing.Fail(span, err)
 					o.handleError(fmt.Errorf("stage %s failed: %w", stage, err))
 					break
 				}
 			}
+			span.End()
+			o.cycleMu.Unlock()
 		}
 	}
 }
 
-func (o *Orchestrator) monitorLoop(ctx context.Context) {
+func (o *Orchestrator) monitorLoop(ctx context.Context, stopCh <-chan struct{}) {
 	ticker := time.NewTicker(o.config.MonitorInterval)
 	defer ticker.Stop()
 
@@ -291,10 +629,10 @@ func (o *Orchestrator) monitorLoop(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
-		case <-o.stopCh:
+		case <-stopCh:
 			return
 		case <-ticker.C:
-			if err := o.runStage(ctx, StageMonitoring); err != nil {
+			if _, err := o.runStage(ctx, StageMonitoring); err != nil {
 				o.handleError(fmt.Errorf("monitoring failed: %w", err))
 			}
 		}
@@ -303,7 +641,10 @@ func (o *Orchestrator) monitorLoop(ctx context.Context) {
 
 // --- Stage Execution ---
 
-func (o *Orchestrator) runStage(ctx context.Context, stage Stage) error {
+func (o *Orchestrator) runStage(ctx context.Context, stage Stage) (*StageResult, error) {
+	ctx, span := tracing.Start(ctx, "stage."+string(stage))
+	defer span.End()
+
 	start := time.Now()
 	var err error
 	var data interface{}
@@ -337,12 +678,13 @@ func (o *Orchestrator) runStage(ctx context.Context, stage Stage) error {
 	if err != nil {
 		result.Error = err.Error()
 	}
+	tracing.Fail(span, err)
 
 	if o.onStageComplete != nil {
 		o.onStageComplete(result)
 	}
 
-	return err
+	return result, err
 }
 
 func (o *Orchestrator) executeMarketDiscovery(ctx context.Context) (interface{}, error) {
@@ -353,7 +695,7 @@ func (o *Orchestrator) executeMarketDiscovery(ctx context.Context) (interface{},
 	}
 
 	// Filter by volume and spread
-	filtered := make([]gamma.Market, 0, o.config.MaxMarkets)
+	filtered := make([]gamma.Market, 0, len(markets))
 	for _, m := range markets {
 		if m.Volume.Float64() < o.config.MinVolume.InexactFloat64() {
 			continue
@@ -363,19 +705,79 @@ func (o *Orchestrator) executeMarketDiscovery(ctx context.Context) (interface{},
 		}
 
 		filtered = append(filtered, m)
-		if len(filtered) >= o.config.MaxMarkets {
-			break
+	}
+	ranked := selectMarkets(filtered, len(filtered), o.config.MarketSelection, o.edgeBps, float64(o.config.MinEdgeBps))
+	o.mu.Lock()
+	filtered, explored := explore(ranked, o.config.MaxMarkets, o.config.ExploreEpsilon, o.rng)
+	o.mu.Unlock()
+	if explored != nil {
+		log.Printf("[ORCH] Exploring off-list market %s: %s", explored.ConditionID, explored.Question)
+	}
+
+	// Let the paper engine net complementary YES/NO holdings
+	if o.paperEngine != nil {
+		for _, m := range filtered {
+			o.paperEngine.SetMarketTokens(m.ConditionID, m.YesTokenID(), m.NoTokenID())
+		}
+	}
+
+	// Assign both tokens to the market's category for MaxCategoryExposure
+	if o.policyEngine != nil {
+		for _, m := range filtered {
+			for _, tokenID := range []string{m.YesTokenID(), m.NoTokenID()} {
+				if tokenID != "" {
+					o.policyEngine.SetMarketCategory(tokenID, m.CategoryName())
+				}
+			}
 		}
 	}
 
 	o.mu.Lock()
 	o.activeMarkets = filtered
+	o.pruneMarketStateLocked(filtered)
 	o.mu.Unlock()
 
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"total_fetched": len(markets),
 		"filtered":      len(filtered),
-	}, nil
+	}
+	if explored != nil {
+		result["explored"] = explored.ConditionID
+	}
+	return result, nil
+}
+
+// pruneMarketStateLocked drops the per-market state (forecasts, news, price
+// history, schedules, liquidity, prices, depth, emitted signals, holdings,
+// and activity cursors) of markets no longer in markets, so it doesn't grow
+// with every market ever tracked. Caller holds o.mu.
+func (o *Orchestrator) pruneMarketStateLocked(markets []gamma.Market) {
+	tokens := make(map[string]bool, 2*len(markets))
+	conditions := make(map[string]bool, len(markets))
+	for i := range markets {
+		tokens[markets[i].YesTokenID()] = true
+		tokens[markets[i].NoTokenID()] = true
+		conditions[markets[i].ConditionID] = true
+	}
+	pruneKeys(o.forecasts, tokens)
+	pruneKeys(o.news, tokens)
+	pruneKeys(o.priceHistory, tokens)
+	pruneKeys(o.nextForecast, tokens)
+	pruneKeys(o.illiquid, tokens)
+	pruneKeys(o.refPrices, tokens)
+	pruneKeys(o.bookDepth, tokens)
+	pruneKeys(o.lastEmitted, tokens)
+	pruneKeys(o.holdings, tokens)
+	pruneKeys(o.lastActivity, conditions)
+}
+
+// pruneKeys deletes the entries of m whose key is not in keep.
+func pruneKeys[V any](m map[string]V, keep map[string]bool) {
+	for key := range m {
+		if !keep[key] {
+			delete(m, key)
+		}
+	}
 }
 
 func (o *Orchestrator) executeDataCollection(ctx context.Context) (interface{}, error) {
@@ -387,26 +789,172 @@ func (o *Orchestrator) executeDataCollection(ctx context.Context) (interface{},
 		return nil, nil
 	}
 
-	// Fetch orderbooks for active markets
+	// Fetch orderbooks and news for active markets
 	collected := 0
+	withNews := 0
+	whales := 0
+	skipped := make(map[string]string)
 	for _, m := range markets {
 		tokenID := m.YesTokenID()
 		if tokenID == "" {
 			continue
 		}
 
-		_, err := o.clobClient.GetOrderBook(ctx, tokenID)
-		if err != nil {
-			continue
+		// Gate on book liquidity first so illiquid markets cost nothing more
+		if o.clobClient != nil {
+			book, err := o.clobClient.GetOrderBook(ctx, tokenID)
+			if err != nil {
+				continue
+			}
+			if mid, ok := bookMidpoint(book); ok {
+				o.recordPrice(tokenID, mid)
+			}
+			if price, ok := o.referencePrice(ctx, tokenID, book); ok {
+				o.mu.Lock()
+				o.refPrices[tokenID] = price
+				o.mu.Unlock()
+			}
+			collected++
+
+			_, _, depth := topOfBook(book)
+			reason := o.liquidityCheck(book)
+			o.mu.Lock()
+			o.bookDepth[tokenID] = depth
+			if reason != "" {
+				o.illiquid[tokenID] = reason
+			} else {
+				delete(o.illiquid, tokenID)
+			}
+			o.mu.Unlock()
+			if reason != "" {
+				skipped[tokenID] = reason
+				continue
+			}
 		}
-		collected++
+
+		if o.newsProvider != nil {
+			headlines, err := o.newsProvider.FetchNews(ctx, m.Question)
+			if err == nil {
+				o.mu.Lock()
+				o.news[tokenID] = headlines
+				o.mu.Unlock()
+				withNews++
+			}
+		}
+
+		whales += o.checkWhales(ctx, m.ConditionID)
 	}
 
 	return map[string]interface{}{
 		"markets_collected": collected,
+		"markets_with_news": withNews,
+		"markets_skipped":   skipped,
+		"whale_alerts":      whales,
 	}, nil
 }
 
+// checkWhales reports trades in a market at or above WhaleTradeUSDC that are
+// newer than any seen before, returning how many were reported.
+func (o *Orchestrator) checkWhales(ctx context.Context, conditionID string) int {
+	if o.activity == nil || !o.config.WhaleTradeUSDC.IsPositive() || conditionID == "" {
+		return 0
+	}
+
+	activity, err := o.activity.GetActivity(ctx, &data.ActivityFilter{
+		Market: conditionID,
+		Types:  []data.ActivityType{data.ActivityTrade},
+		Limit:  100,
+	})
+	if err != nil {
+		return 0
+	}
+
+	o.mu.Lock()
+	seen := o.lastActivity[conditionID]
+	for _, a := range activity {
+		if a.Timestamp > o.lastActivity[conditionID] {
+			o.lastActivity[conditionID] = a.Timestamp
+		}
+	}
+	o.mu.Unlock()
+
+	reported := 0
+	for _, whale := range data.Whales(activity, o.config.WhaleTradeUSDC.InexactFloat64()) {
+		if whale.Timestamp <= seen {
+			continue
+		}
+		reported++
+		if o.onWhaleAlert != nil {
+			o.onWhaleAlert(whale)
+		}
+	}
+	return reported
+}
+
+// referencePrice prices a token under PriceMode. It returns false when no
+// mode is configured or the price is unavailable.
+func (o *Orchestrator) referencePrice(ctx context.Context, tokenID string, summary *clob.OrderBookSummary) (decimal.Decimal, bool) {
+	switch o.config.PriceMode {
+	case "":
+		return decimal.Zero, false
+	case book.PriceModeLastTrade:
+		last, err := o.clobClient.GetLastTradePrice(ctx, tokenID)
+		if err != nil {
+			return decimal.Zero, false
+		}
+		price, err := decimal.NewFromString(last)
+		return price, err == nil && price.IsPositive()
+	default:
+		return toOrderBook(tokenID, summary).Price(o.config.PriceMode)
+	}
+}
+
+// toOrderBook converts a CLOB book summary for price calculations.
+func toOrderBook(tokenID string, summary *clob.OrderBookSummary) *book.OrderBook {
+	ob := book.NewOrderBook(tokenID, summary.Market)
+	ob.SetBids(toLevels(summary.Bids))
+	ob.SetAsks(toLevels(summary.Asks))
+	return ob
+}
+
+func toLevels(levels []clob.PriceLevel) []book.PriceLevel {
+	out := make([]book.PriceLevel, 0, len(levels))
+	for _, l := range levels {
+		price, err1 := decimal.NewFromString(l.Price)
+		size, err2 := decimal.NewFromString(l.Size)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		out = append(out, book.PriceLevel{Price: price, Size: size})
+	}
+	return out
+}
+
+// liquidityCheck returns why a book fails the MinBookDepth/MaxBookSpreadBps
+// gate, or "" if it passes or the gate is disabled.
+func (o *Orchestrator) liquidityCheck(book *clob.OrderBookSummary) string {
+	minDepth, maxSpread := o.config.MinBookDepth, o.config.MaxBookSpreadBps
+	if !minDepth.IsPositive() && !maxSpread.IsPositive() {
+		return ""
+	}
+
+	bestBid, bestAsk, depth := topOfBook(book)
+	if bestBid.IsZero() || bestAsk.IsZero() {
+		return "one-sided book"
+	}
+
+	if minDepth.IsPositive() && depth.LessThan(minDepth) {
+		return fmt.Sprintf("top-of-book depth %s below minimum %s", depth, minDepth)
+	}
+
+	spreadBps := bestAsk.Sub(bestBid).Mul(decimal.NewFromInt(10000))
+	if maxSpread.IsPositive() && spreadBps.GreaterThan(maxSpread) {
+		return fmt.Sprintf("spread %s bps above maximum %s", spreadBps, maxSpread)
+	}
+
+	return ""
+}
+
 func (o *Orchestrator) executeForecasting(ctx context.Context) (interface{}, error) {
 	o.mu.RLock()
 	markets := o.activeMarkets
@@ -416,13 +964,32 @@ func (o *Orchestrator) executeForecasting(ctx context.Context) (interface{}, err
 		return nil, nil
 	}
 
+	var opts []agents.ForecastOptions
+	if o.config.MaxForecastLatency > 0 {
+		opts = append(opts, agents.ForecastOptions{MaxLatency: o.config.MaxForecastLatency})
+	}
+
+	now := time.Now()
 	forecasted := 0
+	timedOut := 0
 	for _, m := range markets {
 		tokenID := m.YesTokenID()
 		if tokenID == "" {
 			continue
 		}
 
+		o.mu.RLock()
+		due, scheduled := o.nextForecast[tokenID]
+		_, illiquid := o.illiquid[tokenID]
+		o.mu.RUnlock()
+		if illiquid || (scheduled && now.Before(due)) {
+			continue
+		}
+
+		o.mu.RLock()
+		news := o.news[tokenID]
+		o.mu.RUnlock()
+
 		// Build context
 		mktCtx := &agents.MarketContext{
 			TokenID:      tokenID,
@@ -432,29 +999,124 @@ func (o *Orchestrator) executeForecasting(ctx context.Context) (interface{}, err
 			CurrentPrice: decimal.NewFromFloat(m.YesPrice()),
 			Volume24h:    decimal.NewFromFloat(m.Volume24hr.Float64()),
 			EndDate:      m.EndDate,
+			NewsSnippets: news,
 		}
 
 		// Get ensemble forecast
-		forecast, err := o.forecaster.ForecastEnsemble(ctx, mktCtx)
+		forecast, err := o.forecaster.ForecastEnsemble(ctx, mktCtx, opts...)
 		if err != nil {
+			// Try again next cycle
+			if errors.Is(err, agents.ErrForecastTimeout) {
+				timedOut++
+			}
 			continue
 		}
 
 		o.mu.Lock()
 		o.forecasts[tokenID] = forecast
+		o.nextForecast[tokenID] = now.Add(o.forecastIntervalLocked(tokenID))
 		o.mu.Unlock()
 		forecasted++
 	}
 
 	return map[string]interface{}{
 		"markets_forecasted": forecasted,
+		"markets_timed_out":  timedOut,
 	}, nil
 }
 
+// recordPrice appends a mid price tick, keeping the last VolatilityWindow.
+func (o *Orchestrator) recordPrice(tokenID string, price decimal.Decimal) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	history := append(o.priceHistory[tokenID], price)
+	if window := o.config.VolatilityWindow; window > 0 && len(history) > window {
+		history = history[len(history)-window:]
+	}
+	o.priceHistory[tokenID] = history
+}
+
+// forecastIntervalLocked returns how long to wait before re-forecasting a
+// token, interpolating linearly between MaxForecastInterval (flat) and
+// MinForecastInterval (volatility >= HighVolatilityBps). Caller holds o.mu.
+func (o *Orchestrator) forecastIntervalLocked(tokenID string) time.Duration {
+	minInterval, maxInterval := o.config.MinForecastInterval, o.config.MaxForecastInterval
+	history := o.priceHistory[tokenID]
+	if minInterval <= 0 || maxInterval <= 0 || len(history) < 3 || o.config.HighVolatilityBps <= 0 {
+		return o.config.ForecastInterval
+	}
+
+	ratio := math.Min(priceVolatilityBps(history)/float64(o.config.HighVolatilityBps), 1)
+	return maxInterval - time.Duration(ratio*float64(maxInterval-minInterval))
+}
+
+// priceVolatilityBps returns the standard deviation of tick-to-tick price
+// changes, in basis points of probability.
+func priceVolatilityBps(prices []decimal.Decimal) float64 {
+	if len(prices) < 2 {
+		return 0
+	}
+
+	changes := make([]float64, len(prices)-1)
+	mean := 0.0
+	for i := 1; i < len(prices); i++ {
+		changes[i-1] = prices[i].Sub(prices[i-1]).InexactFloat64() * 10000
+		mean += changes[i-1]
+	}
+	mean /= float64(len(changes))
+
+	variance := 0.0
+	for _, c := range changes {
+		variance += (c - mean) * (c - mean)
+	}
+	return math.Sqrt(variance / float64(len(changes)))
+}
+
+// bookMidpoint returns the midpoint between the best bid and best ask.
+func bookMidpoint(book *clob.OrderBookSummary) (decimal.Decimal, bool) {
+	bestBid, bestAsk, _ := topOfBook(book)
+	if bestBid.IsZero() || bestAsk.IsZero() {
+		return decimal.Zero, false
+	}
+	return bestBid.Add(bestAsk).Div(decimal.NewFromInt(2)), true
+}
+
+// topOfBook returns the best bid and ask (zero for an empty side) and the
+// combined size resting at those two prices.
+func topOfBook(book *clob.OrderBookSummary) (bestBid, bestAsk, depth decimal.Decimal) {
+	var bidSize, askSize decimal.Decimal
+	for _, level := range book.Bids {
+		p, err := decimal.NewFromString(level.Price)
+		if err != nil || !p.GreaterThan(bestBid) {
+			continue
+		}
+		bestBid = p
+		bidSize, _ = decimal.NewFromString(level.Size)
+	}
+	for _, level := range book.Asks {
+		p, err := decimal.NewFromString(level.Price)
+		if err != nil || !(bestAsk.IsZero() || p.LessThan(bestAsk)) {
+			continue
+		}
+		bestAsk = p
+		askSize, _ = decimal.NewFromString(level.Size)
+	}
+	return bestBid, bestAsk, bidSize.Add(askSize)
+}
+
 func (o *Orchestrator) executeSignalGen(ctx context.Context) (interface{}, error) {
 	o.mu.RLock()
 	markets := o.activeMarkets
 	forecasts := o.forecasts
+	refPrices := make(map[string]decimal.Decimal, len(o.refPrices))
+	for tokenID, price := range o.refPrices {
+		refPrices[tokenID] = price
+	}
+	bookDepth := make(map[string]decimal.Decimal, len(o.bookDepth))
+	for tokenID, depth := range o.bookDepth {
+		bookDepth[tokenID] = depth
+	}
 	o.mu.RUnlock()
 
 	signals := make([]*agents.TradingSignal, 0)
@@ -466,24 +1128,45 @@ func (o *Orchestrator) executeSignalGen(ctx context.Context) (interface{}, error
 			continue
 		}
 
+		price := decimal.NewFromFloat(m.YesPrice())
+		if ref, ok := refPrices[tokenID]; ok {
+			price = ref
+		}
+
+		_, span := tracing.Start(ctx, "signal.evaluate",
+			attribute.String("market.id", m.ConditionID),
+			attribute.String("market.token_id", tokenID))
 		signal := o.forecaster.GenerateSignal(
 			forecast,
-			decimal.NewFromFloat(m.YesPrice()),
+			price,
 			o.config.MinEdgeBps,
 		)
+		span.SetAttributes(
+			attribute.String("signal", signal.Signal.String()),
+			attribute.Float64("signal.edge_bps", signal.EdgeBps.InexactFloat64()))
+		span.End()
 
 		if signal.Signal == agents.SignalBuy &&
 			signal.Forecast.Confidence.GreaterThanOrEqual(o.config.MinConfidence) {
 			signals = append(signals, signal)
 
-			if o.onSignal != nil {
+			if o.onSignal != nil && o.shouldEmitSignal(signal) {
 				o.onSignal(signal)
 			}
 		}
 	}
 
-	// Rank signals by expected value
-	signals = agents.RankSignals(signals)
+	// Rank signals by conviction: expected value, fillability, freshness
+	signals = agents.RankSignals(signals,
+		agents.WithSignalLiquidity(func(s *agents.TradingSignal) decimal.Decimal {
+			depth, ok := bookDepth[s.TokenID]
+			if !ok || !o.config.MaxOrderSize.IsPositive() {
+				return decimal.NewFromInt(1) // No book seen: don't penalize
+			}
+			return depth.Div(o.config.MaxOrderSize)
+		}),
+		agents.WithForecastHalfLife(o.config.ConvictionHalfLife),
+	)
 
 	o.mu.Lock()
 	o.signals = signals
@@ -494,6 +1177,24 @@ func (o *Orchestrator) executeSignalGen(ctx context.Context) (interface{}, error
 	}, nil
 }
 
+// shouldEmitSignal reports whether a signal differs enough from the last one
+// emitted for its token to be worth surfacing, and records it if so.
+func (o *Orchestrator) shouldEmitSignal(signal *agents.TradingSignal) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	last, ok := o.lastEmitted[signal.TokenID]
+	if ok && last.Side == signal.Side {
+		delta := signal.EdgeBps.Sub(last.EdgeBps).Abs()
+		if delta.LessThanOrEqual(decimal.NewFromInt(int64(o.config.SignalChangeThresholdBps))) {
+			return false
+		}
+	}
+
+	o.lastEmitted[signal.TokenID] = signal
+	return true
+}
+
 func (o *Orchestrator) executeRiskCheck(ctx context.Context) (interface{}, error) {
 	o.mu.RLock()
 	signals := o.signals
@@ -510,7 +1211,7 @@ func (o *Orchestrator) executeRiskCheck(ctx context.Context) (interface{}, error
 		}
 
 		// Calculate order size
-		size := o.config.MaxOrderSize
+		size, _ := o.orderSize(signal)
 		price := signal.CurrentPrice
 		if signal.Side == "NO" {
 			price = decimal.NewFromInt(1).Sub(price)
@@ -537,6 +1238,7 @@ func (o *Orchestrator) executeRiskCheck(ctx context.Context) (interface{}, error
 func (o *Orchestrator) executeOrderExecution(ctx context.Context) (interface{}, error) {
 	o.mu.RLock()
 	signals := o.signals
+	disabled := o.disabledTokensLocked()
 	o.mu.RUnlock()
 
 	if len(signals) == 0 {
@@ -548,10 +1250,27 @@ func (o *Orchestrator) executeOrderExecution(ctx context.Context) (interface{},
 		if signal.Signal != agents.SignalBuy {
 			continue
 		}
+		if disabled[signal.TokenID] {
+			log.Printf("[ORCH] Skipped %s %s order: market disabled", signal.TokenID, signal.Side)
+			continue
+		}
+
+		size, clamped := o.orderSize(signal)
+		if !size.IsPositive() {
+			continue
+		}
+		if clamped {
+			log.Printf("[ORCH] Clamped %s %s order to %s shares (max signal notional $%s)",
+				signal.TokenID, signal.Side, size, o.config.MaxSignalNotional)
+		}
+
+		if reason := o.holdingBlocks(signal); reason != "" {
+			log.Printf("[ORCH] Suppressed %s %s order: %s", signal.TokenID, signal.Side, reason)
+			continue
+		}
 
 		// Re-check risk
 		if o.policyEngine != nil {
-			size := o.config.MaxOrderSize
 			price := signal.CurrentPrice
 			if signal.Side == "NO" {
 				price = decimal.NewFromInt(1).Sub(price)
@@ -562,7 +1281,18 @@ func (o *Orchestrator) executeOrderExecution(ctx context.Context) (interface{},
 			}
 		}
 
-		if o.config.UsePaperTrade && o.paperEngine != nil {
+		// YES buys the token; NO sells it
+		limitPrice := signal.CurrentPrice
+		if o.config.MarketableLimits && o.policyEngine != nil {
+			var err error
+			limitPrice, err = o.marketableLimit(ctx, signal.TokenID, signal.Side == "YES", signal.CurrentPrice, size)
+			if err != nil {
+				log.Printf("[ORCH] Skipped %s %s order: %v", signal.TokenID, signal.Side, err)
+				continue
+			}
+		}
+
+		if o.tradesPaper() {
 			// Paper trade
 			var side paper.Side
 			if signal.Side == "YES" {
@@ -575,15 +1305,25 @@ func (o *Orchestrator) executeOrderExecution(ctx context.Context) (interface{},
 				TokenID:   signal.TokenID,
 				Side:      side,
 				OrderType: paper.OrderTypeMarket,
-				Size:      o.config.MaxOrderSize,
+				Size:      size,
+			}
+			if o.config.MarketableLimits && o.policyEngine != nil {
+				req.OrderType = paper.OrderTypeLimit
+				req.Price = limitPrice
 			}
 
 			_, err := o.paperEngine.PlaceOrder(ctx, req)
 			if err != nil {
 				continue
 			}
+			if o.config.ShadowMode {
+				o.mu.Lock()
+				o.shadowOrders++
+				o.mu.Unlock()
+			}
+			o.recordHolding(signal)
 			executed++
-		} else if o.clobClient != nil && o.clobClient.HasCredentials() {
+		} else if !o.config.ShadowMode && o.clobClient != nil && o.clobClient.HasCredentials() {
 			// Live trade
 			var side clob.OrderSide
 			tokenID := signal.TokenID
@@ -596,14 +1336,20 @@ func (o *Orchestrator) executeOrderExecution(ctx context.Context) (interface{},
 			args := &clob.OrderArgs{
 				TokenID: tokenID,
 				Side:    side,
-				Price:   signal.CurrentPrice.InexactFloat64(),
-				Size:    o.config.MaxOrderSize.InexactFloat64(),
+				Price:   limitPrice.InexactFloat64(),
+				Size:    size.InexactFloat64(),
 			}
 
-			_, err := o.clobClient.CreateAndPostOrder(ctx, args, "0.01", false)
+			resp, err := o.clobClient.CreateAndPostOrder(ctx, args, "0.01", false)
 			if err != nil {
 				continue
 			}
+			if resp.OrderID != "" {
+				o.mu.Lock()
+				o.pendingOrders = append(o.pendingOrders, resp.OrderID)
+				o.mu.Unlock()
+			}
+			o.recordHolding(signal)
 			executed++
 		}
 
@@ -618,12 +1364,155 @@ func (o *Orchestrator) executeOrderExecution(ctx context.Context) (interface{},
 	}, nil
 }
 
+// holding is the direction a token was last traded in, for MinHoldingPeriod.
+type holding struct {
+	side  string          // "YES" or "NO"
+	entry decimal.Decimal // Price of side when the direction was set
+	since time.Time
+}
+
+// sidePrice is the price of side given the YES price.
+func sidePrice(side string, yesPrice decimal.Decimal) decimal.Decimal {
+	if side == "NO" {
+		return decimal.NewFromInt(1).Sub(yesPrice)
+	}
+	return yesPrice
+}
+
+// holdingBlocks returns why MinHoldingPeriod suppresses signal, or "" if
+// it may trade.
+func (o *Orchestrator) holdingBlocks(signal *agents.TradingSignal) string {
+	if o.config.MinHoldingPeriod <= 0 {
+		return ""
+	}
+
+	o.mu.RLock()
+	h, ok := o.holdings[signal.TokenID]
+	o.mu.RUnlock()
+	if !ok || h.side == signal.Side {
+		return ""
+	}
+
+	held := o.now().Sub(h.since)
+	if held >= o.config.MinHoldingPeriod {
+		return ""
+	}
+
+	if o.config.HoldingStopLoss.IsPositive() && h.entry.IsPositive() {
+		loss := h.entry.Sub(sidePrice(h.side, signal.CurrentPrice)).Div(h.entry)
+		if loss.GreaterThanOrEqual(o.config.HoldingStopLoss) {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("%s held %s of minimum %s", h.side, held.Round(time.Second), o.config.MinHoldingPeriod)
+}
+
+// recordHolding notes an executed signal order. Only a change of direction
+// restarts the holding clock.
+func (o *Orchestrator) recordHolding(signal *agents.TradingSignal) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if h, ok := o.holdings[signal.TokenID]; ok && h.side == signal.Side {
+		return
+	}
+	o.holdings[signal.TokenID] = holding{
+		side:  signal.Side,
+		entry: sidePrice(signal.Side, signal.CurrentPrice),
+		since: o.now(),
+	}
+}
+
+// now is the paper engine's time when paper trading, so simulated clocks
+// drive time-based rules, and the wall clock otherwise.
+func (o *Orchestrator) now() time.Time {
+	if o.paperEngine != nil {
+		return o.paperEngine.Now()
+	}
+	return time.Now()
+}
+
+// marketableTick is the price grid marketable limits are rounded to, matching
+// the tick live orders are posted with.
+var marketableTick = decimal.NewFromFloat(0.01)
+
+// marketableLimit returns the limit price for a MarketableLimits order:
+// expected moved against us by MaxSlippage and rounded to the tick inside
+// the tolerance. With a CLOB client it first simulates the fill against the
+// live book and fails if the book can't fill size, CheckSlippage rejects
+// the average price, or too little of the book is inside the limit for the
+// order to fill rather than rest.
+func (o *Orchestrator) marketableLimit(ctx context.Context, tokenID string, isBuy bool, expected, size decimal.Decimal) (decimal.Decimal, error) {
+	one := decimal.NewFromInt(1)
+	var limit decimal.Decimal
+	if isBuy {
+		limit = expected.Mul(one.Add(o.policyEngine.MaxSlippage()))
+		limit = limit.Div(marketableTick).Floor().Mul(marketableTick)
+	} else {
+		limit = expected.Mul(one.Sub(o.policyEngine.MaxSlippage()))
+		limit = limit.Div(marketableTick).Ceil().Mul(marketableTick)
+	}
+
+	if o.clobClient != nil {
+		summary, err := o.clobClient.GetOrderBook(ctx, tokenID)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("fetch book for slippage check: %w", err)
+		}
+		side := book.SideBuy
+		if !isBuy {
+			side = book.SideSell
+		}
+		ob := toOrderBook(tokenID, summary)
+		_, avgPrice, unfilled := ob.CostToFill(side, size)
+		if unfilled.IsPositive() {
+			return decimal.Zero, fmt.Errorf("book can fill only %s of %s", size.Sub(unfilled), size)
+		}
+		if err := o.policyEngine.CheckSlippage(expected, avgPrice); err != nil {
+			return decimal.Zero, fmt.Errorf("simulated fill at %s vs expected %s: %w", avgPrice.StringFixed(4), expected, err)
+		}
+		if available := ob.SizeAvailable(side, limit); available.LessThan(size) {
+			return decimal.Zero, fmt.Errorf("only %s of %s available at limit %s", available, size, limit)
+		}
+	}
+
+	return limit, nil
+}
+
+// tradesPaper reports whether orders go to the paper engine.
+func (o *Orchestrator) tradesPaper() bool {
+	return (o.config.UsePaperTrade || o.config.ShadowMode) && o.paperEngine != nil
+}
+
 func (o *Orchestrator) executeMonitoring(ctx context.Context) (interface{}, error) {
 	// Update prices if using paper trading
 	if o.paperEngine != nil {
 		o.paperEngine.UpdatePrices(ctx)
 	}
 
+	// Liquidate positions held past the policy's max age
+	if o.paperEngine != nil && o.policyEngine != nil {
+		o.liquidateStalePositions(ctx)
+	}
+
+	// Sample book quality for every tracked market
+	o.mu.RLock()
+	markets := o.activeMarkets
+	o.mu.RUnlock()
+	if o.onBook != nil && o.clobClient != nil {
+		for _, m := range markets {
+			tokenID := m.YesTokenID()
+			if tokenID == "" {
+				continue
+			}
+			summary, err := o.clobClient.GetOrderBook(ctx, tokenID)
+			if err != nil {
+				continue
+			}
+			o.onBook(tokenID, toOrderBook(tokenID, summary))
+		}
+	}
+
 	// Get stats
 	var stats interface{}
 	if o.paperEngine != nil {
@@ -636,6 +1525,34 @@ func (o *Orchestrator) executeMonitoring(ctx context.Context) (interface{}, erro
 	return stats, nil
 }
 
+// liquidateStalePositions closes paper positions flagged by the policy engine
+// as open longer than MaxPositionAge.
+func (o *Orchestrator) liquidateStalePositions(ctx context.Context) {
+	stale := o.policyEngine.StalePositions(o.paperEngine.GetPositions(), o.paperEngine.Now())
+	for _, tokenID := range stale {
+		pos, ok := o.paperEngine.GetPosition(tokenID)
+		if !ok {
+			continue
+		}
+
+		side := paper.SideSell
+		if pos.Side == paper.SideSell {
+			side = paper.SideBuy
+		}
+
+		_, err := o.paperEngine.PlaceOrder(ctx, &paper.OrderRequest{
+			TokenID:   pos.TokenID,
+			Market:    pos.Market,
+			Side:      side,
+			OrderType: paper.OrderTypeMarket,
+			Size:      pos.Size,
+		})
+		if err != nil {
+			o.handleError(fmt.Errorf("liquidate stale position %s: %w", tokenID, err))
+		}
+	}
+}
+
 func (o *Orchestrator) handleError(err error) {
 	if o.onError != nil {
 		o.onError(err)
@@ -650,13 +1567,70 @@ type Status struct {
 	Signals       int                  `json:"signals"`
 	PolicyStatus  *policy.PolicyStatus `json:"policy_status,omitempty"`
 	PaperStats    *paper.AccountStats  `json:"paper_stats,omitempty"`
+	Shadow        *ShadowReport        `json:"shadow,omitempty"`
+}
+
+// ShadowReport scores shadow-mode decisions against the baseline of not
+// trading at all, i.e. keeping the starting balance.
+type ShadowReport struct {
+	Orders    int             `json:"orders"`     // Orders that would have gone live
+	Baseline  decimal.Decimal `json:"baseline"`   // Starting balance
+	Equity    decimal.Decimal `json:"equity"`     // Balance plus marked positions
+	PnL       decimal.Decimal `json:"pnl"`        // Equity minus Baseline
+	ReturnPct decimal.Decimal `json:"return_pct"` // PnL as a % of Baseline
+}
+
+// Snapshot is a point-in-time copy of orchestrator state taken under a
+// single lock, so its parts agree with each other. Slices and maps are
+// copies; the forecasts and signals they point to are shared and must not be
+// modified.
+type Snapshot struct {
+	TakenAt       time.Time                           `json:"taken_at"`
+	Status        *Status                             `json:"status"`
+	ActiveMarkets []gamma.Market                      `json:"active_markets"`
+	Forecasts     map[string]*agents.EnsembleForecast `json:"forecasts"`
+	Signals       []*agents.TradingSignal             `json:"signals"`
+	PendingOrders []string                            `json:"pending_orders"`
+	Disabled      []string                            `json:"disabled_markets,omitempty"` // See DisableMarket
+}
+
+// Snapshot captures markets, forecasts, signals, pending orders, disabled
+// markets, and status, including running and policy halt state, in one
+// consistent read.
+func (o *Orchestrator) Snapshot() *Snapshot {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	snap := &Snapshot{
+		TakenAt:       time.Now(),
+		Status:        o.statusLocked(),
+		ActiveMarkets: make([]gamma.Market, len(o.activeMarkets)),
+		Forecasts:     make(map[string]*agents.EnsembleForecast, len(o.forecasts)),
+		Signals:       make([]*agents.TradingSignal, len(o.signals)),
+		PendingOrders: make([]string, len(o.pendingOrders)),
+	}
+	copy(snap.ActiveMarkets, o.activeMarkets)
+	copy(snap.Signals, o.signals)
+	copy(snap.PendingOrders, o.pendingOrders)
+	for id := range o.disabled {
+		snap.Disabled = append(snap.Disabled, id)
+	}
+	sort.Strings(snap.Disabled)
+	for tokenID, forecast := range o.forecasts {
+		snap.Forecasts[tokenID] = forecast
+	}
+	return snap
 }
 
 // GetStatus returns the current status.
 func (o *Orchestrator) GetStatus() *Status {
 	o.mu.RLock()
 	defer o.mu.RUnlock()
+	return o.statusLocked()
+}
 
+// statusLocked builds the status. Callers must hold o.mu.
+func (o *Orchestrator) statusLocked() *Status {
 	status := &Status{
 		Running:       o.running,
 		ActiveMarkets: len(o.activeMarkets),
@@ -673,5 +1647,20 @@ func (o *Orchestrator) GetStatus() *Status {
 		status.PaperStats = o.paperEngine.GetStats()
 	}
 
+	if o.config.ShadowMode && o.paperEngine != nil {
+		baseline := o.paperEngine.GetAccount().InitialBalance
+		equity := o.paperEngine.GetEquity()
+		report := &ShadowReport{
+			Orders:   o.shadowOrders,
+			Baseline: baseline,
+			Equity:   equity,
+			PnL:      equity.Sub(baseline),
+		}
+		if baseline.IsPositive() {
+			report.ReturnPct = report.PnL.Div(baseline).Mul(decimal.NewFromInt(100))
+		}
+		status.Shadow = report
+	}
+
 	return status
 }
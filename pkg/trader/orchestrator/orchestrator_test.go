@@ -0,0 +1,1020 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/book"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/clob"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/data"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/gamma"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/agents"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/paper"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/policy"
+
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func newTestOrchestrator(markets []gamma.Market) *Orchestrator {
+	o := NewOrchestrator(nil, nil, nil, agents.NewForecaster(nil), nil, nil)
+	o.activeMarkets = markets
+	return o
+}
+
+func testMarket(yesToken, yesPrice string) gamma.Market {
+	return gamma.Market{
+		ConditionID:      "cond-" + yesToken,
+		ClobTokenIDsRaw:  `["` + yesToken + `","` + yesToken + `-no"]`,
+		OutcomePricesRaw: `["` + yesPrice + `","0.5"]`,
+	}
+}
+
+func TestSignalDebounce(t *testing.T) {
+	o := newTestOrchestrator([]gamma.Market{testMarket("tok", "0.40")})
+	o.forecasts["tok"] = &agents.EnsembleForecast{
+		TokenID:     "tok",
+		Probability: decimal.NewFromFloat(0.60),
+		Confidence:  decimal.NewFromFloat(0.80),
+	}
+
+	emitted := 0
+	o.OnSignal(func(*agents.TradingSignal) { emitted++ })
+
+	for i := 0; i < 2; i++ {
+		if _, err := o.executeSignalGen(context.Background()); err != nil {
+			t.Fatalf("executeSignalGen failed: %v", err)
+		}
+	}
+
+	if emitted != 1 {
+		t.Errorf("Expected 1 emission for identical signals, got %d", emitted)
+	}
+
+	// The signal is still kept for execution even when not re-emitted
+	if len(o.GetSignals()) != 1 {
+		t.Errorf("Expected 1 active signal, got %d", len(o.GetSignals()))
+	}
+}
+
+func TestSignalDebounceEdgeChange(t *testing.T) {
+	o := newTestOrchestrator([]gamma.Market{testMarket("tok", "0.40")})
+	o.forecasts["tok"] = &agents.EnsembleForecast{
+		TokenID:     "tok",
+		Probability: decimal.NewFromFloat(0.60),
+		Confidence:  decimal.NewFromFloat(0.80),
+	}
+
+	emitted := 0
+	o.OnSignal(func(*agents.TradingSignal) { emitted++ })
+
+	o.executeSignalGen(context.Background())
+
+	// Forecast moves enough to shift the edge well past the threshold
+	o.forecasts["tok"].Probability = decimal.NewFromFloat(0.70)
+	o.executeSignalGen(context.Background())
+
+	if emitted != 2 {
+		t.Errorf("Expected 2 emissions after edge change, got %d", emitted)
+	}
+
+	// Side flip always re-emits
+	o.activeMarkets = []gamma.Market{testMarket("tok", "0.90")}
+	o.executeSignalGen(context.Background())
+
+	if emitted != 3 {
+		t.Errorf("Expected 3 emissions after side flip, got %d", emitted)
+	}
+}
+
+type mockNewsProvider struct {
+	headlines []string
+}
+
+func (m *mockNewsProvider) FetchNews(ctx context.Context, query string) ([]string, error) {
+	return m.headlines, nil
+}
+
+// promptCapturingClient records the last prompt it was sent.
+type promptCapturingClient struct {
+	mu     sync.Mutex
+	prompt string
+}
+
+func (c *promptCapturingClient) Complete(ctx context.Context, prompt string, systemPrompt string) (string, error) {
+	c.mu.Lock()
+	c.prompt = prompt
+	c.mu.Unlock()
+	return `{"probability": 0.6, "confidence": 0.8, "reasoning": "test"}`, nil
+}
+
+func (c *promptCapturingClient) Provider() agents.LLMProvider {
+	return agents.ProviderClaude
+}
+
+func TestNewsSnippetsReachPrompt(t *testing.T) {
+	client := &promptCapturingClient{}
+	forecaster := agents.NewForecaster(&agents.ForecasterConfig{
+		Clients: map[agents.LLMProvider]agents.LLMClient{agents.ProviderClaude: client},
+	})
+
+	o := NewOrchestrator(nil, nil, nil, forecaster, nil, nil)
+	o.activeMarkets = []gamma.Market{testMarket("tok", "0.40")}
+	o.SetNewsProvider(&mockNewsProvider{headlines: []string{"Candidate leads in new poll"}})
+
+	ctx := context.Background()
+	if _, err := o.executeDataCollection(ctx); err != nil {
+		t.Fatalf("executeDataCollection failed: %v", err)
+	}
+	if _, err := o.executeForecasting(ctx); err != nil {
+		t.Fatalf("executeForecasting failed: %v", err)
+	}
+
+	if !strings.Contains(client.prompt, "Recent News:") {
+		t.Error("Prompt should contain a news section")
+	}
+	if !strings.Contains(client.prompt, "Candidate leads in new poll") {
+		t.Errorf("Prompt should contain the headline, got:\n%s", client.prompt)
+	}
+}
+
+func TestAdaptiveForecastInterval(t *testing.T) {
+	client := &promptCapturingClient{}
+	forecaster := agents.NewForecaster(&agents.ForecasterConfig{
+		Clients: map[agents.LLMProvider]agents.LLMClient{agents.ProviderClaude: client},
+	})
+
+	config := DefaultWorkflowConfig()
+	config.MinForecastInterval = 30 * time.Second
+	config.MaxForecastInterval = 10 * time.Minute
+	o := NewOrchestrator(config, nil, nil, forecaster, nil, nil)
+	o.activeMarkets = []gamma.Market{testMarket("volatile", "0.50"), testMarket("flat", "0.50")}
+
+	for _, p := range []string{"0.50", "0.55", "0.48", "0.56", "0.47"} {
+		o.recordPrice("volatile", decimal.RequireFromString(p))
+		o.recordPrice("flat", decimal.RequireFromString("0.50"))
+	}
+
+	ctx := context.Background()
+	if _, err := o.executeForecasting(ctx); err != nil {
+		t.Fatalf("executeForecasting failed: %v", err)
+	}
+
+	volatileDue, flatDue := o.nextForecast["volatile"], o.nextForecast["flat"]
+	if !volatileDue.Before(flatDue) {
+		t.Errorf("Expected volatile market due sooner: volatile=%v flat=%v", volatileDue, flatDue)
+	}
+
+	cfg := o.config
+	if got := flatDue.Sub(volatileDue); got < cfg.MaxForecastInterval-cfg.MinForecastInterval-time.Second {
+		t.Errorf("Expected intervals at the bounds, gap was %v", got)
+	}
+
+	// Neither market is due again yet, so a second pass forecasts nothing
+	data, _ := o.executeForecasting(ctx)
+	if n := data.(map[string]interface{})["markets_forecasted"]; n != 0 {
+		t.Errorf("Expected no markets due, forecasted %v", n)
+	}
+}
+
+func TestLiquidityGateSkipsWideSpread(t *testing.T) {
+	books := map[string]clob.OrderBookSummary{
+		"tight": {
+			Bids: []clob.PriceLevel{{Price: "0.49", Size: "500"}},
+			Asks: []clob.PriceLevel{{Price: "0.51", Size: "500"}},
+		},
+		"wide": {
+			Bids: []clob.PriceLevel{{Price: "0.30", Size: "500"}},
+			Asks: []clob.PriceLevel{{Price: "0.70", Size: "500"}},
+		},
+		"one-sided": {
+			Bids: []clob.PriceLevel{{Price: "0.49", Size: "500"}},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(books[r.URL.Query().Get("token_id")])
+	}))
+	defer server.Close()
+
+	client := &promptCapturingClient{}
+	forecaster := agents.NewForecaster(&agents.ForecasterConfig{
+		Clients: map[agents.LLMProvider]agents.LLMClient{agents.ProviderClaude: client},
+	})
+
+	config := DefaultWorkflowConfig()
+	o := NewOrchestrator(config, nil, clob.NewPublicClient(clob.WithCLOBBaseURL(server.URL)), forecaster, nil, nil)
+	o.activeMarkets = []gamma.Market{testMarket("tight", "0.50"), testMarket("wide", "0.50"), testMarket("one-sided", "0.50")}
+
+	// Gate disabled by default: nothing is skipped, one-sided books included
+	ctx := context.Background()
+	data, err := o.executeDataCollection(ctx)
+	if err != nil {
+		t.Fatalf("executeDataCollection failed: %v", err)
+	}
+	if skipped := data.(map[string]interface{})["markets_skipped"].(map[string]string); len(skipped) != 0 {
+		t.Errorf("Expected no skips with the gate disabled, got %v", skipped)
+	}
+
+	config.MinBookDepth = decimal.NewFromInt(100)
+	config.MaxBookSpreadBps = decimal.NewFromInt(500)
+	data, err = o.executeDataCollection(ctx)
+	if err != nil {
+		t.Fatalf("executeDataCollection failed: %v", err)
+	}
+	skipped := data.(map[string]interface{})["markets_skipped"].(map[string]string)
+	if !strings.Contains(skipped["wide"], "spread") {
+		t.Errorf("Expected wide market skipped for spread, got %v", skipped)
+	}
+	if _, ok := skipped["tight"]; ok {
+		t.Errorf("Tight market should pass the gate, got %q", skipped["tight"])
+	}
+	if skipped["one-sided"] != "one-sided book" {
+		t.Errorf("Expected one-sided market skipped, got %q", skipped["one-sided"])
+	}
+
+	if _, err := o.executeForecasting(ctx); err != nil {
+		t.Fatalf("executeForecasting failed: %v", err)
+	}
+	if _, ok := o.GetForecast("wide"); ok {
+		t.Error("Wide-spread market should not be forecast")
+	}
+	if _, ok := o.GetForecast("tight"); !ok {
+		t.Error("Tight market should be forecast")
+	}
+}
+
+func TestWhaleAlertsReportedOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"timestamp":1700000100,"conditionId":"cond-tok","type":"TRADE","usdcSize":25000,"side":"BUY","asset":"tok"},
+			{"timestamp":1700000050,"conditionId":"cond-tok","type":"TRADE","usdcSize":40,"side":"SELL","asset":"tok"}
+		]`))
+	}))
+	defer server.Close()
+
+	config := DefaultWorkflowConfig()
+	config.WhaleTradeUSDC = decimal.NewFromInt(10000)
+	o := NewOrchestrator(config, nil, nil, agents.NewForecaster(nil), nil, nil)
+	o.SetActivityClient(data.NewClient(data.WithBaseURL(server.URL)))
+	o.activeMarkets = []gamma.Market{testMarket("tok", "0.50")}
+
+	var alerts []data.Activity
+	o.OnWhaleAlert(func(a data.Activity) { alerts = append(alerts, a) })
+
+	for i := 0; i < 2; i++ {
+		if _, err := o.executeDataCollection(context.Background()); err != nil {
+			t.Fatalf("executeDataCollection failed: %v", err)
+		}
+	}
+
+	if len(alerts) != 1 {
+		t.Fatalf("Expected 1 whale alert across two cycles, got %d", len(alerts))
+	}
+	if alerts[0].USDCSize != 25000 || alerts[0].Side != data.SideBuy {
+		t.Errorf("Unexpected alert: %+v", alerts[0])
+	}
+}
+
+func TestStopDrainsInFlightCycle(t *testing.T) {
+	var calls int32
+	var finished atomic.Bool
+	entered := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first discovery (from Start) is fast; the RunOnce cycle stalls.
+		// finished is set inside the cycle, so it happens before the cycle
+		// releases cycleMu and Stop can return.
+		if atomic.AddInt32(&calls, 1) == 2 {
+			close(entered)
+			time.Sleep(100 * time.Millisecond)
+			finished.Store(true)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	config := DefaultWorkflowConfig()
+	config.DiscoveryInterval = time.Hour
+	config.ForecastInterval = time.Hour
+	config.MonitorInterval = time.Hour
+	o := NewOrchestrator(config, gamma.NewClient(gamma.WithBaseURL(server.URL)), nil, agents.NewForecaster(nil), nil, nil)
+
+	ctx := context.Background()
+	if err := o.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	go o.RunOnce(ctx)
+	<-entered
+
+	report := o.Stop()
+	if !finished.Load() {
+		t.Error("Stop returned before the in-flight cycle finished")
+	}
+	if report.TimedOut {
+		t.Error("Drain should not have timed out")
+	}
+
+	// A drain shorter than the cycle reports the timeout instead of blocking
+	o.config.DrainTimeout = 10 * time.Millisecond
+	atomic.StoreInt32(&calls, 0)
+	entered = make(chan struct{})
+	if err := o.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	go o.RunOnce(ctx)
+	<-entered
+	if report := o.Stop(); !report.TimedOut {
+		t.Error("Expected drain to time out")
+	}
+}
+
+// fixedPrice serves one mid price for every token and no books.
+type fixedPrice decimal.Decimal
+
+func (p fixedPrice) GetMidPrice(ctx context.Context, tokenID string) (decimal.Decimal, error) {
+	return decimal.Decimal(p), nil
+}
+
+func (p fixedPrice) GetOrderBook(ctx context.Context, tokenID string) (*book.OrderBook, error) {
+	return nil, errors.New("no book")
+}
+
+func TestMaxSignalNotionalClampsBeforePolicy(t *testing.T) {
+	price := decimal.RequireFromString("0.40")
+
+	config := DefaultWorkflowConfig()
+	config.MaxOrderSize = decimal.NewFromInt(10000) // $4000 at 0.40
+	config.MaxSignalNotional = decimal.NewFromInt(40)
+
+	engine := paper.NewEngine(&paper.SimulationConfig{
+		Mode:           paper.ModeSimple,
+		InitialBalance: decimal.NewFromInt(10000),
+	}, fixedPrice(price))
+
+	// Tight limits reject anything over $50, so the unclamped order would fail
+	o := NewOrchestrator(config, nil, nil, agents.NewForecaster(nil),
+		policy.NewPolicyEngine(policy.TightRiskLimits()), engine)
+	o.signals = []*agents.TradingSignal{{
+		Signal:       agents.SignalBuy,
+		TokenID:      "tok",
+		Side:         "YES",
+		CurrentPrice: price,
+	}}
+
+	if _, err := o.executeOrderExecution(context.Background()); err != nil {
+		t.Fatalf("executeOrderExecution failed: %v", err)
+	}
+
+	pos, ok := engine.GetPosition("tok")
+	if !ok {
+		t.Fatal("Expected the clamped order to pass policy and fill")
+	}
+	if want := decimal.NewFromInt(100); !pos.Size.Equal(want) {
+		t.Errorf("Expected %s shares ($40 / 0.40), got %s", want, pos.Size)
+	}
+}
+
+func TestDisabledMarketSkipsExecution(t *testing.T) {
+	engine := paper.NewEngine(&paper.SimulationConfig{
+		Mode:           paper.ModeSimple,
+		InitialBalance: decimal.NewFromInt(10000),
+	}, fixedPrice(decimal.RequireFromString("0.50")))
+	o := NewOrchestrator(DefaultWorkflowConfig(), nil, nil, agents.NewForecaster(nil), nil, engine)
+	o.activeMarkets = []gamma.Market{testMarket("tok-a", "0.50"), testMarket("tok-b", "0.50")}
+	o.signals = []*agents.TradingSignal{
+		{Signal: agents.SignalBuy, TokenID: "tok-a", Side: "YES", CurrentPrice: decimal.RequireFromString("0.50")},
+		{Signal: agents.SignalBuy, TokenID: "tok-b", Side: "YES", CurrentPrice: decimal.RequireFromString("0.50")},
+	}
+
+	o.DisableMarket("cond-tok-a")
+	if got := o.DisabledMarkets(); len(got) != 1 || got[0] != "cond-tok-a" {
+		t.Fatalf("DisabledMarkets = %v", got)
+	}
+	if _, err := o.executeOrderExecution(context.Background()); err != nil {
+		t.Fatalf("executeOrderExecution failed: %v", err)
+	}
+	if _, ok := engine.GetPosition("tok-a"); ok {
+		t.Error("Expected no order for the disabled market")
+	}
+	if _, ok := engine.GetPosition("tok-b"); !ok {
+		t.Error("Expected the enabled market to trade")
+	}
+
+	o.EnableMarket("cond-tok-a")
+	if _, err := o.executeOrderExecution(context.Background()); err != nil {
+		t.Fatalf("executeOrderExecution failed: %v", err)
+	}
+	if _, ok := engine.GetPosition("tok-a"); !ok {
+		t.Error("Expected the re-enabled market to trade")
+	}
+}
+
+func TestMinHoldingPeriodSuppressesReversal(t *testing.T) {
+	config := DefaultWorkflowConfig()
+	config.MinHoldingPeriod = time.Hour
+	config.HoldingStopLoss = decimal.NewFromFloat(0.2)
+
+	engine := paper.NewEngine(&paper.SimulationConfig{
+		Mode:           paper.ModeSimple,
+		InitialBalance: decimal.NewFromInt(10000),
+	}, fixedPrice(decimal.RequireFromString("0.50")))
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	engine.SetClock(paper.ClockFunc(func() time.Time { return now }))
+	o := NewOrchestrator(config, nil, nil, agents.NewForecaster(nil), nil, engine)
+
+	execute := func(side, yesPrice string) {
+		o.signals = []*agents.TradingSignal{{
+			Signal:       agents.SignalBuy,
+			TokenID:      "tok",
+			Side:         side,
+			CurrentPrice: decimal.RequireFromString(yesPrice),
+		}}
+		if _, err := o.executeOrderExecution(context.Background()); err != nil {
+			t.Fatalf("executeOrderExecution failed: %v", err)
+		}
+	}
+
+	execute("YES", "0.50")
+	if trades := engine.GetStats().TotalTrades; trades != 1 {
+		t.Fatalf("Expected the opening order to fill, got %d trades", trades)
+	}
+
+	// Reversal inside the hold, price down only 10%: suppressed
+	execute("NO", "0.45")
+	if trades := engine.GetStats().TotalTrades; trades != 1 {
+		t.Fatalf("Expected the early reversal to be suppressed, got %d trades", trades)
+	}
+
+	// Same direction still trades
+	execute("YES", "0.45")
+	if trades := engine.GetStats().TotalTrades; trades != 2 {
+		t.Fatalf("Expected an add in the held direction to fill, got %d trades", trades)
+	}
+
+	// Down 30%: the stop-loss lifts the hold
+	execute("NO", "0.35")
+	if trades := engine.GetStats().TotalTrades; trades != 3 {
+		t.Fatalf("Expected the stop-loss to allow the reversal, got %d trades", trades)
+	}
+
+	// Back to YES once the engine's clock has moved past the hold
+	now = now.Add(2 * time.Hour)
+	execute("YES", "0.36")
+	if trades := engine.GetStats().TotalTrades; trades != 4 {
+		t.Fatalf("Expected the reversal after the hold to fill, got %d trades", trades)
+	}
+}
+
+func TestShadowModeNeverPostsLive(t *testing.T) {
+	var posted int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/order" {
+			atomic.AddInt32(&posted, 1)
+			w.Write([]byte(`{"success":true,"orderID":"live-1"}`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	price := decimal.RequireFromString("0.40")
+	run := func(shadow bool) *Orchestrator {
+		live, err := clob.NewClient("0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80",
+			clob.WithCLOBBaseURL(server.URL),
+			clob.WithCredentials(&clob.APICredentials{APIKey: "k", Secret: "c2VjcmV0", Passphrase: "p"}),
+		)
+		if err != nil {
+			t.Fatalf("NewClient failed: %v", err)
+		}
+		engine := paper.NewEngine(&paper.SimulationConfig{
+			Mode:           paper.ModeSimple,
+			InitialBalance: decimal.NewFromInt(1000),
+		}, fixedPrice(price))
+
+		config := DefaultWorkflowConfig()
+		config.UsePaperTrade = false
+		config.ShadowMode = shadow
+
+		o := NewOrchestrator(config, nil, live, agents.NewForecaster(nil), nil, engine)
+		o.signals = []*agents.TradingSignal{{
+			Signal:       agents.SignalBuy,
+			TokenID:      "1001", // Signed orders need a numeric token ID
+			Side:         "YES",
+			CurrentPrice: price,
+		}}
+		if _, err := o.executeOrderExecution(context.Background()); err != nil {
+			t.Fatalf("executeOrderExecution failed: %v", err)
+		}
+		return o
+	}
+
+	// Control: the same setup trades live without shadow mode
+	run(false)
+	if got := atomic.SwapInt32(&posted, 0); got != 1 {
+		t.Fatalf("Expected the control run to post 1 live order, got %d", got)
+	}
+
+	o := run(true)
+	if got := atomic.LoadInt32(&posted); got != 0 {
+		t.Errorf("Expected no live orders in shadow mode, got %d", got)
+	}
+	if _, ok := o.paperEngine.GetPosition("1001"); !ok {
+		t.Error("Expected the shadow order to fill in paper")
+	}
+
+	shadow := o.GetStatus().Shadow
+	if shadow == nil || shadow.Orders != 1 {
+		t.Fatalf("Expected a shadow report with 1 order, got %+v", shadow)
+	}
+	if !shadow.Baseline.Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("Expected baseline 1000, got %s", shadow.Baseline)
+	}
+}
+
+func TestSnapshotConsistentUnderMutation(t *testing.T) {
+	// Discovery alternates between one and two markets
+	var discoveries int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		markets := []gamma.Market{testMarket("1001", "0.40")}
+		if atomic.AddInt32(&discoveries, 1)%2 == 0 {
+			markets = append(markets, testMarket("1002", "0.30"))
+		}
+		body, _ := json.Marshal(markets)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	forecaster := agents.NewForecaster(&agents.ForecasterConfig{
+		Clients: map[agents.LLMProvider]agents.LLMClient{agents.ProviderClaude: &promptCapturingClient{}},
+	})
+	config := DefaultWorkflowConfig()
+	config.MinVolume = decimal.Zero
+	gammaClient := gamma.NewClient(gamma.WithBaseURL(server.URL), gamma.WithRateLimit(1e6, 1000))
+	o := NewOrchestrator(config, gammaClient, nil, forecaster, nil, nil)
+
+	// Discovery runs on its own loop, concurrently with full cycles
+	ctx := context.Background()
+	var writers sync.WaitGroup
+	writers.Add(2)
+	go func() {
+		defer writers.Done()
+		for i := 0; i < 10; i++ {
+			o.executeMarketDiscovery(ctx)
+		}
+	}()
+	go func() {
+		defer writers.Done()
+		for i := 0; i < 10; i++ {
+			o.RunOnce(ctx)
+		}
+	}()
+
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				snap := o.Snapshot()
+				if snap.Status.ActiveMarkets != len(snap.ActiveMarkets) ||
+					snap.Status.Forecasts != len(snap.Forecasts) ||
+					snap.Status.Signals != len(snap.Signals) {
+					t.Errorf("Inconsistent snapshot: status %+v with %d markets, %d forecasts, %d signals",
+						snap.Status, len(snap.ActiveMarkets), len(snap.Forecasts), len(snap.Signals))
+					return
+				}
+			}
+		}()
+	}
+
+	writers.Wait()
+	close(done)
+	readers.Wait()
+
+	if snap := o.Snapshot(); len(snap.Forecasts) == 0 || len(snap.Signals) == 0 {
+		t.Errorf("Expected cycles to produce forecasts and signals, got %d and %d", len(snap.Forecasts), len(snap.Signals))
+	}
+}
+
+func TestDiscoveryPrunesDepartedMarketState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := json.Marshal([]gamma.Market{testMarket("1001", "0.40")})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	config := DefaultWorkflowConfig()
+	config.MinVolume = decimal.Zero
+	o := NewOrchestrator(config, gamma.NewClient(gamma.WithBaseURL(server.URL)), nil, nil, nil, nil)
+	for _, tokenID := range []string{"1001", "gone"} {
+		o.refPrices[tokenID] = decimal.NewFromFloat(0.4)
+		o.bookDepth[tokenID] = decimal.NewFromInt(100)
+	}
+
+	if _, err := o.executeMarketDiscovery(context.Background()); err != nil {
+		t.Fatalf("executeMarketDiscovery failed: %v", err)
+	}
+
+	if _, ok := o.refPrices["gone"]; ok {
+		t.Error("Expected reference price of a departed market pruned")
+	}
+	if _, ok := o.bookDepth["gone"]; ok {
+		t.Error("Expected book depth of a departed market pruned")
+	}
+	if _, ok := o.refPrices["1001"]; !ok {
+		t.Error("Active market's reference price should be kept")
+	}
+	if _, ok := o.bookDepth["1001"]; !ok {
+		t.Error("Active market's book depth should be kept")
+	}
+}
+
+func TestDiscoveryRotationPrunesAllMarketState(t *testing.T) {
+	current := "1001"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := json.Marshal([]gamma.Market{testMarket(current, "0.40")})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	config := DefaultWorkflowConfig()
+	config.MinVolume = decimal.Zero
+	o := NewOrchestrator(config, gamma.NewClient(gamma.WithBaseURL(server.URL)), nil, nil, nil, nil)
+	ctx := context.Background()
+
+	if _, err := o.executeMarketDiscovery(ctx); err != nil {
+		t.Fatalf("executeMarketDiscovery failed: %v", err)
+	}
+	o.mu.Lock()
+	o.forecasts["1001"] = &agents.EnsembleForecast{TokenID: "1001"}
+	o.news["1001"] = []string{"headline"}
+	o.priceHistory["1001"] = []decimal.Decimal{decimal.NewFromFloat(0.4)}
+	o.nextForecast["1001"] = time.Now()
+	o.illiquid["1001"] = "thin book"
+	o.refPrices["1001"] = decimal.NewFromFloat(0.4)
+	o.bookDepth["1001"] = decimal.NewFromInt(100)
+	o.lastEmitted["1001"] = &agents.TradingSignal{TokenID: "1001"}
+	o.holdings["1001"] = holding{side: "YES", since: time.Now()}
+	o.lastActivity["cond-1001"] = 1700000000
+	o.mu.Unlock()
+
+	current = "2002"
+	if _, err := o.executeMarketDiscovery(ctx); err != nil {
+		t.Fatalf("executeMarketDiscovery failed: %v", err)
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	sizes := map[string]int{
+		"forecasts":    len(o.forecasts),
+		"news":         len(o.news),
+		"priceHistory": len(o.priceHistory),
+		"nextForecast": len(o.nextForecast),
+		"illiquid":     len(o.illiquid),
+		"refPrices":    len(o.refPrices),
+		"bookDepth":    len(o.bookDepth),
+		"lastEmitted":  len(o.lastEmitted),
+		"holdings":     len(o.holdings),
+		"lastActivity": len(o.lastActivity),
+	}
+	for name, n := range sizes {
+		if n != 0 {
+			t.Errorf("Expected %s of the rotated-out market pruned, %d entries left", name, n)
+		}
+	}
+}
+
+func TestDiscoveryAssignsMarketCategories(t *testing.T) {
+	crypto := testMarket("1001", "0.40")
+	crypto.Tags = []gamma.Tag{{Label: "Crypto"}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := json.Marshal([]gamma.Market{crypto, testMarket("2002", "0.40")})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	limits := policy.DefaultRiskLimits()
+	limits.MaxCategoryExposure = map[string]decimal.Decimal{"Crypto": decimal.NewFromFloat(0.01)}
+	policyEngine := policy.NewPolicyEngine(limits)
+
+	config := DefaultWorkflowConfig()
+	config.MinVolume = decimal.Zero
+	o := NewOrchestrator(config, gamma.NewClient(gamma.WithBaseURL(server.URL)), nil, nil, policyEngine, nil)
+	if _, err := o.executeMarketDiscovery(context.Background()); err != nil {
+		t.Fatalf("executeMarketDiscovery failed: %v", err)
+	}
+
+	size := limits.MaxTotalExposure.Mul(decimal.NewFromFloat(0.02))
+	for _, tokenID := range []string{"1001", "1001-no"} {
+		err := policyEngine.CheckOrder(tokenID, size, decimal.NewFromFloat(0.4), true)
+		if err == nil || !strings.Contains(err.Error(), "Crypto exposure") {
+			t.Errorf("Expected %s capped as Crypto, got %v", tokenID, err)
+		}
+	}
+	if err := policyEngine.CheckOrder("2002", size, decimal.NewFromFloat(0.4), true); err != nil {
+		t.Errorf("Uncategorized market should not be capped: %v", err)
+	}
+}
+
+func TestRunOnceRecordsStageSpans(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := json.Marshal([]gamma.Market{testMarket("1001", "0.40")})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	forecaster := agents.NewForecaster(&agents.ForecasterConfig{
+		Clients: map[agents.LLMProvider]agents.LLMClient{agents.ProviderClaude: &promptCapturingClient{}},
+	})
+	config := DefaultWorkflowConfig()
+	config.MinVolume = decimal.Zero
+	o := NewOrchestrator(config, gamma.NewClient(gamma.WithBaseURL(server.URL)), nil, forecaster, nil, nil)
+
+	if _, err := o.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, s := range exporter.GetSpans() {
+		spans[s.Name] = s
+	}
+	cycle, ok := spans["workflow.cycle"]
+	if !ok {
+		t.Fatal("Expected a workflow.cycle span")
+	}
+	for _, stage := range []Stage{StageMarketDiscovery, StageDataCollection, StageForecasting, StageSignalGen, StageRiskCheck, StageOrderExecution} {
+		s, ok := spans["stage."+string(stage)]
+		if !ok {
+			t.Errorf("Missing span for stage %s", stage)
+			continue
+		}
+		if s.Parent.SpanID() != cycle.SpanContext.SpanID() {
+			t.Errorf("Stage %s span is not a child of the cycle", stage)
+		}
+	}
+
+	llm, ok := spans["llm.forecast"]
+	if !ok {
+		t.Fatal("Expected an llm.forecast span")
+	}
+	if llm.SpanContext.TraceID() != cycle.SpanContext.TraceID() || !hasAttr(llm, attribute.String("market.token_id", "1001")) {
+		t.Errorf("Unexpected llm.forecast span: %+v", llm)
+	}
+	if s, ok := spans["signal.evaluate"]; !ok || !hasAttrKey(s, "signal.edge_bps") {
+		t.Errorf("Expected a signal.evaluate span with edge, got %+v", s)
+	}
+}
+
+// hasAttr reports whether span carries attr.
+func hasAttr(span tracetest.SpanStub, attr attribute.KeyValue) bool {
+	for _, a := range span.Attributes {
+		if a == attr {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAttrKey reports whether span carries an attribute named key.
+func hasAttrKey(span tracetest.SpanStub, key attribute.Key) bool {
+	for _, a := range span.Attributes {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMarketableLimitSkipsThinBook(t *testing.T) {
+	price := decimal.RequireFromString("0.40")
+	books := map[string]clob.OrderBookSummary{
+		// 100 shares walk to 0.50, far past the 2% tolerance
+		"thin": {
+			Bids: []clob.PriceLevel{{Price: "0.39", Size: "500"}},
+			Asks: []clob.PriceLevel{{Price: "0.40", Size: "5"}, {Price: "0.50", Size: "1000"}},
+		},
+		"deep": {
+			Bids: []clob.PriceLevel{{Price: "0.39", Size: "500"}},
+			Asks: []clob.PriceLevel{{Price: "0.40", Size: "1000"}},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(books[r.URL.Query().Get("token_id")])
+	}))
+	defer server.Close()
+
+	config := DefaultWorkflowConfig()
+	config.MaxOrderSize = decimal.NewFromInt(10000)
+	config.MaxSignalNotional = decimal.NewFromInt(40) // 100 shares at 0.40
+	config.MarketableLimits = true
+
+	engine := paper.NewEngine(&paper.SimulationConfig{
+		Mode:           paper.ModeSimple,
+		InitialBalance: decimal.NewFromInt(10000),
+	}, fixedPrice(price))
+
+	o := NewOrchestrator(config, nil, clob.NewPublicClient(clob.WithCLOBBaseURL(server.URL)),
+		agents.NewForecaster(nil), policy.NewPolicyEngine(policy.DefaultRiskLimits()), engine)
+	o.signals = []*agents.TradingSignal{
+		{Signal: agents.SignalBuy, TokenID: "thin", Side: "YES", CurrentPrice: price},
+		{Signal: agents.SignalBuy, TokenID: "deep", Side: "YES", CurrentPrice: price},
+	}
+
+	if _, err := o.executeOrderExecution(context.Background()); err != nil {
+		t.Fatalf("executeOrderExecution failed: %v", err)
+	}
+
+	if _, ok := engine.GetPosition("thin"); ok {
+		t.Error("Thin-book order should not be sent")
+	}
+	for _, order := range engine.GetOpenOrders() {
+		if order.TokenID == "thin" {
+			t.Error("Thin-book order should not be sent")
+		}
+	}
+
+	pos, ok := engine.GetPosition("deep")
+	if !ok {
+		t.Fatal("Expected the deep-book order to fill")
+	}
+	if want := decimal.NewFromInt(100); !pos.Size.Equal(want) {
+		t.Errorf("Expected %s shares, got %s", want, pos.Size)
+	}
+}
+
+func TestMarketableLimitPrice(t *testing.T) {
+	o := NewOrchestrator(DefaultWorkflowConfig(), nil, nil, agents.NewForecaster(nil),
+		policy.NewPolicyEngine(policy.DefaultRiskLimits()), nil)
+
+	expected := decimal.RequireFromString("0.55")
+	tests := []struct {
+		isBuy bool
+		want  string
+	}{
+		{true, "0.56"},  // 0.561 floored inside tolerance
+		{false, "0.54"}, // 0.539 ceiled inside tolerance
+	}
+	for _, tt := range tests {
+		got, err := o.marketableLimit(context.Background(), "tok", tt.isBuy, expected, decimal.NewFromInt(10))
+		if err != nil {
+			t.Fatalf("marketableLimit failed: %v", err)
+		}
+		if !got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("isBuy=%v: expected %s, got %s", tt.isBuy, tt.want, got)
+		}
+	}
+}
+
+func TestMarketableLimitNeedsSizeInsideLimit(t *testing.T) {
+	// 20 shares average 0.505, inside the 2% tolerance, but the 0.51 limit
+	// reaches only the first 15 and the rest would rest on the book
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(clob.OrderBookSummary{
+			Bids: []clob.PriceLevel{{Price: "0.49", Size: "100"}},
+			Asks: []clob.PriceLevel{{Price: "0.50", Size: "15"}, {Price: "0.52", Size: "100"}},
+		})
+	}))
+	defer server.Close()
+
+	o := NewOrchestrator(DefaultWorkflowConfig(), nil, clob.NewPublicClient(clob.WithCLOBBaseURL(server.URL)),
+		agents.NewForecaster(nil), policy.NewPolicyEngine(policy.DefaultRiskLimits()), nil)
+
+	expected := decimal.RequireFromString("0.50")
+	if _, err := o.marketableLimit(context.Background(), "tok", true, expected, decimal.NewFromInt(20)); err == nil {
+		t.Error("Expected an error when the limit can't reach the full size")
+	}
+	if limit, err := o.marketableLimit(context.Background(), "tok", true, expected, decimal.NewFromInt(15)); err != nil || !limit.Equal(decimal.RequireFromString("0.51")) {
+		t.Errorf("Expected a 0.51 limit for 15 shares, got %s (%v)", limit, err)
+	}
+}
+
+func TestExploreEpsilonPicksOffListMarkets(t *testing.T) {
+	var ranked []gamma.Market
+	for _, token := range []string{"3001", "3002", "3003", "3004", "3005"} {
+		ranked = append(ranked, testMarket(token, "0.50"))
+	}
+	rng := rand.New(rand.NewPCG(42, 0))
+
+	const runs = 1000
+	explored := 0
+	for i := 0; i < runs; i++ {
+		selected, pick := explore(ranked, 2, 0.25, rng)
+		if len(selected) != 2 || selected[0].YesTokenID() != "3001" {
+			t.Fatalf("expected the top market to keep its slot, got %+v", selected)
+		}
+		if pick == nil {
+			if selected[1].YesTokenID() != "3002" {
+				t.Fatalf("expected the ranked market without exploration, got %s", selected[1].YesTokenID())
+			}
+			continue
+		}
+		if selected[1].YesTokenID() != pick.YesTokenID() || pick.YesTokenID() <= "3002" {
+			t.Fatalf("expected an off-list market in the last slot, got %s", selected[1].YesTokenID())
+		}
+		explored++
+	}
+	if rate := float64(explored) / runs; rate < 0.22 || rate > 0.28 {
+		t.Errorf("explored %d of %d discoveries (%.3f), want about 0.25", explored, runs, rate)
+	}
+	if ranked[1].YesTokenID() != "3002" {
+		t.Error("explore modified the ranking")
+	}
+}
+
+func TestDiscoveryReportsExploredMarket(t *testing.T) {
+	discovered := []gamma.Market{testMarket("3001", "0.50"), testMarket("3002", "0.50")}
+	for i := range discovered {
+		discovered[i].Volume = 1e5
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(discovered)
+	}))
+	defer server.Close()
+
+	config := DefaultWorkflowConfig()
+	config.MaxMarkets = 1
+	config.ExploreEpsilon = 1
+	o := NewOrchestrator(config, gamma.NewClient(gamma.WithBaseURL(server.URL)), nil, agents.NewForecaster(nil), nil, nil)
+
+	result, err := o.executeMarketDiscovery(context.Background())
+	if err != nil {
+		t.Fatalf("discovery failed: %v", err)
+	}
+	if len(o.activeMarkets) != 1 || o.activeMarkets[0].YesTokenID() != "3002" {
+		t.Fatalf("expected the off-list market, got %+v", o.activeMarkets)
+	}
+	if explored := result.(map[string]interface{})["explored"]; explored != "cond-3002" {
+		t.Errorf("explored = %v, want cond-3002", explored)
+	}
+}
+
+func TestEdgeSelectionPrefersEdgeOverVolume(t *testing.T) {
+	// Discovery returns the high-volume, low-edge market first
+	busy := testMarket("2001", "0.50")
+	busy.Volume = 1e6
+	quiet := testMarket("2002", "0.50")
+	quiet.Volume = 2e4
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := json.Marshal([]gamma.Market{busy, quiet})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	config := DefaultWorkflowConfig()
+	config.MaxMarkets = 1
+	config.MarketSelection = SelectionEdge
+	o := NewOrchestrator(config, gamma.NewClient(gamma.WithBaseURL(server.URL)), nil, agents.NewForecaster(nil), nil, nil)
+	o.forecasts["2001"] = &agents.EnsembleForecast{Probability: decimal.NewFromFloat(0.51)} // 100 bps
+	o.forecasts["2002"] = &agents.EnsembleForecast{Probability: decimal.NewFromFloat(0.70)} // 2000 bps
+
+	if _, err := o.executeMarketDiscovery(context.Background()); err != nil {
+		t.Fatalf("discovery failed: %v", err)
+	}
+	if len(o.activeMarkets) != 1 || o.activeMarkets[0].YesTokenID() != "2002" {
+		t.Fatalf("expected the higher-edge market, got %+v", o.activeMarkets)
+	}
+
+	// By volume the busy market wins
+	o.config.MarketSelection = SelectionVolume
+	if _, err := o.executeMarketDiscovery(context.Background()); err != nil {
+		t.Fatalf("discovery failed: %v", err)
+	}
+	if o.activeMarkets[0].YesTokenID() != "2001" {
+		t.Errorf("expected the higher-volume market, got %s", o.activeMarkets[0].YesTokenID())
+	}
+}
@@ -0,0 +1,147 @@
+package orchestrator
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/gamma"
+)
+
+// MarketSelection chooses which discovered markets fill the MaxMarkets slots.
+type MarketSelection string
+
+const (
+	// SelectionDiscovery keeps markets in the order discovery returned them.
+	SelectionDiscovery MarketSelection = ""
+	// SelectionVolume keeps the highest-volume markets.
+	SelectionVolume MarketSelection = "volume"
+	// SelectionEdge keeps the markets with the largest edge between the cached
+	// forecast and the current YES price. Markets not yet forecast are assumed
+	// to sit exactly at MinEdgeBps, so they still displace known low-edge
+	// markets and get a first forecast.
+	SelectionEdge MarketSelection = "edge"
+	// SelectionDiversified takes markets round-robin across categories (first
+	// tag label), highest volume first within each, so no single category
+	// uses up the forecast budget.
+	SelectionDiversified MarketSelection = "diversified"
+)
+
+// ParseMarketSelection validates a selection policy name. Empty selects
+// SelectionDiscovery.
+func ParseMarketSelection(s string) (MarketSelection, error) {
+	switch policy := MarketSelection(s); policy {
+	case SelectionDiscovery, SelectionVolume, SelectionEdge, SelectionDiversified:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown market selection %q", s)
+	}
+}
+
+// selectMarkets returns up to n of markets according to policy. edgeBps
+// reports a market's cached edge, false if it has no forecast yet; it is
+// only called for SelectionEdge. Ties keep discovery order.
+func selectMarkets(markets []gamma.Market, n int, policy MarketSelection, edgeBps func(gamma.Market) (float64, bool), defaultEdgeBps float64) []gamma.Market {
+	ranked := append([]gamma.Market(nil), markets...)
+
+	switch policy {
+	case SelectionVolume:
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return ranked[i].Volume.Float64() > ranked[j].Volume.Float64()
+		})
+
+	case SelectionEdge:
+		type scored struct {
+			market gamma.Market
+			edge   float64
+		}
+		byEdge := make([]scored, len(ranked))
+		for i, m := range ranked {
+			byEdge[i] = scored{market: m, edge: defaultEdgeBps}
+			if edge, ok := edgeBps(m); ok {
+				byEdge[i].edge = edge
+			}
+		}
+		sort.SliceStable(byEdge, func(i, j int) bool {
+			return byEdge[i].edge > byEdge[j].edge
+		})
+		for i, s := range byEdge {
+			ranked[i] = s.market
+		}
+
+	case SelectionDiversified:
+		ranked = diversify(ranked)
+	}
+
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// explore cuts ranked to n and, with probability epsilon, gives the last
+// slot to a market drawn uniformly from those past the cut. It returns the
+// explored market, or nil if it kept the ranking.
+func explore(ranked []gamma.Market, n int, epsilon float64, rng *rand.Rand) ([]gamma.Market, *gamma.Market) {
+	if len(ranked) <= n {
+		return ranked, nil
+	}
+	selected := ranked[:n:n]
+	if n == 0 || epsilon <= 0 || rng.Float64() >= epsilon {
+		return selected, nil
+	}
+	offList := ranked[n:]
+	pick := offList[rng.IntN(len(offList))]
+	selected = append(selected[:n-1:n-1], pick)
+	return selected, &pick
+}
+
+// diversify interleaves markets across categories, highest volume first
+// within each category. Categories take turns in order of their top market's
+// volume.
+func diversify(markets []gamma.Market) []gamma.Market {
+	sort.SliceStable(markets, func(i, j int) bool {
+		return markets[i].Volume.Float64() > markets[j].Volume.Float64()
+	})
+
+	var categories []string
+	groups := make(map[string][]gamma.Market)
+	for _, m := range markets {
+		c := marketCategory(m)
+		if _, ok := groups[c]; !ok {
+			categories = append(categories, c)
+		}
+		groups[c] = append(groups[c], m)
+	}
+
+	out := make([]gamma.Market, 0, len(markets))
+	for round := 0; len(out) < len(markets); round++ {
+		for _, c := range categories {
+			if round < len(groups[c]) {
+				out = append(out, groups[c][round])
+			}
+		}
+	}
+	return out
+}
+
+// marketCategory is a market's first tag label, or "" if untagged.
+func marketCategory(m gamma.Market) string {
+	if len(m.Tags) == 0 {
+		return ""
+	}
+	return m.Tags[0].Label
+}
+
+// edgeBps is the absolute edge in bps between the cached forecast for the
+// market's YES token and its current YES price.
+func (o *Orchestrator) edgeBps(m gamma.Market) (float64, bool) {
+	o.mu.RLock()
+	forecast, ok := o.forecasts[m.YesTokenID()]
+	o.mu.RUnlock()
+	if !ok || forecast == nil {
+		return 0, false
+	}
+	return math.Abs(forecast.Probability.InexactFloat64()-m.YesPrice()) * 10000, true
+}
@@ -0,0 +1,85 @@
+package orchestrator
+
+import (
+	"math"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/agents"
+
+	"github.com/shopspring/decimal"
+)
+
+// SizingCurveKind selects how a SizingCurve maps edge to size.
+type SizingCurveKind string
+
+const (
+	// SizingCurveFixed always trades MaxOrderSize.
+	SizingCurveFixed SizingCurveKind = "fixed"
+	// SizingCurveStep trades the fraction of the highest step the edge reaches.
+	SizingCurveStep SizingCurveKind = "step"
+	// SizingCurveLinear scales from zero at zero edge to full size at FullEdgeBps.
+	SizingCurveLinear SizingCurveKind = "linear"
+	// SizingCurveLogistic follows a logistic centered on MidpointBps, capped at
+	// full size, so marginal edges trade small and strong edges near full.
+	SizingCurveLogistic SizingCurveKind = "logistic"
+)
+
+// SizingStep is one threshold of a step curve.
+type SizingStep struct {
+	EdgeBps  float64
+	Fraction float64 // Of MaxOrderSize
+}
+
+// SizingCurve maps a signal's edge (bps) to a fraction of MaxOrderSize.
+type SizingCurve struct {
+	Kind SizingCurveKind
+
+	FullEdgeBps float64      // Linear
+	MidpointBps float64      // Logistic: edge that trades half size
+	Steepness   float64      // Logistic: slope per bps, e.g. 0.02
+	Steps       []SizingStep // Step: ascending by EdgeBps
+}
+
+// Fraction returns the share of MaxOrderSize to trade at edgeBps, in [0, 1].
+func (c *SizingCurve) Fraction(edgeBps float64) float64 {
+	if c == nil {
+		return 1
+	}
+
+	var f float64
+	switch c.Kind {
+	case SizingCurveStep:
+		for _, step := range c.Steps {
+			if edgeBps >= step.EdgeBps {
+				f = step.Fraction
+			}
+		}
+	case SizingCurveLinear:
+		if c.FullEdgeBps <= 0 {
+			return 1
+		}
+		f = edgeBps / c.FullEdgeBps
+	case SizingCurveLogistic:
+		f = 1 / (1 + math.Exp(-c.Steepness*(edgeBps-c.MidpointBps)))
+	default:
+		return 1
+	}
+	return math.Min(math.Max(f, 0), 1)
+}
+
+// orderSize sizes an order for signal from the configured curve, rounded to
+// cents of shares, then clamps it to MaxSignalNotional. clamped reports
+// whether the cap applied.
+func (o *Orchestrator) orderSize(signal *agents.TradingSignal) (size decimal.Decimal, clamped bool) {
+	fraction := o.config.SizingCurve.Fraction(signal.EdgeBps.InexactFloat64())
+	size = o.config.MaxOrderSize.Mul(decimal.NewFromFloat(fraction)).Round(2)
+
+	limit := o.config.MaxSignalNotional
+	price := signal.CurrentPrice
+	if signal.Side == "NO" {
+		price = decimal.NewFromInt(1).Sub(price)
+	}
+	if !limit.IsPositive() || !price.IsPositive() || size.Mul(price).LessThanOrEqual(limit) {
+		return size, false
+	}
+	return limit.Div(price).RoundDown(2), true
+}
@@ -0,0 +1,75 @@
+package orchestrator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/agents"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestSizingCurveFraction(t *testing.T) {
+	logistic := &SizingCurve{Kind: SizingCurveLogistic, MidpointBps: 200, Steepness: 0.02}
+	step := &SizingCurve{Kind: SizingCurveStep, Steps: []SizingStep{
+		{EdgeBps: 100, Fraction: 0.25},
+		{EdgeBps: 300, Fraction: 0.5},
+		{EdgeBps: 600, Fraction: 1},
+	}}
+	linear := &SizingCurve{Kind: SizingCurveLinear, FullEdgeBps: 400}
+
+	tests := []struct {
+		name    string
+		curve   *SizingCurve
+		edgeBps float64
+		want    float64
+	}{
+		{"nil trades full size", nil, 50, 1},
+		{"fixed", &SizingCurve{Kind: SizingCurveFixed}, 50, 1},
+		{"step below first", step, 50, 0},
+		{"step at threshold", step, 100, 0.25},
+		{"step between", step, 450, 0.5},
+		{"step top", step, 900, 1},
+		{"linear partial", linear, 100, 0.25},
+		{"linear capped", linear, 800, 1},
+		{"linear negative edge", linear, -100, 0},
+		{"linear without full edge", &SizingCurve{Kind: SizingCurveLinear}, 100, 1},
+		{"logistic midpoint", logistic, 200, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.curve.Fraction(tt.edgeBps); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Fraction(%v) = %v, want %v", tt.edgeBps, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogisticSizingSmallVsLargeEdge(t *testing.T) {
+	config := DefaultWorkflowConfig()
+	config.MaxOrderSize = decimal.NewFromInt(100)
+	config.SizingCurve = &SizingCurve{Kind: SizingCurveLogistic, MidpointBps: 200, Steepness: 0.02}
+	o := NewOrchestrator(config, nil, nil, nil, nil, nil)
+
+	signal := func(edgeBps int64) *agents.TradingSignal {
+		return &agents.TradingSignal{
+			Side:         "YES",
+			CurrentPrice: decimal.NewFromFloat(0.5),
+			EdgeBps:      decimal.NewFromInt(edgeBps),
+		}
+	}
+
+	small, _ := o.orderSize(signal(50))
+	large, _ := o.orderSize(signal(500))
+
+	// 1/(1+e^3) ~ 4.7% and 1/(1+e^-6) ~ 99.8% of MaxOrderSize
+	if !small.Equal(decimal.RequireFromString("4.74")) {
+		t.Errorf("Expected 50 bps edge to size 4.74, got %s", small)
+	}
+	if !large.Equal(decimal.RequireFromString("99.75")) {
+		t.Errorf("Expected 500 bps edge to size 99.75, got %s", large)
+	}
+	if !large.GreaterThan(small.Mul(decimal.NewFromInt(10))) {
+		t.Errorf("Expected 500 bps size well above 50 bps size, got %s vs %s", large, small)
+	}
+}
@@ -3,6 +3,11 @@ package paper
 import (
 	"context"
 	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,11 +28,15 @@ type Engine struct {
 	config   *SimulationConfig
 	account  *Account
 	provider PriceProvider
+	clock    Clock
 
 	mu       sync.RWMutex
 	orderSeq int64
 	tradeSeq int64
 
+	// Complementary YES/NO token pairs, keyed by market
+	pairs map[string]tokenPair
+
 	// Callbacks
 	onOrder func(*Order)
 	onTrade func(*Trade)
@@ -43,6 +52,8 @@ func NewEngine(config *SimulationConfig, provider PriceProvider) *Engine {
 	return &Engine{
 		config:   config,
 		provider: provider,
+		clock:    realClock{},
+		pairs:    make(map[string]tokenPair),
 		account: &Account{
 			ID:             uuid.New().String(),
 			Name:           "Paper Trading Account",
@@ -57,6 +68,51 @@ func NewEngine(config *SimulationConfig, provider PriceProvider) *Engine {
 	}
 }
 
+// Clock supplies the engine's notion of the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now implements Clock.
+func (f ClockFunc) Now() time.Time { return f() }
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// SetClock replaces the wall clock used to stamp orders, fills, trades, and
+// positions and to expire orders, e.g. with simulated time in a backtest.
+func (e *Engine) SetClock(clock Clock) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.clock = clock
+}
+
+// Now returns the current time according to the engine's clock.
+func (e *Engine) Now() time.Time {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.clock.Now()
+}
+
+// tokenPair holds the YES and NO token IDs of a binary market.
+type tokenPair struct {
+	yes string
+	no  string
+}
+
+// SetMarketTokens registers the complementary YES/NO tokens of a market.
+// Since YES + NO settles to 1, a matched YES/NO holding carries no price risk,
+// and GetStats nets it out of NetExposure.
+func (e *Engine) SetMarketTokens(market, yesTokenID, noTokenID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.pairs[market] = tokenPair{yes: yesTokenID, no: noTokenID}
+}
+
 // OnOrder sets a callback for order events.
 func (e *Engine) OnOrder(fn func(*Order)) {
 	e.onOrder = fn
@@ -84,6 +140,14 @@ func (e *Engine) PlaceOrder(ctx context.Context, req *OrderRequest) (*Order, err
 	if req.OrderType == OrderTypeLimit && req.Price.LessThanOrEqual(decimal.Zero) {
 		return nil, fmt.Errorf("limit order requires positive price")
 	}
+	if req.PostOnly {
+		if req.OrderType != OrderTypeLimit {
+			return nil, fmt.Errorf("post-only requires a limit order")
+		}
+		if err := e.checkPostOnly(ctx, req); err != nil {
+			return nil, err
+		}
+	}
 
 	// Check balance for buys
 	if req.Side == SideBuy {
@@ -113,18 +177,40 @@ func (e *Engine) PlaceOrder(ctx context.Context, req *OrderRequest) (*Order, err
 		Size:       req.Size,
 		FilledSize: decimal.Zero,
 		Status:     OrderStatusOpen,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+		CreatedAt:  e.clock.Now(),
+		UpdatedAt:  e.clock.Now(),
 		Fills:      make([]Fill, 0),
 	}
 
 	if req.Expiration > 0 {
-		order.Expiration = time.Now().Add(req.Expiration)
+		order.Expiration = e.clock.Now().Add(req.Expiration)
+	}
+
+	if req.OrderType == OrderTypeLimit && req.RepriceOnDrift.IsPositive() {
+		midPrice, err := e.provider.GetMidPrice(ctx, req.TokenID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get price for repricing: %w", err)
+		}
+		order.RepriceOnDrift = req.RepriceOnDrift
+		order.QuoteOffset = req.Price.Sub(midPrice)
+	}
+
+	// Net against our own opposing orders rather than trading with ourselves.
+	// Exits are never netted, so liquidations and take-profits go out whole.
+	if !e.reducesPositionLocked(order) {
+		e.netSelfCross(ctx, order)
+	}
+	if order.Status == OrderStatusCanceled {
+		e.account.UpdatedAt = e.clock.Now()
+		if e.onOrder != nil {
+			e.onOrder(order)
+		}
+		return order, nil
 	}
 
 	// Store order
 	e.account.OpenOrders[order.ID] = order
-	e.account.UpdatedAt = time.Now()
+	e.account.UpdatedAt = e.clock.Now()
 
 	// Notify
 	if e.onOrder != nil {
@@ -139,6 +225,11 @@ func (e *Engine) PlaceOrder(ctx context.Context, req *OrderRequest) (*Order, err
 		e.tryFillRealistic(ctx, order)
 	}
 
+	// A limit order left resting joins the back of the queue at its price
+	if _, resting := e.account.OpenOrders[order.ID]; resting && order.OrderType == OrderTypeLimit {
+		order.QueueAhead = e.queueAhead(ctx, order)
+	}
+
 	return order, nil
 }
 
@@ -157,7 +248,7 @@ func (e *Engine) CancelOrder(orderID string) error {
 	}
 
 	order.Status = OrderStatusCanceled
-	order.UpdatedAt = time.Now()
+	order.UpdatedAt = e.clock.Now()
 	delete(e.account.OpenOrders, orderID)
 
 	if e.onOrder != nil {
@@ -175,7 +266,31 @@ func (e *Engine) CancelAllOrders() int {
 	count := 0
 	for id, order := range e.account.OpenOrders {
 		order.Status = OrderStatusCanceled
-		order.UpdatedAt = time.Now()
+		order.UpdatedAt = e.clock.Now()
+		delete(e.account.OpenOrders, id)
+		count++
+
+		if e.onOrder != nil {
+			e.onOrder(order)
+		}
+	}
+
+	return count
+}
+
+// CancelOrdersForToken cancels all open orders for tokenID and returns how
+// many were canceled.
+func (e *Engine) CancelOrdersForToken(tokenID string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	count := 0
+	for id, order := range e.account.OpenOrders {
+		if order.TokenID != tokenID {
+			continue
+		}
+		order.Status = OrderStatusCanceled
+		order.UpdatedAt = e.clock.Now()
 		delete(e.account.OpenOrders, id)
 		count++
 
@@ -236,6 +351,41 @@ func (e *Engine) GetBalance() decimal.Decimal {
 	return e.account.Balance
 }
 
+// GetEquity returns the cash balance plus positions marked at their current
+// price. Short positions are liabilities: their sale proceeds are already in
+// the balance, so their value is subtracted.
+func (e *Engine) GetEquity() decimal.Decimal {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	equity := e.account.Balance
+	for _, pos := range e.account.Positions {
+		value := pos.Size.Mul(pos.CurrentPrice)
+		if pos.Side == SideSell {
+			value = value.Neg()
+		}
+		equity = equity.Add(value)
+	}
+	return equity
+}
+
+// Withdraw takes amount out of the cash balance, e.g. for a fee charged
+// outside trading. It is not a trade and doesn't count toward PnL.
+func (e *Engine) Withdraw(amount decimal.Decimal) error {
+	if amount.IsNegative() {
+		return fmt.Errorf("withdrawal must not be negative: %s", amount)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if amount.GreaterThan(e.account.Balance) {
+		return fmt.Errorf("insufficient balance: have %s, need %s", e.account.Balance, amount)
+	}
+	e.account.Balance = e.account.Balance.Sub(amount)
+	e.account.UpdatedAt = e.clock.Now()
+	return nil
+}
+
 // GetAccount returns the full account.
 func (e *Engine) GetAccount() *Account {
 	e.mu.RLock()
@@ -276,12 +426,20 @@ func (e *Engine) GetStats() *AccountStats {
 		}
 	}
 
-	// Calculate unrealized P&L from positions
+	// Merges and redemptions realize PnL without being trades
+	for _, s := range e.account.Settlements {
+		stats.RealizedPnL = stats.RealizedPnL.Add(s.PnL)
+	}
+
+	// Calculate unrealized P&L and exposure from positions
 	for _, pos := range e.account.Positions {
 		stats.UnrealizedPnL = stats.UnrealizedPnL.Add(pos.UnrealizedPnL)
+		stats.GrossExposure = stats.GrossExposure.Add(pos.Size.Mul(pos.CurrentPrice))
 	}
+	stats.NetExposure = stats.GrossExposure.Sub(e.hedgedExposure())
 
-	stats.TotalPnL = stats.RealizedPnL.Add(stats.UnrealizedPnL)
+	stats.TotalFunding = e.account.TotalFunding
+	stats.TotalPnL = stats.RealizedPnL.Add(stats.UnrealizedPnL).Sub(stats.TotalFees).Sub(stats.TotalFunding)
 
 	// Win rate
 	if stats.TotalTrades > 0 {
@@ -299,6 +457,75 @@ func (e *Engine) GetStats() *AccountStats {
 	return stats
 }
 
+// GetStatsByMarket breaks PnL, fees, and win rate down by market. Trades and
+// positions without a market are attributed to their token ID.
+func (e *Engine) GetStatsByMarket() map[string]*MarketStats {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	byMarket := make(map[string]*MarketStats)
+	get := func(market, tokenID string) *MarketStats {
+		if market == "" {
+			market = tokenID
+		}
+		ms, ok := byMarket[market]
+		if !ok {
+			ms = &MarketStats{Market: market}
+			byMarket[market] = ms
+		}
+		return ms
+	}
+
+	for _, trade := range e.account.TradeHistory {
+		ms := get(trade.Market, trade.TokenID)
+		ms.TotalTrades++
+		ms.TotalFees = ms.TotalFees.Add(trade.Fee)
+		ms.RealizedPnL = ms.RealizedPnL.Add(trade.PnL)
+
+		if trade.PnL.GreaterThan(decimal.Zero) {
+			ms.WinningTrades++
+		} else if trade.PnL.LessThan(decimal.Zero) {
+			ms.LosingTrades++
+		}
+	}
+
+	for _, s := range e.account.Settlements {
+		ms := get(s.Market, s.Market)
+		ms.RealizedPnL = ms.RealizedPnL.Add(s.PnL)
+	}
+
+	for _, pos := range e.account.Positions {
+		ms := get(pos.Market, pos.TokenID)
+		ms.UnrealizedPnL = ms.UnrealizedPnL.Add(pos.UnrealizedPnL)
+	}
+
+	for _, ms := range byMarket {
+		ms.TotalPnL = ms.RealizedPnL.Add(ms.UnrealizedPnL).Sub(ms.TotalFees)
+		if ms.TotalTrades > 0 {
+			ms.WinRate = decimal.NewFromInt(int64(ms.WinningTrades)).Div(decimal.NewFromInt(int64(ms.TotalTrades)))
+		}
+	}
+
+	return byMarket
+}
+
+// hedgedExposure returns the exposure locked up in matched long YES/NO
+// holdings of registered markets. Each matched unit pays out exactly 1
+// whichever way the market resolves, so it carries no directional risk.
+func (e *Engine) hedgedExposure() decimal.Decimal {
+	hedged := decimal.Zero
+	for market := range e.pairs {
+		yes, no, ok := e.longPair(market)
+		if !ok {
+			continue
+		}
+
+		matched := decimal.Min(yes.Size, no.Size)
+		hedged = hedged.Add(matched.Mul(yes.CurrentPrice.Add(no.CurrentPrice)))
+	}
+	return hedged
+}
+
 // UpdatePrices updates position prices and unrealized P&L.
 func (e *Engine) UpdatePrices(ctx context.Context) error {
 	e.mu.Lock()
@@ -310,21 +537,51 @@ func (e *Engine) UpdatePrices(ctx context.Context) error {
 			continue // Skip on error
 		}
 
-		pos.CurrentPrice = midPrice
+		e.markLocked(pos, midPrice)
+	}
 
-		// Calculate unrealized P&L
-		if pos.Side == SideBuy {
-			// Long: profit if price went up
-			pos.UnrealizedPnL = midPrice.Sub(pos.AvgEntry).Mul(pos.Size)
-		} else {
-			// Short: profit if price went down
-			pos.UnrealizedPnL = pos.AvgEntry.Sub(midPrice).Mul(pos.Size)
-		}
+	return nil
+}
+
+// markLocked accrues any borrow cost up to now and then marks pos to
+// midPrice, updating its unrealized P&L.
+func (e *Engine) markLocked(pos *Position, midPrice decimal.Decimal) {
+	e.accrueFundingLocked(pos)
+	pos.CurrentPrice = midPrice
 
-		pos.UpdatedAt = time.Now()
+	// Calculate unrealized P&L
+	if pos.Side == SideBuy {
+		// Long: profit if price went up
+		pos.UnrealizedPnL = midPrice.Sub(pos.AvgEntry).Mul(pos.Size)
+	} else {
+		// Short: profit if price went down
+		pos.UnrealizedPnL = pos.AvgEntry.Sub(midPrice).Mul(pos.Size)
 	}
 
-	return nil
+	pos.UpdatedAt = e.clock.Now()
+}
+
+// accrueFundingLocked charges a short position's borrow cost from FundedAt
+// to now on its notional at CurrentPrice, the price it was held at over the
+// period, and moves FundedAt to now. Long positions only advance FundedAt,
+// so a later reversal to short starts accruing from the reversal.
+func (e *Engine) accrueFundingLocked(pos *Position) {
+	now := e.clock.Now()
+	since := pos.FundedAt
+	if since.IsZero() {
+		since = pos.OpenedAt
+	}
+	pos.FundedAt = now
+
+	if pos.Side != SideSell || !e.config.ShortFundingBps.IsPositive() || !now.After(since) {
+		return
+	}
+	days := decimal.NewFromFloat(now.Sub(since).Hours() / 24)
+	charge := pos.Size.Mul(pos.CurrentPrice).Mul(e.config.ShortFundingBps).Div(decimal.NewFromInt(10000)).Mul(days)
+	pos.FundingPaid = pos.FundingPaid.Add(charge)
+	e.account.TotalFunding = e.account.TotalFunding.Add(charge)
+	e.account.Balance = e.account.Balance.Sub(charge)
+	e.account.UpdatedAt = now
 }
 
 // Reset resets the account to initial state.
@@ -340,17 +597,224 @@ func (e *Engine) Reset() {
 		Positions:      make(map[string]*Position),
 		OpenOrders:     make(map[string]*Order),
 		TradeHistory:   make([]Trade, 0),
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
+		CreatedAt:      e.clock.Now(),
+		UpdatedAt:      e.clock.Now(),
 	}
 	e.orderSeq = 0
 	e.tradeSeq = 0
 }
 
+// --- Persistence ---
+
+// Save writes the account to store. The engine is read-locked only while
+// the snapshot is copied, so the snapshot is consistent and the store write
+// doesn't block trading.
+func (e *Engine) Save(store AccountStore) error {
+	e.mu.RLock()
+	snapshot := e.snapshotLocked()
+	e.mu.RUnlock()
+	return store.Save(snapshot)
+}
+
+// snapshotLocked returns a deep copy of the account, so it can be marshaled
+// and written without holding e.mu. Caller holds e.mu.
+func (e *Engine) snapshotLocked() *Account {
+	acc := *e.account
+
+	acc.Positions = make(map[string]*Position, len(e.account.Positions))
+	for id, pos := range e.account.Positions {
+		p := *pos
+		p.TakeProfit = slices.Clone(pos.TakeProfit)
+		acc.Positions[id] = &p
+	}
+	acc.OpenOrders = make(map[string]*Order, len(e.account.OpenOrders))
+	for id, order := range e.account.OpenOrders {
+		o := *order
+		o.Fills = slices.Clone(order.Fills)
+		acc.OpenOrders[id] = &o
+	}
+	acc.TradeHistory = slices.Clone(e.account.TradeHistory)
+	acc.Settlements = slices.Clone(e.account.Settlements)
+	return &acc
+}
+
+// Restore replaces the engine's account, e.g. with one loaded from an
+// AccountStore. Order and trade numbering resume after the highest IDs seen.
+func (e *Engine) Restore(account *Account) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if account.Positions == nil {
+		account.Positions = make(map[string]*Position)
+	}
+	if account.OpenOrders == nil {
+		account.OpenOrders = make(map[string]*Order)
+	}
+	if account.TradeHistory == nil {
+		account.TradeHistory = make([]Trade, 0)
+	}
+
+	e.account = account
+	e.orderSeq = 0
+	e.tradeSeq = 0
+	for id := range account.OpenOrders {
+		e.orderSeq = max(e.orderSeq, idSeq(id, "paper-"))
+	}
+	for _, t := range account.TradeHistory {
+		e.orderSeq = max(e.orderSeq, idSeq(t.OrderID, "paper-"))
+		e.tradeSeq = max(e.tradeSeq, idSeq(t.ID, "trade-"))
+	}
+}
+
+// RunAutosave saves the account to store every interval until ctx is done.
+// Failed saves are logged and retried on the next tick.
+func (e *Engine) RunAutosave(ctx context.Context, store AccountStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.Save(store); err != nil {
+				log.Printf("[PAPER] Autosave failed: %v", err)
+			}
+		}
+	}
+}
+
+// idSeq parses the sequence number from an ID like "paper-12", or 0.
+func idSeq(id, prefix string) int64 {
+	n, err := strconv.ParseInt(strings.TrimPrefix(id, prefix), 10, 64)
+	if err != nil || !strings.HasPrefix(id, prefix) {
+		return 0
+	}
+	return n
+}
+
 // --- Fill Logic ---
 
+// checkPostOnly returns an error if a limit order would take liquidity on
+// placement. Realistic mode compares against the top of book; simple mode
+// against the mid, since that is the price simple fills use.
+func (e *Engine) checkPostOnly(ctx context.Context, req *OrderRequest) error {
+	if e.config.Mode == ModeRealistic {
+		ob, err := e.provider.GetOrderBook(ctx, req.TokenID)
+		if err != nil {
+			return fmt.Errorf("failed to get orderbook: %w", err)
+		}
+		if req.Side == SideBuy {
+			if ask, _ := ob.BestAsk(); !ask.IsZero() && req.Price.GreaterThanOrEqual(ask) {
+				return fmt.Errorf("post-only buy at %s would cross best ask %s", req.Price, ask)
+			}
+		} else {
+			if bid, _ := ob.BestBid(); !bid.IsZero() && req.Price.LessThanOrEqual(bid) {
+				return fmt.Errorf("post-only sell at %s would cross best bid %s", req.Price, bid)
+			}
+		}
+		return nil
+	}
+
+	midPrice, err := e.provider.GetMidPrice(ctx, req.TokenID)
+	if err != nil {
+		return fmt.Errorf("failed to get price: %w", err)
+	}
+	if req.Side == SideBuy && req.Price.GreaterThanOrEqual(midPrice) {
+		return fmt.Errorf("post-only buy at %s would cross mid %s", req.Price, midPrice)
+	}
+	if req.Side == SideSell && req.Price.LessThanOrEqual(midPrice) {
+		return fmt.Errorf("post-only sell at %s would cross mid %s", req.Price, midPrice)
+	}
+	return nil
+}
+
+// netSelfCross cancels the overlap between an incoming order and resting
+// orders of the opposite side on the same token whose limit it reaches. A
+// market order reaches a limit at or through the current mid. Both sides
+// shrink by the netted quantity; an order netted to nothing is canceled.
+func (e *Engine) netSelfCross(ctx context.Context, order *Order) {
+	price := order.Price
+	if order.OrderType == OrderTypeMarket {
+		mid, err := e.provider.GetMidPrice(ctx, order.TokenID)
+		if err != nil {
+			return
+		}
+		price = mid
+	}
+
+	for _, resting := range e.sortedOpenOrders() {
+		remaining := order.Size.Sub(order.FilledSize)
+		if !remaining.IsPositive() {
+			break
+		}
+		if resting.TokenID != order.TokenID || resting.Side == order.Side || !crosses(order.Side, price, resting) {
+			continue
+		}
+
+		netted := decimal.Min(remaining, resting.Size.Sub(resting.FilledSize))
+		if !netted.IsPositive() {
+			continue
+		}
+
+		order.Size = order.Size.Sub(netted)
+		order.NettedSize = order.NettedSize.Add(netted)
+		resting.Size = resting.Size.Sub(netted)
+		resting.NettedSize = resting.NettedSize.Add(netted)
+		resting.UpdatedAt = e.clock.Now()
+
+		if resting.Size.LessThanOrEqual(resting.FilledSize) {
+			if resting.FilledSize.IsPositive() {
+				resting.Status = OrderStatusFilled
+			} else {
+				resting.Status = OrderStatusCanceled
+			}
+			delete(e.account.OpenOrders, resting.ID)
+		}
+		if e.onOrder != nil {
+			e.onOrder(resting)
+		}
+	}
+
+	if order.NettedSize.IsPositive() && order.Size.LessThanOrEqual(order.FilledSize) {
+		order.Status = OrderStatusCanceled
+		order.UpdatedAt = e.clock.Now()
+	}
+}
+
+// sortedOpenOrders returns open orders oldest first so netting is deterministic.
+func (e *Engine) sortedOpenOrders() []*Order {
+	orders := make([]*Order, 0, len(e.account.OpenOrders))
+	for _, o := range e.account.OpenOrders {
+		orders = append(orders, o)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+		}
+		return idSeq(orders[i].ID, "paper-") < idSeq(orders[j].ID, "paper-")
+	})
+	return orders
+}
+
+// crosses reports whether an incoming order on side at price would match a
+// resting order of the opposite side.
+func crosses(side Side, price decimal.Decimal, resting *Order) bool {
+	if side == SideBuy {
+		return price.GreaterThanOrEqual(resting.Price)
+	}
+	return price.LessThanOrEqual(resting.Price)
+}
+
+// reducesPositionLocked reports whether order trades against the current
+// position in its token, i.e. reduces or closes it. Caller holds e.mu.
+func (e *Engine) reducesPositionLocked(order *Order) bool {
+	pos, ok := e.account.Positions[order.TokenID]
+	return ok && pos.Size.IsPositive() && pos.Side != order.Side
+}
+
 func (e *Engine) tryFillSimple(ctx context.Context, order *Order) {
-	// Simple mode: fill at mid price instantly
+	// Simple mode: fill instantly at mid price
 	midPrice, err := e.provider.GetMidPrice(ctx, order.TokenID)
 	if err != nil {
 		return
@@ -366,8 +830,12 @@ func (e *Engine) tryFillSimple(ctx context.Context, order *Order) {
 		}
 	}
 
-	// Fill the entire order at mid price
-	e.executeFill(order, midPrice, order.Size)
+	// Fill the entire order at mid, with slippage for market orders if asked
+	fillPrice := midPrice
+	if order.OrderType == OrderTypeMarket && e.config.SimpleModeSlippage {
+		fillPrice = e.applySlippage(midPrice, order.Side, order.Size)
+	}
+	e.executeFill(order, fillPrice, order.Size, false)
 }
 
 func (e *Engine) tryFillRealistic(ctx context.Context, order *Order) {
@@ -414,7 +882,32 @@ func (e *Engine) tryFillRealistic(ctx context.Context, order *Order) {
 	}
 
 	// Execute fill
-	e.executeFill(order, fillPrice, result.TotalSize)
+	e.executeFill(order, fillPrice, result.TotalSize, false)
+}
+
+// queueAhead is the size assumed to rest ahead of a limit order joining the
+// book: the book's size at the order's price and side under QueueFromBook in
+// realistic mode, else QueueDepth. A book that can't be fetched falls back to
+// QueueDepth.
+func (e *Engine) queueAhead(ctx context.Context, order *Order) decimal.Decimal {
+	if e.config.Mode != ModeRealistic || !e.config.QueueFromBook {
+		return e.config.QueueDepth
+	}
+	ob, err := e.provider.GetOrderBook(ctx, order.TokenID)
+	if err != nil {
+		return e.config.QueueDepth
+	}
+
+	levels := ob.Bids()
+	if order.Side == SideSell {
+		levels = ob.Asks()
+	}
+	for _, level := range levels {
+		if level.Price.Equal(order.Price) {
+			return level.Size
+		}
+	}
+	return decimal.Zero
 }
 
 func (e *Engine) applySlippage(price decimal.Decimal, side Side, size decimal.Decimal) decimal.Decimal {
@@ -455,22 +948,25 @@ func (e *Engine) applySlippage(price decimal.Decimal, side Side, size decimal.De
 	}
 }
 
-func (e *Engine) executeFill(order *Order, price, size decimal.Decimal) {
+// executeFill applies a fill. maker is true when the order was resting on the
+// book before it filled; an order that fills on placement took liquidity and
+// pays the taker fee regardless of its type.
+func (e *Engine) executeFill(order *Order, price, size decimal.Decimal, maker bool) {
 	// Calculate fee
-	var feeBps decimal.Decimal
-	if order.OrderType == OrderTypeLimit {
-		feeBps = e.config.MakerFeeBps
-	} else {
-		feeBps = e.config.TakerFeeBps
+	feeBps := e.config.TakerFeeBps
+	if maker {
+		feeBps = e.config.MakerFeeBps.Sub(e.config.MakerRebateBps)
 	}
 	fee := price.Mul(size).Mul(feeBps).Div(decimal.NewFromInt(10000))
+	filledAt := e.fillTime()
 
 	// Create fill
 	fill := Fill{
 		Price:     price,
 		Size:      size,
-		Timestamp: time.Now(),
+		Timestamp: filledAt,
 		Fee:       fee,
+		Maker:     maker,
 	}
 	order.Fills = append(order.Fills, fill)
 
@@ -493,9 +989,9 @@ func (e *Engine) executeFill(order *Order, price, size decimal.Decimal) {
 	if !totalSize.IsZero() {
 		order.AvgFillPrice = totalCost.Div(totalSize)
 	}
-	order.UpdatedAt = time.Now()
+	order.UpdatedAt = filledAt
 
-	// Update balance
+	// Update balance; a negative fee (rebate) is credited either way
 	cost := price.Mul(size).Add(fee)
 	if order.Side == SideBuy {
 		e.account.Balance = e.account.Balance.Sub(cost)
@@ -518,10 +1014,10 @@ func (e *Engine) executeFill(order *Order, price, size decimal.Decimal) {
 		Size:      size,
 		Fee:       fee,
 		PnL:       tradePnL,
-		Timestamp: time.Now(),
+		Timestamp: filledAt,
 	}
 	e.account.TradeHistory = append(e.account.TradeHistory, trade)
-	e.account.UpdatedAt = time.Now()
+	e.account.UpdatedAt = e.clock.Now()
 
 	// Notify
 	if e.onFill != nil {
@@ -535,6 +1031,15 @@ func (e *Engine) executeFill(order *Order, price, size decimal.Decimal) {
 	}
 }
 
+// fillTime is when a fill happening now is recorded, after LatencyMs in
+// simple mode.
+func (e *Engine) fillTime() time.Time {
+	if e.config.Mode == ModeSimple {
+		return e.clock.Now().Add(time.Duration(e.config.LatencyMs) * time.Millisecond)
+	}
+	return e.clock.Now()
+}
+
 // updatePositionWithPnL updates position and returns the PnL realized on this trade (if any).
 func (e *Engine) updatePositionWithPnL(tokenID, market string, side Side, size, price decimal.Decimal) decimal.Decimal {
 	pos, exists := e.account.Positions[tokenID]
@@ -548,13 +1053,17 @@ func (e *Engine) updatePositionWithPnL(tokenID, market string, side Side, size,
 			Size:         size,
 			AvgEntry:     price,
 			CurrentPrice: price,
-			OpenedAt:     time.Now(),
-			UpdatedAt:    time.Now(),
+			OpenedAt:     e.clock.Now(),
+			UpdatedAt:    e.clock.Now(),
+			FundedAt:     e.clock.Now(),
 		}
 		e.account.Positions[tokenID] = pos
 		return decimal.Zero
 	}
 
+	// Settle the borrow cost on the size held so far before it changes
+	e.accrueFundingLocked(pos)
+
 	var tradePnL decimal.Decimal
 
 	// Update existing position
@@ -579,10 +1088,11 @@ func (e *Engine) updatePositionWithPnL(tokenID, market string, side Side, size,
 			pos.RealizedPnL = pos.RealizedPnL.Add(tradePnL)
 
 			if reverseSize.GreaterThan(decimal.Zero) {
-				// Reverse position
+				// Reverse position; the ladder belonged to the old side
 				pos.Side = side
 				pos.Size = reverseSize
 				pos.AvgEntry = price
+				pos.TakeProfit = nil
 			} else {
 				// Close position
 				delete(e.account.Positions, tokenID)
@@ -601,15 +1111,17 @@ func (e *Engine) updatePositionWithPnL(tokenID, market string, side Side, size,
 	}
 
 	pos.CurrentPrice = price
-	pos.UpdatedAt = time.Now()
+	pos.UpdatedAt = e.clock.Now()
 	return tradePnL
 }
 
-// ProcessTick processes market updates (for limit order matching).
+// ProcessTick processes market updates (for limit order matching) and marks
+// any position in tokenID to midPrice.
 func (e *Engine) ProcessTick(ctx context.Context, tokenID string, midPrice decimal.Decimal) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	var drifted []*Order
 	for _, order := range e.account.OpenOrders {
 		if order.TokenID != tokenID {
 			continue
@@ -627,19 +1139,271 @@ func (e *Engine) ProcessTick(ctx context.Context, tokenID string, midPrice decim
 			canFill = true
 		}
 
-		if canFill {
+		if canFill && order.QueueAhead.IsPositive() {
+			// Still queued behind others at this price; wait for ProcessTrade
+		} else if canFill {
 			remainingSize := order.Size.Sub(order.FilledSize)
-			e.executeFill(order, order.Price, remainingSize)
+			e.executeFill(order, order.Price, remainingSize, true)
+		} else if order.RepriceOnDrift.IsPositive() {
+			// An order past its expiry expires rather than being re-posted
+			if e.expireIfDueLocked(order, e.clock.Now()) {
+				continue
+			}
+			quotedMid := order.Price.Sub(order.QuoteOffset)
+			if midPrice.Sub(quotedMid).Abs().GreaterThan(order.RepriceOnDrift) {
+				drifted = append(drifted, order)
+				continue
+			}
+		}
+
+		e.expireIfDueLocked(order, e.clock.Now())
+	}
+
+	for _, order := range drifted {
+		e.repriceOrder(ctx, order, midPrice)
+	}
+
+	if pos, ok := e.account.Positions[tokenID]; ok {
+		e.markLocked(pos, midPrice)
+	}
+
+	e.takeProfitLocked(tokenID, midPrice)
+}
+
+// SetTakeProfit attaches a take-profit ladder to the position in tokenID,
+// replacing any existing one. Each level's Fraction is of the current
+// position size, and the fractions may not sum to more than 1. ProcessTick
+// closes each level's share at the mid, as a taker, once the mid reaches it.
+func (e *Engine) SetTakeProfit(tokenID string, levels []TakeProfitLevel) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pos, ok := e.account.Positions[tokenID]
+	if !ok {
+		return fmt.Errorf("no position in %s", tokenID)
+	}
+
+	ladder := make([]TakeProfitLevel, len(levels))
+	total := decimal.Zero
+	for i, level := range levels {
+		if !level.Price.IsPositive() {
+			return fmt.Errorf("take-profit level %d: price must be positive", i)
+		}
+		if !level.Fraction.IsPositive() {
+			return fmt.Errorf("take-profit level %d: fraction must be positive", i)
+		}
+		total = total.Add(level.Fraction)
+		ladder[i] = TakeProfitLevel{
+			Price:    level.Price,
+			Fraction: level.Fraction,
+			Size:     pos.Size.Mul(level.Fraction),
+		}
+	}
+	if total.GreaterThan(decimal.NewFromInt(1)) {
+		return fmt.Errorf("take-profit fractions sum to %s, more than 1", total)
+	}
+
+	// Nearest level first: ascending for a long, descending for a short
+	sort.SliceStable(ladder, func(i, j int) bool {
+		if pos.Side == SideBuy {
+			return ladder[i].Price.LessThan(ladder[j].Price)
+		}
+		return ladder[i].Price.GreaterThan(ladder[j].Price)
+	})
+	pos.TakeProfit = ladder
+	return nil
+}
+
+// takeProfitLocked closes the share of every take-profit level midPrice has
+// reached. Caller holds e.mu.
+func (e *Engine) takeProfitLocked(tokenID string, midPrice decimal.Decimal) {
+	pos, ok := e.account.Positions[tokenID]
+	if !ok {
+		return
+	}
+
+	for len(pos.TakeProfit) > 0 {
+		level := pos.TakeProfit[0]
+		if pos.Side == SideBuy && midPrice.LessThan(level.Price) ||
+			pos.Side == SideSell && midPrice.GreaterThan(level.Price) {
+			return
 		}
+		pos.TakeProfit = pos.TakeProfit[1:]
 
-		// Check expiration
-		if !order.Expiration.IsZero() && time.Now().After(order.Expiration) {
-			order.Status = OrderStatusExpired
-			order.UpdatedAt = time.Now()
-			delete(e.account.OpenOrders, order.ID)
-			if e.onOrder != nil {
-				e.onOrder(order)
+		size := decimal.Min(level.Size, pos.Size)
+		side := SideSell
+		if pos.Side == SideSell {
+			side = SideBuy
+		}
+
+		e.orderSeq++
+		now := e.clock.Now()
+		order := &Order{
+			ID:         fmt.Sprintf("paper-%d", e.orderSeq),
+			TokenID:    tokenID,
+			Market:     pos.Market,
+			Side:       side,
+			OrderType:  OrderTypeMarket,
+			Size:       size,
+			FilledSize: decimal.Zero,
+			Status:     OrderStatusOpen,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+			Fills:      make([]Fill, 0),
+		}
+		e.executeFill(order, e.applySlippage(midPrice, side, size), size, false)
+
+		// Closing the last share deletes the position and its ladder
+		if _, open := e.account.Positions[tokenID]; !open {
+			return
+		}
+	}
+}
+
+// ProcessTrade reports size traded in tokenID at price. Each resting limit
+// order the trade reaches (a buy at or above the trade price, a sell at or
+// below) works through its QueueAhead first; volume beyond the queue fills
+// the order at its limit price as maker. A trade strictly through the price
+// clears the queue, since the level must have traded out. Each order sees
+// the whole trade.
+//
+// The engine has no trade feed of its own: with QueueDepth or QueueFromBook
+// set, the caller must drive ProcessTrade (as the backtest does from each
+// point's volume, or from the CLOB WebSocket's last_trade_price events), or
+// queued orders never fill.
+func (e *Engine) ProcessTrade(ctx context.Context, tokenID string, price, size decimal.Decimal) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, order := range e.sortedOpenOrders() {
+		if order.TokenID != tokenID || order.OrderType != OrderTypeLimit {
+			continue
+		}
+		if order.Side == SideBuy && price.GreaterThan(order.Price) {
+			continue
+		}
+		if order.Side == SideSell && price.LessThan(order.Price) {
+			continue
+		}
+
+		volume := size
+		if !price.Equal(order.Price) {
+			order.QueueAhead = decimal.Zero
+		}
+		if order.QueueAhead.IsPositive() {
+			consumed := decimal.Min(order.QueueAhead, volume)
+			order.QueueAhead = order.QueueAhead.Sub(consumed)
+			volume = volume.Sub(consumed)
+		}
+
+		fillSize := decimal.Min(volume, order.Size.Sub(order.FilledSize))
+		if fillSize.IsPositive() {
+			e.executeFill(order, order.Price, fillSize, true)
+		}
+	}
+}
+
+// ExpireOrders expires every open order past its Expiration, regardless of
+// token, and returns how many it expired.
+func (e *Engine) ExpireOrders() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.clock.Now()
+	expired := 0
+	for _, order := range e.account.OpenOrders {
+		if e.expireIfDueLocked(order, now) {
+			expired++
+		}
+	}
+	return expired
+}
+
+// StartExpirySweeper calls ExpireOrders every interval in the background, so
+// orders expire even in markets that stop ticking. Call the returned function
+// to stop it; extra calls are no-ops.
+func (e *Engine) StartExpirySweeper(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				e.ExpireOrders()
 			}
 		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// expireIfDueLocked expires a still-open order whose Expiration has passed.
+// Caller holds e.mu.
+func (e *Engine) expireIfDueLocked(order *Order, now time.Time) bool {
+	if order.Expiration.IsZero() || !now.After(order.Expiration) {
+		return false
+	}
+	if order.Status != OrderStatusOpen && order.Status != OrderStatusPartiallyFilled {
+		return false
+	}
+
+	order.Status = OrderStatusExpired
+	order.UpdatedAt = now
+	delete(e.account.OpenOrders, order.ID)
+	if e.onOrder != nil {
+		e.onOrder(order)
+	}
+	return true
+}
+
+// repriceOrder cancels a drifted order and re-posts its remaining size at the
+// original offset from the new mid. Orders whose new price would leave (0, 1)
+// are left resting. Caller holds e.mu.
+func (e *Engine) repriceOrder(ctx context.Context, order *Order, midPrice decimal.Decimal) {
+	newPrice := midPrice.Add(order.QuoteOffset)
+	if !newPrice.IsPositive() || newPrice.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return
+	}
+
+	now := e.clock.Now()
+	order.Status = OrderStatusCanceled
+	order.UpdatedAt = now
+	delete(e.account.OpenOrders, order.ID)
+	if e.onOrder != nil {
+		e.onOrder(order)
+	}
+
+	e.orderSeq++
+	replacement := &Order{
+		ID:             fmt.Sprintf("paper-%d", e.orderSeq),
+		TokenID:        order.TokenID,
+		Market:         order.Market,
+		Side:           order.Side,
+		OrderType:      OrderTypeLimit,
+		Price:          newPrice,
+		Size:           order.Size.Sub(order.FilledSize),
+		FilledSize:     decimal.Zero,
+		Status:         OrderStatusOpen,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Expiration:     order.Expiration,
+		Fills:          make([]Fill, 0),
+		RepriceOnDrift: order.RepriceOnDrift,
+		QuoteOffset:    order.QuoteOffset,
+		RepricedFrom:   order.ID,
+	}
+	replacement.QueueAhead = e.queueAhead(ctx, replacement) // Repricing loses queue priority
+
+	e.account.OpenOrders[replacement.ID] = replacement
+	e.account.UpdatedAt = now
+	if e.onOrder != nil {
+		e.onOrder(replacement)
 	}
 }
@@ -287,6 +287,40 @@ func TestCancelAllOrders(t *testing.T) {
 	}
 }
 
+func TestCancelOrdersForToken(t *testing.T) {
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("token1", decimal.NewFromFloat(0.6))
+	provider.SetMidPrice("token2", decimal.NewFromFloat(0.7))
+
+	engine := NewEngine(DefaultSimulationConfig(), provider)
+
+	ctx := context.Background()
+	for _, token := range []string{"token1", "token2", "token1"} {
+		if _, err := engine.PlaceOrder(ctx, &OrderRequest{
+			TokenID:   token,
+			Side:      SideBuy,
+			OrderType: OrderTypeLimit,
+			Price:     decimal.NewFromFloat(0.4),
+			Size:      decimal.NewFromInt(100),
+		}); err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+	}
+
+	if count := engine.CancelOrdersForToken("token1"); count != 2 {
+		t.Errorf("Expected to cancel 2 orders, got %d", count)
+	}
+
+	orders := engine.GetOpenOrders()
+	if len(orders) != 1 || orders[0].TokenID != "token2" {
+		t.Fatalf("Expected only the token2 order to remain, got %+v", orders)
+	}
+
+	if count := engine.CancelOrdersForToken("token3"); count != 0 {
+		t.Errorf("Expected no orders canceled for unknown token, got %d", count)
+	}
+}
+
 func TestGetPosition(t *testing.T) {
 	provider := newMockPriceProvider()
 	provider.SetMidPrice("token1", decimal.NewFromFloat(0.5))
@@ -378,6 +412,121 @@ func TestGetStats(t *testing.T) {
 	}
 }
 
+func TestGetStats_NetExposureComplementaryTokens(t *testing.T) {
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("yes", decimal.NewFromFloat(0.6))
+	provider.SetMidPrice("no", decimal.NewFromFloat(0.4))
+
+	engine := NewEngine(DefaultSimulationConfig(), provider)
+	engine.SetMarketTokens("market1", "yes", "no")
+
+	ctx := context.Background()
+	for _, tokenID := range []string{"yes", "no"} {
+		_, err := engine.PlaceOrder(ctx, &OrderRequest{
+			TokenID:   tokenID,
+			Market:    "market1",
+			Side:      SideBuy,
+			OrderType: OrderTypeMarket,
+			Size:      decimal.NewFromInt(100),
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder %s failed: %v", tokenID, err)
+		}
+	}
+
+	stats := engine.GetStats()
+	if !stats.GrossExposure.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected gross exposure of 100, got %s", stats.GrossExposure)
+	}
+	if !stats.NetExposure.IsZero() {
+		t.Errorf("Expected zero net exposure for matched YES/NO, got %s", stats.NetExposure)
+	}
+
+	// Unregistered tokens are not netted
+	other := NewEngine(DefaultSimulationConfig(), provider)
+	for _, tokenID := range []string{"yes", "no"} {
+		other.PlaceOrder(ctx, &OrderRequest{
+			TokenID:   tokenID,
+			Side:      SideBuy,
+			OrderType: OrderTypeMarket,
+			Size:      decimal.NewFromInt(100),
+		})
+	}
+	if !other.GetStats().NetExposure.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected unnetted exposure of 100, got %s", other.GetStats().NetExposure)
+	}
+}
+
+func TestGetStatsByMarket(t *testing.T) {
+	provider := newMockPriceProvider()
+	config := DefaultSimulationConfig()
+	config.TakerFeeBps = decimal.Zero
+	engine := NewEngine(config, provider)
+
+	ctx := context.Background()
+	trade := func(tokenID, market string, side Side, price float64) {
+		provider.SetMidPrice(tokenID, decimal.NewFromFloat(price))
+		_, err := engine.PlaceOrder(ctx, &OrderRequest{
+			TokenID:   tokenID,
+			Market:    market,
+			Side:      side,
+			OrderType: OrderTypeMarket,
+			Size:      decimal.NewFromInt(100),
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+	}
+
+	// Winner: buy at 0.40, sell at 0.50
+	trade("tokenA", "marketA", SideBuy, 0.40)
+	trade("tokenA", "marketA", SideSell, 0.50)
+
+	// Loser: buy at 0.60, sell at 0.45
+	trade("tokenB", "marketB", SideBuy, 0.60)
+	trade("tokenB", "marketB", SideSell, 0.45)
+
+	// Still open, marked up from 0.30 to 0.35
+	trade("tokenC", "marketC", SideBuy, 0.30)
+	provider.SetMidPrice("tokenC", decimal.NewFromFloat(0.35))
+	engine.UpdatePrices(ctx)
+
+	stats := engine.GetStatsByMarket()
+	if len(stats) != 3 {
+		t.Fatalf("Expected 3 markets, got %d", len(stats))
+	}
+
+	a := stats["marketA"]
+	if !a.RealizedPnL.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("marketA: expected realized PnL 10, got %s", a.RealizedPnL)
+	}
+	if a.TotalTrades != 2 || a.WinningTrades != 1 || a.LosingTrades != 0 {
+		t.Errorf("marketA: unexpected counts %+v", a)
+	}
+	if !a.WinRate.Equal(decimal.NewFromFloat(0.5)) {
+		t.Errorf("marketA: expected win rate 0.5, got %s", a.WinRate)
+	}
+
+	b := stats["marketB"]
+	if !b.RealizedPnL.Equal(decimal.NewFromInt(-15)) {
+		t.Errorf("marketB: expected realized PnL -15, got %s", b.RealizedPnL)
+	}
+	if b.LosingTrades != 1 || b.WinningTrades != 0 {
+		t.Errorf("marketB: unexpected counts %+v", b)
+	}
+
+	c := stats["marketC"]
+	if !c.RealizedPnL.IsZero() {
+		t.Errorf("marketC: expected no realized PnL, got %s", c.RealizedPnL)
+	}
+	if !c.UnrealizedPnL.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("marketC: expected unrealized PnL 5, got %s", c.UnrealizedPnL)
+	}
+	if !c.TotalPnL.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("marketC: expected total PnL 5, got %s", c.TotalPnL)
+	}
+}
+
 func TestGetAccount(t *testing.T) {
 	provider := newMockPriceProvider()
 	config := DefaultSimulationConfig()
@@ -467,6 +616,145 @@ func TestOrderCallbacks(t *testing.T) {
 	}
 }
 
+func TestProcessTick_RepricesOnDrift(t *testing.T) {
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("token1", decimal.NewFromFloat(0.60))
+
+	engine := NewEngine(DefaultSimulationConfig(), provider)
+	ctx := context.Background()
+
+	// Bid 5 cents under the mid, follow the market once it moves 2 cents
+	order, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:        "token1",
+		Side:           SideBuy,
+		OrderType:      OrderTypeLimit,
+		Price:          decimal.NewFromFloat(0.55),
+		Size:           decimal.NewFromInt(100),
+		RepriceOnDrift: decimal.NewFromFloat(0.02),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	// Small move: stays put
+	engine.ProcessTick(ctx, "token1", decimal.NewFromFloat(0.61))
+	if _, ok := engine.GetOrder(order.ID); !ok {
+		t.Fatal("Order should still be resting after a move within the threshold")
+	}
+
+	// Mid drifts up 5 cents: cancel and re-post at the new mid minus 5 cents
+	engine.ProcessTick(ctx, "token1", decimal.NewFromFloat(0.65))
+
+	if _, ok := engine.GetOrder(order.ID); ok {
+		t.Error("Drifted order should have been canceled")
+	}
+	if order.Status != OrderStatusCanceled {
+		t.Errorf("Expected original order canceled, got %s", order.Status)
+	}
+
+	open := engine.GetOpenOrders()
+	if len(open) != 1 {
+		t.Fatalf("Expected 1 replacement order, got %d", len(open))
+	}
+	replacement := open[0]
+	if !replacement.Price.Equal(decimal.NewFromFloat(0.60)) {
+		t.Errorf("Expected repriced order at 0.60, got %s", replacement.Price)
+	}
+	if replacement.RepricedFrom != order.ID {
+		t.Errorf("Expected replacement to reference %s, got %q", order.ID, replacement.RepricedFrom)
+	}
+	if !replacement.Size.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("Expected replacement size 100, got %s", replacement.Size)
+	}
+}
+
+func TestProcessTick_ExpiresDriftedOrder(t *testing.T) {
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("token1", decimal.NewFromFloat(0.60))
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	engine := NewEngine(DefaultSimulationConfig(), provider)
+	engine.SetClock(ClockFunc(func() time.Time { return now }))
+	ctx := context.Background()
+
+	order, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:        "token1",
+		Side:           SideBuy,
+		OrderType:      OrderTypeLimit,
+		Price:          decimal.NewFromFloat(0.55),
+		Size:           decimal.NewFromInt(100),
+		RepriceOnDrift: decimal.NewFromFloat(0.02),
+		Expiration:     time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	// Drifted and expired on the same tick: expire, don't re-post
+	now = now.Add(2 * time.Minute)
+	engine.ProcessTick(ctx, "token1", decimal.NewFromFloat(0.65))
+
+	if order.Status != OrderStatusExpired {
+		t.Errorf("Expected order expired, got %s", order.Status)
+	}
+	if open := engine.GetOpenOrders(); len(open) != 0 {
+		t.Errorf("Expected no replacement order, got %d open", len(open))
+	}
+}
+
+func TestPlaceOrder_PostOnlyRejectsCrossing(t *testing.T) {
+	provider := newMockPriceProvider() // Book: best bid 0.49, best ask 0.51
+	config := DefaultSimulationConfig()
+	config.Mode = ModeRealistic
+	engine := NewEngine(config, provider)
+	ctx := context.Background()
+
+	// Buy at the ask would take liquidity
+	_, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideBuy,
+		OrderType: OrderTypeLimit,
+		Price:     decimal.NewFromFloat(0.51),
+		Size:      decimal.NewFromInt(10),
+		PostOnly:  true,
+	})
+	if err == nil {
+		t.Fatal("Crossing post-only buy should be rejected")
+	}
+	if len(engine.GetAccount().TradeHistory) != 0 {
+		t.Error("Rejected post-only order must not trade")
+	}
+
+	// Sell at the bid would take liquidity
+	_, err = engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideSell,
+		OrderType: OrderTypeLimit,
+		Price:     decimal.NewFromFloat(0.49),
+		Size:      decimal.NewFromInt(10),
+		PostOnly:  true,
+	})
+	if err == nil {
+		t.Fatal("Crossing post-only sell should be rejected")
+	}
+
+	// Resting inside the spread is fine
+	order, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideBuy,
+		OrderType: OrderTypeLimit,
+		Price:     decimal.NewFromFloat(0.50),
+		Size:      decimal.NewFromInt(10),
+		PostOnly:  true,
+	})
+	if err != nil {
+		t.Fatalf("Non-crossing post-only order should be accepted: %v", err)
+	}
+	if order.Status != OrderStatusOpen {
+		t.Errorf("Expected post-only order to rest, got %s", order.Status)
+	}
+}
+
 func TestProcessTick_FillsLimitOrder(t *testing.T) {
 	provider := newMockPriceProvider()
 	provider.SetMidPrice("token1", decimal.NewFromFloat(0.6)) // Price too high initially
@@ -699,3 +987,543 @@ func TestSlippageModels(t *testing.T) {
 		})
 	}
 }
+
+func TestPlaceOrder_NetsSelfCross(t *testing.T) {
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("token1", decimal.NewFromFloat(0.50))
+	engine := NewEngine(DefaultSimulationConfig(), provider)
+	ctx := context.Background()
+	startBalance := engine.GetBalance()
+
+	// Resting buy below mid
+	buy, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideBuy,
+		OrderType: OrderTypeLimit,
+		Price:     decimal.NewFromFloat(0.45),
+		Size:      decimal.NewFromInt(10),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder buy failed: %v", err)
+	}
+	if buy.Status != OrderStatusOpen {
+		t.Fatalf("Expected resting buy, got %s", buy.Status)
+	}
+
+	// Sell that crosses our own buy (and would otherwise fill at mid)
+	sell, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideSell,
+		OrderType: OrderTypeLimit,
+		Price:     decimal.NewFromFloat(0.44),
+		Size:      decimal.NewFromInt(6),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder sell failed: %v", err)
+	}
+
+	if sell.Status != OrderStatusCanceled || !sell.NettedSize.Equal(decimal.NewFromInt(6)) {
+		t.Errorf("Expected sell netted away, got status=%s netted=%s", sell.Status, sell.NettedSize)
+	}
+	if !buy.Size.Equal(decimal.NewFromInt(4)) || buy.Status != OrderStatusOpen {
+		t.Errorf("Expected buy reduced to 4 and still open, got size=%s status=%s", buy.Size, buy.Status)
+	}
+	if len(engine.GetAccount().TradeHistory) != 0 {
+		t.Errorf("Netted orders must not trade, got %d trades", len(engine.GetAccount().TradeHistory))
+	}
+	if !engine.GetBalance().Equal(startBalance) {
+		t.Errorf("Balance changed by netting: %s -> %s", startBalance, engine.GetBalance())
+	}
+	if len(engine.GetOpenOrders()) != 1 {
+		t.Errorf("Expected only the reduced buy open, got %d", len(engine.GetOpenOrders()))
+	}
+}
+
+func TestPlaceOrder_SelfCrossOnlyWhenPricesOverlap(t *testing.T) {
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("token1", decimal.NewFromFloat(0.50))
+	engine := NewEngine(DefaultSimulationConfig(), provider)
+	ctx := context.Background()
+
+	// Resting sell well above the mid
+	ask, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideSell,
+		OrderType: OrderTypeLimit,
+		Price:     decimal.NewFromFloat(0.60),
+		Size:      decimal.NewFromInt(10),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder sell failed: %v", err)
+	}
+
+	// A market buy at the 0.50 mid never reaches it
+	buy, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideBuy,
+		OrderType: OrderTypeMarket,
+		Size:      decimal.NewFromInt(10),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder market buy failed: %v", err)
+	}
+	if buy.Status != OrderStatusFilled || buy.NettedSize.IsPositive() {
+		t.Errorf("Expected market buy filled without netting, got status=%s netted=%s", buy.Status, buy.NettedSize)
+	}
+	if !ask.Size.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("Resting sell should be untouched, got size %s", ask.Size)
+	}
+
+	// Resting bid that the exit below would otherwise cross
+	if _, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideBuy,
+		OrderType: OrderTypeLimit,
+		Price:     decimal.NewFromFloat(0.45),
+		Size:      decimal.NewFromInt(10),
+	}); err != nil {
+		t.Fatalf("PlaceOrder bid failed: %v", err)
+	}
+
+	// Closing the long is an exit and must not be netted away
+	provider.SetMidPrice("token1", decimal.NewFromFloat(0.40))
+	exit, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideSell,
+		OrderType: OrderTypeMarket,
+		Size:      decimal.NewFromInt(10),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder exit failed: %v", err)
+	}
+	if exit.Status != OrderStatusFilled || exit.NettedSize.IsPositive() {
+		t.Errorf("Expected exit filled without netting, got status=%s netted=%s", exit.Status, exit.NettedSize)
+	}
+}
+
+func TestFees_CrossingLimitPaysTaker(t *testing.T) {
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("token1", decimal.NewFromFloat(0.50))
+	config := DefaultSimulationConfig()
+	config.MakerFeeBps = decimal.NewFromInt(10)
+	config.TakerFeeBps = decimal.NewFromInt(50)
+	engine := NewEngine(config, provider)
+	ctx := context.Background()
+
+	// Limit buy above mid fills on placement: taker
+	crossing, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideBuy,
+		OrderType: OrderTypeLimit,
+		Price:     decimal.NewFromFloat(0.55),
+		Size:      decimal.NewFromInt(10),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if len(crossing.Fills) != 1 {
+		t.Fatalf("Expected an immediate fill, got %d", len(crossing.Fills))
+	}
+	fill := crossing.Fills[0]
+	// 0.50 * 10 * 50bps
+	if fill.Maker || !fill.Fee.Equal(decimal.RequireFromString("0.025")) {
+		t.Errorf("Expected taker fill with fee 0.025, got maker=%v fee=%s", fill.Maker, fill.Fee)
+	}
+
+	// Limit buy below mid rests, then fills on a later tick: maker
+	resting, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideBuy,
+		OrderType: OrderTypeLimit,
+		Price:     decimal.NewFromFloat(0.45),
+		Size:      decimal.NewFromInt(10),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	engine.ProcessTick(ctx, "token1", decimal.NewFromFloat(0.44))
+	if len(resting.Fills) != 1 {
+		t.Fatalf("Expected resting order to fill on tick, got %d fills", len(resting.Fills))
+	}
+	fill = resting.Fills[0]
+	// 0.45 * 10 * 10bps
+	if !fill.Maker || !fill.Fee.Equal(decimal.RequireFromString("0.0045")) {
+		t.Errorf("Expected maker fill with fee 0.0045, got maker=%v fee=%s", fill.Maker, fill.Fee)
+	}
+}
+
+func TestExpirySweeper(t *testing.T) {
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("token1", decimal.NewFromFloat(0.50))
+	engine := NewEngine(DefaultSimulationConfig(), provider)
+
+	expired := make(chan *Order, 1)
+	engine.OnOrder(func(o *Order) {
+		if o.Status == OrderStatusExpired {
+			expired <- o
+		}
+	})
+
+	order, err := engine.PlaceOrder(context.Background(), &OrderRequest{
+		TokenID:    "token1",
+		Side:       SideBuy,
+		OrderType:  OrderTypeLimit,
+		Price:      decimal.NewFromFloat(0.40),
+		Size:       decimal.NewFromInt(10),
+		Expiration: 30 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	stop := engine.StartExpirySweeper(10 * time.Millisecond)
+	defer stop()
+
+	// No ticks arrive for token1; only the sweeper can expire the order
+	select {
+	case o := <-expired:
+		if o.ID != order.ID {
+			t.Errorf("Expected %s to expire, got %s", order.ID, o.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Sweeper did not expire the order")
+	}
+
+	if len(engine.GetOpenOrders()) != 0 {
+		t.Error("Expired order should no longer be open")
+	}
+	stop() // Idempotent
+}
+
+func TestQueueDepth_LimitWaitsForVolume(t *testing.T) {
+	d := decimal.RequireFromString
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("token1", d("0.60"))
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	config := DefaultSimulationConfig()
+	config.QueueDepth = d("300")
+	config.LatencyMs = 250
+	engine := NewEngine(config, provider)
+	engine.SetClock(ClockFunc(func() time.Time { return now }))
+
+	ctx := context.Background()
+	order, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideBuy,
+		OrderType: OrderTypeLimit,
+		Price:     d("0.55"),
+		Size:      d("100"),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if !order.QueueAhead.Equal(d("300")) {
+		t.Fatalf("Expected 300 queued ahead, got %s", order.QueueAhead)
+	}
+
+	// The mid crossing the price is not enough while others are ahead
+	engine.ProcessTick(ctx, "token1", d("0.50"))
+	if !order.FilledSize.IsZero() {
+		t.Fatalf("Filled %s with the queue ahead", order.FilledSize)
+	}
+
+	// 200 traded at the level: 100 still ahead
+	engine.ProcessTrade(ctx, "token1", d("0.55"), d("200"))
+	if !order.FilledSize.IsZero() || !order.QueueAhead.Equal(d("100")) {
+		t.Fatalf("Expected no fill and 100 ahead, got %s filled and %s ahead", order.FilledSize, order.QueueAhead)
+	}
+
+	// Trades at a worse price for the buyer don't reach the order
+	engine.ProcessTrade(ctx, "token1", d("0.56"), d("500"))
+	if !order.QueueAhead.Equal(d("100")) {
+		t.Fatalf("Trade above the bid consumed the queue: %s ahead", order.QueueAhead)
+	}
+
+	// 150 more clears the queue and fills 50
+	engine.ProcessTrade(ctx, "token1", d("0.55"), d("150"))
+	if !order.FilledSize.Equal(d("50")) || order.Status != OrderStatusPartiallyFilled {
+		t.Fatalf("Expected a 50 partial fill, got %s (%s)", order.FilledSize, order.Status)
+	}
+	if !order.UpdatedAt.Equal(now.Add(250 * time.Millisecond)) {
+		t.Errorf("Expected fill stamped after latency, got %v", order.UpdatedAt)
+	}
+
+	engine.ProcessTrade(ctx, "token1", d("0.55"), d("100"))
+	if order.Status != OrderStatusFilled || !order.FilledSize.Equal(d("100")) {
+		t.Errorf("Expected the order filled, got %s (%s)", order.FilledSize, order.Status)
+	}
+	if fill := order.Fills[len(order.Fills)-1]; !fill.Maker {
+		t.Error("Queued fills should be maker fills")
+	}
+}
+
+func TestQueueDepth_TradeThroughClearsQueue(t *testing.T) {
+	d := decimal.RequireFromString
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("token1", d("0.40"))
+
+	config := DefaultSimulationConfig()
+	config.QueueDepth = d("1000")
+	engine := NewEngine(config, provider)
+
+	ctx := context.Background()
+	order, _ := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideSell,
+		OrderType: OrderTypeLimit,
+		Price:     d("0.45"),
+		Size:      d("100"),
+	})
+
+	engine.ProcessTrade(ctx, "token1", d("0.46"), d("40"))
+	if !order.QueueAhead.IsZero() || !order.FilledSize.Equal(d("40")) {
+		t.Errorf("Expected the queue cleared and 40 filled, got %s ahead and %s filled", order.QueueAhead, order.FilledSize)
+	}
+}
+
+func TestQueueFromBook_WaitsBehindBookSize(t *testing.T) {
+	d := decimal.RequireFromString
+	provider := newMockPriceProvider() // Bids 0.49 x 100, 0.48 x 200; asks from 0.51
+
+	config := DefaultSimulationConfig()
+	config.Mode = ModeRealistic
+	config.QueueFromBook = true
+	engine := NewEngine(config, provider)
+
+	ctx := context.Background()
+	order, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideBuy,
+		OrderType: OrderTypeLimit,
+		Price:     d("0.49"),
+		Size:      d("50"),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if !order.QueueAhead.Equal(d("100")) {
+		t.Fatalf("Expected the 100 resting at 0.49 ahead, got %s", order.QueueAhead)
+	}
+
+	// The book touching the price doesn't fill an order at the back
+	engine.ProcessTick(ctx, "token1", d("0.49"))
+	if !order.FilledSize.IsZero() {
+		t.Fatalf("Filled %s with the queue ahead", order.FilledSize)
+	}
+
+	engine.ProcessTrade(ctx, "token1", d("0.49"), d("60"))
+	if !order.FilledSize.IsZero() || !order.QueueAhead.Equal(d("40")) {
+		t.Fatalf("Expected no fill and 40 ahead, got %s filled and %s ahead", order.FilledSize, order.QueueAhead)
+	}
+
+	engine.ProcessTrade(ctx, "token1", d("0.49"), d("70"))
+	if !order.FilledSize.Equal(d("30")) || !order.Fills[0].Maker {
+		t.Fatalf("Expected a 30 maker fill once the queue traded through, got %s", order.FilledSize)
+	}
+
+	// A new best bid starts at the front of the queue
+	improved, _ := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideBuy,
+		OrderType: OrderTypeLimit,
+		Price:     d("0.495"),
+		Size:      d("10"),
+	})
+	if !improved.QueueAhead.IsZero() {
+		t.Errorf("Expected no queue at a new price level, got %s", improved.QueueAhead)
+	}
+
+	// Without the model, realistic mode keeps filling on touch
+	config = DefaultSimulationConfig()
+	config.Mode = ModeRealistic
+	engine = NewEngine(config, provider)
+	order, _ = engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideBuy,
+		OrderType: OrderTypeLimit,
+		Price:     d("0.49"),
+		Size:      d("50"),
+	})
+	engine.ProcessTick(ctx, "token1", d("0.49"))
+	if order.Status != OrderStatusFilled {
+		t.Errorf("Expected a fill on touch without QueueFromBook, got %s", order.Status)
+	}
+}
+
+func TestTakeProfitLadder_ScalesOut(t *testing.T) {
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("token1", decimal.NewFromFloat(0.5))
+	engine := NewEngine(&SimulationConfig{
+		Mode:           ModeSimple,
+		InitialBalance: decimal.NewFromInt(1000),
+		SlippageModel:  SlippageNone,
+	}, provider)
+	ctx := context.Background()
+
+	_, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Market:    "market1",
+		Side:      SideBuy,
+		OrderType: OrderTypeMarket,
+		Size:      decimal.NewFromInt(100),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	if err := engine.SetTakeProfit("token1", []TakeProfitLevel{
+		{Price: decimal.NewFromFloat(0.6), Fraction: decimal.NewFromFloat(0.5)},
+		{Price: decimal.NewFromFloat(0.7), Fraction: decimal.NewFromFloat(0.9)},
+	}); err == nil {
+		t.Error("Expected fractions over 1 to be rejected")
+	}
+	// Levels are given out of order; the nearer one triggers first
+	if err := engine.SetTakeProfit("token1", []TakeProfitLevel{
+		{Price: decimal.NewFromFloat(0.7), Fraction: decimal.NewFromFloat(0.3)},
+		{Price: decimal.NewFromFloat(0.6), Fraction: decimal.NewFromFloat(0.5)},
+	}); err != nil {
+		t.Fatalf("SetTakeProfit failed: %v", err)
+	}
+
+	// Below both levels: nothing happens
+	engine.ProcessTick(ctx, "token1", decimal.NewFromFloat(0.55))
+	if pos, _ := engine.GetPosition("token1"); !pos.Size.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("Expected no scale-out below the ladder, size %s", pos.Size)
+	}
+
+	// First level: sell 50 at 0.62
+	engine.ProcessTick(ctx, "token1", decimal.NewFromFloat(0.62))
+	pos, _ := engine.GetPosition("token1")
+	if !pos.Size.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("Expected 50 left after first level, got %s", pos.Size)
+	}
+	if !pos.RealizedPnL.Equal(decimal.NewFromInt(6)) {
+		t.Errorf("Expected realized PnL 6 (50 x 0.12), got %s", pos.RealizedPnL)
+	}
+
+	// Second level: sell 30 (of the original 100) at 0.75
+	engine.ProcessTick(ctx, "token1", decimal.NewFromFloat(0.75))
+	pos, _ = engine.GetPosition("token1")
+	if !pos.Size.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("Expected 20 left after second level, got %s", pos.Size)
+	}
+	if !pos.RealizedPnL.Equal(decimal.NewFromFloat(13.5)) {
+		t.Errorf("Expected realized PnL 13.5 (6 + 30 x 0.25), got %s", pos.RealizedPnL)
+	}
+	if len(pos.TakeProfit) != 0 {
+		t.Errorf("Expected ladder exhausted, %d levels left", len(pos.TakeProfit))
+	}
+
+	// The remainder is held through further gains
+	engine.ProcessTick(ctx, "token1", decimal.NewFromFloat(0.9))
+	if pos, _ := engine.GetPosition("token1"); !pos.Size.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("Expected remainder held, size %s", pos.Size)
+	}
+	if n := len(engine.GetAccount().TradeHistory); n != 3 {
+		t.Errorf("Expected 3 trades (entry + 2 tranches), got %d", n)
+	}
+}
+
+func TestShortFunding_AccruesOverHeldDays(t *testing.T) {
+	d := decimal.RequireFromString
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("token1", d("0.50"))
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	config := DefaultSimulationConfig()
+	config.TakerFeeBps = decimal.Zero
+	config.SlippageModel = SlippageNone
+	config.ShortFundingBps = d("10") // 0.1% of notional per day
+	engine := NewEngine(config, provider)
+	engine.SetClock(ClockFunc(func() time.Time { return now }))
+
+	ctx := context.Background()
+	if _, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideSell,
+		OrderType: OrderTypeMarket,
+		Size:      d("100"),
+	}); err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	opened := engine.GetBalance()
+
+	// Two days short 100 at 0.50: 50 notional * 0.1% * 2
+	now = now.Add(48 * time.Hour)
+	engine.ProcessTick(ctx, "token1", d("0.50"))
+	if got := opened.Sub(engine.GetBalance()); !got.Equal(d("0.1")) {
+		t.Fatalf("Expected 0.1 funding after two days, got %s", got)
+	}
+
+	// A day more at 0.50, then marked down to 0.40
+	now = now.Add(24 * time.Hour)
+	provider.SetMidPrice("token1", d("0.40"))
+	if err := engine.UpdatePrices(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Half a day at 0.40 is charged when the short is covered
+	now = now.Add(12 * time.Hour)
+	if _, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID:   "token1",
+		Side:      SideBuy,
+		OrderType: OrderTypeMarket,
+		Size:      d("100"),
+	}); err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	stats := engine.GetStats()
+	if !stats.TotalFunding.Equal(d("0.17")) {
+		t.Errorf("Expected 0.17 total funding, got %s", stats.TotalFunding)
+	}
+	// Shorted at 0.50 and covered at 0.40, less the borrow cost
+	if !stats.TotalPnL.Equal(d("9.83")) {
+		t.Errorf("Expected 9.83 total PnL, got %s", stats.TotalPnL)
+	}
+	if got := engine.GetBalance().Sub(config.InitialBalance); !got.Equal(d("9.83")) {
+		t.Errorf("Expected balance up 9.83, got %s", got)
+	}
+}
+
+func TestGetEquity_ValuesShortsAsLiabilities(t *testing.T) {
+	d := decimal.RequireFromString
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("long", d("0.50"))
+	provider.SetMidPrice("short", d("0.50"))
+
+	config := DefaultSimulationConfig()
+	config.TakerFeeBps = decimal.Zero
+	engine := NewEngine(config, provider)
+	ctx := context.Background()
+
+	for _, req := range []*OrderRequest{
+		{TokenID: "long", Side: SideBuy, OrderType: OrderTypeMarket, Size: d("100")},
+		{TokenID: "short", Side: SideSell, OrderType: OrderTypeMarket, Size: d("100")},
+	} {
+		if _, err := engine.PlaceOrder(ctx, req); err != nil {
+			t.Fatalf("PlaceOrder %s failed: %v", req.TokenID, err)
+		}
+	}
+	if got := engine.GetEquity(); !got.Equal(config.InitialBalance) {
+		t.Fatalf("Expected equity unchanged at entry, got %s", got)
+	}
+
+	// Both marked up 10 cents: the long gains 10, the short loses 10
+	provider.SetMidPrice("long", d("0.60"))
+	provider.SetMidPrice("short", d("0.60"))
+	if err := engine.UpdatePrices(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := engine.GetEquity(); !got.Equal(config.InitialBalance) {
+		t.Errorf("Expected offsetting long and short to leave equity flat, got %s", got)
+	}
+
+	provider.SetMidPrice("long", d("0.50"))
+	if err := engine.UpdatePrices(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if want := config.InitialBalance.Sub(d("10")); !engine.GetEquity().Equal(want) {
+		t.Errorf("Expected equity %s with the short down 10, got %s", want, engine.GetEquity())
+	}
+}
@@ -0,0 +1,154 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// Portfolio runs several independent paper accounts side by side, e.g. one
+// per strategy. Each sub-account is a full Engine with its own balance,
+// positions, and orders; they share only the price provider.
+type Portfolio struct {
+	provider PriceProvider
+
+	mu       sync.RWMutex
+	accounts map[string]*Engine
+	names    []string // Insertion order
+}
+
+// PortfolioStats aggregates statistics across sub-accounts.
+type PortfolioStats struct {
+	InitialBalance decimal.Decimal          `json:"initial_balance"`
+	Balance        decimal.Decimal          `json:"balance"`
+	Total          *AccountStats            `json:"total"`
+	Accounts       map[string]*AccountStats `json:"accounts"` // name -> stats
+}
+
+// NewPortfolio creates an empty portfolio priced by provider.
+func NewPortfolio(provider PriceProvider) *Portfolio {
+	return &Portfolio{
+		provider: provider,
+		accounts: make(map[string]*Engine),
+	}
+}
+
+// AddAccount creates a sub-account with its own config (nil uses the
+// default) and returns its engine.
+func (p *Portfolio) AddAccount(name string, config *SimulationConfig) (*Engine, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, exists := p.accounts[name]; exists {
+		return nil, fmt.Errorf("account %q already exists", name)
+	}
+
+	engine := NewEngine(config, p.provider)
+	engine.account.Name = name
+	p.accounts[name] = engine
+	p.names = append(p.names, name)
+	return engine, nil
+}
+
+// Account returns the engine for a sub-account.
+func (p *Portfolio) Account(name string) (*Engine, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	engine, ok := p.accounts[name]
+	return engine, ok
+}
+
+// Names returns the sub-account names in the order they were added.
+func (p *Portfolio) Names() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]string(nil), p.names...)
+}
+
+// ProcessTick forwards a price update to every sub-account.
+func (p *Portfolio) ProcessTick(ctx context.Context, tokenID string, midPrice decimal.Decimal) {
+	for _, engine := range p.engines() {
+		engine.ProcessTick(ctx, tokenID, midPrice)
+	}
+}
+
+// ProcessTrade forwards a reported trade to every sub-account.
+func (p *Portfolio) ProcessTrade(ctx context.Context, tokenID string, price, size decimal.Decimal) {
+	for _, engine := range p.engines() {
+		engine.ProcessTrade(ctx, tokenID, price, size)
+	}
+}
+
+// UpdatePrices marks every sub-account's positions to market.
+func (p *Portfolio) UpdatePrices(ctx context.Context) error {
+	for _, name := range p.Names() {
+		engine, _ := p.Account(name)
+		if err := engine.UpdatePrices(ctx); err != nil {
+			return fmt.Errorf("update prices for %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// GetStats returns per-account statistics and their aggregate. Counts, PnL,
+// fees, volume, and exposure are summed; rates and averages are recomputed
+// over the combined trades.
+func (p *Portfolio) GetStats() *PortfolioStats {
+	stats := &PortfolioStats{
+		Total:    &AccountStats{},
+		Accounts: make(map[string]*AccountStats),
+	}
+
+	var totalWins, totalLosses decimal.Decimal
+	for _, name := range p.Names() {
+		engine, _ := p.Account(name)
+		s := engine.GetStats()
+		stats.Accounts[name] = s
+		stats.InitialBalance = stats.InitialBalance.Add(engine.config.InitialBalance)
+		stats.Balance = stats.Balance.Add(engine.GetBalance())
+
+		t := stats.Total
+		t.TotalPnL = t.TotalPnL.Add(s.TotalPnL)
+		t.RealizedPnL = t.RealizedPnL.Add(s.RealizedPnL)
+		t.UnrealizedPnL = t.UnrealizedPnL.Add(s.UnrealizedPnL)
+		t.TotalTrades += s.TotalTrades
+		t.WinningTrades += s.WinningTrades
+		t.LosingTrades += s.LosingTrades
+		t.LargestWin = decimal.Max(t.LargestWin, s.LargestWin)
+		t.LargestLoss = decimal.Max(t.LargestLoss, s.LargestLoss)
+		t.TotalVolume = t.TotalVolume.Add(s.TotalVolume)
+		t.TotalFees = t.TotalFees.Add(s.TotalFees)
+		t.TotalFunding = t.TotalFunding.Add(s.TotalFunding)
+		t.GrossExposure = t.GrossExposure.Add(s.GrossExposure)
+		t.NetExposure = t.NetExposure.Add(s.NetExposure)
+
+		totalWins = totalWins.Add(s.AvgWin.Mul(decimal.NewFromInt(int64(s.WinningTrades))))
+		totalLosses = totalLosses.Add(s.AvgLoss.Mul(decimal.NewFromInt(int64(s.LosingTrades))))
+	}
+
+	t := stats.Total
+	if t.TotalTrades > 0 {
+		t.WinRate = decimal.NewFromInt(int64(t.WinningTrades)).Div(decimal.NewFromInt(int64(t.TotalTrades)))
+	}
+	if t.WinningTrades > 0 {
+		t.AvgWin = totalWins.Div(decimal.NewFromInt(int64(t.WinningTrades)))
+	}
+	if t.LosingTrades > 0 {
+		t.AvgLoss = totalLosses.Div(decimal.NewFromInt(int64(t.LosingTrades)))
+	}
+
+	return stats
+}
+
+func (p *Portfolio) engines() []*Engine {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	engines := make([]*Engine, 0, len(p.names))
+	for _, name := range p.names {
+		engines = append(engines, p.accounts[name])
+	}
+	return engines
+}
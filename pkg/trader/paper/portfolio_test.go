@@ -0,0 +1,111 @@
+package paper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPortfolioAggregatesSubAccounts(t *testing.T) {
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("token1", decimal.NewFromFloat(0.50))
+	provider.SetMidPrice("token2", decimal.NewFromFloat(0.40))
+
+	portfolio := NewPortfolio(provider)
+	momentum, err := portfolio.AddAccount("momentum", nil)
+	if err != nil {
+		t.Fatalf("AddAccount failed: %v", err)
+	}
+	config := DefaultSimulationConfig()
+	config.InitialBalance = decimal.NewFromInt(5000)
+	meanRev, err := portfolio.AddAccount("mean-reversion", config)
+	if err != nil {
+		t.Fatalf("AddAccount failed: %v", err)
+	}
+	if _, err := portfolio.AddAccount("momentum", nil); err == nil {
+		t.Error("Expected error for duplicate account name")
+	}
+
+	ctx := context.Background()
+	for _, trade := range []struct {
+		engine *Engine
+		token  string
+	}{{momentum, "token1"}, {meanRev, "token2"}} {
+		if _, err := trade.engine.PlaceOrder(ctx, &OrderRequest{
+			TokenID: trade.token, Side: SideBuy, OrderType: OrderTypeMarket, Size: decimal.NewFromInt(100),
+		}); err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+	}
+
+	// Sub-accounts don't share balances or positions
+	if _, ok := momentum.GetPosition("token2"); ok {
+		t.Error("momentum should not hold token2")
+	}
+	if meanRev.GetBalance().GreaterThan(decimal.NewFromInt(5000)) {
+		t.Errorf("mean-reversion balance should be drawn from its own 5000, got %s", meanRev.GetBalance())
+	}
+
+	// One winner, one loser
+	provider.SetMidPrice("token1", decimal.NewFromFloat(0.60))
+	provider.SetMidPrice("token2", decimal.NewFromFloat(0.35))
+	if err := portfolio.UpdatePrices(ctx); err != nil {
+		t.Fatalf("UpdatePrices failed: %v", err)
+	}
+
+	stats := portfolio.GetStats()
+	sum := stats.Accounts["momentum"].TotalPnL.Add(stats.Accounts["mean-reversion"].TotalPnL)
+	if !stats.Total.TotalPnL.Equal(sum) {
+		t.Errorf("Aggregate PnL %s != sum of sub-accounts %s", stats.Total.TotalPnL, sum)
+	}
+	if !stats.Accounts["momentum"].TotalPnL.IsPositive() || !stats.Accounts["mean-reversion"].TotalPnL.IsNegative() {
+		t.Errorf("Unexpected attribution: %+v", stats.Accounts)
+	}
+	if stats.Total.TotalTrades != 2 {
+		t.Errorf("Expected 2 trades in aggregate, got %d", stats.Total.TotalTrades)
+	}
+	if !stats.InitialBalance.Equal(decimal.NewFromInt(15000)) {
+		t.Errorf("Expected combined initial balance 15000, got %s", stats.InitialBalance)
+	}
+}
+
+func TestPortfolioTotalIncludesFunding(t *testing.T) {
+	d := decimal.RequireFromString
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("token1", d("0.50"))
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	config := DefaultSimulationConfig()
+	config.TakerFeeBps = decimal.Zero
+	config.ShortFundingBps = d("10")
+
+	portfolio := NewPortfolio(provider)
+	shorts, err := portfolio.AddAccount("shorts", config)
+	if err != nil {
+		t.Fatalf("AddAccount failed: %v", err)
+	}
+	shorts.SetClock(ClockFunc(func() time.Time { return now }))
+
+	ctx := context.Background()
+	if _, err := shorts.PlaceOrder(ctx, &OrderRequest{
+		TokenID: "token1", Side: SideSell, OrderType: OrderTypeMarket, Size: d("100"),
+	}); err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	// One day short 100 at 0.50: 50 notional * 0.1%
+	now = now.Add(24 * time.Hour)
+	if err := portfolio.UpdatePrices(ctx); err != nil {
+		t.Fatalf("UpdatePrices failed: %v", err)
+	}
+
+	stats := portfolio.GetStats()
+	if !stats.Total.TotalFunding.Equal(d("0.05")) {
+		t.Errorf("Expected 0.05 total funding, got %s", stats.Total.TotalFunding)
+	}
+	if !stats.Total.TotalFunding.Equal(stats.Accounts["shorts"].TotalFunding) {
+		t.Errorf("Aggregate funding %s != sub-account's %s", stats.Total.TotalFunding, stats.Accounts["shorts"].TotalFunding)
+	}
+}
@@ -0,0 +1,135 @@
+package paper
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// MergeableSize is how many YES/NO pairs of a market registered with
+// SetMarketTokens the account holds long, i.e. the most MergePositions
+// accepts. It is zero for unregistered markets.
+func (e *Engine) MergeableSize(market string) decimal.Decimal {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	yes, no, ok := e.longPair(market)
+	if !ok {
+		return decimal.Zero
+	}
+	return decimal.Min(yes.Size, no.Size)
+}
+
+// MergePositions burns size YES and size NO shares of a registered market
+// for size in collateral, like the CTF merge: no order, no fee, and no price
+// risk, since a pair always settles to 1. The realized PnL is the collateral
+// less both legs' average entry cost.
+func (e *Engine) MergePositions(market string, size decimal.Decimal) (*Settlement, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !size.IsPositive() {
+		return nil, fmt.Errorf("merge size must be positive")
+	}
+	yes, no, ok := e.longPair(market)
+	if !ok {
+		return nil, fmt.Errorf("no long YES and NO positions to merge in market %s", market)
+	}
+	if matched := decimal.Min(yes.Size, no.Size); size.GreaterThan(matched) {
+		return nil, fmt.Errorf("merge size %s exceeds matched pairs %s", size, matched)
+	}
+
+	cost := yes.AvgEntry.Add(no.AvgEntry).Mul(size)
+	e.reducePosition(yes, size)
+	e.reducePosition(no, size)
+	return e.settle(SettlementMerge, market, size, size, size.Sub(cost)), nil
+}
+
+// RedeemPositions settles every position in a resolved, registered market:
+// each winning share pays 1 and each losing share 0. Short positions pay out
+// instead. It returns nil if the account holds neither token.
+func (e *Engine) RedeemPositions(market string, yesWon bool) (*Settlement, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pair, ok := e.pairs[market]
+	if !ok {
+		return nil, fmt.Errorf("market %s has no registered tokens", market)
+	}
+
+	size, payout, pnl := decimal.Zero, decimal.Zero, decimal.Zero
+	for _, leg := range []struct {
+		tokenID string
+		won     bool
+	}{{pair.yes, yesWon}, {pair.no, !yesWon}} {
+		pos, ok := e.account.Positions[leg.tokenID]
+		if !ok {
+			continue
+		}
+		value := decimal.Zero
+		if leg.won {
+			value = decimal.NewFromInt(1)
+		}
+
+		legPayout := value.Mul(pos.Size)
+		legPnL := value.Sub(pos.AvgEntry).Mul(pos.Size)
+		if pos.Side == SideSell {
+			legPayout = legPayout.Neg()
+			legPnL = legPnL.Neg()
+		}
+		size = size.Add(pos.Size)
+		payout = payout.Add(legPayout)
+		pnl = pnl.Add(legPnL)
+		delete(e.account.Positions, leg.tokenID)
+	}
+	if size.IsZero() {
+		return nil, nil
+	}
+	return e.settle(SettlementRedeem, market, size, payout, pnl), nil
+}
+
+// longPair returns the market's long YES and NO positions. Caller holds e.mu.
+func (e *Engine) longPair(market string) (yes, no *Position, ok bool) {
+	pair, ok := e.pairs[market]
+	if !ok {
+		return nil, nil, false
+	}
+	yes, ok = e.account.Positions[pair.yes]
+	if !ok || yes.Side != SideBuy {
+		return nil, nil, false
+	}
+	no, ok = e.account.Positions[pair.no]
+	if !ok || no.Side != SideBuy {
+		return nil, nil, false
+	}
+	return yes, no, true
+}
+
+// reducePosition removes size shares from pos, deleting it when none are
+// left. Caller holds e.mu.
+func (e *Engine) reducePosition(pos *Position, size decimal.Decimal) {
+	pos.Size = pos.Size.Sub(size)
+	if !pos.Size.IsPositive() {
+		delete(e.account.Positions, pos.TokenID)
+		return
+	}
+	pos.UnrealizedPnL = pos.CurrentPrice.Sub(pos.AvgEntry).Mul(pos.Size)
+	pos.UpdatedAt = e.clock.Now()
+}
+
+// settle credits payout and records the settlement. Caller holds e.mu.
+func (e *Engine) settle(kind SettlementKind, market string, size, payout, pnl decimal.Decimal) *Settlement {
+	e.account.Balance = e.account.Balance.Add(payout)
+	s := Settlement{
+		ID:        fmt.Sprintf("settle-%d", len(e.account.Settlements)+1),
+		Kind:      kind,
+		Market:    market,
+		Size:      size,
+		Payout:    payout,
+		PnL:       pnl,
+		Timestamp: e.clock.Now(),
+	}
+	e.account.Settlements = append(e.account.Settlements, s)
+	e.account.UpdatedAt = s.Timestamp
+	return &s
+}
@@ -0,0 +1,95 @@
+package paper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMergePositions_ReturnsCollateralWithoutTrade(t *testing.T) {
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("yes", decimal.NewFromFloat(0.6))
+	provider.SetMidPrice("no", decimal.NewFromFloat(0.38))
+
+	engine := NewEngine(DefaultSimulationConfig(), provider)
+	engine.SetMarketTokens("market1", "yes", "no")
+
+	ctx := context.Background()
+	for _, tokenID := range []string{"yes", "no"} {
+		_, err := engine.PlaceOrder(ctx, &OrderRequest{
+			TokenID:   tokenID,
+			Market:    "market1",
+			Side:      SideBuy,
+			OrderType: OrderTypeMarket,
+			Size:      decimal.NewFromInt(100),
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder %s failed: %v", tokenID, err)
+		}
+	}
+	if !engine.MergeableSize("market1").Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("Expected 100 mergeable pairs, got %s", engine.MergeableSize("market1"))
+	}
+
+	yes, _ := engine.GetPosition("yes")
+	no, _ := engine.GetPosition("no")
+	entryCost := yes.AvgEntry.Add(no.AvgEntry)
+	before := engine.GetStats()
+	balanceBefore := engine.GetBalance()
+
+	size := decimal.NewFromInt(60)
+	settlement, err := engine.MergePositions("market1", size)
+	if err != nil {
+		t.Fatalf("MergePositions failed: %v", err)
+	}
+
+	if got := engine.GetBalance().Sub(balanceBefore); !got.Equal(size) {
+		t.Errorf("Expected merge to return %s collateral, got %s", size, got)
+	}
+	wantPnL := decimal.NewFromInt(1).Sub(entryCost).Mul(size)
+	if !settlement.PnL.Equal(wantPnL) || settlement.Kind != SettlementMerge {
+		t.Errorf("Expected merge PnL %s, got %+v", wantPnL, settlement)
+	}
+
+	after := engine.GetStats()
+	if after.TotalTrades != before.TotalTrades || !after.TotalFees.Equal(before.TotalFees) || !after.TotalVolume.Equal(before.TotalVolume) {
+		t.Errorf("Merge must not trade: trades %d→%d, fees %s→%s, volume %s→%s",
+			before.TotalTrades, after.TotalTrades, before.TotalFees, after.TotalFees, before.TotalVolume, after.TotalVolume)
+	}
+	if !after.RealizedPnL.Sub(before.RealizedPnL).Equal(wantPnL) {
+		t.Errorf("Expected realized PnL to grow by %s, got %s", wantPnL, after.RealizedPnL.Sub(before.RealizedPnL))
+	}
+	for _, tokenID := range []string{"yes", "no"} {
+		if pos, ok := engine.GetPosition(tokenID); !ok || !pos.Size.Equal(decimal.NewFromInt(40)) {
+			t.Errorf("Expected 40 %s shares left, got %v", tokenID, pos)
+		}
+	}
+
+	if _, err := engine.MergePositions("market1", decimal.NewFromInt(41)); err == nil {
+		t.Error("Merging more than the matched pairs should fail")
+	}
+	if _, err := engine.MergePositions("unregistered", decimal.NewFromInt(1)); err == nil {
+		t.Error("Merging in an unregistered market should fail")
+	}
+
+	// YES resolves: 40 winning shares pay 1, the NO shares nothing
+	balanceBefore = engine.GetBalance()
+	redeemed, err := engine.RedeemPositions("market1", true)
+	if err != nil {
+		t.Fatalf("RedeemPositions failed: %v", err)
+	}
+	forty := decimal.NewFromInt(40)
+	if !redeemed.Payout.Equal(forty) || !engine.GetBalance().Sub(balanceBefore).Equal(forty) {
+		t.Errorf("Expected redemption to pay 40, got %+v", redeemed)
+	}
+	if want := decimal.NewFromInt(1).Sub(entryCost).Mul(forty); !redeemed.PnL.Equal(want) {
+		t.Errorf("Expected redemption PnL %s, got %s", want, redeemed.PnL)
+	}
+	if len(engine.GetPositions()) != 0 {
+		t.Errorf("Expected no positions after redemption, got %d", len(engine.GetPositions()))
+	}
+	if stats := engine.GetStatsByMarket()["market1"]; !stats.RealizedPnL.Equal(wantPnL.Add(redeemed.PnL)) {
+		t.Errorf("Expected market realized PnL to include both settlements, got %s", stats.RealizedPnL)
+	}
+}
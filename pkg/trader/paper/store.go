@@ -0,0 +1,75 @@
+package paper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// AccountStore persists paper trading accounts so a session can survive a
+// restart. Save must not retain the account: the engine keeps mutating it.
+type AccountStore interface {
+	Save(account *Account) error
+	// Load returns an error wrapping os.ErrNotExist if no account has the ID.
+	Load(id string) (*Account, error)
+}
+
+// FileAccountStore stores each account as <dir>/<id>.json.
+type FileAccountStore struct {
+	dir string
+}
+
+// NewFileAccountStore creates a file store rooted at dir, creating it if needed.
+func NewFileAccountStore(dir string) (*FileAccountStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create store dir: %w", err)
+	}
+	return &FileAccountStore{dir: dir}, nil
+}
+
+// Save implements AccountStore. The file is replaced atomically so a crash
+// mid-write leaves the previous snapshot intact.
+func (s *FileAccountStore) Save(account *Account) error {
+	data, err := json.MarshalIndent(account, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal account: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, account.ID+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write account: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write account: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(account.ID)); err != nil {
+		return fmt.Errorf("replace account file: %w", err)
+	}
+	return nil
+}
+
+// Load implements AccountStore.
+func (s *FileAccountStore) Load(id string) (*Account, error) {
+	data, err := os.ReadFile(s.path(id))
+	if err != nil {
+		return nil, fmt.Errorf("read account %s: %w", id, err)
+	}
+
+	var account Account
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("decode account %s: %w", id, err)
+	}
+	return &account, nil
+}
+
+func (s *FileAccountStore) path(id string) string {
+	return filepath.Join(s.dir, filepath.Base(id)+".json")
+}
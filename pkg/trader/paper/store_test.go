@@ -0,0 +1,79 @@
+package paper
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestFileAccountStoreRoundTrip(t *testing.T) {
+	provider := newMockPriceProvider()
+	provider.SetMidPrice("token1", decimal.NewFromFloat(0.50))
+	engine := NewEngine(DefaultSimulationConfig(), provider)
+	ctx := context.Background()
+
+	// One filled buy (position + trade) and one resting limit order
+	if _, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID: "token1", Market: "market1", Side: SideBuy, OrderType: OrderTypeMarket, Size: decimal.NewFromInt(100),
+	}); err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	resting, err := engine.PlaceOrder(ctx, &OrderRequest{
+		TokenID: "token1", Market: "market1", Side: SideSell, OrderType: OrderTypeLimit,
+		Price: decimal.NewFromFloat(0.60), Size: decimal.NewFromInt(50),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	store, err := NewFileAccountStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileAccountStore failed: %v", err)
+	}
+	if err := engine.Save(store); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	saved := engine.GetAccount()
+	loaded, err := store.Load(saved.ID)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	restored := NewEngine(DefaultSimulationConfig(), provider)
+	restored.Restore(loaded)
+
+	if !restored.GetBalance().Equal(engine.GetBalance()) {
+		t.Errorf("Balance: expected %s, got %s", engine.GetBalance(), restored.GetBalance())
+	}
+	pos, ok := restored.GetPosition("token1")
+	if !ok || !pos.Size.Equal(decimal.NewFromInt(100)) || pos.Market != "market1" {
+		t.Errorf("Position not restored: %+v", pos)
+	}
+	order, ok := restored.GetOrder(resting.ID)
+	if !ok || !order.Price.Equal(decimal.NewFromFloat(0.60)) || order.Status != OrderStatusOpen {
+		t.Errorf("Open order not restored: %+v", order)
+	}
+	if got := len(restored.GetAccount().TradeHistory); got != 1 {
+		t.Errorf("Expected 1 trade in history, got %d", got)
+	}
+
+	// New orders must not reuse restored IDs
+	next, err := restored.PlaceOrder(ctx, &OrderRequest{
+		TokenID: "token1", Side: SideBuy, OrderType: OrderTypeLimit,
+		Price: decimal.NewFromFloat(0.40), Size: decimal.NewFromInt(10),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder after restore failed: %v", err)
+	}
+	if next.ID == resting.ID {
+		t.Errorf("Order ID %s reused after restore", next.ID)
+	}
+
+	if _, err := store.Load("missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected os.ErrNotExist for missing account, got %v", err)
+	}
+}
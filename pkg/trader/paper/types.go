@@ -52,6 +52,20 @@ type Order struct {
 	UpdatedAt    time.Time       `json:"updated_at"`
 	Expiration   time.Time       `json:"expiration,omitempty"`
 	Fills        []Fill          `json:"fills,omitempty"`
+
+	// Repricing (limit orders placed with RepriceOnDrift)
+	RepriceOnDrift decimal.Decimal `json:"reprice_on_drift,omitempty"`
+	QuoteOffset    decimal.Decimal `json:"quote_offset,omitempty"`  // Price minus mid when quoted
+	RepricedFrom   string          `json:"repriced_from,omitempty"` // ID of the order this replaced
+
+	// NettedSize is the quantity canceled against the account's own opposing
+	// orders instead of self-trading. It is not included in Size.
+	NettedSize decimal.Decimal `json:"netted_size,omitempty"`
+
+	// QueueAhead is the size still resting ahead of this limit order at its
+	// price under SimulationConfig.QueueDepth or QueueFromBook; it fills only
+	// once trades reported to ProcessTrade have consumed it.
+	QueueAhead decimal.Decimal `json:"queue_ahead,omitempty"`
 }
 
 // Side represents order side.
@@ -121,6 +135,7 @@ type Fill struct {
 	Size      decimal.Decimal `json:"size"`
 	Timestamp time.Time       `json:"timestamp"`
 	Fee       decimal.Decimal `json:"fee"`
+	Maker     bool            `json:"maker"` // Filled while resting rather than on placement
 }
 
 // Position represents a position in a market.
@@ -135,6 +150,23 @@ type Position struct {
 	RealizedPnL   decimal.Decimal `json:"realized_pnl"`
 	OpenedAt      time.Time       `json:"opened_at"`
 	UpdatedAt     time.Time       `json:"updated_at"`
+
+	// FundingPaid is the short borrow cost charged on this position so far,
+	// accrued up to FundedAt; see SimulationConfig.ShortFundingBps.
+	FundingPaid decimal.Decimal `json:"funding_paid,omitempty"`
+	FundedAt    time.Time       `json:"funded_at,omitempty"`
+
+	// TakeProfit is the ladder still to be hit, nearest level first; see
+	// Engine.SetTakeProfit.
+	TakeProfit []TakeProfitLevel `json:"take_profit,omitempty"`
+}
+
+// TakeProfitLevel closes Fraction of a position once the price reaches
+// Price: at or above it for a long, at or below for a short.
+type TakeProfitLevel struct {
+	Price    decimal.Decimal `json:"price"`
+	Fraction decimal.Decimal `json:"fraction"` // Of the position size when the ladder was set (0-1]
+	Size     decimal.Decimal `json:"size"`     // Shares to close, set by SetTakeProfit
 }
 
 // Trade represents a completed trade.
@@ -151,6 +183,29 @@ type Trade struct {
 	Timestamp time.Time       `json:"timestamp"`
 }
 
+// SettlementKind is how positions were closed without a market trade.
+type SettlementKind string
+
+const (
+	// SettlementMerge burned matched YES/NO shares for 1 each.
+	SettlementMerge SettlementKind = "merge"
+	// SettlementRedeem paid out a resolved market: 1 per winning share.
+	SettlementRedeem SettlementKind = "redeem"
+)
+
+// Settlement records positions closed through the Conditional Tokens
+// contract rather than the book. It pays no fee and is not a Trade, but its
+// PnL counts toward realized PnL.
+type Settlement struct {
+	ID        string          `json:"id"`
+	Kind      SettlementKind  `json:"kind"`
+	Market    string          `json:"market"`
+	Size      decimal.Decimal `json:"size"`   // Pairs merged, or shares redeemed
+	Payout    decimal.Decimal `json:"payout"` // Collateral credited
+	PnL       decimal.Decimal `json:"pnl"`    // Payout less the shares' entry cost
+	Timestamp time.Time       `json:"timestamp"`
+}
+
 // Account represents a paper trading account.
 type Account struct {
 	ID             string               `json:"id"`
@@ -160,14 +215,16 @@ type Account struct {
 	Positions      map[string]*Position `json:"positions"`   // tokenID -> position
 	OpenOrders     map[string]*Order    `json:"open_orders"` // orderID -> order
 	TradeHistory   []Trade              `json:"trade_history"`
+	Settlements    []Settlement         `json:"settlements,omitempty"`
+	TotalFunding   decimal.Decimal      `json:"total_funding,omitempty"` // Short borrow cost, including closed positions
 	CreatedAt      time.Time            `json:"created_at"`
 	UpdatedAt      time.Time            `json:"updated_at"`
 }
 
 // AccountStats provides account statistics.
 type AccountStats struct {
-	TotalPnL      decimal.Decimal `json:"total_pnl"`
-	RealizedPnL   decimal.Decimal `json:"realized_pnl"`
+	TotalPnL      decimal.Decimal `json:"total_pnl"`    // Realized + unrealized - fees - funding
+	RealizedPnL   decimal.Decimal `json:"realized_pnl"` // Before fees, including settlements
 	UnrealizedPnL decimal.Decimal `json:"unrealized_pnl"`
 	TotalTrades   int             `json:"total_trades"`
 	WinningTrades int             `json:"winning_trades"`
@@ -181,6 +238,22 @@ type AccountStats struct {
 	MaxDrawdown   decimal.Decimal `json:"max_drawdown"`
 	TotalVolume   decimal.Decimal `json:"total_volume"`
 	TotalFees     decimal.Decimal `json:"total_fees"`
+	TotalFunding  decimal.Decimal `json:"total_funding"`  // Short borrow cost
+	GrossExposure decimal.Decimal `json:"gross_exposure"` // Sum of position notionals
+	NetExposure   decimal.Decimal `json:"net_exposure"`   // Gross minus matched YES/NO pairs
+}
+
+// MarketStats attributes PnL and activity to a single market.
+type MarketStats struct {
+	Market        string          `json:"market"`
+	RealizedPnL   decimal.Decimal `json:"realized_pnl"`
+	UnrealizedPnL decimal.Decimal `json:"unrealized_pnl"`
+	TotalPnL      decimal.Decimal `json:"total_pnl"` // Net of fees
+	TotalFees     decimal.Decimal `json:"total_fees"`
+	TotalTrades   int             `json:"total_trades"`
+	WinningTrades int             `json:"winning_trades"`
+	LosingTrades  int             `json:"losing_trades"`
+	WinRate       decimal.Decimal `json:"win_rate"`
 }
 
 // OrderRequest is a request to place an order.
@@ -192,6 +265,15 @@ type OrderRequest struct {
 	Price      decimal.Decimal `json:"price"` // Required for limit orders
 	Size       decimal.Decimal `json:"size"`
 	Expiration time.Duration   `json:"expiration"` // Optional TTL
+
+	// RepriceOnDrift, if positive, makes a resting limit order follow the
+	// market: when the mid moves more than this far from the mid at quote
+	// time, ProcessTick cancels the order and re-posts the remaining size at
+	// the same offset from the new mid.
+	RepriceOnDrift decimal.Decimal `json:"reprice_on_drift,omitempty"`
+
+	// PostOnly rejects a limit order that would fill immediately on placement.
+	PostOnly bool `json:"post_only,omitempty"`
 }
 
 // SimulationConfig configures the paper trading simulation.
@@ -199,14 +281,41 @@ type SimulationConfig struct {
 	Mode           Mode            `json:"mode"`
 	InitialBalance decimal.Decimal `json:"initial_balance"`
 
-	// Fee settings
-	MakerFeeBps decimal.Decimal `json:"maker_fee_bps"`
-	TakerFeeBps decimal.Decimal `json:"taker_fee_bps"`
+	// Fee settings. MakerRebateBps is paid back on maker fills on top of
+	// MakerFeeBps, so a rebate above the fee makes the fill's Fee negative: a
+	// credit to the balance, TotalFees and TotalPnL.
+	MakerFeeBps    decimal.Decimal `json:"maker_fee_bps"`
+	TakerFeeBps    decimal.Decimal `json:"taker_fee_bps"`
+	MakerRebateBps decimal.Decimal `json:"maker_rebate_bps,omitempty"`
+
+	// Realistic mode settings. SimpleModeSlippage also applies
+	// SlippageModel to market orders in simple mode, which otherwise fill
+	// at the mid.
+	SlippageModel      SlippageModel   `json:"slippage_model"`
+	SimpleModeSlippage bool            `json:"simple_mode_slippage,omitempty"`
+	FillProbability    decimal.Decimal `json:"fill_probability"` // 0-1, chance of fill per tick
+	LatencyMs          int             `json:"latency_ms"`       // Simulated latency, see below
+
+	// QueueFromBook queues each resting limit order behind the size the book
+	// shows at its price and side when it is placed (or repriced), instead of
+	// QueueDepth, so it fills only after that much has traded there (see
+	// ProcessTrade). An order setting a new price level is at the front.
+	// Realistic mode only; without it a touch of the price fills the order.
+	QueueFromBook bool `json:"queue_from_book,omitempty"`
+
+	// Simple mode settings. In simple mode LatencyMs delays fills: fill,
+	// trade, and order UpdatedAt timestamps land this long after the event
+	// that caused them. QueueDepth is the size assumed to rest ahead of each
+	// new limit order at its price; the order fills only after that much
+	// volume has traded at or through its price, which the caller must report
+	// through ProcessTrade. Zero disables either model.
+	QueueDepth decimal.Decimal `json:"queue_depth,omitempty"`
 
-	// Realistic mode settings
-	SlippageModel   SlippageModel   `json:"slippage_model"`
-	FillProbability decimal.Decimal `json:"fill_probability"` // 0-1, chance of fill per tick
-	LatencyMs       int             `json:"latency_ms"`       // Simulated latency
+	// ShortFundingBps is the daily borrow cost of a short position, in bps
+	// of its notional at the current price. It accrues continuously and is
+	// taken from the balance whenever the position is marked (UpdatePrices,
+	// ProcessTick) or resized. Zero disables it.
+	ShortFundingBps decimal.Decimal `json:"short_funding_bps,omitempty"`
 
 	// Backtest settings
 	StartTime  time.Time `json:"start_time"`
@@ -6,6 +6,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/paper"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -17,11 +19,26 @@ type RiskLimits struct {
 	MaxConcentration decimal.Decimal // Max % of exposure in single market (0-1)
 	MaxOpenOrders    int             // Max concurrent open orders
 
+	// MaxCategoryExposure caps the combined exposure of all markets in a
+	// category, as a fraction (0-1) of MaxTotalExposure, e.g. {"Crypto": 0.4}.
+	// Markets are assigned with SetMarketCategory; unassigned markets and
+	// categories without an entry are not capped.
+	MaxCategoryExposure map[string]decimal.Decimal
+
+	// SkewFactor (0-1) tightens MaxPositionSize for orders that add to an
+	// existing position: the effective limit is MaxPositionSize * (1 - SkewFactor).
+	// Orders that reduce inventory are checked against the full limit.
+	SkewFactor decimal.Decimal
+
 	// Daily limits
 	MaxDailyLoss   decimal.Decimal // Max loss per day
 	MaxDailyVolume decimal.Decimal // Max volume per day
 	MaxDailyOrders int             // Max orders per day
 
+	// MaxConsecutiveLosses halts trading after this many losing fills in a
+	// row until a win or ResetStreak. Zero disables it.
+	MaxConsecutiveLosses int
+
 	// Per-trade limits
 	MaxOrderSize decimal.Decimal // Max single order size
 	MinOrderSize decimal.Decimal // Min single order size
@@ -30,6 +47,7 @@ type RiskLimits struct {
 	// Time limits
 	CooldownAfterLoss  time.Duration // Cooldown after significant loss
 	MaxSessionDuration time.Duration // Max continuous trading session
+	MaxPositionAge     time.Duration // Liquidate positions held longer than this (0 = no limit)
 
 	// Market restrictions
 	AllowedMarkets []string // If set, only trade these markets
@@ -84,6 +102,7 @@ type PolicyEngine struct {
 
 	mu           sync.RWMutex
 	positions    map[string]decimal.Decimal // market -> size
+	categories   map[string]string          // market -> category
 	openOrders   int
 	dailyLoss    decimal.Decimal
 	dailyVolume  decimal.Decimal
@@ -91,6 +110,7 @@ type PolicyEngine struct {
 	lastLossTime time.Time
 	sessionStart time.Time
 	lastTradeDay int // Day of year
+	streak       int // >0 consecutive winning fills, <0 consecutive losing fills
 }
 
 // NewPolicyEngine creates a new policy engine with the given limits.
@@ -101,6 +121,7 @@ func NewPolicyEngine(limits *RiskLimits) *PolicyEngine {
 	return &PolicyEngine{
 		limits:       limits,
 		positions:    make(map[string]decimal.Decimal),
+		categories:   make(map[string]string),
 		sessionStart: time.Now(),
 		lastTradeDay: time.Now().YearDay(),
 	}
@@ -111,37 +132,78 @@ func (p *PolicyEngine) CheckOrder(market string, size, price decimal.Decimal, is
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	_, err := p.checkOrderLocked(market, size, price, isBuy)
+	return err
+}
+
+// ProposedOrder is an order to evaluate with SimulateOrders.
+type ProposedOrder struct {
+	Market string          `json:"market"`
+	Size   decimal.Decimal `json:"size"`
+	Price  decimal.Decimal `json:"price"`
+	IsBuy  bool            `json:"is_buy"`
+}
+
+// PolicyDecision is the outcome of evaluating a ProposedOrder.
+type PolicyDecision struct {
+	Order   ProposedOrder `json:"order"`
+	Allowed bool          `json:"allowed"`
+	Limit   string        `json:"limit,omitempty"`  // Name of the breached limit
+	Reason  string        `json:"reason,omitempty"` // Error from the breached check
+}
+
+// SimulateOrders evaluates each order against the current state without
+// recording anything. Orders are evaluated independently, not cumulatively.
+func (p *PolicyEngine) SimulateOrders(orders []ProposedOrder) []PolicyDecision {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	decisions := make([]PolicyDecision, len(orders))
+	for i, order := range orders {
+		limit, err := p.checkOrderLocked(order.Market, order.Size, order.Price, order.IsBuy)
+		decisions[i] = PolicyDecision{Order: order, Allowed: err == nil}
+		if err != nil {
+			decisions[i].Limit = limit
+			decisions[i].Reason = err.Error()
+		}
+	}
+	return decisions
+}
+
+// checkOrderLocked runs every limit check in order and returns the name of
+// the first limit breached along with its error. Caller holds p.mu.
+func (p *PolicyEngine) checkOrderLocked(market string, size, price decimal.Decimal, isBuy bool) (string, error) {
 	// Reset daily counters if new day
 	p.resetDailyIfNeeded()
 
 	// Check if market is allowed
 	if err := p.checkMarketAllowed(market); err != nil {
-		return err
+		return "market_restriction", err
 	}
 
 	// Check order size limits
 	orderValue := size.Mul(price)
 	if orderValue.GreaterThan(p.limits.MaxOrderSize) {
-		return fmt.Errorf("order size $%s exceeds max $%s", orderValue, p.limits.MaxOrderSize)
+		return "max_order_size", fmt.Errorf("order size $%s exceeds max $%s", orderValue, p.limits.MaxOrderSize)
 	}
 	if orderValue.LessThan(p.limits.MinOrderSize) {
-		return fmt.Errorf("order size $%s below min $%s", orderValue, p.limits.MinOrderSize)
+		return "min_order_size", fmt.Errorf("order size $%s below min $%s", orderValue, p.limits.MinOrderSize)
 	}
 
 	// Check open orders limit
 	if p.openOrders >= p.limits.MaxOpenOrders {
-		return fmt.Errorf("too many open orders: %d >= %d", p.openOrders, p.limits.MaxOpenOrders)
+		return "max_open_orders", fmt.Errorf("too many open orders: %d >= %d", p.openOrders, p.limits.MaxOpenOrders)
 	}
 
 	// Check daily limits
 	if p.dailyOrders >= p.limits.MaxDailyOrders {
-		return fmt.Errorf("daily order limit reached: %d", p.limits.MaxDailyOrders)
+		return "max_daily_orders", fmt.Errorf("daily order limit reached: %d", p.limits.MaxDailyOrders)
 	}
 	if p.dailyVolume.Add(orderValue).GreaterThan(p.limits.MaxDailyVolume) {
-		return fmt.Errorf("would exceed daily volume limit $%s", p.limits.MaxDailyVolume)
+		return "max_daily_volume", fmt.Errorf("would exceed daily volume limit $%s", p.limits.MaxDailyVolume)
 	}
-	if p.dailyLoss.GreaterThan(p.limits.MaxDailyLoss) {
-		return fmt.Errorf("daily loss limit exceeded: $%s", p.dailyLoss)
+	if reason, err := p.haltedLocked(); err != nil {
+		return reason, err
 	}
 
 	// Check position limits
@@ -154,7 +216,15 @@ func (p *PolicyEngine) CheckOrder(market string, size, price decimal.Decimal, is
 	}
 
 	if newPos.Abs().GreaterThan(p.limits.MaxPositionSize) {
-		return fmt.Errorf("position size would exceed limit: $%s > $%s", newPos.Abs(), p.limits.MaxPositionSize)
+		return "max_position_size", fmt.Errorf("position size would exceed limit: $%s > $%s", newPos.Abs(), p.limits.MaxPositionSize)
+	}
+
+	// Apply inventory skew to orders that grow an existing position
+	if !currentPos.IsZero() && newPos.Abs().GreaterThan(currentPos.Abs()) && p.limits.SkewFactor.IsPositive() {
+		skewedLimit := p.limits.MaxPositionSize.Mul(decimal.NewFromInt(1).Sub(p.limits.SkewFactor))
+		if newPos.Abs().GreaterThan(skewedLimit) {
+			return "inventory_skew", fmt.Errorf("order adds to inventory beyond skewed limit: $%s > $%s", newPos.Abs(), skewedLimit)
+		}
 	}
 
 	// Check total exposure (using position sizes as exposure proxy)
@@ -164,7 +234,7 @@ func (p *PolicyEngine) CheckOrder(market string, size, price decimal.Decimal, is
 		newTotalExposure = totalExposure.Add(size)
 	}
 	if newTotalExposure.GreaterThan(p.limits.MaxTotalExposure) {
-		return fmt.Errorf("total exposure would exceed limit: $%s > $%s", newTotalExposure, p.limits.MaxTotalExposure)
+		return "max_total_exposure", fmt.Errorf("total exposure would exceed limit: $%s > $%s", newTotalExposure, p.limits.MaxTotalExposure)
 	}
 
 	// Check concentration (position size as % of total exposure)
@@ -173,24 +243,35 @@ func (p *PolicyEngine) CheckOrder(market string, size, price decimal.Decimal, is
 		// Single market is always 100% concentration by definition
 		concentration := newPos.Abs().Div(newTotalExposure)
 		if concentration.GreaterThan(p.limits.MaxConcentration) {
-			return fmt.Errorf("concentration would exceed limit: %.2f%% > %.2f%%",
+			return "max_concentration", fmt.Errorf("concentration would exceed limit: %.2f%% > %.2f%%",
 				concentration.Mul(decimal.NewFromInt(100)).InexactFloat64(),
 				p.limits.MaxConcentration.Mul(decimal.NewFromInt(100)).InexactFloat64())
 		}
 	}
 
+	// Check category exposure
+	if category, ok := p.categories[market]; ok {
+		if maxShare, capped := p.limits.MaxCategoryExposure[category]; capped {
+			categoryLimit := p.limits.MaxTotalExposure.Mul(maxShare)
+			categoryExposure := p.categoryExposureLocked(category).Sub(currentPos.Abs()).Add(newPos.Abs())
+			if categoryExposure.GreaterThan(categoryLimit) {
+				return "max_category_exposure", fmt.Errorf("%s exposure would exceed limit: $%s > $%s", category, categoryExposure, categoryLimit)
+			}
+		}
+	}
+
 	// Check cooldown after loss
 	if !p.lastLossTime.IsZero() && time.Since(p.lastLossTime) < p.limits.CooldownAfterLoss {
 		remaining := p.limits.CooldownAfterLoss - time.Since(p.lastLossTime)
-		return fmt.Errorf("in cooldown period after loss, %v remaining", remaining)
+		return "loss_cooldown", fmt.Errorf("in cooldown period after loss, %v remaining", remaining)
 	}
 
 	// Check session duration
 	if time.Since(p.sessionStart) > p.limits.MaxSessionDuration {
-		return fmt.Errorf("max session duration exceeded: %v", p.limits.MaxSessionDuration)
+		return "max_session_duration", fmt.Errorf("max session duration exceeded: %v", p.limits.MaxSessionDuration)
 	}
 
-	return nil
+	return "", nil
 }
 
 // RecordOrder records an order being placed.
@@ -231,12 +312,25 @@ func (p *PolicyEngine) RecordFill(market string, size, price decimal.Decimal, is
 		p.lastLossTime = time.Now()
 	}
 
+	// Fills that realize nothing (e.g. opening a position) don't affect the streak
+	switch {
+	case pnl.IsPositive():
+		p.streak = max(p.streak, 0) + 1
+	case pnl.IsNegative():
+		p.streak = min(p.streak, 0) - 1
+	}
+
 	// Decrement open orders (order was filled)
 	if p.openOrders > 0 {
 		p.openOrders--
 	}
 }
 
+// MaxSlippage returns the slippage tolerance CheckSlippage enforces (0-1).
+func (p *PolicyEngine) MaxSlippage() decimal.Decimal {
+	return p.limits.MaxSlippage
+}
+
 // CheckSlippage checks if slippage is acceptable.
 func (p *PolicyEngine) CheckSlippage(expectedPrice, actualPrice decimal.Decimal) error {
 	if expectedPrice.IsZero() {
@@ -252,6 +346,22 @@ func (p *PolicyEngine) CheckSlippage(expectedPrice, actualPrice decimal.Decimal)
 	return nil
 }
 
+// StalePositions returns the token IDs of positions open longer than
+// MaxPositionAge as of now, which should be liquidated.
+func (p *PolicyEngine) StalePositions(positions []*paper.Position, now time.Time) []string {
+	if p.limits.MaxPositionAge <= 0 {
+		return nil
+	}
+
+	var stale []string
+	for _, pos := range positions {
+		if now.Sub(pos.OpenedAt) > p.limits.MaxPositionAge {
+			stale = append(stale, pos.TokenID)
+		}
+	}
+	return stale
+}
+
 // GetPosition returns the current position in a market.
 func (p *PolicyEngine) GetPosition(market string) decimal.Decimal {
 	p.mu.RLock()
@@ -266,6 +376,33 @@ func (p *PolicyEngine) GetTotalExposure() decimal.Decimal {
 	return p.calculateTotalExposure()
 }
 
+// SetMarketCategory assigns a market to a category for MaxCategoryExposure.
+// An empty category removes the assignment.
+func (p *PolicyEngine) SetMarketCategory(market, category string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if category == "" {
+		delete(p.categories, market)
+		return
+	}
+	p.categories[market] = category
+}
+
+// CategoryExposure returns total exposure per category. Markets without a
+// category are not included.
+func (p *PolicyEngine) CategoryExposure() map[string]decimal.Decimal {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	exposure := make(map[string]decimal.Decimal)
+	for market, pos := range p.positions {
+		if category, ok := p.categories[market]; ok {
+			exposure[category] = exposure[category].Add(pos.Abs())
+		}
+	}
+	return exposure
+}
+
 // GetDailyStats returns daily trading statistics.
 func (p *PolicyEngine) GetDailyStats() (loss, volume decimal.Decimal, orders int) {
 	p.mu.RLock()
@@ -273,6 +410,21 @@ func (p *PolicyEngine) GetDailyStats() (loss, volume decimal.Decimal, orders int
 	return p.dailyLoss, p.dailyVolume, p.dailyOrders
 }
 
+// CurrentStreak returns the run of consecutive realized wins (positive) or
+// losses (negative), e.g. for scaling size with recent performance.
+func (p *PolicyEngine) CurrentStreak() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.streak
+}
+
+// ResetStreak clears the win/loss streak, lifting a MaxConsecutiveLosses halt.
+func (p *PolicyEngine) ResetStreak() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.streak = 0
+}
+
 // ResetSession resets the session timer.
 func (p *PolicyEngine) ResetSession() {
 	p.mu.Lock()
@@ -300,6 +452,16 @@ func (p *PolicyEngine) calculateTotalExposure() decimal.Decimal {
 	return total
 }
 
+func (p *PolicyEngine) categoryExposureLocked(category string) decimal.Decimal {
+	total := decimal.Zero
+	for market, pos := range p.positions {
+		if p.categories[market] == category {
+			total = total.Add(pos.Abs())
+		}
+	}
+	return total
+}
+
 func (p *PolicyEngine) checkMarketAllowed(market string) error {
 	// Check blocklist
 	for _, blocked := range p.limits.BlockedMarkets {
@@ -335,8 +497,14 @@ type PolicyStatus struct {
 	MaxDailyOrders  int    `json:"max_daily_orders"`
 	SessionDuration string `json:"session_duration"`
 	MaxSessionDur   string `json:"max_session_duration"`
+	Streak          int    `json:"streak"`
 	InCooldown      bool   `json:"in_cooldown"`
 	CooldownRemain  string `json:"cooldown_remaining,omitempty"`
+
+	// Halted is set while a loss limit rejects every order until reset;
+	// HaltReason is the rejecting check, as reported by CheckOrder.
+	Halted     bool   `json:"halted"`
+	HaltReason string `json:"halt_reason,omitempty"`
 }
 
 // Status returns the current policy status.
@@ -357,6 +525,7 @@ func (p *PolicyEngine) Status() PolicyStatus {
 		MaxDailyOrders:  p.limits.MaxDailyOrders,
 		SessionDuration: time.Since(p.sessionStart).Round(time.Second).String(),
 		MaxSessionDur:   p.limits.MaxSessionDuration.String(),
+		Streak:          p.streak,
 	}
 
 	if !p.lastLossTime.IsZero() && time.Since(p.lastLossTime) < p.limits.CooldownAfterLoss {
@@ -364,5 +533,21 @@ func (p *PolicyEngine) Status() PolicyStatus {
 		status.CooldownRemain = (p.limits.CooldownAfterLoss - time.Since(p.lastLossTime)).Round(time.Second).String()
 	}
 
+	if reason, err := p.haltedLocked(); err != nil {
+		status.Halted, status.HaltReason = true, reason
+	}
+
 	return status
 }
+
+// haltedLocked reports whether trading is halted by the daily loss or
+// consecutive loss limits, with the violation name used in metrics.
+func (p *PolicyEngine) haltedLocked() (string, error) {
+	if p.dailyLoss.GreaterThan(p.limits.MaxDailyLoss) {
+		return "max_daily_loss", fmt.Errorf("daily loss limit exceeded: $%s", p.dailyLoss)
+	}
+	if limit := p.limits.MaxConsecutiveLosses; limit > 0 && -p.streak >= limit {
+		return "max_consecutive_losses", fmt.Errorf("halted after %d consecutive losses", -p.streak)
+	}
+	return "", nil
+}
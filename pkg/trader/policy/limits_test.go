@@ -1,9 +1,12 @@
 package policy
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/paper"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -247,6 +250,40 @@ func TestCheckOrder_PositionLimit(t *testing.T) {
 	}
 }
 
+func TestCheckOrder_InventorySkew(t *testing.T) {
+	limits := &RiskLimits{
+		MaxPositionSize:    decimal.NewFromInt(100),
+		SkewFactor:         decimal.NewFromFloat(0.2), // Adds capped at $80
+		MaxTotalExposure:   decimal.NewFromInt(50000),
+		MaxConcentration:   decimal.NewFromInt(1),
+		MaxOrderSize:       decimal.NewFromInt(5000),
+		MinOrderSize:       decimal.NewFromInt(1),
+		MaxOpenOrders:      100,
+		MaxDailyOrders:     100,
+		MaxDailyVolume:     decimal.NewFromInt(100000),
+		MaxDailyLoss:       decimal.NewFromInt(5000),
+		MaxSessionDuration: 24 * time.Hour,
+	}
+	engine := NewPolicyEngine(limits)
+	engine.RecordFill("market1", decimal.NewFromInt(75), decimal.NewFromInt(1), true, decimal.Zero)
+
+	// Reducing by $10 passes
+	if err := engine.CheckOrder("market1", decimal.NewFromInt(10), decimal.NewFromInt(1), false); err != nil {
+		t.Errorf("Inventory-reducing order should pass: %v", err)
+	}
+
+	// Adding $10 would reach $85: inside MaxPositionSize but past the skewed limit
+	if err := engine.CheckOrder("market1", decimal.NewFromInt(10), decimal.NewFromInt(1), true); err == nil {
+		t.Error("Inventory-increasing order should be rejected near the limit")
+	}
+
+	// Without skew the same add is allowed
+	limits.SkewFactor = decimal.Zero
+	if err := engine.CheckOrder("market1", decimal.NewFromInt(10), decimal.NewFromInt(1), true); err != nil {
+		t.Errorf("Add should pass with no skew: %v", err)
+	}
+}
+
 func TestCheckOrder_TotalExposureLimit(t *testing.T) {
 	limits := &RiskLimits{
 		MaxPositionSize:    decimal.NewFromInt(10000),
@@ -335,6 +372,46 @@ func TestCheckOrder_ConcentrationLimit(t *testing.T) {
 	}
 }
 
+func TestCheckOrder_CategoryExposureLimit(t *testing.T) {
+	limits := &RiskLimits{
+		MaxPositionSize:     decimal.NewFromInt(300),
+		MaxTotalExposure:    decimal.NewFromInt(1000),
+		MaxConcentration:    decimal.NewFromInt(1),
+		MaxCategoryExposure: map[string]decimal.Decimal{"Crypto": decimal.NewFromFloat(0.4)}, // $400
+		MaxOrderSize:        decimal.NewFromInt(500),
+		MinOrderSize:        decimal.NewFromInt(1),
+		MaxOpenOrders:       100,
+		MaxDailyOrders:      100,
+		MaxDailyVolume:      decimal.NewFromInt(100000),
+		MaxDailyLoss:        decimal.NewFromInt(5000),
+		MaxSessionDuration:  24 * time.Hour,
+	}
+	engine := NewPolicyEngine(limits)
+	engine.SetMarketCategory("btc-100k", "Crypto")
+	engine.SetMarketCategory("eth-5k", "Crypto")
+
+	if err := engine.CheckOrder("btc-100k", decimal.NewFromInt(250), decimal.NewFromFloat(0.5), true); err != nil {
+		t.Fatalf("First crypto order should pass: %v", err)
+	}
+	engine.RecordFill("btc-100k", decimal.NewFromInt(250), decimal.NewFromFloat(0.5), true, decimal.Zero)
+
+	// 200 is within the per-market limit, but 250+200 breaches the $400 crypto cap
+	order := ProposedOrder{Market: "eth-5k", Size: decimal.NewFromInt(200), Price: decimal.NewFromFloat(0.5), IsBuy: true}
+	decisions := engine.SimulateOrders([]ProposedOrder{order})
+	if decisions[0].Allowed || decisions[0].Limit != "max_category_exposure" {
+		t.Errorf("Expected max_category_exposure rejection, got %+v", decisions[0])
+	}
+
+	// The same order in an uncategorized market passes
+	if err := engine.CheckOrder("election", decimal.NewFromInt(200), decimal.NewFromFloat(0.5), true); err != nil {
+		t.Errorf("Uncategorized market should not be capped: %v", err)
+	}
+
+	if got := engine.CategoryExposure()["Crypto"]; !got.Equal(decimal.NewFromInt(250)) {
+		t.Errorf("Expected crypto exposure 250, got %s", got)
+	}
+}
+
 func TestCheckOrder_CooldownAfterLoss(t *testing.T) {
 	limits := &RiskLimits{
 		MaxPositionSize:    decimal.NewFromInt(10000),
@@ -386,6 +463,67 @@ func TestCheckOrder_DailyLossExceeded(t *testing.T) {
 	}
 }
 
+func TestCheckOrder_ConsecutiveLosses(t *testing.T) {
+	limits := &RiskLimits{
+		MaxPositionSize:      decimal.NewFromInt(10000),
+		MaxTotalExposure:     decimal.NewFromInt(50000),
+		MaxConcentration:     decimal.NewFromInt(1),
+		MaxOrderSize:         decimal.NewFromInt(5000),
+		MinOrderSize:         decimal.NewFromInt(1),
+		MaxOpenOrders:        100,
+		MaxDailyOrders:       100,
+		MaxDailyVolume:       decimal.NewFromInt(100000),
+		MaxDailyLoss:         decimal.NewFromInt(5000),
+		MaxConsecutiveLosses: 3,
+		MaxSessionDuration:   24 * time.Hour,
+	}
+	engine := NewPolicyEngine(limits)
+	loss := func() {
+		engine.RecordFill("market1", decimal.NewFromInt(1), decimal.NewFromFloat(0.5), false, decimal.NewFromInt(-1))
+	}
+	check := func() error {
+		return engine.CheckOrder("market1", decimal.NewFromInt(10), decimal.NewFromFloat(0.5), true)
+	}
+
+	// Opening fills realize nothing and leave the streak alone
+	engine.RecordFill("market1", decimal.NewFromInt(10), decimal.NewFromFloat(0.5), true, decimal.Zero)
+	if engine.CurrentStreak() != 0 {
+		t.Errorf("Expected streak 0, got %d", engine.CurrentStreak())
+	}
+
+	loss()
+	loss()
+	if engine.CurrentStreak() != -2 {
+		t.Errorf("Expected streak -2, got %d", engine.CurrentStreak())
+	}
+	if err := check(); err != nil {
+		t.Errorf("Two losses should not halt: %v", err)
+	}
+
+	loss()
+	if err := check(); err == nil || !strings.Contains(err.Error(), "consecutive losses") {
+		t.Errorf("Expected consecutive-loss halt, got %v", err)
+	}
+
+	// A win resets the streak and lifts the halt
+	engine.RecordFill("market1", decimal.NewFromInt(1), decimal.NewFromFloat(0.5), false, decimal.NewFromInt(2))
+	if engine.CurrentStreak() != 1 {
+		t.Errorf("Expected streak 1 after win, got %d", engine.CurrentStreak())
+	}
+	if err := check(); err != nil {
+		t.Errorf("Win should lift halt: %v", err)
+	}
+
+	// ResetStreak also lifts it
+	loss()
+	loss()
+	loss()
+	engine.ResetStreak()
+	if err := check(); err != nil {
+		t.Errorf("ResetStreak should lift halt: %v", err)
+	}
+}
+
 func TestCheckSlippage(t *testing.T) {
 	limits := DefaultRiskLimits() // 2% max slippage
 	engine := NewPolicyEngine(limits)
@@ -549,3 +687,71 @@ func TestSellPositionUpdates(t *testing.T) {
 		t.Errorf("Expected zero position after full sell, got %s", pos)
 	}
 }
+
+func TestStalePositions(t *testing.T) {
+	if DefaultRiskLimits().MaxPositionAge != 0 || TightRiskLimits().MaxPositionAge != 0 {
+		t.Error("MaxPositionAge should be opt-in")
+	}
+
+	limits := DefaultRiskLimits()
+	limits.MaxPositionAge = 24 * time.Hour
+	engine := NewPolicyEngine(limits)
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	positions := []*paper.Position{
+		{TokenID: "fresh", OpenedAt: now.Add(-1 * time.Hour)},
+		{TokenID: "old", OpenedAt: now.Add(-48 * time.Hour)},
+	}
+
+	stale := engine.StalePositions(positions, now)
+	if len(stale) != 1 || stale[0] != "old" {
+		t.Errorf("Expected only [old] to be stale, got %v", stale)
+	}
+
+	// No limit flags nothing
+	limits.MaxPositionAge = 0
+	if stale := engine.StalePositions(positions, now); len(stale) != 0 {
+		t.Errorf("Expected no stale positions without a limit, got %v", stale)
+	}
+}
+
+func TestSimulateOrders(t *testing.T) {
+	limits := TightRiskLimits() // $100 position, $50 order, $5 min
+	engine := NewPolicyEngine(limits)
+	engine.RecordFill("market1", decimal.NewFromInt(90), decimal.NewFromInt(1), true, decimal.Zero)
+	limits.BlockedMarkets = []string{"blocked"}
+
+	orders := []ProposedOrder{
+		{Market: "market2", Size: decimal.NewFromInt(20), Price: decimal.NewFromFloat(0.5), IsBuy: true},  // $10, fine
+		{Market: "market2", Size: decimal.NewFromInt(200), Price: decimal.NewFromFloat(0.5), IsBuy: true}, // $100 order
+		{Market: "market2", Size: decimal.NewFromInt(2), Price: decimal.NewFromFloat(0.5), IsBuy: true},   // $1 order
+		{Market: "market1", Size: decimal.NewFromInt(20), Price: decimal.NewFromInt(1), IsBuy: true},      // 110 position
+		{Market: "blocked", Size: decimal.NewFromInt(20), Price: decimal.NewFromFloat(0.5), IsBuy: true},
+	}
+	want := []string{"", "max_order_size", "min_order_size", "max_position_size", "market_restriction"}
+
+	decisions := engine.SimulateOrders(orders)
+	if len(decisions) != len(orders) {
+		t.Fatalf("Expected %d decisions, got %d", len(orders), len(decisions))
+	}
+	for i, d := range decisions {
+		if d.Allowed != (want[i] == "") {
+			t.Errorf("Order %d: allowed=%v, want %v (%s)", i, d.Allowed, want[i] == "", d.Reason)
+		}
+		if d.Limit != want[i] {
+			t.Errorf("Order %d: limit=%q, want %q", i, d.Limit, want[i])
+		}
+		if !d.Allowed && d.Reason == "" {
+			t.Errorf("Order %d: rejected without a reason", i)
+		}
+	}
+
+	// Nothing was recorded
+	loss, volume, count := engine.GetDailyStats()
+	if count != 0 || !loss.IsZero() || !volume.Equal(decimal.NewFromInt(90)) {
+		t.Errorf("Simulation should not change state: orders=%d volume=%s", count, volume)
+	}
+	if !engine.GetPosition("market2").IsZero() {
+		t.Errorf("Simulation should not open positions")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
 )
 
 // EventType represents the type of streaming event.
@@ -23,6 +24,7 @@ const (
 	EventTypeStatus    EventType = "status"
 	EventTypeError     EventType = "error"
 	EventTypeHeartbeat EventType = "heartbeat"
+	EventTypeEquity    EventType = "equity"
 )
 
 // Event is a streaming event sent to clients.
@@ -32,6 +34,13 @@ type Event struct {
 	Data      interface{} `json:"data"`
 }
 
+// EquityPoint is one sample of an account's equity curve.
+type EquityPoint struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Equity    decimal.Decimal `json:"equity"`
+	Drawdown  decimal.Decimal `json:"drawdown"` // Fraction below the running peak
+}
+
 // Hub manages WebSocket connections and broadcasts events.
 type Hub struct {
 	clients    map[*Client]bool
@@ -213,6 +222,18 @@ func (h *Hub) BroadcastError(err error, context string) {
 	})
 }
 
+// BroadcastEquity broadcasts an equity curve sample.
+func (h *Hub) BroadcastEquity(point EquityPoint) {
+	if point.Timestamp.IsZero() {
+		point.Timestamp = time.Now()
+	}
+	h.Broadcast(Event{
+		Type:      EventTypeEquity,
+		Timestamp: point.Timestamp,
+		Data:      point,
+	})
+}
+
 // ClientCount returns the number of connected clients.
 func (h *Hub) ClientCount() int {
 	h.mu.RLock()
@@ -244,6 +265,7 @@ func (h *Hub) ServeWS(w http.ResponseWriter, r *http.Request) {
 	client.subscriptions[EventTypeStatus] = true
 	client.subscriptions[EventTypeError] = true
 	client.subscriptions[EventTypeHeartbeat] = true
+	client.subscriptions[EventTypeEquity] = true
 
 	h.register <- client
 
@@ -0,0 +1,71 @@
+package streaming
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+)
+
+func TestBroadcastEquityReachesSubscribedClient(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	server := httptest.NewServer(http.HandlerFunc(hub.ServeWS))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	// Registration happens on the hub goroutine after the upgrade
+	deadline := time.Now().Add(2 * time.Second)
+	for hub.ClientCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Client never registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	hub.BroadcastEquity(EquityPoint{
+		Equity:   decimal.NewFromInt(9500),
+		Drawdown: decimal.NewFromFloat(0.05),
+	})
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("No equity event received: %v", err)
+		}
+
+		// Queued events share a frame, one per line
+		for _, line := range bytes.Split(message, []byte{'\n'}) {
+			var event struct {
+				Type EventType   `json:"type"`
+				Data EquityPoint `json:"data"`
+			}
+			if err := json.Unmarshal(line, &event); err != nil {
+				t.Fatalf("Bad event %q: %v", line, err)
+			}
+			if event.Type != EventTypeEquity {
+				continue
+			}
+			if !event.Data.Equity.Equal(decimal.NewFromInt(9500)) || !event.Data.Drawdown.Equal(decimal.NewFromFloat(0.05)) {
+				t.Errorf("Expected equity 9500 at 5%% drawdown, got %+v", event.Data)
+			}
+			if event.Data.Timestamp.IsZero() {
+				t.Error("Expected a timestamp to be filled in")
+			}
+			return
+		}
+	}
+}
@@ -2,14 +2,18 @@ package tools
 
 import (
 	"github.com/phenomenon0/polymarket-agents/core"
+	"github.com/phenomenon0/polymarket-agents/pkg/httpx"
+	"github.com/phenomenon0/polymarket-agents/pkg/redact"
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -26,19 +30,82 @@ type LLMConfig struct {
 	Temperature float64
 	Timeout     time.Duration
 	RetryPolicy RetryPolicy
+
+	// ReasoningEffort ("low", "medium", "high") trades answer quality for
+	// cost and latency on models that support it: gpt-5.x, o3, and o4. It is
+	// not sent to other models. LLMRequest.ReasoningEffort overrides it.
+	ReasoningEffort string
+
+	// MaxReasoningTokens caps the tokens a reasoning model may spend
+	// thinking before it answers. OpenRouter receives it as
+	// reasoning.max_tokens when no effort is set. DeepSeek's own API has no
+	// separate budget and counts chain-of-thought against max_tokens, so for
+	// deepseek-reasoner it is added to max_tokens instead, bounding thinking
+	// and answer together. 0 leaves the provider default.
+	// LLMRequest.MaxReasoningTokens overrides it.
+	MaxReasoningTokens int
+
+	// MaxConcurrent bounds in-flight requests to this provider endpoint,
+	// shared across every LLMTool with the same Provider and BaseURL.
+	// Requests over the limit wait for a free slot. 0 means unlimited.
+	MaxConcurrent int
+
+	// Transport tunes the HTTP connection pool. Nil uses
+	// DefaultLLMTransportConfig. Tools made for failover share the first
+	// tool's client, and so its transport and DebugLog.
+	Transport *httpx.TransportConfig
+
+	// DebugLog, if set, receives every provider request and its raw
+	// response as a JSON DebugEntry per line, for debugging prompts and
+	// parsing. Pass an *os.File or a log.Logger's Writer(). API keys are
+	// scrubbed, but prompts and completions are written in full.
+	DebugLog io.Writer
+
+	// Failover, if set, is asked for an alternative endpoint once
+	// RetryPolicy.FailoverAfter consecutive attempts have failed, so the
+	// remaining retries go elsewhere instead of hammering a rate-limited or
+	// down provider. See ModelRouter.Failover.
+	Failover FailoverFunc
+}
+
+// String summarizes the config with the API key hidden, so configs printed
+// with %v or %+v never leak it.
+func (c LLMConfig) String() string {
+	return fmt.Sprintf("{Provider:%s Model:%s Tier:%s Preset:%s APIKey:%s BaseURL:%s MaxTokens:%d Temperature:%g Timeout:%s}",
+		c.Provider, c.Model, c.Tier, c.Preset, redact.Hide(c.APIKey), c.BaseURL, c.MaxTokens, c.Temperature, c.Timeout)
+}
+
+// GoString is String for %#v.
+func (c LLMConfig) GoString() string {
+	return "tools.LLMConfig" + c.String()
 }
 
 type RetryPolicy struct {
 	MaxRetries int
 	Backoff    time.Duration
+
+	// FailoverAfter is the number of consecutive failures on one endpoint
+	// before LLMConfig.Failover is consulted. 0 disables failover.
+	FailoverAfter int
 }
 
+// FailoverFunc returns the config to use after failed has exhausted its
+// retry budget, or false if there is no alternative.
+type FailoverFunc func(failed LLMConfig) (LLMConfig, bool)
+
 type CostTracker struct {
 	TotalTokens      int64
 	PromptTokens     int64
 	CompletionTokens int64
 	EstimatedCostUSD float64
-	lastCost         float64
+
+	// ReasoningTokens and ReasoningCostUSD are the share of
+	// CompletionTokens and EstimatedCostUSD spent on hidden reasoning.
+	ReasoningTokens  int64
+	ReasoningCostUSD float64
+
+	lastCost float64
+	mu       sync.Mutex
 }
 
 // Rough rate table (USD per token) for December 2025 SOTA models; fallback uses heuristics.
@@ -62,6 +129,7 @@ var modelRates = []struct {
 
 	// DeepSeek R1 (Jan 2025) - 97.3% MATH-500 ⭐
 	{"deepseek-r1", 0.0000004, 0.00000175, false},
+	{"deepseek-reasoner", 0.00000055, 0.00000219, false},
 
 	// DeepSeek V3.2 (Dec 2025) - 96% AIME, insane value ⭐
 	{"deepseek-v3.2", 0.00000028, 0.00000041, true},
@@ -139,18 +207,42 @@ func calculateCost(model string, promptTokens, completionTokens int) float64 {
 }
 
 func (c *CostTracker) AddUsage(prompt, completion int, model string) {
+	c.AddReasoningUsage(prompt, completion, 0, model)
+}
+
+// AddReasoningUsage is AddUsage for a completion of which reasoning tokens
+// were spent thinking. completion includes them, as DeepSeek and OpenAI
+// report it; they are billed at the model's output rate, which is what both
+// charge, and also counted separately in ReasoningTokens and
+// ReasoningCostUSD.
+func (c *CostTracker) AddReasoningUsage(prompt, completion, reasoning int, model string) {
+	reasoning = min(max(reasoning, 0), completion)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.PromptTokens += int64(prompt)
 	c.CompletionTokens += int64(completion)
+	c.ReasoningTokens += int64(reasoning)
 	c.TotalTokens += int64(prompt + completion)
 	cost := calculateCost(model, prompt, completion)
 	c.EstimatedCostUSD += cost
+	c.ReasoningCostUSD += calculateCost(model, 0, reasoning)
 	c.lastCost = cost
 }
 
 func (c *CostTracker) LastCost() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.lastCost
 }
 
+// TotalCost returns the estimated spend in USD so far.
+func (c *CostTracker) TotalCost() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.EstimatedCostUSD
+}
+
 var DefaultOpenAIConfig = LLMConfig{
 	Provider:    "openai",
 	Model:       "gpt-4o-mini",
@@ -199,6 +291,14 @@ type LLMRequest struct {
 	System      string       `json:"system,omitempty"`
 	MaxTokens   int          `json:"max_tokens,omitempty"`
 	Temperature float64      `json:"temperature,omitempty"`
+	Model       string       `json:"model,omitempty"` // Overrides LLMConfig.Model for this request
+
+	// TemperatureSet sends Temperature as given, even 0. Otherwise a zero
+	// Temperature means LLMConfig.Temperature.
+	TemperatureSet bool `json:"temperature_set,omitempty"`
+
+	ReasoningEffort    string `json:"reasoning_effort,omitempty"`     // Overrides LLMConfig.ReasoningEffort
+	MaxReasoningTokens int    `json:"max_reasoning_tokens,omitempty"` // Overrides LLMConfig.MaxReasoningTokens
 }
 
 type LLMResponse struct {
@@ -207,8 +307,9 @@ type LLMResponse struct {
 	FinishReason string `json:"finish_reason"`
 	Usage        struct {
 		PromptTokens     int `json:"prompt_tokens"`
-		CompletionTokens int `json:"completion_tokens"`
+		CompletionTokens int `json:"completion_tokens"` // Includes ReasoningTokens
 		TotalTokens      int `json:"total_tokens"`
+		ReasoningTokens  int `json:"reasoning_tokens,omitempty"`
 	} `json:"usage"`
 }
 
@@ -218,6 +319,52 @@ type LLMTool struct {
 	config      LLMConfig
 	client      *http.Client
 	costTracker *CostTracker
+	slots       chan struct{} // nil when MaxConcurrent is unset
+}
+
+// Concurrency slots per provider endpoint (provider + base URL), since that
+// is where upstream concurrency limits are enforced. The limit is part of the
+// key so a config asking for a different MaxConcurrent gets its own pool
+// rather than silently sharing the first one registered.
+var (
+	providerSlotsMu sync.Mutex
+	providerSlots   = make(map[string]chan struct{})
+)
+
+func slotsFor(config LLMConfig) chan struct{} {
+	if config.MaxConcurrent <= 0 {
+		return nil
+	}
+
+	providerSlotsMu.Lock()
+	defer providerSlotsMu.Unlock()
+
+	key := fmt.Sprintf("%s|%s|%d", config.Provider, config.BaseURL, config.MaxConcurrent)
+	slots, ok := providerSlots[key]
+	if !ok {
+		slots = make(chan struct{}, config.MaxConcurrent)
+		providerSlots[key] = slots
+	}
+	return slots
+}
+
+// acquireSlot blocks until a concurrency slot is free or ctx is done.
+func (t *LLMTool) acquireSlot(ctx context.Context) error {
+	if t.slots == nil {
+		return nil
+	}
+	select {
+	case t.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *LLMTool) releaseSlot() {
+	if t.slots != nil {
+		<-t.slots
+	}
 }
 
 func (t *LLMTool) parseRequest(input any) (*LLMRequest, error) {
@@ -246,9 +393,101 @@ func (t *LLMTool) applyDefaults(req *LLMRequest) {
 	if req.MaxTokens == 0 {
 		req.MaxTokens = t.config.MaxTokens
 	}
-	if req.Temperature == 0 {
+	if req.Temperature == 0 && !req.TemperatureSet {
 		req.Temperature = t.config.Temperature
 	}
+	if req.Model == "" {
+		req.Model = t.config.Model
+	}
+	if req.ReasoningEffort == "" {
+		req.ReasoningEffort = t.config.ReasoningEffort
+	}
+	if req.MaxReasoningTokens == 0 {
+		req.MaxReasoningTokens = t.config.MaxReasoningTokens
+	}
+}
+
+// supportsReasoningEffort reports whether model accepts a reasoning effort,
+// ignoring a vendor prefix such as OpenRouter's "openai/".
+func supportsReasoningEffort(model string) bool {
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:]
+	}
+	for _, prefix := range []string{"gpt-5", "o3", "o4"} {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// setReasoningEffort adds req's reasoning effort to an OpenAI-style request
+// body if both the value and the model are valid for it.
+func (t *LLMTool) setReasoningEffort(body map[string]any, req *LLMRequest) {
+	switch req.ReasoningEffort {
+	case "low", "medium", "high":
+	default:
+		return
+	}
+	if !supportsReasoningEffort(req.Model) {
+		return
+	}
+
+	if t.config.Provider == "openrouter" {
+		body["reasoning"] = map[string]any{"effort": req.ReasoningEffort}
+	} else {
+		body["reasoning_effort"] = req.ReasoningEffort
+	}
+}
+
+// setReasoningBudget applies req's reasoning token cap to an OpenAI-style
+// request body. It goes after setReasoningEffort, since OpenRouter takes an
+// effort or a token budget but not both.
+func (t *LLMTool) setReasoningBudget(body map[string]any, req *LLMRequest) {
+	if req.MaxReasoningTokens <= 0 {
+		return
+	}
+
+	switch t.config.Provider {
+	case "openrouter":
+		if _, ok := body["reasoning"]; !ok {
+			body["reasoning"] = map[string]any{"max_tokens": req.MaxReasoningTokens}
+		}
+	case "deepseek":
+		if isDeepSeekReasoner(req.Model) {
+			body["max_tokens"] = req.MaxTokens + req.MaxReasoningTokens
+		}
+	}
+}
+
+// isDeepSeekReasoner reports whether model is one of DeepSeek's thinking
+// models, whose chain-of-thought counts against max_tokens.
+func isDeepSeekReasoner(model string) bool {
+	model = strings.ToLower(model)
+	return strings.Contains(model, "deepseek-reasoner") || strings.Contains(model, "deepseek-r1")
+}
+
+// openAIUsage is the usage object of OpenAI-compatible chat APIs. DeepSeek
+// and OpenAI report reasoning tokens in completion_tokens_details.
+type openAIUsage struct {
+	PromptTokens            int `json:"prompt_tokens"`
+	CompletionTokens        int `json:"completion_tokens"`
+	TotalTokens             int `json:"total_tokens"`
+	CompletionTokensDetails struct {
+		ReasoningTokens int `json:"reasoning_tokens"`
+	} `json:"completion_tokens_details"`
+}
+
+// completion returns the completion tokens, including reasoning, and the
+// reasoning tokens among them. Some OpenAI-compatible providers leave
+// reasoning out of completion_tokens; their total_tokens then exceeds
+// prompt plus completion, and the reasoning is added back so it is billed.
+func (u openAIUsage) completion() (completion, reasoning int) {
+	completion, reasoning = u.CompletionTokens, u.CompletionTokensDetails.ReasoningTokens
+	if reasoning > 0 && u.TotalTokens >= u.PromptTokens+completion+reasoning {
+		completion += reasoning
+	}
+	return completion, reasoning
 }
 
 func (t *LLMTool) normalizeRequest(ctx *core.ToolContext) (*LLMRequest, *core.ToolExecResult) {
@@ -264,21 +503,25 @@ func (t *LLMTool) normalizeRequest(ctx *core.ToolContext) (*LLMRequest, *core.To
 	return req, nil
 }
 
+// DefaultLLMTransportConfig is the shared default pool with a per-host
+// connection cap and a long header timeout, since LLMs can be slow to start
+// responding.
+func DefaultLLMTransportConfig() httpx.TransportConfig {
+	config := httpx.DefaultTransportConfig()
+	config.MaxConnsPerHost = 20
+	config.ResponseHeaderTimeout = 120 * time.Second
+	return config
+}
+
 func NewLLMTool(config LLMConfig) *LLMTool {
-	// Create HTTP client with connection pooling for better performance
-	transport := &http.Transport{
-		MaxIdleConns:        20,               // Max idle connections across all hosts
-		MaxIdleConnsPerHost: 10,               // Max idle connections per host (LLM APIs)
-		MaxConnsPerHost:     20,               // Max total connections per host
-		IdleConnTimeout:     90 * time.Second, // How long idle connections stay open
-		DisableKeepAlives:   false,            // Enable connection reuse
-		DialContext: (&net.Dialer{
-			Timeout:   15 * time.Second, // Connection timeout (generous for LLM APIs)
-			KeepAlive: 30 * time.Second, // TCP keepalive
-		}).DialContext,
-		ForceAttemptHTTP2:     true,              // Try HTTP/2
-		TLSHandshakeTimeout:   15 * time.Second,  // TLS handshake timeout
-		ResponseHeaderTimeout: 120 * time.Second, // Waiting for response headers (LLMs can be slow)
+	transportConfig := DefaultLLMTransportConfig()
+	if config.Transport != nil {
+		transportConfig = *config.Transport
+	}
+
+	var transport http.RoundTripper = httpx.NewTransport(transportConfig)
+	if config.DebugLog != nil {
+		transport = newDebugTransport(transport, config.DebugLog)
 	}
 
 	return &LLMTool{
@@ -288,6 +531,18 @@ func NewLLMTool(config LLMConfig) *LLMTool {
 			Timeout:   config.Timeout,
 		},
 		costTracker: &CostTracker{},
+		slots:       slotsFor(config),
+	}
+}
+
+// withConfig returns a tool for another endpoint that shares this tool's
+// HTTP client and cost tracker.
+func (t *LLMTool) withConfig(config LLMConfig) *LLMTool {
+	return &LLMTool{
+		config:      config,
+		client:      t.client,
+		costTracker: t.costTracker,
+		slots:       slotsFor(config),
 	}
 }
 
@@ -299,6 +554,29 @@ func (t *LLMTool) Name() string {
 	return "llm"
 }
 
+func (t *LLMTool) InputSchema() []byte {
+	return []byte(`{
+		"type": "object",
+		"required": ["messages"],
+		"properties": {
+			"messages": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"role": {"type": "string", "enum": ["user", "assistant"]},
+						"content": {"type": "string"}
+					}
+				}
+			},
+			"system": {"type": "string", "description": "System prompt"},
+			"max_tokens": {"type": "integer"},
+			"temperature": {"type": "number"},
+			"model": {"type": "string", "description": "Override the configured model"}
+		}
+	}`)
+}
+
 // EstimateCost provides a preflight cost estimate (tokens + USD) for budgeting.
 func (t *LLMTool) EstimateCost(input any) (float64, int, int, bool) {
 	req, err := t.parseRequest(input)
@@ -314,7 +592,7 @@ func (t *LLMTool) EstimateCost(input any) (float64, int, int, bool) {
 		completionTokens = t.config.MaxTokens
 	}
 
-	cost := calculateCost(t.config.Model, promptTokens, completionTokens)
+	cost := calculateCost(req.Model, promptTokens, completionTokens)
 	return cost, promptTokens, completionTokens, true
 }
 
@@ -379,34 +657,49 @@ func (t *LLMTool) Execute(ctx *core.ToolContext) *core.ToolExecResult {
 	var resp *LLMResponse
 	var err error
 
-	// Retry loop
+	// Retry loop. llm is the endpoint currently in use; it changes if the
+	// failover hook moves us to another provider.
 	maxRetries := t.config.RetryPolicy.MaxRetries
 	if maxRetries == 0 {
 		maxRetries = 1
 	}
+	llm := t
+	defaultModel := req.Model == t.config.Model
+	failures := 0
+	attempts := 0
 
 	for i := 0; i < maxRetries; i++ {
 		if i > 0 {
 			time.Sleep(t.config.RetryPolicy.Backoff * time.Duration(i))
 		}
 
-		switch t.config.Provider {
+		if err := llm.acquireSlot(ctx.Ctx); err != nil {
+			return &core.ToolExecResult{
+				Status: core.ToolCanceled,
+				Error:  "request cancelled",
+			}
+		}
+
+		switch llm.config.Provider {
 		case "openai":
-			resp, err = t.callOpenAI(ctx, req)
+			resp, err = llm.callOpenAI(ctx, req)
 		case "anthropic":
-			resp, err = t.callAnthropic(ctx, req)
+			resp, err = llm.callAnthropic(ctx, req)
 		case "ollama":
-			resp, err = t.callOllama(ctx, req)
+			resp, err = llm.callOllama(ctx, req)
 		case "openrouter":
-			resp, err = t.callOpenAI(ctx, req) // OpenAI-compatible
+			resp, err = llm.callOpenAI(ctx, req) // OpenAI-compatible
 		case "deepseek":
-			resp, err = t.callOpenAI(ctx, req) // DeepSeek is OpenAI-compatible
+			resp, err = llm.callOpenAI(ctx, req) // DeepSeek is OpenAI-compatible
 		default:
+			llm.releaseSlot()
 			return &core.ToolExecResult{
 				Status: core.ToolFailed,
-				Error:  fmt.Sprintf("unknown provider: %s", t.config.Provider),
+				Error:  fmt.Sprintf("unknown provider: %s", llm.config.Provider),
 			}
 		}
+		llm.releaseSlot()
+		attempts++
 
 		if err == nil {
 			break
@@ -422,19 +715,42 @@ func (t *LLMTool) Execute(ctx *core.ToolContext) *core.ToolExecResult {
 		default:
 		}
 
-		// If error is not temporary (e.g. 401), don't retry?
-		// For now, retry all errors except context cancellation
+		// A bad key or bad request fails the same way every time
+		if classifyLLMError(err) == ErrorTerminal {
+			break
+		}
+
+		failures++
+		if n := t.config.RetryPolicy.FailoverAfter; n > 0 && failures >= n && t.config.Failover != nil {
+			if next, ok := t.config.Failover(llm.config); ok {
+				llm = t.withConfig(next)
+				if defaultModel {
+					req.Model = next.Model
+				}
+				failures = 0
+			}
+		}
 	}
 
 	if err != nil {
+		metadata := map[string]any{
+			"error_class": classifyLLMError(err),
+			"attempts":    attempts,
+			"provider":    llm.config.Provider,
+		}
+		var perr *ProviderError
+		if errors.As(err, &perr) {
+			metadata["status_code"] = perr.StatusCode
+		}
 		return &core.ToolExecResult{
-			Status: core.ToolFailed,
-			Error:  err.Error(),
+			Status:   core.ToolFailed,
+			Error:    err.Error(),
+			Metadata: metadata,
 		}
 	}
 
 	// Track cost
-	t.costTracker.AddUsage(resp.Usage.PromptTokens, resp.Usage.CompletionTokens, resp.Model)
+	t.costTracker.AddReasoningUsage(resp.Usage.PromptTokens, resp.Usage.CompletionTokens, resp.Usage.ReasoningTokens, resp.Model)
 
 	callCost := t.costTracker.LastCost()
 	return &core.ToolExecResult{
@@ -444,11 +760,12 @@ func (t *LLMTool) Execute(ctx *core.ToolContext) *core.ToolExecResult {
 			"cost":              callCost,
 			"prompt_tokens":     resp.Usage.PromptTokens,
 			"completion_tokens": resp.Usage.CompletionTokens,
+			"reasoning_tokens":  resp.Usage.ReasoningTokens,
 			"total_tokens":      resp.Usage.TotalTokens,
 			"model":             resp.Model,
-			"provider":          t.config.Provider,
-			"tier":              t.config.Tier,
-			"preset":            t.config.Preset,
+			"provider":          llm.config.Provider,
+			"tier":              llm.config.Tier,
+			"preset":            llm.config.Preset,
 			"estimated":         false,
 		},
 	}
@@ -459,12 +776,12 @@ func (t *LLMTool) Execute(ctx *core.ToolContext) *core.ToolExecResult {
 func (t *LLMTool) callOpenAI(ctx *core.ToolContext, req *LLMRequest) (*LLMResponse, error) {
 	// Build OpenAI request
 	openaiReq := map[string]any{
-		"model":    t.config.Model,
+		"model":    req.Model,
 		"messages": req.Messages,
 	}
 
 	// GPT-5 models and reasoning models have special requirements
-	isReasoningModel := strings.HasPrefix(t.config.Model, "gpt-5") || strings.HasPrefix(t.config.Model, "o1") || strings.HasPrefix(t.config.Model, "o3")
+	isReasoningModel := strings.HasPrefix(req.Model, "gpt-5") || strings.HasPrefix(req.Model, "o1") || strings.HasPrefix(req.Model, "o3")
 
 	if isReasoningModel {
 		// Use max_completion_tokens instead of max_tokens
@@ -474,6 +791,8 @@ func (t *LLMTool) callOpenAI(ctx *core.ToolContext, req *LLMRequest) (*LLMRespon
 		openaiReq["max_tokens"] = req.MaxTokens
 		openaiReq["temperature"] = req.Temperature
 	}
+	t.setReasoningEffort(openaiReq, req)
+	t.setReasoningBudget(openaiReq, req)
 
 	body, _ := json.Marshal(openaiReq)
 
@@ -500,7 +819,7 @@ func (t *LLMTool) callOpenAI(ctx *core.ToolContext, req *LLMRequest) (*LLMRespon
 
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("OpenAI API error %d: %s", resp.StatusCode, string(body))
+		return nil, &ProviderError{Provider: "OpenAI", StatusCode: resp.StatusCode, Body: redact.Redact(string(body), t.config.APIKey)}
 	}
 
 	var openaiResp struct {
@@ -511,12 +830,8 @@ func (t *LLMTool) callOpenAI(ctx *core.ToolContext, req *LLMRequest) (*LLMRespon
 			} `json:"message"`
 			FinishReason string `json:"finish_reason"`
 		} `json:"choices"`
-		Usage struct {
-			PromptTokens     int `json:"prompt_tokens"`
-			CompletionTokens int `json:"completion_tokens"`
-			TotalTokens      int `json:"total_tokens"`
-		} `json:"usage"`
-		Model string `json:"model"`
+		Usage openAIUsage `json:"usage"`
+		Model string      `json:"model"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&openaiResp); err != nil {
@@ -534,18 +849,21 @@ func (t *LLMTool) callOpenAI(ctx *core.ToolContext, req *LLMRequest) (*LLMRespon
 		content = openaiResp.Choices[0].Message.Reasoning
 	}
 
-	return &LLMResponse{
+	out := &LLMResponse{
 		Content:      content,
 		Model:        openaiResp.Model,
 		FinishReason: openaiResp.Choices[0].FinishReason,
-		Usage:        openaiResp.Usage,
-	}, nil
+	}
+	out.Usage.PromptTokens = openaiResp.Usage.PromptTokens
+	out.Usage.CompletionTokens, out.Usage.ReasoningTokens = openaiResp.Usage.completion()
+	out.Usage.TotalTokens = out.Usage.PromptTokens + out.Usage.CompletionTokens
+	return out, nil
 }
 
 func (t *LLMTool) callAnthropic(ctx *core.ToolContext, req *LLMRequest) (*LLMResponse, error) {
 	// Build Anthropic request
 	anthropicReq := map[string]any{
-		"model":      t.config.Model,
+		"model":      req.Model,
 		"max_tokens": req.MaxTokens,
 		"messages":   req.Messages,
 	}
@@ -574,7 +892,7 @@ func (t *LLMTool) callAnthropic(ctx *core.ToolContext, req *LLMRequest) (*LLMRes
 
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Anthropic API error %d: %s", resp.StatusCode, string(body))
+		return nil, &ProviderError{Provider: "Anthropic", StatusCode: resp.StatusCode, Body: redact.Redact(string(body), t.config.APIKey)}
 	}
 
 	var anthropicResp struct {
@@ -607,8 +925,9 @@ func (t *LLMTool) callAnthropic(ctx *core.ToolContext, req *LLMRequest) (*LLMRes
 		FinishReason: anthropicResp.StopReason,
 		Usage: struct {
 			PromptTokens     int `json:"prompt_tokens"`
-			CompletionTokens int `json:"completion_tokens"`
+			CompletionTokens int `json:"completion_tokens"` // Includes ReasoningTokens
 			TotalTokens      int `json:"total_tokens"`
+			ReasoningTokens  int `json:"reasoning_tokens,omitempty"`
 		}{
 			PromptTokens:     anthropicResp.Usage.InputTokens,
 			CompletionTokens: anthropicResp.Usage.OutputTokens,
@@ -620,7 +939,7 @@ func (t *LLMTool) callAnthropic(ctx *core.ToolContext, req *LLMRequest) (*LLMRes
 func (t *LLMTool) callOllama(ctx *core.ToolContext, req *LLMRequest) (*LLMResponse, error) {
 	// Build Ollama request (uses OpenAI-compatible endpoint)
 	ollamaReq := map[string]any{
-		"model":    t.config.Model,
+		"model":    req.Model,
 		"messages": req.Messages,
 		"stream":   false,
 		"options": map[string]any{
@@ -647,7 +966,7 @@ func (t *LLMTool) callOllama(ctx *core.ToolContext, req *LLMRequest) (*LLMRespon
 
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Ollama API error %d: %s", resp.StatusCode, string(body))
+		return nil, &ProviderError{Provider: "Ollama", StatusCode: resp.StatusCode, Body: redact.Redact(string(body), t.config.APIKey)}
 	}
 
 	var ollamaResp struct {
@@ -679,8 +998,14 @@ func (t *LLMTool) streamOpenAI(ctx *core.ToolContext, req *LLMRequest, chunkChan
 	defer close(chunkChan)
 	defer close(resultChan)
 
+	if err := t.acquireSlot(ctx.Ctx); err != nil {
+		resultChan <- &core.ToolExecResult{Status: core.ToolCanceled, Error: "request cancelled"}
+		return
+	}
+	defer t.releaseSlot()
+
 	openaiReq := map[string]any{
-		"model":       t.config.Model,
+		"model":       req.Model,
 		"messages":    req.Messages,
 		"max_tokens":  req.MaxTokens,
 		"temperature": req.Temperature,
@@ -689,6 +1014,8 @@ func (t *LLMTool) streamOpenAI(ctx *core.ToolContext, req *LLMRequest, chunkChan
 			"include_usage": true,
 		},
 	}
+	t.setReasoningEffort(openaiReq, req)
+	t.setReasoningBudget(openaiReq, req)
 
 	body, _ := json.Marshal(openaiReq)
 	httpReq, err := http.NewRequestWithContext(ctx.Ctx, "POST",
@@ -715,9 +1042,11 @@ func (t *LLMTool) streamOpenAI(ctx *core.ToolContext, req *LLMRequest, chunkChan
 
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
+		perr := &ProviderError{Provider: "OpenAI", StatusCode: resp.StatusCode, Body: redact.Redact(string(body), t.config.APIKey)}
 		resultChan <- &core.ToolExecResult{
-			Status: core.ToolFailed,
-			Error:  fmt.Sprintf("OpenAI API error %d: %s", resp.StatusCode, string(body)),
+			Status:   core.ToolFailed,
+			Error:    perr.Error(),
+			Metadata: map[string]any{"error_class": perr.Class(), "status_code": perr.StatusCode},
 		}
 		return
 	}
@@ -731,6 +1060,7 @@ func (t *LLMTool) streamOpenAI(ctx *core.ToolContext, req *LLMRequest, chunkChan
 	index := 0
 	promptTokens := 0
 	completionTokens := 0
+	reasoningTokens := 0
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -755,11 +1085,7 @@ func (t *LLMTool) streamOpenAI(ctx *core.ToolContext, req *LLMRequest, chunkChan
 				} `json:"delta"`
 				FinishReason string `json:"finish_reason"`
 			} `json:"choices"`
-			Usage struct {
-				PromptTokens     int `json:"prompt_tokens"`
-				CompletionTokens int `json:"completion_tokens"`
-				TotalTokens      int `json:"total_tokens"`
-			} `json:"usage"`
+			Usage openAIUsage `json:"usage"`
 		}
 
 		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
@@ -786,7 +1112,7 @@ func (t *LLMTool) streamOpenAI(ctx *core.ToolContext, req *LLMRequest, chunkChan
 
 		if chunk.Usage.TotalTokens > 0 {
 			promptTokens = chunk.Usage.PromptTokens
-			completionTokens = chunk.Usage.CompletionTokens
+			completionTokens, reasoningTokens = chunk.Usage.completion()
 		}
 	}
 
@@ -808,7 +1134,7 @@ func (t *LLMTool) streamOpenAI(ctx *core.ToolContext, req *LLMRequest, chunkChan
 		FinishReason: finishReason,
 	}
 	if respObj.Model == "" {
-		respObj.Model = t.config.Model
+		respObj.Model = req.Model
 	}
 
 	if promptTokens == 0 && completionTokens == 0 {
@@ -816,7 +1142,7 @@ func (t *LLMTool) streamOpenAI(ctx *core.ToolContext, req *LLMRequest, chunkChan
 		completionTokens = estimateTokens(respObj.Content)
 	}
 
-	t.costTracker.AddUsage(promptTokens, completionTokens, respObj.Model)
+	t.costTracker.AddReasoningUsage(promptTokens, completionTokens, reasoningTokens, respObj.Model)
 
 	resultChan <- &core.ToolExecResult{
 		Status: core.ToolComplete,
@@ -825,6 +1151,7 @@ func (t *LLMTool) streamOpenAI(ctx *core.ToolContext, req *LLMRequest, chunkChan
 			"cost":              t.costTracker.LastCost(),
 			"prompt_tokens":     promptTokens,
 			"completion_tokens": completionTokens,
+			"reasoning_tokens":  reasoningTokens,
 			"total_tokens":      promptTokens + completionTokens,
 			"model":             respObj.Model,
 			"provider":          t.config.Provider,
@@ -840,8 +1167,14 @@ func (t *LLMTool) streamAnthropic(ctx *core.ToolContext, req *LLMRequest, chunkC
 	defer close(chunkChan)
 	defer close(resultChan)
 
+	if err := t.acquireSlot(ctx.Ctx); err != nil {
+		resultChan <- &core.ToolExecResult{Status: core.ToolCanceled, Error: "request cancelled"}
+		return
+	}
+	defer t.releaseSlot()
+
 	anthropicReq := map[string]any{
-		"model":      t.config.Model,
+		"model":      req.Model,
 		"max_tokens": req.MaxTokens,
 		"messages":   req.Messages,
 		"stream":     true,
@@ -872,9 +1205,11 @@ func (t *LLMTool) streamAnthropic(ctx *core.ToolContext, req *LLMRequest, chunkC
 
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
+		perr := &ProviderError{Provider: "Anthropic", StatusCode: resp.StatusCode, Body: redact.Redact(string(body), t.config.APIKey)}
 		resultChan <- &core.ToolExecResult{
-			Status: core.ToolFailed,
-			Error:  fmt.Sprintf("Anthropic API error %d: %s", resp.StatusCode, string(body)),
+			Status:   core.ToolFailed,
+			Error:    perr.Error(),
+			Metadata: map[string]any{"error_class": perr.Class(), "status_code": perr.StatusCode},
 		}
 		return
 	}
@@ -977,14 +1312,14 @@ func (t *LLMTool) streamAnthropic(ctx *core.ToolContext, req *LLMRequest, chunkC
 		FinishReason: finishReason,
 	}
 	if respObj.Model == "" {
-		respObj.Model = t.config.Model
+		respObj.Model = req.Model
 	}
 
 	if promptTokens == 0 && completionTokens == 0 {
 		promptTokens = estimatePromptTokens(req)
 		completionTokens = estimateTokens(respObj.Content)
 	}
-	t.costTracker.AddUsage(promptTokens, completionTokens, t.config.Model)
+	t.costTracker.AddUsage(promptTokens, completionTokens, req.Model)
 
 	resultChan <- &core.ToolExecResult{
 		Status: core.ToolComplete,
@@ -1068,8 +1403,9 @@ func (t *LLMTool) callMock(ctx *core.ToolContext, req *LLMRequest) (*LLMResponse
 		FinishReason: "stop",
 		Usage: struct {
 			PromptTokens     int `json:"prompt_tokens"`
-			CompletionTokens int `json:"completion_tokens"`
+			CompletionTokens int `json:"completion_tokens"` // Includes ReasoningTokens
 			TotalTokens      int `json:"total_tokens"`
+			ReasoningTokens  int `json:"reasoning_tokens,omitempty"`
 		}{
 			PromptTokens:     10,
 			CompletionTokens: 10,
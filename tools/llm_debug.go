@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/phenomenon0/polymarket-agents/pkg/redact"
+)
+
+// DebugEntry is one provider round trip as written to LLMConfig.DebugLog.
+// Bodies are raw, so a streamed response appears as its SSE lines.
+type DebugEntry struct {
+	ID       string        `json:"id"`
+	Time     time.Time     `json:"time"`
+	Method   string        `json:"method"`
+	URL      string        `json:"url"`
+	Status   int           `json:"status,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Request  string        `json:"request"`
+	Response string        `json:"response,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// debugTransport writes every request and its raw response to w as one
+// JSON DebugEntry per line. Credentials sent in the Authorization and
+// x-api-key headers are scrubbed from the URL and both bodies; headers are
+// not logged.
+type debugTransport struct {
+	base http.RoundTripper
+	w    io.Writer
+	mu   sync.Mutex // Serializes writes to w
+	seq  atomic.Uint64
+}
+
+func newDebugTransport(base http.RoundTripper, w io.Writer) *debugTransport {
+	return &debugTransport{base: base, w: w}
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry := &DebugEntry{
+		ID:     fmt.Sprintf("llm-%06d", t.seq.Add(1)),
+		Time:   time.Now(),
+		Method: req.Method,
+	}
+	secrets := requestSecrets(req)
+	entry.URL = redact.Redact(req.URL.String(), secrets...)
+
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		entry.Request = redact.Redact(string(body), secrets...)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		entry.Duration = time.Since(entry.Time)
+		entry.Error = redact.Redact(err.Error(), secrets...)
+		t.write(entry)
+		return nil, err
+	}
+
+	entry.Status = resp.StatusCode
+	resp.Body = &debugBody{ReadCloser: resp.Body, done: func(body []byte) {
+		entry.Duration = time.Since(entry.Time)
+		entry.Response = redact.Redact(string(body), secrets...)
+		t.write(entry)
+	}}
+	return resp, nil
+}
+
+func (t *debugTransport) write(entry *DebugEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.w.Write(append(line, '\n'))
+}
+
+// requestSecrets returns the credentials req carries in its headers.
+func requestSecrets(req *http.Request) []string {
+	var secrets []string
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		secrets = append(secrets, strings.TrimPrefix(auth, "Bearer "))
+	}
+	if key := req.Header.Get("x-api-key"); key != "" {
+		secrets = append(secrets, key)
+	}
+	return secrets
+}
+
+// debugBody records a response body as it is read and hands it to done
+// when closed.
+type debugBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	once sync.Once
+	done func(body []byte)
+}
+
+func (b *debugBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	return n, err
+}
+
+func (b *debugBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.done(b.buf.Bytes()) })
+	return err
+}
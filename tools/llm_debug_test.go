@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phenomenon0/polymarket-agents/core"
+)
+
+func TestDebugLogCapturesRequestAndResponse(t *testing.T) {
+	const apiKey = "sk-test-secret-123456"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A provider echoing the key must not leak it into the log
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model":"test","choices":[{"message":{"content":"forecast 0.62 for ` + r.Header.Get("Authorization") + `"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	run := func(debugLog *bytes.Buffer) {
+		config := LLMConfig{
+			Provider: "openai",
+			Model:    "gpt-4o-mini",
+			APIKey:   apiKey,
+			BaseURL:  server.URL,
+			Timeout:  5 * time.Second,
+		}
+		if debugLog != nil {
+			config.DebugLog = debugLog
+		}
+		result := NewLLMTool(config).Execute(&core.ToolContext{
+			Ctx: context.Background(),
+			Request: &core.Message{
+				ToolReq: &core.ToolRequestPayload{Input: "will it rain?"},
+			},
+		})
+		if result.Status != core.ToolComplete {
+			t.Fatalf("expected completion, got %s: %s", result.Status, result.Error)
+		}
+	}
+
+	var debugLog bytes.Buffer
+	run(&debugLog)
+
+	lines := strings.Split(strings.TrimSpace(debugLog.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one log entry, got %d: %s", len(lines), debugLog.String())
+	}
+	if strings.Contains(debugLog.String(), apiKey) {
+		t.Fatalf("debug log leaked the API key: %s", debugLog.String())
+	}
+	var entry DebugEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("entry is not JSON: %v", err)
+	}
+	if entry.ID == "" || entry.Status != http.StatusOK || entry.URL != server.URL+"/chat/completions" {
+		t.Errorf("unexpected entry header: %+v", entry)
+	}
+	if !strings.Contains(entry.Request, "will it rain?") {
+		t.Errorf("request body not captured: %s", entry.Request)
+	}
+	if !strings.Contains(entry.Response, "forecast 0.62") {
+		t.Errorf("response body not captured: %s", entry.Response)
+	}
+
+	// Disabled: the plain transport, so nothing is recorded anywhere
+	run(nil)
+	if _, ok := NewLLMTool(LLMConfig{}).client.Transport.(*http.Transport); !ok {
+		t.Error("expected no debug transport without DebugLog")
+	}
+	if n := strings.Count(debugLog.String(), "\n"); n != 1 {
+		t.Errorf("expected the disabled run to log nothing, got %d entries", n)
+	}
+}
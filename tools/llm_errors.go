@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrorClass says whether an LLM call failure is worth retrying.
+type ErrorClass string
+
+const (
+	// ErrorRetriable covers rate limits, server errors, and network failures.
+	ErrorRetriable ErrorClass = "retriable"
+	// ErrorTerminal covers failures that will recur on retry: bad requests,
+	// bad or unauthorized keys, and prompts over the context length.
+	ErrorTerminal ErrorClass = "terminal"
+)
+
+// ProviderError is a non-2xx response from an LLM provider.
+type ProviderError struct {
+	Provider   string // Display name, e.g. "OpenAI"
+	StatusCode int
+	Body       string
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("%s API error %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// Class classifies the response by status code. Timeouts (408), rate limits
+// (429), and 5xx are retriable; any other 4xx is terminal.
+func (e *ProviderError) Class() ErrorClass {
+	switch {
+	case e.StatusCode == http.StatusRequestTimeout,
+		e.StatusCode == http.StatusTooManyRequests,
+		e.StatusCode >= 500:
+		return ErrorRetriable
+	case e.StatusCode >= 400:
+		return ErrorTerminal
+	default:
+		return ErrorRetriable
+	}
+}
+
+// classifyLLMError classifies err from a callX method. Errors that didn't
+// come from a provider response (network, timeout, decode) are retriable.
+func classifyLLMError(err error) ErrorClass {
+	var perr *ProviderError
+	if errors.As(err, &perr) {
+		return perr.Class()
+	}
+	return ErrorRetriable
+}
@@ -3,6 +3,7 @@ package tools
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -491,6 +492,8 @@ func (r *ModelRouter) GetConfig(tier ModelTier, index int) (LLMConfig, error) {
 	return LLMConfig{
 		Provider:    preset.Provider,
 		Model:       preset.Model,
+		Tier:        string(preset.Tier),
+		Preset:      preset.Name,
 		APIKey:      apiKey,
 		BaseURL:     preset.BaseURL,
 		MaxTokens:   4096,
@@ -499,6 +502,46 @@ func (r *ModelRouter) GetConfig(tier ModelTier, index int) (LLMConfig, error) {
 	}, nil
 }
 
+// Failover returns a FailoverFunc implementing tier-level failover: a failing
+// config is replaced by the next preset in its tier served by a different
+// endpoint, wrapping around the tier. The caller's sampling, timeout, retry,
+// and concurrency settings carry over.
+func (r *ModelRouter) Failover() FailoverFunc {
+	return func(failed LLMConfig) (LLMConfig, bool) {
+		tier := ModelTier(failed.Tier)
+		presets := r.presets[tier]
+
+		start := -1
+		for i, preset := range presets {
+			if preset.Name == failed.Preset {
+				start = i
+				break
+			}
+		}
+
+		for i := 1; i <= len(presets); i++ {
+			idx := (start + i) % len(presets)
+			preset := presets[idx]
+			if preset.Name == failed.Preset || (preset.Provider == failed.Provider && preset.BaseURL == failed.BaseURL) {
+				continue
+			}
+
+			next, err := r.GetConfig(tier, idx)
+			if err != nil {
+				continue
+			}
+			next.MaxTokens = failed.MaxTokens
+			next.Temperature = failed.Temperature
+			next.Timeout = failed.Timeout
+			next.RetryPolicy = failed.RetryPolicy
+			next.MaxConcurrent = failed.MaxConcurrent
+			next.Failover = failed.Failover
+			return next, true
+		}
+		return LLMConfig{}, false
+	}
+}
+
 // GetBestFor returns the best model for a specific use case
 func (r *ModelRouter) GetBestFor(useCase string) (LLMConfig, error) {
 	switch useCase {
@@ -570,6 +613,29 @@ func (r *ModelRouter) GetPresetByName(name string) (ModelPreset, error) {
 	return ModelPreset{}, fmt.Errorf("model not found: %s", name)
 }
 
+// EstimateWorkloadCost estimates the USD cost of numCalls requests to the
+// named preset with the given average token counts. Models in the rate table
+// (matched without a vendor prefix such as "anthropic/") are priced per
+// prompt and completion token; others use the preset's blended CostPer1k.
+// Free presets (CostPer1k 0, e.g. Ollama and ":free" routes) and unknown
+// names estimate 0.
+func (r *ModelRouter) EstimateWorkloadCost(preset string, numCalls int, avgPromptTokens, avgCompletionTokens int) float64 {
+	p, err := r.GetPresetByName(preset)
+	if err != nil || p.CostPer1k == 0 || numCalls <= 0 {
+		return 0
+	}
+
+	model := p.Model
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:]
+	}
+	perCall := float64(avgPromptTokens+avgCompletionTokens) / 1000 * p.CostPer1k
+	if in, out, ok := rateForModel(model); ok {
+		perCall = float64(avgPromptTokens)*in + float64(avgCompletionTokens)*out
+	}
+	return float64(numCalls) * perCall
+}
+
 // ListTier returns all models in a tier
 func (r *ModelRouter) ListTier(tier ModelTier) []ModelPreset {
 	return r.presets[tier]
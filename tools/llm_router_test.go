@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"math"
 	"os"
 	"testing"
 	"time"
@@ -384,3 +385,27 @@ func TestModelPresetFields(t *testing.T) {
 		}
 	}
 }
+
+func TestEstimateWorkloadCost(t *testing.T) {
+	router := NewModelRouter()
+
+	cases := []struct {
+		preset string
+		want   float64
+	}{
+		// anthropic/claude-sonnet-4.5 is in the rate table at $3/M prompt and
+		// $15/M completion: 1000 * (2000*0.000003 + 500*0.000015)
+		{"Claude Sonnet 4.5", 1000 * (2000*0.000003 + 500*0.000015)},
+		// deepcogito/cogito-v2.1-671b isn't, so CostPer1k applies to all
+		// 2500 tokens: 1000 * 2.5 * 0.00125
+		{"DeepSeek V3 (Cogito)", 1000 * 2.5 * 0.00125},
+		{"Ollama Qwen3 8B", 0},
+		{"no such preset", 0},
+	}
+	for _, tc := range cases {
+		got := router.EstimateWorkloadCost(tc.preset, 1000, 2000, 500)
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("%s: expected $%.4f, got $%.4f", tc.preset, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/phenomenon0/polymarket-agents/core"
+	"github.com/phenomenon0/polymarket-agents/pkg/httpx"
+)
+
+func TestLLMToolMaxConcurrent(t *testing.T) {
+	var inFlight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model":"test","choices":[{"message":{"content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	const maxConcurrent = 3
+	cfg := LLMConfig{
+		Provider:      "openai",
+		Model:         "gpt-4o-mini",
+		BaseURL:       server.URL,
+		Timeout:       5 * time.Second,
+		MaxConcurrent: maxConcurrent,
+	}
+
+	// Two tools on the same endpoint share the limit
+	tools := []*LLMTool{NewLLMTool(cfg), NewLLMTool(cfg)}
+
+	var wg sync.WaitGroup
+	var failed int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(llm *LLMTool) {
+			defer wg.Done()
+			result := llm.Execute(&core.ToolContext{
+				Ctx: context.Background(),
+				Request: &core.Message{
+					ToolReq: &core.ToolRequestPayload{Input: "hi"},
+				},
+			})
+			if result.Status != core.ToolComplete {
+				atomic.AddInt32(&failed, 1)
+			}
+		}(tools[i%len(tools)])
+	}
+	wg.Wait()
+
+	if failed > 0 {
+		t.Errorf("%d requests failed, expected all to queue and complete", failed)
+	}
+	if peak > maxConcurrent {
+		t.Errorf("Peak concurrency %d exceeded cap %d", peak, maxConcurrent)
+	}
+	if peak == 0 {
+		t.Error("Server saw no requests")
+	}
+}
+
+func TestLLMToolSlotsHonorEachLimit(t *testing.T) {
+	cfg := LLMConfig{Provider: "openai", BaseURL: "http://slots.test", MaxConcurrent: 2}
+	small := NewLLMTool(cfg)
+	cfg.MaxConcurrent = 8
+	large := NewLLMTool(cfg)
+
+	if cap(small.slots) != 2 || cap(large.slots) != 8 {
+		t.Errorf("expected pools of 2 and 8, got %d and %d", cap(small.slots), cap(large.slots))
+	}
+	if again := NewLLMTool(cfg); again.slots != large.slots {
+		t.Error("same endpoint and limit should share one pool")
+	}
+}
+
+func TestLLMToolTransportConfig(t *testing.T) {
+	transport := func(tool *LLMTool) *http.Transport {
+		return tool.client.Transport.(*http.Transport)
+	}
+
+	if tr := transport(NewLLMTool(LLMConfig{})); tr.MaxConnsPerHost != 20 || tr.ResponseHeaderTimeout != 120*time.Second {
+		t.Errorf("expected LLM defaults, got %d conns/host, %s header timeout", tr.MaxConnsPerHost, tr.ResponseHeaderTimeout)
+	}
+
+	config := httpx.DefaultTransportConfig()
+	config.MaxIdleConnsPerHost = 64
+	config.MaxConnsPerHost = 128
+	tr := transport(NewLLMTool(LLMConfig{Transport: &config}))
+	if tr.MaxIdleConnsPerHost != 64 || tr.MaxConnsPerHost != 128 {
+		t.Errorf("transport config not applied: %d idle/host, %d conns/host", tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost)
+	}
+}
+
+func TestLLMToolFailsOverWithinTier(t *testing.T) {
+	var primaryHits, secondaryHits int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&primaryHits, 1)
+		http.Error(w, `{"error":"rate limited"}`, http.StatusTooManyRequests)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&secondaryHits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model":"backup-model","choices":[{"message":{"content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer secondary.Close()
+
+	router := &ModelRouter{
+		presets: map[ModelTier][]ModelPreset{
+			TierFast: {
+				{Name: "Primary", Provider: "openai", Model: "primary-model", BaseURL: primary.URL, Tier: TierFast},
+				{Name: "Secondary", Provider: "openai", Model: "backup-model", BaseURL: secondary.URL, Tier: TierFast},
+			},
+		},
+		apiKeys: map[string]string{},
+	}
+
+	cfg, err := router.GetConfig(TierFast, 0)
+	if err != nil {
+		t.Fatalf("GetConfig failed: %v", err)
+	}
+	cfg.RetryPolicy = RetryPolicy{MaxRetries: 4, FailoverAfter: 2}
+	cfg.Failover = router.Failover()
+
+	result := NewLLMTool(cfg).Execute(&core.ToolContext{
+		Ctx: context.Background(),
+		Request: &core.Message{
+			ToolReq: &core.ToolRequestPayload{Input: "hi"},
+		},
+	})
+	if result.Status != core.ToolComplete {
+		t.Fatalf("Expected completion after failover, got %s: %s", result.Status, result.Error)
+	}
+	if got := atomic.LoadInt32(&primaryHits); got != 2 {
+		t.Errorf("Expected 2 attempts on the primary before failover, got %d", got)
+	}
+	if got := atomic.LoadInt32(&secondaryHits); got != 1 {
+		t.Errorf("Expected 1 attempt on the secondary, got %d", got)
+	}
+	if preset := result.Metadata["preset"]; preset != "Secondary" {
+		t.Errorf("Expected result attributed to Secondary, got %v", preset)
+	}
+	if resp := result.Output.(*LLMResponse); resp.Model != "backup-model" {
+		t.Errorf("Expected backup-model, got %s", resp.Model)
+	}
+}
+
+func TestLLMToolRetriesOnlyRetriableErrors(t *testing.T) {
+	tests := []struct {
+		status   int
+		attempts int32
+		class    ErrorClass
+	}{
+		{http.StatusUnauthorized, 1, ErrorTerminal},
+		{http.StatusServiceUnavailable, 3, ErrorRetriable},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			var hits int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&hits, 1)
+				http.Error(w, `{"error":"nope"}`, tt.status)
+			}))
+			defer server.Close()
+
+			result := NewLLMTool(LLMConfig{
+				Provider:    "openai",
+				Model:       "gpt-4o-mini",
+				BaseURL:     server.URL,
+				Timeout:     5 * time.Second,
+				RetryPolicy: RetryPolicy{MaxRetries: 3},
+			}).Execute(&core.ToolContext{
+				Ctx: context.Background(),
+				Request: &core.Message{
+					ToolReq: &core.ToolRequestPayload{Input: "hi"},
+				},
+			})
+
+			if result.Status != core.ToolFailed {
+				t.Fatalf("Expected failure, got %s", result.Status)
+			}
+			if got := atomic.LoadInt32(&hits); got != tt.attempts {
+				t.Errorf("Expected %d attempts, got %d", tt.attempts, got)
+			}
+			if class := result.Metadata["error_class"]; class != tt.class {
+				t.Errorf("Expected error_class %s, got %v", tt.class, class)
+			}
+			if code := result.Metadata["status_code"]; code != tt.status {
+				t.Errorf("Expected status_code %d, got %v", tt.status, code)
+			}
+		})
+	}
+}
+
+func TestReasoningEffortOnlyForReasoningModels(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model":"test","choices":[{"message":{"content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		model string
+		want  any
+	}{
+		{"gpt-5.1", "low"},
+		{"o4-mini", "low"},
+		{"gpt-4o-mini", nil},
+	}
+
+	for _, tt := range tests {
+		result := NewLLMTool(LLMConfig{
+			Provider:        "openai",
+			Model:           tt.model,
+			BaseURL:         server.URL,
+			Timeout:         5 * time.Second,
+			ReasoningEffort: "low",
+		}).Execute(&core.ToolContext{
+			Ctx: context.Background(),
+			Request: &core.Message{
+				ToolReq: &core.ToolRequestPayload{Input: "hi"},
+			},
+		})
+		if result.Status != core.ToolComplete {
+			t.Fatalf("%s: expected completion, got %s: %s", tt.model, result.Status, result.Error)
+		}
+		if got := body["reasoning_effort"]; got != tt.want {
+			t.Errorf("%s: expected reasoning_effort %v, got %v", tt.model, tt.want, got)
+		}
+	}
+}
+
+func TestDeepSeekReasoningTokensCountedAndPriced(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model":"deepseek-reasoner","choices":[{"message":{"content":"ok"},"finish_reason":"stop"}],
+			"usage":{"prompt_tokens":100,"completion_tokens":500,"total_tokens":600,
+			"completion_tokens_details":{"reasoning_tokens":400}}}`))
+	}))
+	defer server.Close()
+
+	llm := NewLLMTool(LLMConfig{
+		Provider:           "deepseek",
+		Model:              "deepseek-reasoner",
+		BaseURL:            server.URL,
+		MaxTokens:          1000,
+		Timeout:            5 * time.Second,
+		MaxReasoningTokens: 2000,
+	})
+	result := llm.Execute(&core.ToolContext{
+		Ctx: context.Background(),
+		Request: &core.Message{
+			ToolReq: &core.ToolRequestPayload{Input: "hi"},
+		},
+	})
+	if result.Status != core.ToolComplete {
+		t.Fatalf("expected completion, got %s: %s", result.Status, result.Error)
+	}
+	if got := body["max_tokens"]; got != float64(3000) {
+		t.Errorf("expected max_tokens to include the reasoning budget, got %v", got)
+	}
+
+	resp := result.Output.(*LLMResponse)
+	if resp.Usage.CompletionTokens != 500 || resp.Usage.ReasoningTokens != 400 {
+		t.Errorf("expected 500 completion tokens with 400 reasoning, got %d and %d", resp.Usage.CompletionTokens, resp.Usage.ReasoningTokens)
+	}
+
+	costs := llm.costTracker
+	if costs.ReasoningTokens != 400 {
+		t.Errorf("expected 400 reasoning tokens tracked, got %d", costs.ReasoningTokens)
+	}
+	const inputRate, outputRate = 0.00000055, 0.00000219
+	if want := 100*inputRate + 500*outputRate; math.Abs(costs.EstimatedCostUSD-want) > 1e-12 {
+		t.Errorf("expected total cost %g, got %g", want, costs.EstimatedCostUSD)
+	}
+	if want := 400 * outputRate; math.Abs(costs.ReasoningCostUSD-want) > 1e-12 {
+		t.Errorf("expected reasoning cost %g, got %g", want, costs.ReasoningCostUSD)
+	}
+}
+
+func TestReasoningTokensOutsideCompletionAreBilled(t *testing.T) {
+	var u openAIUsage
+	json.Unmarshal([]byte(`{"prompt_tokens":100,"completion_tokens":100,"total_tokens":500,
+		"completion_tokens_details":{"reasoning_tokens":300}}`), &u)
+	if completion, reasoning := u.completion(); completion != 400 || reasoning != 300 {
+		t.Errorf("expected 400 completion tokens with 300 reasoning, got %d and %d", completion, reasoning)
+	}
+}
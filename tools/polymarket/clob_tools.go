@@ -3,6 +3,7 @@ package polymarket
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/phenomenon0/polymarket-agents/core"
@@ -28,20 +29,27 @@ type GetOrderBookTool struct {
 }
 
 type GetOrderBookInput struct {
-	TokenID string `json:"token_id"` // Token ID (YES or NO outcome)
+	TokenID           string  `json:"token_id"`                      // Token ID (YES or NO outcome)
+	StaleAfterSeconds float64 `json:"stale_after_seconds,omitempty"` // Age at which the book is flagged stale (default 60)
 }
 
+// DefaultBookStaleAfter is the book age at which GetOrderBookTool sets Stale.
+const DefaultBookStaleAfter = 60 * time.Second
+
 type GetOrderBookOutput struct {
-	TokenID   string      `json:"token_id"`
-	BestBid   *PriceSize  `json:"best_bid,omitempty"`
-	BestAsk   *PriceSize  `json:"best_ask,omitempty"`
-	Midpoint  string      `json:"midpoint"`
-	Spread    string      `json:"spread"`
-	SpreadBps string      `json:"spread_bps"`
-	BidDepth  int         `json:"bid_depth"`
-	AskDepth  int         `json:"ask_depth"`
-	Bids      []PriceSize `json:"bids"`
-	Asks      []PriceSize `json:"asks"`
+	TokenID    string      `json:"token_id"`
+	Timestamp  string      `json:"timestamp,omitempty"`   // Venue timestamp, as reported
+	AgeSeconds *float64    `json:"age_seconds,omitempty"` // Nil if the venue sent no parseable timestamp
+	Stale      bool        `json:"stale"`
+	BestBid    *PriceSize  `json:"best_bid,omitempty"`
+	BestAsk    *PriceSize  `json:"best_ask,omitempty"`
+	Midpoint   string      `json:"midpoint"`
+	Spread     string      `json:"spread"`
+	SpreadBps  string      `json:"spread_bps"`
+	BidDepth   int         `json:"bid_depth"`
+	AskDepth   int         `json:"ask_depth"`
+	Bids       []PriceSize `json:"bids"`
+	Asks       []PriceSize `json:"asks"`
 }
 
 type PriceSize struct {
@@ -62,7 +70,8 @@ func (t *GetOrderBookTool) InputSchema() []byte {
 		"type": "object",
 		"required": ["token_id"],
 		"properties": {
-			"token_id": {"type": "string", "description": "Token ID for the outcome to fetch orderbook"}
+			"token_id": {"type": "string", "description": "Token ID for the outcome to fetch orderbook"},
+			"stale_after_seconds": {"type": "number", "description": "Flag the book as stale when older than this (default 60)"}
 		}
 	}`)
 }
@@ -111,6 +120,7 @@ func (t *GetOrderBookTool) Execute(tc *core.ToolContext) *core.ToolExecResult {
 	// Build output
 	output := GetOrderBookOutput{
 		TokenID:   input.TokenID,
+		Timestamp: bookSummary.Timestamp,
 		Midpoint:  ob.Midpoint().String(),
 		Spread:    ob.Spread().String(),
 		SpreadBps: ob.SpreadBps().StringFixed(2),
@@ -118,6 +128,18 @@ func (t *GetOrderBookTool) Execute(tc *core.ToolContext) *core.ToolExecResult {
 		AskDepth:  ob.AskDepth(),
 	}
 
+	// Freshness
+	if ts, ok := parseBookTimestamp(bookSummary.Timestamp); ok {
+		staleAfter := DefaultBookStaleAfter
+		if input.StaleAfterSeconds > 0 {
+			staleAfter = time.Duration(input.StaleAfterSeconds * float64(time.Second))
+		}
+		age := time.Since(ts)
+		ageSeconds := age.Seconds()
+		output.AgeSeconds = &ageSeconds
+		output.Stale = age > staleAfter
+	}
+
 	// Best bid/ask
 	if bestBidPrice, bestBidSize := ob.BestBid(); !bestBidPrice.IsZero() {
 		output.BestBid = &PriceSize{Price: bestBidPrice.String(), Size: bestBidSize.String()}
@@ -149,6 +171,24 @@ func (t *GetOrderBookTool) Execute(tc *core.ToolContext) *core.ToolExecResult {
 	}
 }
 
+// parseBookTimestamp parses a CLOB book timestamp, which the venue sends as
+// Unix milliseconds (seconds and RFC 3339 are accepted too).
+func parseBookTimestamp(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, false
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n >= 1e12 {
+			return time.UnixMilli(n), true
+		}
+		return time.Unix(n, 0), true
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
 // GetMarketInfoTool fetches market information from CLOB.
 type GetMarketInfoTool struct {
 	client *clob.Client
@@ -510,6 +550,11 @@ func (t *GetTradesTool) Execute(tc *core.ToolContext) *core.ToolExecResult {
 // WARNING: This tool modifies positions and should have strict rate limits.
 type PlaceOrderTool struct {
 	client *clob.Client
+
+	// RequireCommit makes every placement a prepare: orders are signed and
+	// simulated but only posted by polymarket_commit_order, so an approval
+	// step sits between the agent and the exchange.
+	RequireCommit bool
 }
 
 type PlaceOrderInput struct {
@@ -519,12 +564,24 @@ type PlaceOrderInput struct {
 	Size      float64 `json:"size"`                 // Amount in tokens
 	OrderType string  `json:"order_type,omitempty"` // "GTC", "FOK", "GTD"
 	NegRisk   bool    `json:"neg_risk,omitempty"`   // For neg-risk markets
+	PostOnly  bool    `json:"post_only,omitempty"`  // Reject if the order would cross the book
+	Prepare   bool    `json:"prepare,omitempty"`    // Sign and simulate only; requires RequireCommit
+
+	// ConditionID is the token's market, required with neg_risk so the price
+	// can be checked against the market's tick and outcome prices.
+	ConditionID string `json:"condition_id,omitempty"`
 }
 
 type PlaceOrderOutput struct {
 	Success bool   `json:"success"`
 	OrderID string `json:"order_id,omitempty"`
 	Error   string `json:"error,omitempty"`
+
+	// Set instead of OrderID when the order was prepared, not posted
+	PreparedID string  `json:"prepared_id,omitempty"`
+	ExpiresAt  string  `json:"expires_at,omitempty"`
+	FillSize   float64 `json:"fill_size,omitempty"` // Size the book would fill immediately
+	AvgPrice   float64 `json:"avg_price,omitempty"`
 }
 
 func NewPlaceOrderTool(client *clob.Client) *PlaceOrderTool {
@@ -545,7 +602,10 @@ func (t *PlaceOrderTool) InputSchema() []byte {
 			"price": {"type": "number", "minimum": 0.01, "maximum": 0.99, "description": "Limit price"},
 			"size": {"type": "number", "minimum": 0, "description": "Order size in tokens"},
 			"order_type": {"type": "string", "enum": ["GTC", "FOK", "GTD"], "description": "Order type (default GTC)"},
-			"neg_risk": {"type": "boolean", "description": "Whether this is a neg-risk market"}
+			"neg_risk": {"type": "boolean", "description": "Whether this is a neg-risk market"},
+			"condition_id": {"type": "string", "description": "Market condition ID (required with neg_risk)"},
+			"post_only": {"type": "boolean", "description": "Reject instead of taking liquidity if the order would cross the book"},
+			"prepare": {"type": "boolean", "description": "Sign and simulate without posting, returning a prepared_id for human approval. Only accepted when the server requires approval, where every order is prepared anyway"}
 		}
 	}`)
 }
@@ -577,6 +637,10 @@ func (t *PlaceOrderTool) Execute(tc *core.ToolContext) *core.ToolExecResult {
 	if input.Size <= 0 {
 		return errorResult(fmt.Errorf("size must be positive"))
 	}
+	// Without RequireCommit nothing can commit a prepared order
+	if input.Prepare && !t.RequireCommit {
+		return errorResult(fmt.Errorf("prepare is only available when orders require approval (-require-commit)"))
+	}
 
 	ctx, cancel := context.WithTimeout(tc.Ctx, 30*time.Second)
 	defer cancel()
@@ -599,11 +663,37 @@ func (t *PlaceOrderTool) Execute(tc *core.ToolContext) *core.ToolExecResult {
 		Price:     input.Price,
 		Size:      input.Size,
 		OrderType: orderType,
+		PostOnly:  input.PostOnly,
 	}
 
 	// Get tick size from market (use default for now)
 	tickSize := "0.01"
 
+	if input.NegRisk {
+		meta, err := validateNegRiskOrder(ctx, t.client, &input)
+		if err != nil {
+			return errorResult(err)
+		}
+		tickSize = meta.MinimumTickSize
+	}
+
+	if t.RequireCommit {
+		prepared, err := t.client.PrepareOrder(ctx, args, tickSize, input.NegRisk)
+		if err != nil {
+			return errorResult(fmt.Errorf("prepare order failed: %w", err))
+		}
+		return &core.ToolExecResult{
+			Status: core.ToolComplete,
+			Output: PlaceOrderOutput{
+				Success:    true,
+				PreparedID: prepared.ID,
+				ExpiresAt:  prepared.ExpiresAt.Format(time.RFC3339),
+				FillSize:   prepared.FillSize,
+				AvgPrice:   prepared.AvgPrice,
+			},
+		}
+	}
+
 	resp, err := t.client.CreateAndPostOrder(ctx, args, tickSize, input.NegRisk)
 	if err != nil {
 		return errorResult(fmt.Errorf("place order failed: %w", err))
@@ -619,6 +709,124 @@ func (t *PlaceOrderTool) Execute(tc *core.ToolContext) *core.ToolExecResult {
 	}
 }
 
+// validateNegRiskOrder checks a neg-risk order against its market before
+// signing: the market must be neg-risk and list the token, the price must
+// sit on the market's tick within [tick, 1-tick], and a buy must not pay more
+// than one minus the complementary outcome's price.
+func validateNegRiskOrder(ctx context.Context, client *clob.Client, input *PlaceOrderInput) (*clob.MarketMeta, error) {
+	if input.ConditionID == "" {
+		return nil, fmt.Errorf("condition_id is required for neg-risk orders")
+	}
+
+	meta, err := client.GetMarketMeta(ctx, input.ConditionID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch market %s: %w", input.ConditionID, err)
+	}
+	if !meta.NegRisk {
+		return nil, fmt.Errorf("market %s is not neg-risk", input.ConditionID)
+	}
+
+	tick, err := decimal.NewFromString(meta.MinimumTickSize)
+	if err != nil || !tick.IsPositive() {
+		return nil, fmt.Errorf("market %s has invalid tick size %q", input.ConditionID, meta.MinimumTickSize)
+	}
+	price := decimal.NewFromFloat(input.Price)
+	maxPrice := decimal.NewFromInt(1).Sub(tick)
+	if price.LessThan(tick) || price.GreaterThan(maxPrice) {
+		return nil, fmt.Errorf("price %s outside neg-risk band [%s, %s]", price, tick, maxPrice)
+	}
+	if !price.Mod(tick).IsZero() {
+		return nil, fmt.Errorf("price %s is not a multiple of tick size %s", price, tick)
+	}
+
+	var found bool
+	var complement []clob.Token
+	for _, token := range meta.Tokens {
+		if token.TokenID == input.TokenID {
+			found = true
+		} else {
+			complement = append(complement, token)
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("token %s is not in market %s", input.TokenID, input.ConditionID)
+	}
+
+	// Outcome prices sum to one: buying above 1 - complement overpays versus
+	// selling the complementary outcome
+	if input.Side == "BUY" && len(complement) == 1 {
+		if other, err := decimal.NewFromString(complement[0].Price); err == nil && other.IsPositive() {
+			if ceiling := decimal.NewFromInt(1).Sub(other).Add(tick); price.GreaterThan(ceiling) {
+				return nil, fmt.Errorf("buy at %s exceeds %s implied by %s outcome at %s",
+					price, ceiling, complement[0].Outcome, other)
+			}
+		}
+	}
+
+	return meta, nil
+}
+
+// CommitOrderTool posts an order prepared by polymarket_place_order. It is
+// the approval step, so serve it to a human approver rather than registering
+// it alongside polymarket_place_order.
+type CommitOrderTool struct {
+	client *clob.Client
+}
+
+type CommitOrderInput struct {
+	PreparedID string `json:"prepared_id"`
+}
+
+func NewCommitOrderTool(client *clob.Client) *CommitOrderTool {
+	return &CommitOrderTool{client: client}
+}
+
+func (t *CommitOrderTool) Name() string {
+	return "polymarket_commit_order"
+}
+
+func (t *CommitOrderTool) InputSchema() []byte {
+	return []byte(`{
+		"type": "object",
+		"required": ["prepared_id"],
+		"properties": {
+			"prepared_id": {"type": "string", "description": "ID returned by polymarket_place_order under require-commit"}
+		}
+	}`)
+}
+
+func (t *CommitOrderTool) OutputSchema() []byte {
+	return []byte(`{"type": "object"}`)
+}
+
+func (t *CommitOrderTool) Execute(tc *core.ToolContext) *core.ToolExecResult {
+	var input CommitOrderInput
+	if err := parseInput(tc.Request, &input); err != nil {
+		return errorResult(err)
+	}
+
+	if input.PreparedID == "" {
+		return errorResult(fmt.Errorf("prepared_id is required"))
+	}
+
+	ctx, cancel := context.WithTimeout(tc.Ctx, 30*time.Second)
+	defer cancel()
+
+	resp, err := t.client.CommitOrder(ctx, input.PreparedID)
+	if err != nil {
+		return errorResult(fmt.Errorf("commit order failed: %w", err))
+	}
+
+	return &core.ToolExecResult{
+		Status: core.ToolComplete,
+		Output: PlaceOrderOutput{
+			Success: resp.Success,
+			OrderID: resp.OrderID,
+			Error:   resp.ErrorMsg,
+		},
+	}
+}
+
 // CancelOrderTool cancels an open order.
 type CancelOrderTool struct {
 	client *clob.Client
@@ -691,7 +899,11 @@ func (t *CancelOrderTool) Execute(tc *core.ToolContext) *core.ToolExecResult {
 	}
 }
 
-// CancelAllOrdersTool cancels all open orders.
+// cancelAllRetries is how many times CancelAllOrdersTool re-cancels orders
+// the venue left open.
+const cancelAllRetries = 2
+
+// CancelAllOrdersTool cancels all open orders, verifying none survive.
 type CancelAllOrdersTool struct {
 	client *clob.Client
 }
@@ -720,7 +932,7 @@ func (t *CancelAllOrdersTool) Execute(tc *core.ToolContext) *core.ToolExecResult
 	ctx, cancel := context.WithTimeout(tc.Ctx, 30*time.Second)
 	defer cancel()
 
-	err := t.client.CancelAllOrders(ctx)
+	err := t.client.CancelAllOrdersVerified(ctx, cancelAllRetries)
 	if err != nil {
 		return &core.ToolExecResult{
 			Status: core.ToolComplete,
@@ -776,9 +988,12 @@ func RegisterCLOBAuthenticatedTools(registry *core.ToolRegistry, client *clob.Cl
 	registry.Register(NewGetTradesTool(client), policy, RiskClassAuthenticated)
 }
 
-// RegisterCLOBTradingTools registers trading tools.
+// RegisterCLOBTradingTools registers trading tools. With requireCommit,
+// polymarket_place_order only prepares orders. polymarket_commit_order is
+// never registered here: it belongs on a human approval path, not in the
+// registry of the agent that places the orders.
 // WARNING: These tools can modify positions and should be used with care.
-func RegisterCLOBTradingTools(registry *core.ToolRegistry, client *clob.Client) {
+func RegisterCLOBTradingTools(registry *core.ToolRegistry, client *clob.Client, requireCommit bool) {
 	// Strict rate limiting for trading
 	tradingPolicy := core.ToolPolicy{
 		MaxRetries:      1, // No retries for order placement
@@ -793,7 +1008,9 @@ func RegisterCLOBTradingTools(registry *core.ToolRegistry, client *clob.Client)
 		CostPerCall:     1.0,   // Each order counts as 1
 	}
 
-	registry.Register(NewPlaceOrderTool(client), tradingPolicy, RiskClassTrading)
+	placeOrder := NewPlaceOrderTool(client)
+	placeOrder.RequireCommit = requireCommit
+	registry.Register(placeOrder, tradingPolicy, RiskClassTrading)
 
 	// Cancel operations can be slightly more frequent
 	cancelPolicy := tradingPolicy
@@ -806,8 +1023,8 @@ func RegisterCLOBTradingTools(registry *core.ToolRegistry, client *clob.Client)
 }
 
 // RegisterAllCLOBTools registers all CLOB tools.
-func RegisterAllCLOBTools(registry *core.ToolRegistry, client *clob.Client) {
+func RegisterAllCLOBTools(registry *core.ToolRegistry, client *clob.Client, requireCommit bool) {
 	RegisterCLOBReadOnlyTools(registry, client)
 	RegisterCLOBAuthenticatedTools(registry, client)
-	RegisterCLOBTradingTools(registry, client)
+	RegisterCLOBTradingTools(registry, client, requireCommit)
 }
@@ -0,0 +1,108 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/phenomenon0/polymarket-agents/core"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/clob"
+)
+
+const testPrivateKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+func TestPlaceOrderPrepareNeedsRequireCommit(t *testing.T) {
+	client, err := clob.NewClient(testPrivateKey,
+		clob.WithCLOBBaseURL("http://127.0.0.1:0"),
+		clob.WithCredentials(&clob.APICredentials{APIKey: "key", Secret: "c2VjcmV0", Passphrase: "pass"}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	result := NewPlaceOrderTool(client).Execute(&core.ToolContext{
+		Ctx: context.Background(),
+		Request: &core.Message{ToolReq: &core.ToolRequestPayload{
+			Input: PlaceOrderInput{TokenID: "1001", Side: "BUY", Price: 0.4, Size: 10, Prepare: true},
+		}},
+	})
+	if result.Status != core.ToolFailed || !strings.Contains(result.Error, "require-commit") {
+		t.Errorf("Expected prepare rejected without RequireCommit, got %s %q", result.Status, result.Error)
+	}
+}
+
+func TestPlaceOrderNegRiskRejectsOutOfBand(t *testing.T) {
+	var posts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		markets := map[string]clob.MarketInfo{
+			"/markets/0xneg": {
+				ConditionID:     "0xneg",
+				MinimumTickSize: "0.001",
+				NegRisk:         true,
+				Tokens: []clob.Token{
+					{TokenID: "1001", Outcome: "Yes", Price: "0.400"},
+					{TokenID: "1002", Outcome: "No", Price: "0.600"},
+				},
+			},
+			"/markets/0xcoarse": {
+				ConditionID:     "0xcoarse",
+				MinimumTickSize: "0.1",
+				NegRisk:         true,
+				Tokens:          []clob.Token{{TokenID: "2001", Outcome: "Yes"}, {TokenID: "2002", Outcome: "No"}},
+			},
+			"/markets/0xbinary": {ConditionID: "0xbinary", MinimumTickSize: "0.01"},
+		}
+		if market, ok := markets[r.URL.Path]; ok {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(market)
+			return
+		}
+		atomic.AddInt32(&posts, 1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client, err := clob.NewClient(testPrivateKey,
+		clob.WithCLOBBaseURL(server.URL),
+		clob.WithCredentials(&clob.APICredentials{APIKey: "key", Secret: "c2VjcmV0", Passphrase: "pass"}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	tool := NewPlaceOrderTool(client)
+
+	cases := []struct {
+		name  string
+		input PlaceOrderInput
+		want  string
+	}{
+		{"missing market", PlaceOrderInput{TokenID: "1001", Side: "BUY", Price: 0.4, Size: 10, NegRisk: true}, "condition_id is required"},
+		{"not neg-risk", PlaceOrderInput{TokenID: "1001", Side: "BUY", Price: 0.4, Size: 10, NegRisk: true, ConditionID: "0xbinary"}, "is not neg-risk"},
+		{"out of band", PlaceOrderInput{TokenID: "2001", Side: "SELL", Price: 0.95, Size: 10, NegRisk: true, ConditionID: "0xcoarse"}, "outside neg-risk band [0.1, 0.9]"},
+		{"off tick", PlaceOrderInput{TokenID: "1001", Side: "SELL", Price: 0.0105, Size: 10, NegRisk: true, ConditionID: "0xneg"}, "not a multiple of tick"},
+		{"above complement", PlaceOrderInput{TokenID: "1001", Side: "BUY", Price: 0.45, Size: 10, NegRisk: true, ConditionID: "0xneg"}, "implied by No outcome"},
+		{"unknown token", PlaceOrderInput{TokenID: "9999", Side: "BUY", Price: 0.4, Size: 10, NegRisk: true, ConditionID: "0xneg"}, "not in market"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := tool.Execute(&core.ToolContext{
+				Ctx:     context.Background(),
+				Request: &core.Message{ToolReq: &core.ToolRequestPayload{Input: tc.input}},
+			})
+			if result.Status != core.ToolFailed {
+				t.Fatalf("Expected rejection, got %s", result.Status)
+			}
+			if !strings.Contains(result.Error, tc.want) {
+				t.Errorf("Expected error containing %q, got %q", tc.want, result.Error)
+			}
+		})
+	}
+
+	if n := atomic.LoadInt32(&posts); n != 0 {
+		t.Errorf("Expected no order to reach the exchange, got %d requests", n)
+	}
+}
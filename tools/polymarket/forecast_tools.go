@@ -0,0 +1,278 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phenomenon0/polymarket-agents/core"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/gamma"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/agents"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultForecastMaxBatch    = 20
+	defaultForecastConcurrency = 4
+	defaultForecastMinEdgeBps  = 100
+)
+
+// ForecastMarketsTool forecasts a batch of markets in one call, so an agent
+// can screen a whole category without a round trip per market. It builds
+// each market's context from Gamma, runs the ensemble forecaster on the
+// markets concurrently, and returns their signals ranked by conviction.
+type ForecastMarketsTool struct {
+	gamma       *gamma.Client
+	forecaster  *agents.Forecaster
+	maxBatch    int
+	concurrency int
+	maxCost     float64 // USD per call; 0 means unlimited
+	minEdgeBps  int
+}
+
+// ForecastMarketsOption configures a ForecastMarketsTool.
+type ForecastMarketsOption func(*ForecastMarketsTool)
+
+// WithMaxBatch caps how many markets one call may forecast (default 20).
+// Larger batches are rejected rather than truncated.
+func WithMaxBatch(n int) ForecastMarketsOption {
+	return func(t *ForecastMarketsTool) {
+		t.maxBatch = n
+	}
+}
+
+// WithForecastConcurrency sets how many markets are forecast at once
+// (default 4).
+func WithForecastConcurrency(n int) ForecastMarketsOption {
+	return func(t *ForecastMarketsTool) {
+		t.concurrency = n
+	}
+}
+
+// WithForecastBudget caps the LLM spend of one call in USD, as reported by
+// the forecaster's cost-tracking clients. Once it is reached no further
+// markets are started; forecasts already in flight still finish, so a call
+// can overshoot by up to the concurrency's worth of forecasts.
+func WithForecastBudget(usd float64) ForecastMarketsOption {
+	return func(t *ForecastMarketsTool) {
+		t.maxCost = usd
+	}
+}
+
+// WithMinEdgeBps sets the edge a forecast needs to produce a BUY signal
+// when the input doesn't give one (default 100).
+func WithMinEdgeBps(bps int) ForecastMarketsOption {
+	return func(t *ForecastMarketsTool) {
+		t.minEdgeBps = bps
+	}
+}
+
+type ForecastMarketsInput struct {
+	Markets    []string `json:"markets"`      // Condition IDs or outcome token IDs
+	MinEdgeBps int      `json:"min_edge_bps"` // Overrides the tool default
+}
+
+type ForecastMarketsOutput struct {
+	Signals []MarketSignal  `json:"signals"` // Highest conviction first
+	Failed  []MarketFailure `json:"failed,omitempty"`
+	Skipped []string        `json:"skipped,omitempty"` // Not started: budget reached
+	Cost    float64         `json:"cost"`              // Estimated LLM spend in USD
+}
+
+type MarketSignal struct {
+	Market       string  `json:"market"` // As given in the input
+	ConditionID  string  `json:"condition_id"`
+	Question     string  `json:"question"`
+	YesTokenID   string  `json:"yes_token_id"`
+	Signal       string  `json:"signal"` // BUY or HOLD
+	Side         string  `json:"side"`   // YES or NO
+	Probability  float64 `json:"probability"`
+	YesPrice     float64 `json:"yes_price"`
+	EdgeBps      float64 `json:"edge_bps"`
+	Confidence   float64 `json:"confidence"`
+	Disagreement float64 `json:"disagreement"`
+	Reasoning    string  `json:"reasoning"`
+}
+
+type MarketFailure struct {
+	Market string `json:"market"`
+	Error  string `json:"error"`
+}
+
+// NewForecastMarketsTool creates a batch forecasting tool.
+func NewForecastMarketsTool(gammaClient *gamma.Client, forecaster *agents.Forecaster, opts ...ForecastMarketsOption) *ForecastMarketsTool {
+	t := &ForecastMarketsTool{
+		gamma:       gammaClient,
+		forecaster:  forecaster,
+		maxBatch:    defaultForecastMaxBatch,
+		concurrency: defaultForecastConcurrency,
+		minEdgeBps:  defaultForecastMinEdgeBps,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+func (t *ForecastMarketsTool) Name() string {
+	return "polymarket_forecast_markets"
+}
+
+func (t *ForecastMarketsTool) InputSchema() []byte {
+	return []byte(fmt.Sprintf(`{
+		"type": "object",
+		"required": ["markets"],
+		"properties": {
+			"markets": {"type": "array", "items": {"type": "string"}, "maxItems": %d, "description": "Condition IDs (0x...) or outcome token IDs of the markets to forecast"},
+			"min_edge_bps": {"type": "integer", "description": "Minimum edge in basis points for a BUY signal (default %d)"}
+		}
+	}`, t.maxBatch, t.minEdgeBps))
+}
+
+func (t *ForecastMarketsTool) OutputSchema() []byte {
+	return []byte(`{
+		"type": "object",
+		"properties": {
+			"signals": {"type": "array", "items": {"type": "object"}},
+			"failed": {"type": "array", "items": {"type": "object"}},
+			"skipped": {"type": "array", "items": {"type": "string"}},
+			"cost": {"type": "number"}
+		}
+	}`)
+}
+
+func (t *ForecastMarketsTool) Execute(tc *core.ToolContext) *core.ToolExecResult {
+	var input ForecastMarketsInput
+	if err := parseInput(tc.Request, &input); err != nil {
+		return errorResult(err)
+	}
+
+	if len(input.Markets) == 0 {
+		return errorResult(fmt.Errorf("markets is required"))
+	}
+	if len(input.Markets) > t.maxBatch {
+		return errorResult(fmt.Errorf("%d markets exceeds the batch limit of %d: split the request", len(input.Markets), t.maxBatch))
+	}
+	minEdgeBps := input.MinEdgeBps
+	if minEdgeBps == 0 {
+		minEdgeBps = t.minEdgeBps
+	}
+
+	startCost := t.forecaster.LLMCost()
+	overBudget := func() bool {
+		return t.maxCost > 0 && t.forecaster.LLMCost()-startCost >= t.maxCost
+	}
+
+	signals := make([]*agents.TradingSignal, len(input.Markets))
+	markets := make([]*gamma.Market, len(input.Markets))
+	errs := make([]error, len(input.Markets))
+	skipped := make([]bool, len(input.Markets))
+
+	sem := make(chan struct{}, max(t.concurrency, 1))
+	var wg sync.WaitGroup
+	for i, id := range input.Markets {
+		sem <- struct{}{}
+		if overBudget() {
+			<-sem
+			skipped[i] = true
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			markets[i], signals[i], errs[i] = t.forecast(tc.Ctx, id, minEdgeBps)
+		}()
+	}
+	wg.Wait()
+
+	output := ForecastMarketsOutput{Signals: make([]MarketSignal, 0, len(input.Markets))}
+	ranked := make([]*agents.TradingSignal, 0, len(signals))
+	index := make(map[*agents.TradingSignal]int, len(signals))
+	for i, id := range input.Markets {
+		switch {
+		case skipped[i]:
+			output.Skipped = append(output.Skipped, id)
+		case errs[i] != nil:
+			output.Failed = append(output.Failed, MarketFailure{Market: id, Error: errs[i].Error()})
+		default:
+			ranked = append(ranked, signals[i])
+			index[signals[i]] = i
+		}
+	}
+
+	for _, s := range agents.RankSignals(ranked) {
+		i := index[s]
+		m := markets[i]
+		output.Signals = append(output.Signals, MarketSignal{
+			Market:       input.Markets[i],
+			ConditionID:  m.ConditionID,
+			Question:     m.Question,
+			YesTokenID:   m.YesTokenID(),
+			Signal:       s.Signal.String(),
+			Side:         s.Side,
+			Probability:  s.Forecast.Probability.InexactFloat64(),
+			YesPrice:     s.CurrentPrice.InexactFloat64(),
+			EdgeBps:      s.EdgeBps.InexactFloat64(),
+			Confidence:   s.Forecast.Confidence.InexactFloat64(),
+			Disagreement: s.Forecast.Disagreement.InexactFloat64(),
+			Reasoning:    s.Reasoning,
+		})
+	}
+	output.Cost = t.forecaster.LLMCost() - startCost
+
+	return &core.ToolExecResult{
+		Status: core.ToolComplete,
+		Output: output,
+	}
+}
+
+// forecast looks up one market and runs the ensemble on its YES outcome.
+func (t *ForecastMarketsTool) forecast(ctx context.Context, id string, minEdgeBps int) (*gamma.Market, *agents.TradingSignal, error) {
+	lookupCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var market *gamma.Market
+	var err error
+	if strings.HasPrefix(id, "0x") {
+		market, err = t.gamma.GetMarket(lookupCtx, id)
+	} else {
+		market, err = t.gamma.GetMarketByTokenID(lookupCtx, id)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("get market failed: %w", err)
+	}
+
+	price := decimal.NewFromFloat(market.YesPrice())
+	if !price.IsPositive() || !price.LessThan(decimal.NewFromInt(1)) {
+		return nil, nil, fmt.Errorf("market %s has no tradable YES price", id)
+	}
+
+	forecast, err := t.forecaster.ForecastEnsemble(ctx, &agents.MarketContext{
+		TokenID:      market.YesTokenID(),
+		Market:       market.ConditionID,
+		Question:     market.Question,
+		Description:  market.Description,
+		CurrentPrice: price,
+		Volume24h:    decimal.NewFromFloat(market.Volume24hr.Float64()),
+		EndDate:      market.EndDate,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("forecast failed: %w", err)
+	}
+	return market, t.forecaster.GenerateSignal(forecast, price, minEdgeBps), nil
+}
+
+// RegisterForecastMarketsTool registers polymarket_forecast_markets. Its
+// policy is read-only but slow: a batch makes many LLM calls.
+func RegisterForecastMarketsTool(registry *core.ToolRegistry, gammaClient *gamma.Client, forecaster *agents.Forecaster, opts ...ForecastMarketsOption) {
+	policy := core.ToolPolicy{
+		MaxRetries:     0,
+		DefaultTimeout: 5 * time.Minute,
+		LimitKey:       "polymarket-forecast",
+	}
+
+	registry.Register(NewForecastMarketsTool(gammaClient, forecaster, opts...), policy, RiskClassReadOnly)
+}
@@ -0,0 +1,79 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/phenomenon0/polymarket-agents/core"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/gamma"
+	"github.com/phenomenon0/polymarket-agents/pkg/trader/agents"
+)
+
+// questionLLM answers each market question with a fixed probability.
+type questionLLM map[string]float64
+
+func (q questionLLM) Complete(ctx context.Context, prompt, systemPrompt string) (string, error) {
+	for question, p := range q {
+		if strings.Contains(prompt, question) {
+			return fmt.Sprintf(`{"probability": %g, "confidence": 0.8, "reasoning": "test"}`, p), nil
+		}
+	}
+	return "", fmt.Errorf("unexpected prompt: %s", prompt)
+}
+
+func (questionLLM) Provider() agents.LLMProvider { return agents.ProviderClaude }
+
+func TestForecastMarketsRanksSignals(t *testing.T) {
+	markets := map[string]string{
+		"/markets/0xaaa": `{"id": "1", "conditionId": "0xaaa", "question": "Will A happen?",
+			"outcomePrices": "[\"0.50\", \"0.50\"]", "clobTokenIds": "[\"111\", \"112\"]", "active": true}`,
+		"/markets/0xbbb": `{"id": "2", "conditionId": "0xbbb", "question": "Will B happen?",
+			"outcomePrices": "[\"0.50\", \"0.50\"]", "clobTokenIds": "[\"221\", \"222\"]", "active": true}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := markets[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	forecaster := agents.NewForecaster(nil)
+	forecaster.AddClient(questionLLM{"Will A happen?": 0.55, "Will B happen?": 0.80}, 1)
+
+	tool := NewForecastMarketsTool(gamma.NewClient(gamma.WithBaseURL(server.URL)), forecaster, WithMaxBatch(2))
+	execute := func(input ForecastMarketsInput) *core.ToolExecResult {
+		return tool.Execute(&core.ToolContext{
+			Ctx:     context.Background(),
+			Request: &core.Message{ToolReq: &core.ToolRequestPayload{Input: input}},
+		})
+	}
+
+	result := execute(ForecastMarketsInput{Markets: []string{"0xaaa", "0xbbb"}})
+	if result.Status != core.ToolComplete {
+		t.Fatalf("Forecast failed: %s", result.Error)
+	}
+	out := result.Output.(ForecastMarketsOutput)
+	if len(out.Signals) != 2 || len(out.Failed) != 0 {
+		t.Fatalf("Expected 2 signals and no failures, got %+v", out)
+	}
+
+	// B's 30-point edge outranks A's 5 points
+	if out.Signals[0].ConditionID != "0xbbb" || out.Signals[1].ConditionID != "0xaaa" {
+		t.Errorf("Expected B then A, got %s then %s", out.Signals[0].ConditionID, out.Signals[1].ConditionID)
+	}
+	if s := out.Signals[0]; s.Signal != "BUY" || s.Side != "YES" || s.YesTokenID != "221" || s.Probability != 0.80 {
+		t.Errorf("Unexpected top signal: %+v", s)
+	}
+
+	if result := execute(ForecastMarketsInput{Markets: []string{"0xaaa", "0xbbb", "0xccc"}}); result.Status != core.ToolFailed {
+		t.Error("Expected a batch over the limit to be rejected")
+	}
+}
@@ -15,13 +15,14 @@ import (
 
 // === Gamma API Tools (Market Research) ===
 
-// SearchMarketsTool searches for markets matching a query.
+// SearchMarketsTool finds markets by topic through Gamma's keyword search.
+// Without a query it lists markets instead.
 type SearchMarketsTool struct {
 	client *gamma.Client
 }
 
 type SearchMarketsInput struct {
-	Query     string  `json:"query"`      // Search query (filters by question text)
+	Query     string  `json:"query"`      // Keywords matched against events and questions
 	Active    *bool   `json:"active"`     // Filter by active status
 	Limit     int     `json:"limit"`      // Max results (default 20)
 	MinVolume float64 `json:"min_volume"` // Minimum volume filter
@@ -33,17 +34,18 @@ type SearchMarketsOutput struct {
 }
 
 type MarketSummary struct {
-	ID         string  `json:"id"`
-	Question   string  `json:"question"`
-	YesPrice   float64 `json:"yes_price"`
-	NoPrice    float64 `json:"no_price"`
-	Volume     float64 `json:"volume"`
-	Liquidity  float64 `json:"liquidity"`
-	Active     bool    `json:"active"`
-	EventSlug  string  `json:"event_slug"`
-	EndDate    string  `json:"end_date"`
-	YesTokenID string  `json:"yes_token_id"`
-	NoTokenID  string  `json:"no_token_id"`
+	ID          string  `json:"id"`
+	ConditionID string  `json:"condition_id,omitempty"`
+	Question    string  `json:"question"`
+	YesPrice    float64 `json:"yes_price"`
+	NoPrice     float64 `json:"no_price"`
+	Volume      float64 `json:"volume"`
+	Liquidity   float64 `json:"liquidity"`
+	Active      bool    `json:"active"`
+	EventSlug   string  `json:"event_slug"`
+	EndDate     string  `json:"end_date"`
+	YesTokenID  string  `json:"yes_token_id"`
+	NoTokenID   string  `json:"no_token_id"`
 }
 
 func NewSearchMarketsTool(client *gamma.Client) *SearchMarketsTool {
@@ -58,7 +60,7 @@ func (t *SearchMarketsTool) InputSchema() []byte {
 	return []byte(`{
 		"type": "object",
 		"properties": {
-			"query": {"type": "string", "description": "Search query for market questions"},
+			"query": {"type": "string", "description": "Keywords describing the topic, e.g. \"election\" or \"fed rates\""},
 			"active": {"type": "boolean", "description": "Filter to only active markets"},
 			"limit": {"type": "integer", "description": "Maximum number of results (default 20)", "maximum": 100},
 			"min_volume": {"type": "number", "description": "Minimum trading volume filter"}
@@ -89,42 +91,46 @@ func (t *SearchMarketsTool) Execute(tc *core.ToolContext) *core.ToolExecResult {
 	ctx, cancel := context.WithTimeout(tc.Ctx, 30*time.Second)
 	defer cancel()
 
-	filter := &gamma.MarketsFilter{
-		Active: input.Active,
-		Limit:  input.Limit * 3, // Fetch more to filter client-side
-	}
-
-	markets, err := t.client.ListMarkets(ctx, filter)
-	if err != nil {
-		return errorResult(fmt.Errorf("list markets failed: %w", err))
+	var markets []gamma.Market
+	var err error
+	if query := strings.TrimSpace(input.Query); query != "" {
+		markets, err = t.client.SearchMarkets(ctx, query, &gamma.SearchFilter{
+			Active: input.Active,
+			Limit:  input.Limit,
+		})
+		if err != nil {
+			return errorResult(fmt.Errorf("search markets failed: %w", err))
+		}
+	} else {
+		markets, err = t.client.ListMarkets(ctx, &gamma.MarketsFilter{
+			Active: input.Active,
+			Limit:  input.Limit * 3, // Fetch more to filter by volume client-side
+		})
+		if err != nil {
+			return errorResult(fmt.Errorf("list markets failed: %w", err))
+		}
 	}
 
-	// Filter by query and volume
-	query := strings.ToLower(input.Query)
 	summaries := make([]MarketSummary, 0, len(markets))
 	for _, m := range markets {
-		// Filter by query if provided
-		if query != "" && !strings.Contains(strings.ToLower(m.Question), query) {
-			continue
-		}
-
 		// Filter by volume
 		if input.MinVolume > 0 && m.Volume.Float64() < input.MinVolume {
 			continue
 		}
 
 		summaries = append(summaries, MarketSummary{
-			ID:         m.ID,
-			Question:   m.Question,
-			YesPrice:   m.YesPrice(),
-			NoPrice:    m.NoPrice(),
-			Volume:     m.Volume.Float64(),
-			Liquidity:  m.Liquidity.Float64(),
-			Active:     m.Active,
-			EventSlug:  m.Slug,
-			EndDate:    m.EndDate.Format(time.RFC3339),
-			YesTokenID: m.YesTokenID(),
-			NoTokenID:  m.NoTokenID(),
+			ID:          m.ID,
+			ConditionID: m.ConditionID,
+			Question:    m.Question,
+			YesPrice:    m.YesPrice(),
+			NoPrice:     m.NoPrice(),
+			Volume:      m.Volume.Float64(),
+			Liquidity:   m.Liquidity.Float64(),
+			Active:      m.Active,
+			EventSlug:   m.Slug,
+			EndDate:     m.EndDate.Format(time.RFC3339),
+			YesTokenID:  m.YesTokenID(),
+			NoTokenID:   m.NoTokenID(),
 		})
 
 		if len(summaries) >= input.Limit {
@@ -418,15 +424,16 @@ func (t *GetEventTool) Execute(tc *core.ToolContext) *core.ToolExecResult {
 	markets := make([]MarketSummary, 0, len(event.Markets))
 	for _, m := range event.Markets {
 		markets = append(markets, MarketSummary{
-			ID:         m.ID,
-			Question:   m.Question,
-			YesPrice:   m.YesPrice(),
-			NoPrice:    m.NoPrice(),
-			Volume:     m.Volume.Float64(),
-			Liquidity:  m.Liquidity.Float64(),
-			Active:     m.Active,
-			YesTokenID: m.YesTokenID(),
-			NoTokenID:  m.NoTokenID(),
+			ID:          m.ID,
+			ConditionID: m.ConditionID,
+			Question:    m.Question,
+			YesPrice:    m.YesPrice(),
+			NoPrice:     m.NoPrice(),
+			Volume:      m.Volume.Float64(),
+			Liquidity:   m.Liquidity.Float64(),
+			Active:      m.Active,
+			YesTokenID:  m.YesTokenID(),
+			NoTokenID:   m.NoTokenID(),
 		})
 	}
 
@@ -0,0 +1,72 @@
+package polymarket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phenomenon0/polymarket-agents/core"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/gamma"
+)
+
+func TestSearchMarketsUsesKeywordSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/public-search" {
+			t.Errorf("Expected /public-search, got %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if q := r.URL.Query(); q.Get("q") != "election" || q.Get("events_status") != "active" {
+			t.Errorf("Unexpected search params: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"events": [{
+			"id": "ev1",
+			"title": "Presidential Election Winner 2028",
+			"markets": [
+				{"id": "1", "conditionId": "0xaaa", "question": "Will candidate A win the election?",
+				 "outcomePrices": "[\"0.62\", \"0.38\"]", "clobTokenIds": "[\"111\", \"112\"]",
+				 "volume": "250000", "active": true, "closed": false},
+				{"id": "2", "conditionId": "0xbbb", "question": "Will candidate B win the election?",
+				 "outcomePrices": "[\"0.30\", \"0.70\"]", "clobTokenIds": "[\"221\", \"222\"]",
+				 "volume": "900", "active": true, "closed": false},
+				{"id": "3", "conditionId": "0xccc", "question": "Will candidate C win the primary?",
+				 "outcomePrices": "[\"0\", \"1\"]", "clobTokenIds": "[\"331\", \"332\"]",
+				 "volume": "50000", "active": true, "closed": true}
+			]
+		}]}`))
+	}))
+	defer server.Close()
+
+	tool := NewSearchMarketsTool(gamma.NewClient(gamma.WithBaseURL(server.URL)))
+	search := func(input SearchMarketsInput) SearchMarketsOutput {
+		t.Helper()
+		result := tool.Execute(&core.ToolContext{
+			Ctx:     context.Background(),
+			Request: &core.Message{ToolReq: &core.ToolRequestPayload{Input: input}},
+		})
+		if result.Status != core.ToolComplete {
+			t.Fatalf("Search failed: %s", result.Error)
+		}
+		return result.Output.(SearchMarketsOutput)
+	}
+
+	// The resolved market is dropped even though its event is active
+	out := search(SearchMarketsInput{Query: "election", Active: gamma.BoolPtr(true)})
+	if out.Count != 2 {
+		t.Fatalf("Expected 2 open matches, got %d: %+v", out.Count, out.Markets)
+	}
+	first := out.Markets[0]
+	if first.ConditionID != "0xaaa" || first.Question != "Will candidate A win the election?" || first.YesPrice != 0.62 {
+		t.Errorf("Unexpected first match: %+v", first)
+	}
+	if first.YesTokenID != "111" || first.NoTokenID != "112" {
+		t.Errorf("Expected token IDs 111/112, got %s/%s", first.YesTokenID, first.NoTokenID)
+	}
+
+	out = search(SearchMarketsInput{Query: "election", Active: gamma.BoolPtr(true), MinVolume: 10000})
+	if out.Count != 1 || out.Markets[0].ConditionID != "0xaaa" {
+		t.Errorf("Expected only the high-volume match, got %+v", out.Markets)
+	}
+}
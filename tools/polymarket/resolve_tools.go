@@ -0,0 +1,170 @@
+package polymarket
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/phenomenon0/polymarket-agents/core"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/clob"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/gamma"
+)
+
+// ResolveTokensTool maps between a market's condition ID and its outcome
+// token IDs, so agents pass the right identifier to tools like
+// polymarket_get_orderbook (token ID) and polymarket_get_market_info
+// (condition ID).
+type ResolveTokensTool struct {
+	clob  *clob.Client
+	gamma *gamma.Client // Optional; resolves tokens not yet seen
+
+	mu      sync.Mutex
+	byToken map[string]string // Token ID -> condition ID
+}
+
+type ResolveTokensInput struct {
+	ConditionID string `json:"condition_id,omitempty"` // Resolve to outcome tokens
+	TokenID     string `json:"token_id,omitempty"`     // Resolve to condition ID and outcome
+}
+
+type ResolveTokensOutput struct {
+	ConditionID string            `json:"condition_id"`
+	Tokens      map[string]string `json:"tokens"`             // Outcome -> token ID
+	TokenID     string            `json:"token_id,omitempty"` // Set for token lookups
+	Outcome     string            `json:"outcome,omitempty"`  // Outcome of TokenID
+}
+
+// NewResolveTokensTool creates a resolver backed by the CLOB market
+// endpoint. Token IDs are resolved from markets already looked up; with a
+// Gamma client, unseen tokens are looked up there.
+func NewResolveTokensTool(clobClient *clob.Client, gammaClient *gamma.Client) *ResolveTokensTool {
+	return &ResolveTokensTool{
+		clob:    clobClient,
+		gamma:   gammaClient,
+		byToken: make(map[string]string),
+	}
+}
+
+func (t *ResolveTokensTool) Name() string {
+	return "polymarket_resolve_tokens"
+}
+
+func (t *ResolveTokensTool) InputSchema() []byte {
+	return []byte(`{
+		"type": "object",
+		"properties": {
+			"condition_id": {"type": "string", "description": "Market condition ID (0x...) to resolve to its outcome token IDs"},
+			"token_id": {"type": "string", "description": "Outcome token ID to resolve to its condition ID and outcome"}
+		}
+	}`)
+}
+
+func (t *ResolveTokensTool) OutputSchema() []byte {
+	return []byte(`{"type": "object"}`)
+}
+
+func (t *ResolveTokensTool) Execute(tc *core.ToolContext) *core.ToolExecResult {
+	var input ResolveTokensInput
+	if err := parseInput(tc.Request, &input); err != nil {
+		return errorResult(err)
+	}
+
+	if (input.ConditionID == "") == (input.TokenID == "") {
+		return errorResult(fmt.Errorf("exactly one of condition_id or token_id is required"))
+	}
+
+	ctx, cancel := context.WithTimeout(tc.Ctx, 30*time.Second)
+	defer cancel()
+
+	conditionID := input.ConditionID
+	if input.TokenID != "" {
+		var err error
+		conditionID, err = t.lookupToken(ctx, input.TokenID)
+		if err != nil {
+			return errorResult(err)
+		}
+	}
+
+	tokens, err := t.resolveCondition(ctx, conditionID)
+	if err != nil {
+		return errorResult(err)
+	}
+
+	output := ResolveTokensOutput{ConditionID: conditionID, Tokens: tokens}
+	if input.TokenID != "" {
+		output.TokenID = input.TokenID
+		for outcome, id := range tokens {
+			if id == input.TokenID {
+				output.Outcome = outcome
+			}
+		}
+	}
+
+	return &core.ToolExecResult{
+		Status: core.ToolComplete,
+		Output: output,
+	}
+}
+
+// resolveCondition fetches a market's outcome tokens and caches them for
+// reverse lookup.
+func (t *ResolveTokensTool) resolveCondition(ctx context.Context, conditionID string) (map[string]string, error) {
+	market, err := t.clob.GetMarket(ctx, conditionID)
+	if err != nil {
+		return nil, fmt.Errorf("get market failed: %w", err)
+	}
+	if len(market.Tokens) == 0 {
+		return nil, fmt.Errorf("market %s has no outcome tokens", conditionID)
+	}
+
+	tokens := make(map[string]string, len(market.Tokens))
+	t.mu.Lock()
+	for _, tok := range market.Tokens {
+		tokens[tok.Outcome] = tok.TokenID
+		t.byToken[tok.TokenID] = conditionID
+	}
+	t.mu.Unlock()
+	return tokens, nil
+}
+
+// lookupToken returns the condition ID of the market a token belongs to,
+// from the cache or Gamma.
+func (t *ResolveTokensTool) lookupToken(ctx context.Context, tokenID string) (string, error) {
+	t.mu.Lock()
+	conditionID, ok := t.byToken[tokenID]
+	t.mu.Unlock()
+	if ok {
+		return conditionID, nil
+	}
+
+	if t.gamma == nil {
+		return "", fmt.Errorf("unknown token %s: resolve its condition_id first", tokenID)
+	}
+	market, err := t.gamma.GetMarketByTokenID(ctx, tokenID)
+	if err != nil {
+		return "", fmt.Errorf("lookup token failed: %w", err)
+	}
+	if market.ConditionID == "" {
+		return "", fmt.Errorf("market for token %s has no condition ID", tokenID)
+	}
+	return market.ConditionID, nil
+}
+
+// RegisterResolveTokensTool registers polymarket_resolve_tokens. gammaClient
+// may be nil, in which case only tokens of markets already resolved by
+// condition ID can be looked up.
+func RegisterResolveTokensTool(registry *core.ToolRegistry, clobClient *clob.Client, gammaClient *gamma.Client) {
+	policy := core.ToolPolicy{
+		MaxRetries:      3,
+		BaseBackoff:     100 * time.Millisecond,
+		MaxBackoff:      5 * time.Second,
+		Retriable:       true,
+		DefaultTimeout:  30 * time.Second,
+		RateLimitPerSec: 10.0,
+		Burst:           20,
+		LimitKey:        "polymarket-clob",
+	}
+
+	registry.Register(NewResolveTokensTool(clobClient, gammaClient), policy, RiskClassReadOnly)
+}
@@ -0,0 +1,93 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/phenomenon0/polymarket-agents/core"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/clob"
+	"github.com/phenomenon0/polymarket-agents/pkg/polymarket/gamma"
+)
+
+func TestResolveTokensBothDirections(t *testing.T) {
+	clobServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/markets/0xabc" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(clob.MarketInfo{
+			ConditionID: "0xabc",
+			Tokens: []clob.Token{
+				{TokenID: "111", Outcome: "Yes"},
+				{TokenID: "222", Outcome: "No"},
+			},
+		})
+	}))
+	defer clobServer.Close()
+
+	var gammaCalls int
+	gammaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gammaCalls++
+		if r.URL.Query().Get("clob_token_ids") != "222" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		json.NewEncoder(w).Encode([]gamma.Market{{ConditionID: "0xabc", ClobTokenIDsRaw: `["111", "222"]`}})
+	}))
+	defer gammaServer.Close()
+
+	tool := NewResolveTokensTool(
+		clob.NewPublicClient(clob.WithCLOBBaseURL(clobServer.URL)),
+		gamma.NewClient(gamma.WithBaseURL(gammaServer.URL)),
+	)
+	resolve := func(input ResolveTokensInput) (ResolveTokensOutput, *core.ToolExecResult) {
+		result := tool.Execute(&core.ToolContext{
+			Ctx:     context.Background(),
+			Request: &core.Message{ToolReq: &core.ToolRequestPayload{Input: input}},
+		})
+		out, _ := result.Output.(ResolveTokensOutput)
+		return out, result
+	}
+
+	// Token not yet seen: found through Gamma
+	out, result := resolve(ResolveTokensInput{TokenID: "222"})
+	if result.Status != core.ToolComplete {
+		t.Fatalf("Token lookup failed: %s", result.Error)
+	}
+	if out.ConditionID != "0xabc" || out.Outcome != "No" {
+		t.Errorf("Expected 0xabc/No, got %s/%s", out.ConditionID, out.Outcome)
+	}
+
+	// Condition to tokens
+	out, result = resolve(ResolveTokensInput{ConditionID: "0xabc"})
+	if result.Status != core.ToolComplete {
+		t.Fatalf("Condition lookup failed: %s", result.Error)
+	}
+	if out.Tokens["Yes"] != "111" || out.Tokens["No"] != "222" {
+		t.Errorf("Unexpected outcome map: %v", out.Tokens)
+	}
+
+	// Sibling token is now cached, so Gamma is not asked again
+	calls := gammaCalls
+	out, result = resolve(ResolveTokensInput{TokenID: "111"})
+	if result.Status != core.ToolComplete {
+		t.Fatalf("Cached token lookup failed: %s", result.Error)
+	}
+	if out.ConditionID != "0xabc" || out.Outcome != "Yes" {
+		t.Errorf("Expected 0xabc/Yes, got %s/%s", out.ConditionID, out.Outcome)
+	}
+	if gammaCalls != calls {
+		t.Errorf("Expected cached lookup, got %d Gamma calls", gammaCalls-calls)
+	}
+
+	if _, result := resolve(ResolveTokensInput{TokenID: "999"}); result.Status != core.ToolFailed {
+		t.Error("Expected unknown token to fail")
+	}
+	if _, result := resolve(ResolveTokensInput{ConditionID: "0xabc", TokenID: "111"}); result.Status != core.ToolFailed {
+		t.Error("Expected both identifiers to be rejected")
+	}
+}